@@ -0,0 +1,44 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"mth/internal/repository"
+	"mth/internal/service"
+	"mth/pkg/config"
+	"mth/pkg/database"
+	"mth/pkg/log"
+	"os"
+)
+
+// Сверка районов мест с границами районов. Без -apply только печатает отчёт в JSON
+func main() {
+	cityID := flag.Int("city", 0, "city id, all cities if 0")
+	apply := flag.Bool("apply", false, "move places into the district computed from coordinates")
+	flag.Parse()
+
+	logger, loggerInfoFile, loggerErrorFile := log.InitLogger()
+	defer loggerInfoFile.Close()
+	defer loggerErrorFile.Close()
+
+	config.InitConfig()
+
+	db := database.GetDB()
+
+	districtService := service.InitDistrictService(repository.InitDistrictRepo(db), logger)
+
+	report, err := districtService.Reconcile(context.Background(), *cityID, *apply)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err = encoder.Encode(report); err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,55 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE places
+    ADD COLUMN reviews_average REAL NOT NULL DEFAULT 0,
+    ADD COLUMN reviews_count INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN reviews_histogram INTEGER[] NOT NULL DEFAULT '{0,0,0,0,0}';
+
+ALTER TABLE routes
+    ADD COLUMN reviews_average REAL NOT NULL DEFAULT 0,
+    ADD COLUMN reviews_count INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN reviews_histogram INTEGER[] NOT NULL DEFAULT '{0,0,0,0,0}';
+
+UPDATE places SET
+    reviews_count = agg.cnt,
+    reviews_average = agg.average,
+    reviews_histogram = ARRAY[agg.h1, agg.h2, agg.h3, agg.h4, agg.h5]
+FROM (
+    SELECT place_id, COUNT(*)::INTEGER AS cnt, COALESCE(AVG(mark), 0) AS average,
+        COUNT(*) FILTER (WHERE LEAST(GREATEST(ROUND(mark), 1), 5) = 1)::INTEGER AS h1,
+        COUNT(*) FILTER (WHERE LEAST(GREATEST(ROUND(mark), 1), 5) = 2)::INTEGER AS h2,
+        COUNT(*) FILTER (WHERE LEAST(GREATEST(ROUND(mark), 1), 5) = 3)::INTEGER AS h3,
+        COUNT(*) FILTER (WHERE LEAST(GREATEST(ROUND(mark), 1), 5) = 4)::INTEGER AS h4,
+        COUNT(*) FILTER (WHERE LEAST(GREATEST(ROUND(mark), 1), 5) = 5)::INTEGER AS h5
+    FROM places_reviews GROUP BY place_id
+) agg
+WHERE places.id = agg.place_id;
+
+UPDATE routes SET
+    reviews_count = agg.cnt,
+    reviews_average = agg.average,
+    reviews_histogram = ARRAY[agg.h1, agg.h2, agg.h3, agg.h4, agg.h5]
+FROM (
+    SELECT route_id, COUNT(*)::INTEGER AS cnt, COALESCE(AVG(mark), 0) AS average,
+        COUNT(*) FILTER (WHERE LEAST(GREATEST(ROUND(mark), 1), 5) = 1)::INTEGER AS h1,
+        COUNT(*) FILTER (WHERE LEAST(GREATEST(ROUND(mark), 1), 5) = 2)::INTEGER AS h2,
+        COUNT(*) FILTER (WHERE LEAST(GREATEST(ROUND(mark), 1), 5) = 3)::INTEGER AS h3,
+        COUNT(*) FILTER (WHERE LEAST(GREATEST(ROUND(mark), 1), 5) = 4)::INTEGER AS h4,
+        COUNT(*) FILTER (WHERE LEAST(GREATEST(ROUND(mark), 1), 5) = 5)::INTEGER AS h5
+    FROM route_reviews GROUP BY route_id
+) agg
+WHERE routes.id = agg.route_id;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE places
+    DROP COLUMN reviews_average,
+    DROP COLUMN reviews_count,
+    DROP COLUMN reviews_histogram;
+
+ALTER TABLE routes
+    DROP COLUMN reviews_average,
+    DROP COLUMN reviews_count,
+    DROP COLUMN reviews_histogram;
+-- +goose StatementEnd
@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS media (
+    id SERIAL PRIMARY KEY,
+    owner_id INTEGER REFERENCES users(id),
+    storage_key VARCHAR UNIQUE NOT NULL,
+    thumbnail_key VARCHAR,
+    content_type VARCHAR NOT NULL,
+    size BIGINT NOT NULL,
+    width INTEGER,
+    height INTEGER,
+    created_at TIMESTAMP NOT NULL DEFAULT current_timestamp
+);
+
+CREATE TABLE IF NOT EXISTS media_attachments (
+    id SERIAL PRIMARY KEY,
+    media_id INTEGER REFERENCES media(id) ON DELETE CASCADE,
+    entity_type VARCHAR NOT NULL CHECK (entity_type IN ('place', 'route', 'place_review', 'route_review', 'note')),
+    entity_id INTEGER NOT NULL,
+    position INTEGER NOT NULL DEFAULT 0,
+    UNIQUE (entity_type, entity_id, media_id)
+);
+
+CREATE INDEX IF NOT EXISTS media_attachments_entity_idx ON media_attachments (entity_type, entity_id, position);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS media_attachments, media CASCADE;
+-- +goose StatementEnd
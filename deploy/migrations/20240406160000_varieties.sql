@@ -0,0 +1,31 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS varieties (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR UNIQUE NOT NULL,
+    display_names JSONB NOT NULL DEFAULT '{}',
+    icon_key VARCHAR NOT NULL DEFAULT '',
+    checkinable BOOLEAN NOT NULL DEFAULT true,
+    geofence_radius INTEGER NOT NULL DEFAULT 50
+);
+
+INSERT INTO varieties (name, display_names)
+    SELECT DISTINCT variety, jsonb_build_object('ru', variety) FROM places
+    WHERE variety IS NOT NULL AND variety <> '';
+
+-- раньше захардкожены в service/user.go как badPlaceVarieties
+INSERT INTO varieties (name, display_names, checkinable, geofence_radius) VALUES
+    ('Редкое событие', '{"ru": "Редкое событие"}', false, 200),
+    ('Площади', '{"ru": "Площади"}', false, 200),
+    ('Архитектура', '{"ru": "Архитектура"}', false, 100),
+    ('Памятники', '{"ru": "Памятники"}', false, 50),
+    ('Набережные', '{"ru": "Набережные"}', false, 300),
+    ('Улицы', '{"ru": "Улицы"}', false, 300),
+    ('Природа', '{"ru": "Природа"}', false, 500)
+ON CONFLICT (name) DO UPDATE SET checkinable = EXCLUDED.checkinable, geofence_radius = EXCLUDED.geofence_radius;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS varieties;
+-- +goose StatementEnd
@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE places
+    ADD COLUMN popularity DOUBLE PRECISION NOT NULL DEFAULT 0;
+
+ALTER TABLE routes
+    ADD COLUMN popularity DOUBLE PRECISION NOT NULL DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS places_popularity_idx ON places (popularity DESC, id);
+CREATE INDEX IF NOT EXISTS routes_popularity_idx ON routes (popularity DESC, id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS places_popularity_idx;
+DROP INDEX IF EXISTS routes_popularity_idx;
+
+ALTER TABLE places
+    DROP COLUMN popularity;
+
+ALTER TABLE routes
+    DROP COLUMN popularity;
+-- +goose StatementEnd
@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS place_duplicates (
+    id SERIAL PRIMARY KEY,
+    place_id INTEGER NOT NULL REFERENCES places(id) ON DELETE CASCADE,
+    duplicate_id INTEGER NOT NULL REFERENCES places(id) ON DELETE CASCADE,
+    similarity REAL NOT NULL,
+    distance DOUBLE PRECISION,
+    status VARCHAR NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'dismissed')),
+    detected_at TIMESTAMP NOT NULL DEFAULT current_timestamp,
+    UNIQUE (place_id, duplicate_id),
+    CHECK (place_id < duplicate_id)
+);
+
+-- журнал слияний хранит id без внешних ключей, чтобы пережить удаление и повторное слияние мест
+CREATE TABLE IF NOT EXISTS place_merges (
+    id SERIAL PRIMARY KEY,
+    survivor_id INTEGER NOT NULL,
+    duplicate_id INTEGER NOT NULL,
+    merged_by INTEGER,
+    duplicate JSONB NOT NULL,
+    counts JSONB NOT NULL,
+    merged_at TIMESTAMP NOT NULL DEFAULT current_timestamp
+);
+
+CREATE INDEX IF NOT EXISTS place_merges_survivor_idx ON place_merges (survivor_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS place_merges;
+DROP TABLE IF EXISTS place_duplicates;
+-- +goose StatementEnd
@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE city
+    ADD COLUMN country VARCHAR NOT NULL DEFAULT '',
+    ADD COLUMN time_zone VARCHAR NOT NULL DEFAULT 'Europe/Moscow',
+    ADD COLUMN latitude DOUBLE PRECISION,
+    ADD COLUMN longitude DOUBLE PRECISION,
+    ADD COLUMN currency VARCHAR(3) NOT NULL DEFAULT 'RUB';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE city
+    DROP COLUMN country,
+    DROP COLUMN time_zone,
+    DROP COLUMN latitude,
+    DROP COLUMN longitude,
+    DROP COLUMN currency;
+-- +goose StatementEnd
@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE district
+    ADD COLUMN boundary JSONB;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE district
+    DROP COLUMN boundary;
+-- +goose StatementEnd
@@ -0,0 +1,36 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS tag_categories (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR NOT NULL UNIQUE
+);
+
+ALTER TABLE tags
+    ADD COLUMN parent_id INTEGER REFERENCES tags(id) ON DELETE SET NULL,
+    ADD COLUMN category_id INTEGER REFERENCES tag_categories(id) ON DELETE SET NULL,
+    ADD CONSTRAINT tags_parent_not_self CHECK (parent_id <> id);
+
+CREATE INDEX IF NOT EXISTS tags_parent_id_idx ON tags (parent_id);
+
+CREATE TABLE IF NOT EXISTS tag_synonyms (
+    id SERIAL PRIMARY KEY,
+    tag_id INTEGER NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+    name VARCHAR NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS tag_synonyms_name_idx ON tag_synonyms (lower(name));
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS tag_synonyms;
+
+DROP INDEX IF EXISTS tags_parent_id_idx;
+
+ALTER TABLE tags
+    DROP CONSTRAINT tags_parent_not_self,
+    DROP COLUMN category_id,
+    DROP COLUMN parent_id;
+
+DROP TABLE IF EXISTS tag_categories;
+-- +goose StatementEnd
@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS translations (
+    id SERIAL PRIMARY KEY,
+    entity_type VARCHAR NOT NULL CHECK (entity_type IN ('tag', 'place', 'route')),
+    entity_id INTEGER NOT NULL,
+    lang VARCHAR(35) NOT NULL,
+    field VARCHAR NOT NULL CHECK (field IN ('name', 'description')),
+    value TEXT NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT current_timestamp,
+    UNIQUE (entity_type, entity_id, lang, field)
+);
+
+CREATE INDEX IF NOT EXISTS translations_lang_idx ON translations (entity_type, lang, field);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS translations;
+-- +goose StatementEnd
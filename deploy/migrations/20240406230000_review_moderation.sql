@@ -0,0 +1,52 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE places_reviews
+    ADD COLUMN IF NOT EXISTS status VARCHAR NOT NULL DEFAULT 'published'
+        CHECK (status IN ('pending', 'published', 'rejected', 'hidden')),
+    ADD COLUMN IF NOT EXISTS moderation_reason TEXT,
+    ADD COLUMN IF NOT EXISTS moderated_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    ADD COLUMN IF NOT EXISTS moderated_at TIMESTAMP;
+
+ALTER TABLE route_reviews
+    ADD COLUMN IF NOT EXISTS status VARCHAR NOT NULL DEFAULT 'published'
+        CHECK (status IN ('pending', 'published', 'rejected', 'hidden')),
+    ADD COLUMN IF NOT EXISTS moderation_reason TEXT,
+    ADD COLUMN IF NOT EXISTS moderated_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    ADD COLUMN IF NOT EXISTS moderated_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS places_reviews_status_idx ON places_reviews (status) WHERE status <> 'published';
+CREATE INDEX IF NOT EXISTS route_reviews_status_idx ON route_reviews (status) WHERE status <> 'published';
+
+CREATE TABLE IF NOT EXISTS review_flags (
+    id SERIAL PRIMARY KEY,
+    place_review_id INTEGER REFERENCES places_reviews(id) ON DELETE CASCADE,
+    route_review_id INTEGER REFERENCES route_reviews(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    reason VARCHAR NOT NULL CHECK (reason IN ('spam', 'offensive', 'off_topic', 'fake', 'other')),
+    comment TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT current_timestamp,
+    resolved_at TIMESTAMP,
+    CHECK (num_nonnulls(place_review_id, route_review_id) = 1),
+    UNIQUE (place_review_id, user_id),
+    UNIQUE (route_review_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS review_flags_open_idx ON review_flags (place_review_id, route_review_id) WHERE resolved_at IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS review_flags;
+
+ALTER TABLE places_reviews
+    DROP COLUMN IF EXISTS status,
+    DROP COLUMN IF EXISTS moderation_reason,
+    DROP COLUMN IF EXISTS moderated_by,
+    DROP COLUMN IF EXISTS moderated_at;
+
+ALTER TABLE route_reviews
+    DROP COLUMN IF EXISTS status,
+    DROP COLUMN IF EXISTS moderation_reason,
+    DROP COLUMN IF EXISTS moderated_by,
+    DROP COLUMN IF EXISTS moderated_at;
+-- +goose StatementEnd
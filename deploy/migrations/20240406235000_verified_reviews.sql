@@ -0,0 +1,9 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE varieties ADD COLUMN IF NOT EXISTS require_verified_review BOOLEAN NOT NULL DEFAULT false;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE varieties DROP COLUMN IF EXISTS require_verified_review;
+-- +goose StatementEnd
@@ -0,0 +1,45 @@
+-- +goose Up
+-- +goose StatementBegin
+-- у существующих пользователей дата регистрации неизвестна, они считаются старыми аккаунтами
+ALTER TABLE users ADD COLUMN IF NOT EXISTS created_at TIMESTAMP;
+ALTER TABLE users ALTER COLUMN created_at SET DEFAULT current_timestamp;
+
+ALTER TABLE places_reviews
+    ADD COLUMN IF NOT EXISTS helpful_count INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS unhelpful_count INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS helpfulness DOUBLE PRECISION NOT NULL DEFAULT 0;
+
+ALTER TABLE route_reviews
+    ADD COLUMN IF NOT EXISTS helpful_count INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS unhelpful_count INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS helpfulness DOUBLE PRECISION NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS review_votes (
+    id SERIAL PRIMARY KEY,
+    place_review_id INTEGER REFERENCES places_reviews(id) ON DELETE CASCADE,
+    route_review_id INTEGER REFERENCES route_reviews(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    helpful BOOLEAN NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT current_timestamp,
+    CHECK (num_nonnulls(place_review_id, route_review_id) = 1),
+    UNIQUE (place_review_id, user_id),
+    UNIQUE (route_review_id, user_id)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS review_votes;
+
+ALTER TABLE places_reviews
+    DROP COLUMN IF EXISTS helpful_count,
+    DROP COLUMN IF EXISTS unhelpful_count,
+    DROP COLUMN IF EXISTS helpfulness;
+
+ALTER TABLE route_reviews
+    DROP COLUMN IF EXISTS helpful_count,
+    DROP COLUMN IF EXISTS unhelpful_count,
+    DROP COLUMN IF EXISTS helpfulness;
+
+ALTER TABLE users DROP COLUMN IF EXISTS created_at;
+-- +goose StatementEnd
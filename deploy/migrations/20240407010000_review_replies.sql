@@ -0,0 +1,50 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS entity_managers (
+    id SERIAL PRIMARY KEY,
+    entity_type VARCHAR NOT NULL CHECK (entity_type IN ('place', 'route')),
+    entity_id INTEGER NOT NULL,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role VARCHAR NOT NULL CHECK (role IN ('owner', 'editor')),
+    created_at TIMESTAMP NOT NULL DEFAULT current_timestamp,
+    UNIQUE (entity_type, entity_id, user_id)
+);
+
+CREATE TABLE IF NOT EXISTS review_replies (
+    id SERIAL PRIMARY KEY,
+    place_review_id INTEGER UNIQUE REFERENCES places_reviews(id) ON DELETE CASCADE,
+    route_review_id INTEGER UNIQUE REFERENCES route_reviews(id) ON DELETE CASCADE,
+    author_id INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    text TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT current_timestamp,
+    updated_at TIMESTAMP NOT NULL DEFAULT current_timestamp,
+    CHECK (num_nonnulls(place_review_id, route_review_id) = 1)
+);
+
+-- прежние версии ответа, текущая хранится в review_replies
+CREATE TABLE IF NOT EXISTS review_reply_history (
+    id SERIAL PRIMARY KEY,
+    reply_id INTEGER NOT NULL REFERENCES review_replies(id) ON DELETE CASCADE,
+    author_id INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    text TEXT NOT NULL,
+    written_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS review_reply_history_reply_id_idx ON review_reply_history (reply_id);
+
+CREATE TABLE IF NOT EXISTS notifications (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    kind VARCHAR NOT NULL,
+    payload JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP NOT NULL DEFAULT current_timestamp,
+    read_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS notifications_user_id_idx ON notifications (user_id, id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS notifications, review_reply_history, review_replies, entity_managers;
+-- +goose StatementEnd
@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+-- у заметок и профилей нет статуса модерации, сработавший фильтр оставляет жалобу для модератора
+CREATE TABLE IF NOT EXISTS content_reports (
+    id SERIAL PRIMARY KEY,
+    entity_type TEXT NOT NULL CHECK (entity_type IN ('note', 'user')),
+    entity_id INTEGER NOT NULL,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    rules TEXT[] NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT current_timestamp,
+    resolved_at TIMESTAMP,
+    resolved_by INTEGER
+);
+
+-- повторная правка до решения модератора обновляет открытую жалобу
+CREATE UNIQUE INDEX IF NOT EXISTS content_reports_open ON content_reports (entity_type, entity_id) WHERE resolved_at IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS content_reports;
+-- +goose StatementEnd
@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+-- критерии оценки мест задаются видом места, для маршрутов - настройкой REVIEW_ROUTE_CRITERIA
+ALTER TABLE varieties ADD COLUMN IF NOT EXISTS review_criteria TEXT[] NOT NULL DEFAULT '{}';
+
+ALTER TABLE places_reviews ADD COLUMN IF NOT EXISTS sub_marks JSONB NOT NULL DEFAULT '{}';
+ALTER TABLE route_reviews ADD COLUMN IF NOT EXISTS sub_marks JSONB NOT NULL DEFAULT '{}';
+
+-- средняя оценка и число оценок по каждому критерию: {"price": {"average": 4.2, "count": 10}}
+ALTER TABLE places ADD COLUMN IF NOT EXISTS reviews_criteria JSONB NOT NULL DEFAULT '{}';
+ALTER TABLE routes ADD COLUMN IF NOT EXISTS reviews_criteria JSONB NOT NULL DEFAULT '{}';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE routes DROP COLUMN IF EXISTS reviews_criteria;
+ALTER TABLE places DROP COLUMN IF EXISTS reviews_criteria;
+
+ALTER TABLE route_reviews DROP COLUMN IF EXISTS sub_marks;
+ALTER TABLE places_reviews DROP COLUMN IF EXISTS sub_marks;
+
+ALTER TABLE varieties DROP COLUMN IF EXISTS review_criteria;
+-- +goose StatementEnd
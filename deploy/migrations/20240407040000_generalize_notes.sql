@@ -0,0 +1,44 @@
+-- +goose Up
+-- +goose StatementBegin
+-- заметка относится к месту, маршруту, поездке или дню поездки, у одной цели может быть несколько заметок
+ALTER TABLE notes DROP CONSTRAINT IF EXISTS notes_user_id_place_id_key;
+
+ALTER TABLE notes
+    ADD COLUMN IF NOT EXISTS target_type TEXT NOT NULL DEFAULT 'place',
+    ADD COLUMN IF NOT EXISTS route_id INTEGER REFERENCES routes(id) ON DELETE CASCADE,
+    ADD COLUMN IF NOT EXISTS trip_id INTEGER REFERENCES trips(id) ON DELETE CASCADE,
+    ADD COLUMN IF NOT EXISTS trip_day INTEGER,
+    ADD COLUMN IF NOT EXISTS created_at TIMESTAMP NOT NULL DEFAULT current_timestamp,
+    ADD COLUMN IF NOT EXISTS updated_at TIMESTAMP NOT NULL DEFAULT current_timestamp;
+
+ALTER TABLE notes ADD CONSTRAINT notes_target CHECK (
+    (target_type = 'place' AND place_id IS NOT NULL AND route_id IS NULL AND trip_id IS NULL AND trip_day IS NULL) OR
+    (target_type = 'route' AND route_id IS NOT NULL AND place_id IS NULL AND trip_id IS NULL AND trip_day IS NULL) OR
+    (target_type = 'trip' AND trip_id IS NOT NULL AND place_id IS NULL AND route_id IS NULL AND trip_day IS NULL) OR
+    (target_type = 'trip_day' AND trip_id IS NOT NULL AND trip_day IS NOT NULL AND place_id IS NULL AND route_id IS NULL)
+);
+
+CREATE INDEX IF NOT EXISTS notes_user_created ON notes (user_id, created_at);
+CREATE INDEX IF NOT EXISTS notes_user_place ON notes (user_id, place_id) WHERE place_id IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+-- заметки не к местам и повторные заметки к одному месту не помещаются в старую схему
+DELETE FROM notes WHERE target_type <> 'place';
+DELETE FROM notes d USING notes s WHERE d.user_id = s.user_id AND d.place_id = s.place_id AND d.id < s.id;
+
+DROP INDEX IF EXISTS notes_user_place;
+DROP INDEX IF EXISTS notes_user_created;
+ALTER TABLE notes DROP CONSTRAINT IF EXISTS notes_target;
+
+ALTER TABLE notes
+    DROP COLUMN IF EXISTS updated_at,
+    DROP COLUMN IF EXISTS created_at,
+    DROP COLUMN IF EXISTS trip_day,
+    DROP COLUMN IF EXISTS trip_id,
+    DROP COLUMN IF EXISTS route_id,
+    DROP COLUMN IF EXISTS target_type;
+
+ALTER TABLE notes ADD CONSTRAINT notes_user_id_place_id_key UNIQUE (user_id, place_id);
+-- +goose StatementEnd
@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+-- по умолчанию заметки остаются личными, как раньше
+ALTER TABLE notes ADD COLUMN IF NOT EXISTS visibility TEXT NOT NULL DEFAULT 'private'
+    CHECK (visibility IN ('private', 'companions', 'link', 'public'));
+
+CREATE INDEX IF NOT EXISTS notes_public_place ON notes (place_id, id) WHERE visibility = 'public';
+CREATE INDEX IF NOT EXISTS notes_public_route ON notes (route_id, id) WHERE visibility = 'public';
+
+-- ссылка отзывается проставлением revoked_at, отозванный токен больше не открывает заметку
+CREATE TABLE IF NOT EXISTS note_share_tokens (
+    token TEXT PRIMARY KEY,
+    note_id INTEGER NOT NULL REFERENCES notes(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL DEFAULT current_timestamp,
+    revoked_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS note_share_tokens_note ON note_share_tokens (note_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS note_share_tokens;
+DROP INDEX IF EXISTS notes_public_route;
+DROP INDEX IF EXISTS notes_public_place;
+ALTER TABLE notes DROP COLUMN IF EXISTS visibility;
+-- +goose StatementEnd
@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+-- запрос присоединиться к объявлению попутчика, owner_id - автор объявления на момент запроса
+CREATE TABLE IF NOT EXISTS companion_requests (
+    id SERIAL PRIMARY KEY,
+    listing_type TEXT NOT NULL CHECK (listing_type IN ('place', 'route')),
+    listing_id INTEGER NOT NULL,
+    requester_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    owner_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'accepted', 'declined', 'cancelled')),
+    message TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT current_timestamp,
+    updated_at TIMESTAMP NOT NULL DEFAULT current_timestamp
+);
+
+-- к одному объявлению у пользователя не больше одного действующего запроса
+CREATE UNIQUE INDEX IF NOT EXISTS companion_requests_active ON companion_requests (listing_type, listing_id, requester_id)
+    WHERE status IN ('pending', 'accepted');
+CREATE INDEX IF NOT EXISTS companion_requests_owner ON companion_requests (owner_id, id);
+CREATE INDEX IF NOT EXISTS companion_requests_requester ON companion_requests (requester_id, id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS companion_requests;
+-- +goose StatementEnd
@@ -0,0 +1,35 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE places
+    ADD COLUMN popularity_prev DOUBLE PRECISION NOT NULL DEFAULT 0;
+
+ALTER TABLE routes
+    ADD COLUMN popularity_prev DOUBLE PRECISION NOT NULL DEFAULT 0;
+
+UPDATE places SET popularity_prev = popularity;
+UPDATE routes SET popularity_prev = popularity;
+
+CREATE INDEX IF NOT EXISTS places_popularity_prev_idx ON places (popularity_prev DESC, id);
+CREATE INDEX IF NOT EXISTS routes_popularity_prev_idx ON routes (popularity_prev DESC, id);
+
+CREATE TABLE IF NOT EXISTS popularity_state (
+    id BOOLEAN PRIMARY KEY DEFAULT TRUE CHECK (id),
+    generation BIGINT NOT NULL DEFAULT 0
+);
+
+INSERT INTO popularity_state (id, generation) VALUES (TRUE, 0);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS popularity_state;
+
+DROP INDEX IF EXISTS places_popularity_prev_idx;
+DROP INDEX IF EXISTS routes_popularity_prev_idx;
+
+ALTER TABLE places
+    DROP COLUMN popularity_prev;
+
+ALTER TABLE routes
+    DROP COLUMN popularity_prev;
+-- +goose StatementEnd
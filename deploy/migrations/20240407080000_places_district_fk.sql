@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+UPDATE places SET district_id = NULL
+WHERE district_id IS NOT NULL AND NOT EXISTS(SELECT 1 FROM district d WHERE d.id = places.district_id);
+
+ALTER TABLE places
+    ADD CONSTRAINT places_district_id_fkey FOREIGN KEY (district_id) REFERENCES district(id) ON DELETE SET NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE places
+    DROP CONSTRAINT IF EXISTS places_district_id_fkey;
+-- +goose StatementEnd
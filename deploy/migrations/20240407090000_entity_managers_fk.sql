@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+DELETE FROM entity_managers m
+WHERE (m.entity_type = 'place' AND NOT EXISTS(SELECT 1 FROM places p WHERE p.id = m.entity_id))
+   OR (m.entity_type = 'route' AND NOT EXISTS(SELECT 1 FROM routes r WHERE r.id = m.entity_id));
+
+-- ссылки на место и маршрут вычисляются из entity_type и entity_id, чтобы менеджеры удалялись вместе с сущностью
+ALTER TABLE entity_managers
+    ADD COLUMN IF NOT EXISTS place_id INTEGER
+        GENERATED ALWAYS AS (CASE WHEN entity_type = 'place' THEN entity_id END) STORED
+        REFERENCES places(id) ON DELETE CASCADE,
+    ADD COLUMN IF NOT EXISTS route_id INTEGER
+        GENERATED ALWAYS AS (CASE WHEN entity_type = 'route' THEN entity_id END) STORED
+        REFERENCES routes(id) ON DELETE CASCADE;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE entity_managers
+    DROP COLUMN IF EXISTS place_id,
+    DROP COLUMN IF EXISTS route_id;
+-- +goose StatementEnd
@@ -15,8 +15,8 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/companions/by_user": {
-            "get": {
+        "/city": {
+            "delete": {
                 "consumes": [
                     "application/json"
                 ],
@@ -24,13 +24,12 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "companions"
+                    "city"
                 ],
-                "summary": "get companion data by user id",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "user id",
+                        "description": "City id",
                         "name": "id",
                         "in": "query",
                         "required": true
@@ -38,13 +37,28 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "success",
+                        "description": "Successfully"
+                    },
+                    "400": {
+                        "description": "Invalid input",
                         "schema": {
-                            "$ref": "#/definitions/swagger.Companion"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
-                    "400": {
-                        "description": "Invalid input data",
+                    "404": {
+                        "description": "City not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "City is in use",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -64,9 +78,8 @@ const docTemplate = `{
                 }
             }
         },
-        "/companions/create_place_companion": {
-            "post": {
-                "description": "Adds a new companion place to the database",
+        "/city/by_id": {
+            "get": {
                 "consumes": [
                     "application/json"
                 ],
@@ -74,26 +87,35 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "companions"
+                    "city"
                 ],
-                "summary": "Create a new companion place",
                 "parameters": [
                     {
-                        "description": "Companion Place Data",
-                        "name": "data",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.CompanionsPlaceCreate"
-                        }
+                        "type": "integer",
+                        "description": "City id",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successfully created companion place with id"
+                        "description": "Successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.City"
+                        }
                     },
                     "400": {
-                        "description": "Invalid input data",
+                        "description": "Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "City not found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -113,9 +135,8 @@ const docTemplate = `{
                 }
             }
         },
-        "/companions/create_route_companion": {
+        "/city/create": {
             "post": {
-                "description": "Adds a new companion place to the database",
                 "consumes": [
                     "application/json"
                 ],
@@ -123,26 +144,28 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "companions"
+                    "city"
                 ],
-                "summary": "Create a new companion place",
                 "parameters": [
                     {
-                        "description": "Companion Place Data",
+                        "description": "City create",
                         "name": "data",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.CompanionsRouteCreate"
+                            "$ref": "#/definitions/models.CityBase"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successfully created companion place with id"
+                        "description": "Successfully created city with id",
+                        "schema": {
+                            "type": "integer"
+                        }
                     },
                     "400": {
-                        "description": "Invalid input data",
+                        "description": "Invalid input",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -162,8 +185,8 @@ const docTemplate = `{
                 }
             }
         },
-        "/companions/get_by_place": {
-            "put": {
+        "/city/get_all": {
+            "get": {
                 "consumes": [
                     "application/json"
                 ],
@@ -171,36 +194,15 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "companions"
-                ],
-                "summary": "get places companions by filters",
-                "parameters": [
-                    {
-                        "description": "user id",
-                        "name": "data",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.CompanionsFilters"
-                        }
-                    }
+                    "city"
                 ],
                 "responses": {
                     "200": {
-                        "description": "success",
+                        "description": "Successfully",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/models.CompanionsPlace"
-                            }
-                        }
-                    },
-                    "400": {
-                        "description": "Invalid input data",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
+                                "$ref": "#/definitions/models.City"
                             }
                         }
                     },
@@ -216,7 +218,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/companions/get_by_route": {
+        "/city/update": {
             "put": {
                 "consumes": [
                     "application/json"
@@ -225,32 +227,34 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "companions"
+                    "city"
                 ],
-                "summary": "get places companions by filters",
                 "parameters": [
                     {
-                        "description": "user id",
+                        "description": "City update",
                         "name": "data",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.CompanionsFilters"
+                            "$ref": "#/definitions/models.City"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "success",
+                        "description": "Successfully"
+                    },
+                    "400": {
+                        "description": "Invalid input",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.CompanionsPlace"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
                             }
                         }
                     },
-                    "400": {
-                        "description": "Invalid input data",
+                    "404": {
+                        "description": "City not found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -270,8 +274,8 @@ const docTemplate = `{
                 }
             }
         },
-        "/companions/place": {
-            "delete": {
+        "/companions/by_user": {
+            "get": {
                 "consumes": [
                     "application/json"
                 ],
@@ -285,15 +289,30 @@ const docTemplate = `{
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "companion table id",
+                        "description": "user id",
                         "name": "id",
                         "in": "query",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Response language, takes precedence over Accept-Language",
+                        "name": "lang",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Preferred response languages",
+                        "name": "Accept-Language",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "success"
+                        "description": "success",
+                        "schema": {
+                            "$ref": "#/definitions/swagger.Companion"
+                        }
                     },
                     "400": {
                         "description": "Invalid input data",
@@ -316,8 +335,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/companions/route": {
-            "delete": {
+        "/companions/create_place_companion": {
+            "post": {
+                "description": "Adds a new companion place to the database",
                 "consumes": [
                     "application/json"
                 ],
@@ -327,19 +347,21 @@ const docTemplate = `{
                 "tags": [
                     "companions"
                 ],
-                "summary": "get companion data by user id",
+                "summary": "Create a new companion place",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "companion table id",
-                        "name": "id",
-                        "in": "query",
-                        "required": true
+                        "description": "Companion Place Data",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CompanionsPlaceCreate"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "success"
+                        "description": "Successfully created companion place with id"
                     },
                     "400": {
                         "description": "Invalid input data",
@@ -362,8 +384,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/district/by_city_id": {
-            "get": {
+        "/companions/create_route_companion": {
+            "post": {
+                "description": "Adds a new companion place to the database",
                 "consumes": [
                     "application/json"
                 ],
@@ -371,29 +394,26 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "district"
+                    "companions"
                 ],
+                "summary": "Create a new companion place",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "City id",
-                        "name": "id",
-                        "in": "query",
-                        "required": true
+                        "description": "Companion Place Data",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CompanionsRouteCreate"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successfully",
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.District"
-                            }
-                        }
+                        "description": "Successfully created companion place with id"
                     },
                     "400": {
-                        "description": "Invalid input",
+                        "description": "Invalid input data",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -413,8 +433,8 @@ const docTemplate = `{
                 }
             }
         },
-        "/favourite/by_user_id": {
-            "get": {
+        "/companions/get_by_place": {
+            "put": {
                 "consumes": [
                     "application/json"
                 ],
@@ -422,26 +442,41 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "favourite"
+                    "companions"
                 ],
+                "summary": "get places companions by filters",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "UserID",
-                        "name": "id",
-                        "in": "query",
-                        "required": true
+                        "description": "filters with cursor",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CompanionsFilters"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Response language, takes precedence over Accept-Language",
+                        "name": "lang",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Preferred response languages",
+                        "name": "Accept-Language",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successfully!",
+                        "description": "success",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/models.Page-models_CompanionsPlace"
                         }
                     },
                     "400": {
-                        "description": "Invalid input",
+                        "description": "Invalid input data",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -461,8 +496,8 @@ const docTemplate = `{
                 }
             }
         },
-        "/favourite/like_place": {
-            "post": {
+        "/companions/get_by_route": {
+            "put": {
                 "consumes": [
                     "application/json"
                 ],
@@ -470,28 +505,41 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "favourite"
+                    "companions"
                 ],
+                "summary": "get places companions by filters",
                 "parameters": [
                     {
-                        "description": "Like",
-                        "name": "like",
+                        "description": "filters with cursor",
+                        "name": "data",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.Like"
+                            "$ref": "#/definitions/models.CompanionsFilters"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Response language, takes precedence over Accept-Language",
+                        "name": "lang",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Preferred response languages",
+                        "name": "Accept-Language",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successfully!",
+                        "description": "success",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/models.Page-models_CompanionsRoute"
                         }
                     },
                     "400": {
-                        "description": "Invalid input",
+                        "description": "Invalid input data",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -509,7 +557,9 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/companions/place": {
             "delete": {
                 "consumes": [
                     "application/json"
@@ -518,28 +568,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "favourite"
+                    "companions"
                 ],
+                "summary": "get companion data by user id",
                 "parameters": [
                     {
-                        "description": "delete data",
-                        "name": "delete",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.Like"
-                        }
+                        "type": "integer",
+                        "description": "companion table id",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successfully!",
-                        "schema": {
-                            "type": "string"
-                        }
+                        "description": "success"
                     },
                     "400": {
-                        "description": "Invalid input",
+                        "description": "Invalid input data",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -559,7 +605,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/favourite/like_route": {
+        "/companions/request": {
             "post": {
                 "consumes": [
                     "application/json"
@@ -568,24 +614,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "favourite"
+                    "companions"
                 ],
                 "parameters": [
                     {
-                        "description": "Like",
-                        "name": "like",
+                        "description": "Request",
+                        "name": "data",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.Like"
+                            "$ref": "#/definitions/models.CompanionRequestCreate"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successfully!",
+                        "description": "Successfully created request with id",
                         "schema": {
-                            "type": "string"
+                            "type": "integer"
                         }
                     },
                     "400": {
@@ -597,6 +643,24 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "404": {
+                        "description": "Listing not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Active request already exists",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -607,8 +671,10 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/companions/request/accept": {
+            "put": {
                 "consumes": [
                     "application/json"
                 ],
@@ -616,25 +682,22 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "favourite"
+                    "companions"
                 ],
                 "parameters": [
                     {
-                        "description": "delete data",
-                        "name": "delete",
+                        "description": "Request id and listing owner",
+                        "name": "data",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.Like"
+                            "$ref": "#/definitions/models.CompanionRequestAction"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successfully!",
-                        "schema": {
-                            "type": "string"
-                        }
+                        "description": "OK"
                     },
                     "400": {
                         "description": "Invalid input",
@@ -645,6 +708,33 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "403": {
+                        "description": "User is not the listing owner",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Request not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Request status does not allow the action",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -657,8 +747,8 @@ const docTemplate = `{
                 }
             }
         },
-        "/note/by_id": {
-            "get": {
+        "/companions/request/cancel": {
+            "put": {
                 "consumes": [
                     "application/json"
                 ],
@@ -666,23 +756,22 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "note"
+                    "companions"
                 ],
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Note id",
-                        "name": "id",
-                        "in": "query",
-                        "required": true
+                        "description": "Request id and request author",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CompanionRequestAction"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successfully",
-                        "schema": {
-                            "$ref": "#/definitions/models.Note"
-                        }
+                        "description": "OK"
                     },
                     "400": {
                         "description": "Invalid input",
@@ -693,6 +782,33 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "403": {
+                        "description": "User is not the request author",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Request not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Request status does not allow the action",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -705,8 +821,8 @@ const docTemplate = `{
                 }
             }
         },
-        "/note/by_user_and_place_ids": {
-            "get": {
+        "/companions/request/decline": {
+            "put": {
                 "consumes": [
                     "application/json"
                 ],
@@ -714,30 +830,22 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "note"
+                    "companions"
                 ],
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "user_id",
-                        "name": "user_id",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "place_id",
-                        "name": "place_id",
-                        "in": "query",
-                        "required": true
+                        "description": "Request id and listing owner",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CompanionRequestAction"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successfully",
-                        "schema": {
-                            "$ref": "#/definitions/models.Note"
-                        }
+                        "description": "OK"
                     },
                     "400": {
                         "description": "Invalid input",
@@ -748,6 +856,33 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "403": {
+                        "description": "User is not the listing owner",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Request not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Request status does not allow the action",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -760,7 +895,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/note/by_user_id": {
+        "/companions/requests/incoming": {
             "get": {
                 "consumes": [
                     "application/json"
@@ -769,25 +904,59 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "note"
+                    "companions"
                 ],
                 "parameters": [
+                    {
+                        "type": "string",
+                        "name": "cursor",
+                        "in": "query"
+                    },
                     {
                         "type": "integer",
-                        "description": "User id",
-                        "name": "id",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "pending",
+                            "accepted",
+                            "declined",
+                            "cancelled"
+                        ],
+                        "type": "string",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "name": "user_id",
                         "in": "query",
                         "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "name": "with_total",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Response language, takes precedence over Accept-Language",
+                        "name": "lang",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Preferred response languages",
+                        "name": "Accept-Language",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "Successfully",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Note"
-                            }
+                            "$ref": "#/definitions/models.Page-models_CompanionRequest"
                         }
                     },
                     "400": {
@@ -811,8 +980,8 @@ const docTemplate = `{
                 }
             }
         },
-        "/note/create": {
-            "post": {
+        "/companions/requests/outgoing": {
+            "get": {
                 "consumes": [
                     "application/json"
                 ],
@@ -820,24 +989,59 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "note"
+                    "companions"
                 ],
                 "parameters": [
                     {
-                        "description": "Note",
-                        "name": "data",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.NoteCreate"
-                        }
+                        "type": "string",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "pending",
+                            "accepted",
+                            "declined",
+                            "cancelled"
+                        ],
+                        "type": "string",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "name": "user_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "name": "with_total",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Response language, takes precedence over Accept-Language",
+                        "name": "lang",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Preferred response languages",
+                        "name": "Accept-Language",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successfully created note with id",
+                        "description": "Successfully",
                         "schema": {
-                            "type": "integer"
+                            "$ref": "#/definitions/models.Page-models_CompanionRequest"
                         }
                     },
                     "400": {
@@ -861,8 +1065,8 @@ const docTemplate = `{
                 }
             }
         },
-        "/note/update": {
-            "put": {
+        "/companions/route": {
+            "delete": {
                 "consumes": [
                     "application/json"
                 ],
@@ -870,22 +1074,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "note"
+                    "companions"
                 ],
+                "summary": "get companion data by user id",
                 "parameters": [
                     {
-                        "description": "Note id",
-                        "name": "data",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.NoteCreate"
-                        }
+                        "type": "integer",
+                        "description": "companion table id",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
                     }
                 ],
                 "responses": {
+                    "200": {
+                        "description": "success"
+                    },
                     "400": {
-                        "description": "Invalid input",
+                        "description": "Invalid input data",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -905,8 +1111,8 @@ const docTemplate = `{
                 }
             }
         },
-        "/place/by_id": {
-            "get": {
+        "/district": {
+            "delete": {
                 "consumes": [
                     "application/json"
                 ],
@@ -914,12 +1120,12 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "place"
+                    "district"
                 ],
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Place id",
+                        "description": "District id",
                         "name": "id",
                         "in": "query",
                         "required": true
@@ -927,10 +1133,7 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successfully",
-                        "schema": {
-                            "$ref": "#/definitions/models.Place"
-                        }
+                        "description": "Successfully"
                     },
                     "400": {
                         "description": "Invalid input",
@@ -941,49 +1144,17 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "500": {
-                        "description": "Internal server error",
+                    "404": {
+                        "description": "District not found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
                                 "type": "string"
                             }
                         }
-                    }
-                }
-            }
-        },
-        "/place/create": {
-            "post": {
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "place"
-                ],
-                "parameters": [
-                    {
-                        "description": "Place with tag ids create",
-                        "name": "data",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.PlaceCreate"
-                        }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "Successfully created place with id",
-                        "schema": {
-                            "type": "integer"
-                        }
                     },
-                    "400": {
-                        "description": "Invalid input",
+                    "409": {
+                        "description": "District has places",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1003,8 +1174,8 @@ const docTemplate = `{
                 }
             }
         },
-        "/place/get_all_with_filter": {
-            "put": {
+        "/district/by_city_id": {
+            "get": {
                 "consumes": [
                     "application/json"
                 ],
@@ -1012,17 +1183,15 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "place"
+                    "district"
                 ],
                 "parameters": [
                     {
-                        "description": "Filters",
-                        "name": "data",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/swagger.Filters"
-                        }
+                        "type": "integer",
+                        "description": "City id",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
                     }
                 ],
                 "responses": {
@@ -1031,7 +1200,7 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/models.Place"
+                                "$ref": "#/definitions/models.District"
                             }
                         }
                     },
@@ -1056,7 +1225,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/review/author": {
+        "/district/by_id": {
             "get": {
                 "consumes": [
                     "application/json"
@@ -1065,12 +1234,12 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "review"
+                    "district"
                 ],
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "author id",
+                        "description": "District id",
                         "name": "id",
                         "in": "query",
                         "required": true
@@ -1080,7 +1249,7 @@ const docTemplate = `{
                     "200": {
                         "description": "Successfully",
                         "schema": {
-                            "$ref": "#/definitions/swagger.GetByAuthor"
+                            "$ref": "#/definitions/models.District"
                         }
                     },
                     "400": {
@@ -1092,6 +1261,15 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "404": {
+                        "description": "District not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -1104,7 +1282,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/review/create_on_place": {
+        "/district/create": {
             "post": {
                 "consumes": [
                     "application/json"
@@ -1113,22 +1291,22 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "review"
+                    "district"
                 ],
                 "parameters": [
                     {
-                        "description": "place review create",
+                        "description": "District create",
                         "name": "data",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.PlaceReviewCreate"
+                            "$ref": "#/definitions/models.DistrictBase"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successfully created route review with id",
+                        "description": "Successfully created district with id",
                         "schema": {
                             "type": "integer"
                         }
@@ -1142,6 +1320,15 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "404": {
+                        "description": "City not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -1154,33 +1341,27 @@ const docTemplate = `{
                 }
             }
         },
-        "/review/create_on_route": {
-            "post": {
-                "consumes": [
-                    "application/json"
-                ],
+        "/district/polygons": {
+            "get": {
                 "produces": [
-                    "application/json"
+                    "application/geo+json"
                 ],
                 "tags": [
-                    "review"
+                    "district"
                 ],
                 "parameters": [
                     {
-                        "description": "Route review create",
-                        "name": "data",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.RouteReviewCreate"
-                        }
+                        "type": "integer",
+                        "description": "City id, all cities if omitted",
+                        "name": "city_id",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successfully created route review with id",
+                        "description": "Successfully",
                         "schema": {
-                            "type": "integer"
+                            "$ref": "#/definitions/geojson.FeatureCollection"
                         }
                     },
                     "400": {
@@ -1204,34 +1385,33 @@ const docTemplate = `{
                 }
             }
         },
-        "/review/place": {
-            "get": {
-                "consumes": [
-                    "application/json"
-                ],
+        "/district/reconcile": {
+            "post": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "review"
+                    "district"
                 ],
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "place id",
-                        "name": "id",
-                        "in": "query",
-                        "required": true
+                        "description": "City id, all cities if omitted",
+                        "name": "city_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Move places into the district computed from coordinates",
+                        "name": "apply",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "Successfully",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.PlaceReview"
-                            }
+                            "$ref": "#/definitions/models.DistrictReconciliation"
                         }
                     },
                     "400": {
@@ -1255,8 +1435,8 @@ const docTemplate = `{
                 }
             }
         },
-        "/review/route": {
-            "get": {
+        "/district/update": {
+            "put": {
                 "consumes": [
                     "application/json"
                 ],
@@ -1264,29 +1444,34 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "review"
+                    "district"
                 ],
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "route id",
-                        "name": "id",
-                        "in": "query",
-                        "required": true
+                        "description": "District update",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.DistrictUpdate"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successfully",
+                        "description": "Successfully"
+                    },
+                    "400": {
+                        "description": "Invalid input",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.RouteReview"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
                             }
                         }
                     },
-                    "400": {
-                        "description": "Invalid input",
+                    "404": {
+                        "description": "District not found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1306,8 +1491,8 @@ const docTemplate = `{
                 }
             }
         },
-        "/review/update_on_place": {
-            "put": {
+        "/favourite/by_user_id": {
+            "get": {
                 "consumes": [
                     "application/json"
                 ],
@@ -1315,22 +1500,32 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "review"
+                    "favourite"
                 ],
                 "parameters": [
                     {
-                        "description": "place review id",
-                        "name": "review",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.ReviewUpdate"
-                        }
+                        "type": "integer",
+                        "description": "UserID",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Response language, takes precedence over Accept-Language",
+                        "name": "lang",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Preferred response languages",
+                        "name": "Accept-Language",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successfully",
+                        "description": "Successfully!",
                         "schema": {
                             "type": "string"
                         }
@@ -1356,8 +1551,8 @@ const docTemplate = `{
                 }
             }
         },
-        "/review/update_on_route": {
-            "put": {
+        "/favourite/like_place": {
+            "post": {
                 "consumes": [
                     "application/json"
                 ],
@@ -1365,22 +1560,22 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "review"
+                    "favourite"
                 ],
                 "parameters": [
                     {
-                        "description": "route review id",
-                        "name": "review",
+                        "description": "Like",
+                        "name": "like",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.ReviewUpdate"
+                            "$ref": "#/definitions/models.Like"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successfully",
+                        "description": "Successfully!",
                         "schema": {
                             "type": "string"
                         }
@@ -1404,10 +1599,8 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/route/by_id": {
-            "get": {
+            },
+            "delete": {
                 "consumes": [
                     "application/json"
                 ],
@@ -1415,22 +1608,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "route"
+                    "favourite"
                 ],
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "id",
-                        "name": "id",
-                        "in": "query",
-                        "required": true
+                        "description": "delete data",
+                        "name": "delete",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Like"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successfully",
+                        "description": "Successfully!",
                         "schema": {
-                            "$ref": "#/definitions/models.Route"
+                            "type": "string"
                         }
                     },
                     "400": {
@@ -1454,8 +1649,8 @@ const docTemplate = `{
                 }
             }
         },
-        "/route/by_page": {
-            "get": {
+        "/favourite/like_route": {
+            "post": {
                 "consumes": [
                     "application/json"
                 ],
@@ -1463,25 +1658,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "route"
+                    "favourite"
                 ],
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "id",
-                        "name": "page",
-                        "in": "query",
-                        "required": true
+                        "description": "Like",
+                        "name": "like",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Like"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successfully",
+                        "description": "Successfully!",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Route"
-                            }
+                            "type": "string"
                         }
                     },
                     "400": {
@@ -1503,10 +1697,8 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/route/create": {
-            "post": {
+            },
+            "delete": {
                 "consumes": [
                     "application/json"
                 ],
@@ -1514,24 +1706,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "route"
+                    "favourite"
                 ],
                 "parameters": [
                     {
-                        "description": "Route with tag ids and place ids create",
-                        "name": "data",
+                        "description": "delete data",
+                        "name": "delete",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.RouteCreate"
+                            "$ref": "#/definitions/models.Like"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successfully created route with id",
+                        "description": "Successfully!",
                         "schema": {
-                            "type": "integer"
+                            "type": "string"
                         }
                     },
                     "400": {
@@ -1555,7 +1747,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/tag/create": {
+        "/media/attach": {
             "post": {
                 "consumes": [
                     "application/json"
@@ -1564,25 +1756,22 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "tag"
+                    "media"
                 ],
                 "parameters": [
                     {
-                        "description": "Tag create",
+                        "description": "Attachment",
                         "name": "data",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.TagCreate"
+                            "$ref": "#/definitions/models.MediaAttachment"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successfully created tag with id",
-                        "schema": {
-                            "type": "integer"
-                        }
+                        "description": "success"
                     },
                     "400": {
                         "description": "Invalid input",
@@ -1593,6 +1782,15 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "403": {
+                        "description": "User is not owner",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -1603,10 +1801,8 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/tag/get_all": {
-            "get": {
+            },
+            "delete": {
                 "consumes": [
                     "application/json"
                 ],
@@ -1614,15 +1810,38 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "tag"
+                    "media"
+                ],
+                "parameters": [
+                    {
+                        "description": "Attachment, position is ignored",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.MediaAttachment"
+                        }
+                    }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successfully created tag with id",
+                        "description": "success"
+                    },
+                    "400": {
+                        "description": "Invalid input",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Tag"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "User is not owner",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
                             }
                         }
                     },
@@ -1638,8 +1857,8 @@ const docTemplate = `{
                 }
             }
         },
-        "/trip/by_id": {
-            "get": {
+        "/media/attach/position": {
+            "put": {
                 "consumes": [
                     "application/json"
                 ],
@@ -1647,23 +1866,22 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "trip"
+                    "media"
                 ],
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "trip id",
-                        "name": "id",
-                        "in": "query",
-                        "required": true
+                        "description": "Attachment with new position",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.MediaAttachment"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successfully created trip with id",
-                        "schema": {
-                            "$ref": "#/definitions/models.Trip"
-                        }
+                        "description": "success"
                     },
                     "400": {
                         "description": "Invalid input",
@@ -1674,6 +1892,15 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "403": {
+                        "description": "User is not owner",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -1686,33 +1913,37 @@ const docTemplate = `{
                 }
             }
         },
-        "/trip/by_user_id": {
+        "/media/by_entity": {
             "get": {
-                "consumes": [
-                    "application/json"
-                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "trip"
+                    "media"
                 ],
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "place, route, place_review, route_review or note",
+                        "name": "entity_type",
+                        "in": "query",
+                        "required": true
+                    },
                     {
                         "type": "integer",
-                        "description": "user id",
-                        "name": "id",
+                        "description": "Entity id",
+                        "name": "entity_id",
                         "in": "query",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successfully created trip with id",
+                        "description": "Successfully",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/models.Trip"
+                                "$ref": "#/definitions/models.AttachedMedia"
                             }
                         }
                     },
@@ -1737,33 +1968,28 @@ const docTemplate = `{
                 }
             }
         },
-        "/trip/create": {
-            "post": {
-                "consumes": [
-                    "application/json"
-                ],
+        "/media/by_id": {
+            "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "trip"
+                    "media"
                 ],
                 "parameters": [
                     {
-                        "description": "trip",
-                        "name": "data",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.TripCreate"
-                        }
+                        "type": "integer",
+                        "description": "Media id",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successfully created trip with id",
+                        "description": "Successfully",
                         "schema": {
-                            "type": "integer"
+                            "$ref": "#/definitions/models.Media"
                         }
                     },
                     "400": {
@@ -1787,45 +2013,32 @@ const docTemplate = `{
                 }
             }
         },
-        "/trip/place": {
-            "delete": {
-                "consumes": [
-                    "application/json"
-                ],
+        "/media/file/{key}": {
+            "get": {
                 "produces": [
-                    "application/json"
+                    "application/octet-stream"
                 ],
                 "tags": [
-                    "trip"
+                    "media"
                 ],
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "trip id",
-                        "name": "trip_id",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "place id",
-                        "name": "place_id",
-                        "in": "query",
+                        "type": "string",
+                        "description": "Storage key",
+                        "name": "key",
+                        "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "400": {
-                        "description": "Invalid input",
+                    "200": {
+                        "description": "File",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "type": "file"
                         }
                     },
-                    "500": {
-                        "description": "Internal server error",
+                    "404": {
+                        "description": "Not found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1836,29 +2049,40 @@ const docTemplate = `{
                 }
             }
         },
-        "/trip/place/add": {
-            "put": {
+        "/media/upload": {
+            "post": {
                 "consumes": [
-                    "application/json"
+                    "multipart/form-data"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "trip"
+                    "media"
                 ],
                 "parameters": [
                     {
-                        "description": "data",
-                        "name": "data",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/swagger.TripAdd"
-                        }
+                        "type": "file",
+                        "description": "Image",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Owner user id",
+                        "name": "user_id",
+                        "in": "formData",
+                        "required": true
                     }
                 ],
                 "responses": {
+                    "200": {
+                        "description": "Successfully uploaded",
+                        "schema": {
+                            "$ref": "#/definitions/models.Media"
+                        }
+                    },
                     "400": {
                         "description": "Invalid input",
                         "schema": {
@@ -1868,6 +2092,24 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "413": {
+                        "description": "File is too large",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported file type",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -1880,8 +2122,8 @@ const docTemplate = `{
                 }
             }
         },
-        "/trip/place/change/day": {
-            "put": {
+        "/moderation/content": {
+            "get": {
                 "consumes": [
                     "application/json"
                 ],
@@ -1889,20 +2131,42 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "trip"
+                    "moderation"
                 ],
                 "parameters": [
                     {
-                        "description": "data",
-                        "name": "data",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/swagger.TripChangeDay"
-                        }
+                        "type": "integer",
+                        "description": "Moderator id",
+                        "name": "moderator_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "next_cursor from the previous page, empty for the first page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size, capped by MAX_PAGE_SIZE",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Count total number of reports",
+                        "name": "with_total",
+                        "in": "query"
                     }
                 ],
                 "responses": {
+                    "200": {
+                        "description": "Successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.Page-models_ContentReport"
+                        }
+                    },
                     "400": {
                         "description": "Invalid input",
                         "schema": {
@@ -1912,6 +2176,15 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "403": {
+                        "description": "User is not a moderator",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -1924,7 +2197,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/trip/place/change/position": {
+        "/moderation/content/resolve": {
             "put": {
                 "consumes": [
                     "application/json"
@@ -1933,20 +2206,26 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "trip"
+                    "moderation"
                 ],
                 "parameters": [
                     {
-                        "description": "data",
+                        "description": "Report",
                         "name": "data",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/swagger.TripChangePosition"
+                            "$ref": "#/definitions/models.ContentReportResolve"
                         }
                     }
                 ],
                 "responses": {
+                    "200": {
+                        "description": "Successfully",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "400": {
                         "description": "Invalid input",
                         "schema": {
@@ -1956,48 +2235,17 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "500": {
-                        "description": "Internal server error",
+                    "403": {
+                        "description": "User is not a moderator",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
                                 "type": "string"
                             }
                         }
-                    }
-                }
-            }
-        },
-        "/trip/route": {
-            "delete": {
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "trip"
-                ],
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "trip id",
-                        "name": "trip_id",
-                        "in": "query",
-                        "required": true
                     },
-                    {
-                        "type": "integer",
-                        "description": "route id",
-                        "name": "route_id",
-                        "in": "query",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "400": {
-                        "description": "Invalid input",
+                    "404": {
+                        "description": "Open report not found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -2017,7 +2265,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/trip/route/add": {
+        "/note": {
             "put": {
                 "consumes": [
                     "application/json"
@@ -2026,20 +2274,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "trip"
+                    "note"
                 ],
                 "parameters": [
                     {
-                        "description": "data",
+                        "description": "Note",
                         "name": "data",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/swagger.TripAdd"
+                            "$ref": "#/definitions/models.NoteUpdate"
                         }
                     }
                 ],
                 "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
                     "400": {
                         "description": "Invalid input",
                         "schema": {
@@ -2049,41 +2300,72 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "500": {
-                        "description": "Internal server error",
+                    "403": {
+                        "description": "Note belongs to another user",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
                                 "type": "string"
                             }
                         }
-                    }
-                }
-            }
-        },
-        "/trip/route/change/day": {
-            "put": {
-                "consumes": [
-                    "application/json"
-                ],
+                    },
+                    "404": {
+                        "description": "Note not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "Text is rejected by the content filter",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "trip"
+                    "note"
                 ],
                 "parameters": [
                     {
-                        "description": "data",
+                        "description": "Note",
                         "name": "data",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/swagger.TripChangeDay"
+                            "$ref": "#/definitions/models.NoteTargetCreate"
                         }
                     }
                 ],
                 "responses": {
+                    "200": {
+                        "description": "Successfully created note with id",
+                        "schema": {
+                            "type": "integer"
+                        }
+                    },
                     "400": {
                         "description": "Invalid input",
                         "schema": {
@@ -2093,6 +2375,33 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "403": {
+                        "description": "Trip belongs to another user",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Target not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "Text is rejected by the content filter",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -2103,10 +2412,8 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/trip/route/change/position": {
-            "put": {
+            },
+            "delete": {
                 "consumes": [
                     "application/json"
                 ],
@@ -2114,20 +2421,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "trip"
+                    "note"
                 ],
                 "parameters": [
                     {
-                        "description": "data",
-                        "name": "data",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/swagger.TripChangePosition"
-                        }
+                        "type": "integer",
+                        "name": "id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "name": "user_id",
+                        "in": "query"
                     }
                 ],
                 "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
                     "400": {
                         "description": "Invalid input",
                         "schema": {
@@ -2137,6 +2448,24 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "403": {
+                        "description": "Note belongs to another user",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Note not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -2149,8 +2478,8 @@ const docTemplate = `{
                 }
             }
         },
-        "/user/check_in": {
-            "post": {
+        "/note/by_id": {
+            "get": {
                 "consumes": [
                     "application/json"
                 ],
@@ -2158,29 +2487,29 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "user"
+                    "note"
                 ],
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Cipher",
-                        "name": "cipher",
+                        "type": "integer",
+                        "description": "Note id",
+                        "name": "id",
                         "in": "query",
                         "required": true
                     },
                     {
                         "type": "integer",
-                        "description": "UserID",
-                        "name": "user_id",
+                        "description": "Who is looking, notes of others are filtered by visibility",
+                        "name": "viewer_id",
                         "in": "query",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Just valid hash",
+                        "description": "Successfully",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/models.Note"
                         }
                     },
                     "400": {
@@ -2192,6 +2521,15 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "404": {
+                        "description": "Note not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -2204,7 +2542,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/user/checked_in": {
+        "/note/by_target": {
             "get": {
                 "consumes": [
                     "application/json"
@@ -2213,24 +2551,49 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "user"
+                    "note"
                 ],
                 "parameters": [
                     {
+                        "type": "integer",
+                        "name": "day",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "name": "target_id",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "place",
+                            "route",
+                            "trip",
+                            "trip_day"
+                        ],
                         "type": "string",
-                        "description": "userID",
-                        "name": "id",
+                        "name": "target_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "name": "user_id",
                         "in": "query",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "name": "viewer_id",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "places",
+                        "description": "Successfully",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/models.Place"
+                                "$ref": "#/definitions/models.Note"
                             }
                         }
                     },
@@ -2255,7 +2618,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/user/chrono": {
+        "/note/by_user_and_place_ids": {
             "get": {
                 "consumes": [
                     "application/json"
@@ -2264,22 +2627,35 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "user"
+                    "note"
                 ],
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "userID",
-                        "name": "id",
+                        "type": "integer",
+                        "description": "user_id",
+                        "name": "user_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "place_id",
+                        "name": "place_id",
                         "in": "query",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Who is looking, notes of others are filtered by visibility, without it only public notes are returned",
+                        "name": "viewer_id",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "user properties json",
+                        "description": "Successfully",
                         "schema": {
-                            "$ref": "#/definitions/models.Chrono"
+                            "$ref": "#/definitions/models.Note"
                         }
                     },
                     "400": {
@@ -2303,7 +2679,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/user/current_route": {
+        "/note/by_user_id": {
             "get": {
                 "consumes": [
                     "application/json"
@@ -2312,22 +2688,31 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "user"
+                    "note"
                 ],
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "userID",
+                        "type": "integer",
+                        "description": "User id",
                         "name": "id",
                         "in": "query",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Who is looking, notes of others are filtered by visibility, without it only public notes are returned",
+                        "name": "viewer_id",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "user properties json",
+                        "description": "Successfully",
                         "schema": {
-                            "$ref": "#/definitions/models.RouteDisplay"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Note"
+                            }
                         }
                     },
                     "400": {
@@ -2351,8 +2736,8 @@ const docTemplate = `{
                 }
             }
         },
-        "/user/login": {
-            "put": {
+        "/note/create": {
+            "post": {
                 "consumes": [
                     "application/json"
                 ],
@@ -2360,22 +2745,22 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "user"
+                    "note"
                 ],
                 "parameters": [
                     {
-                        "description": "login data",
+                        "description": "Note",
                         "name": "data",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/swagger.User"
+                            "$ref": "#/definitions/models.NoteCreate"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "user ID",
+                        "description": "Successfully created note with id",
                         "schema": {
                             "type": "integer"
                         }
@@ -2389,6 +2774,15 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "422": {
+                        "description": "Text is rejected by the content filter",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -2401,7 +2795,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/user/place_check_in_flag": {
+        "/note/feed": {
             "get": {
                 "consumes": [
                     "application/json"
@@ -2410,29 +2804,46 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "user"
+                    "note"
                 ],
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "place_id",
-                        "name": "place_id",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "name": "target_id",
                         "in": "query",
                         "required": true
                     },
                     {
-                        "type": "integer",
-                        "description": "UserID",
-                        "name": "user_id",
+                        "enum": [
+                            "place",
+                            "route"
+                        ],
+                        "type": "string",
+                        "name": "target_type",
                         "in": "query",
                         "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "name": "with_total",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Just valid hash",
+                        "description": "Successfully",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/models.Page-models_Note"
                         }
                     },
                     "400": {
@@ -2456,8 +2867,8 @@ const docTemplate = `{
                 }
             }
         },
-        "/user/properties": {
-            "get": {
+        "/note/share": {
+            "post": {
                 "consumes": [
                     "application/json"
                 ],
@@ -2465,22 +2876,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "user"
+                    "note"
                 ],
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "userID",
-                        "name": "id",
-                        "in": "query",
-                        "required": true
+                        "description": "Note",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.NoteShare"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "user properties json",
+                        "description": "Share token",
                         "schema": {
-                            "$ref": "#/definitions/swagger.UserMe"
+                            "type": "string"
                         }
                     },
                     "400": {
@@ -2492,45 +2905,127 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "500": {
-                        "description": "Internal server error",
+                    "403": {
+                        "description": "Note belongs to another user",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
                                 "type": "string"
                             }
                         }
-                    }
-                }
-            }
-        },
-        "/user/register": {
-            "put": {
-                "consumes": [
-                    "application/json"
+                    },
+                    "404": {
+                        "description": "Note not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Note is not link or public",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "consumes": [
+                    "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "user"
+                    "note"
                 ],
                 "parameters": [
                     {
-                        "description": "create user data",
-                        "name": "data",
-                        "in": "body",
-                        "required": true,
+                        "type": "string",
+                        "name": "token",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "name": "user_id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Invalid input",
                         "schema": {
-                            "$ref": "#/definitions/models.UserCreate"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Token not found or already revoked",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     }
+                }
+            }
+        },
+        "/note/shared": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "note"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Share token",
+                        "name": "token",
+                        "in": "query",
+                        "required": true
+                    }
                 ],
                 "responses": {
                     "200": {
-                        "description": "user ID",
+                        "description": "Successfully",
                         "schema": {
-                            "type": "integer"
+                            "$ref": "#/definitions/models.Note"
                         }
                     },
                     "400": {
@@ -2542,6 +3037,15 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "404": {
+                        "description": "Token not found or revoked",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -2554,7 +3058,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/user/route_check_in_flag": {
+        "/note/shares": {
             "get": {
                 "consumes": [
                     "application/json"
@@ -2563,19 +3067,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "user"
+                    "note"
                 ],
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "route_id",
-                        "name": "route_id",
+                        "type": "integer",
+                        "name": "id",
                         "in": "query",
                         "required": true
                     },
                     {
                         "type": "integer",
-                        "description": "UserID",
                         "name": "user_id",
                         "in": "query",
                         "required": true
@@ -2583,9 +3085,12 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Just valid hash",
+                        "description": "Successfully",
                         "schema": {
-                            "type": "string"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.NoteShareToken"
+                            }
                         }
                     },
                     "400": {
@@ -2597,6 +3102,24 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "403": {
+                        "description": "Note belongs to another user",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Note not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -2609,7 +3132,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/user/update_properties": {
+        "/note/update": {
             "put": {
                 "consumes": [
                     "application/json"
@@ -2618,28 +3141,31 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "user"
+                    "note"
                 ],
                 "parameters": [
                     {
-                        "description": "user data",
+                        "description": "Note id",
                         "name": "data",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/swagger.UserUpdate"
+                            "$ref": "#/definitions/models.NoteCreate"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "user properties json",
+                    "400": {
+                        "description": "Invalid input",
                         "schema": {
-                            "type": "string"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
-                    "400": {
-                        "description": "Invalid input",
+                    "422": {
+                        "description": "Text is rejected by the content filter",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -2659,8 +3185,8 @@ const docTemplate = `{
                 }
             }
         },
-        "/user/validate": {
-            "post": {
+        "/notification": {
+            "get": {
                 "consumes": [
                     "application/json"
                 ],
@@ -2668,22 +3194,46 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "user"
+                    "notification"
                 ],
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "hash",
-                        "name": "hash",
+                        "type": "integer",
+                        "description": "User id",
+                        "name": "user_id",
                         "in": "query",
                         "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only unread notifications",
+                        "name": "unread_only",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next_cursor from the previous page, empty for the first page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size, capped by MAX_PAGE_SIZE",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Count total number of notifications",
+                        "name": "with_total",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Just bool",
+                        "description": "Successfully",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/models.Page-models_Notification"
                         }
                     },
                     "400": {
@@ -2706,328 +3256,6889 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "models.Chrono": {
-            "type": "object",
-            "properties": {
-                "check_ins": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.ChronoEntity"
-                    }
-                },
-                "liked_places": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.ChronoEntity"
-                    }
-                },
-                "liked_routes": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.ChronoEntity"
-                    }
-                },
-                "place_reviews": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.ChronoEntity"
-                    }
-                },
-                "route_logs": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.ChronoEntity"
+        },
+        "/notification/read": {
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notification"
+                ],
+                "parameters": [
+                    {
+                        "description": "Notifications",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.NotificationsRead"
+                        }
                     }
-                },
-                "route_reviews": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.ChronoEntity"
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Number of notifications marked as read",
+                        "schema": {
+                            "type": "integer"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
                     }
                 }
             }
         },
-        "models.ChronoEntity": {
-            "type": "object",
-            "properties": {
+        "/place": {
+            "delete": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "place"
+                ],
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Place id",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "success"
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Place not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Place has history",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/place/archive": {
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "place"
+                ],
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Place id",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Archived flag",
+                        "name": "archived",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "success"
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Place not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/place/by_id": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "place"
+                ],
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Place id",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Response language, takes precedence over Accept-Language",
+                        "name": "lang",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Preferred response languages",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.Place"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/place/create": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "place"
+                ],
+                "parameters": [
+                    {
+                        "description": "Place with tag ids create",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.PlaceCreate"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully created place with id",
+                        "schema": {
+                            "type": "integer"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/place/duplicates": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "place"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Cursor from previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Count total number of pairs",
+                        "name": "with_total",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.Page-models_PlaceDuplicate"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/place/duplicates/detect": {
+            "post": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "place"
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Number of found pairs",
+                        "schema": {
+                            "type": "integer"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/place/duplicates/dismiss": {
+            "put": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "place"
+                ],
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Duplicate pair id",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "success"
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/place/geojson": {
+            "put": {
+                "description": "Same filters as get_all_with_filter, places without coordinates are skipped, page size is GEOJSON_MAX_FEATURES.\nWhen more places match, truncated is true and next_cursor requests the next page",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "place"
+                ],
+                "parameters": [
+                    {
+                        "description": "Filters",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/swagger.Filters"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Response language, takes precedence over Accept-Language",
+                        "name": "lang",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Preferred response languages",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully",
+                        "schema": {
+                            "$ref": "#/definitions/geojson.FeatureCollection"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/place/get_all_with_filter": {
+            "put": {
+                "description": "Keyset pagination: pass next_cursor from the previous page as cursor, keep the same filters and sort_by",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "place"
+                ],
+                "parameters": [
+                    {
+                        "description": "Filters",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/swagger.Filters"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Response language, takes precedence over Accept-Language",
+                        "name": "lang",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Preferred response languages",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.Page-models_Place"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/place/import": {
+            "post": {
+                "description": "CSV columns: name, variety, city, district, tags (separated by ;), lat, lon, other columns go to properties.\nGeoJSON: Point geometry, the same keys in feature properties, tags may be an array.\nPlaces are upserted by name in one transaction; nothing is applied on dry run or when any row is invalid.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "place"
+                ],
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "CSV or GeoJSON file",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "csv or geojson, by default taken from file extension",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only validate",
+                        "name": "dry_run",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Import report",
+                        "schema": {
+                            "$ref": "#/definitions/models.ImportReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "413": {
+                        "description": "File is too large",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "Some rows are invalid, nothing applied",
+                        "schema": {
+                            "$ref": "#/definitions/models.ImportReport"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/place/merge": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "place"
+                ],
+                "parameters": [
+                    {
+                        "description": "Survivor and duplicate ids",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.PlaceMergeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Audit record of the merge",
+                        "schema": {
+                            "$ref": "#/definitions/models.PlaceMerge"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Place not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/place/merges": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "place"
+                ],
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Survivor place id",
+                        "name": "survivor_id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.PlaceMerge"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/place/trending": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "place"
+                ],
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "City id",
+                        "name": "city_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "District id",
+                        "name": "district_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of places, TRENDING_LIMIT by default",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Response language, takes precedence over Accept-Language",
+                        "name": "lang",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Preferred response languages",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Place"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/place/update": {
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "place"
+                ],
+                "parameters": [
+                    {
+                        "description": "Place with tag ids update",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.PlaceUpdate"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Place not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/review": {
+            "delete": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "review"
+                ],
+                "parameters": [
+                    {
+                        "enum": [
+                            "place",
+                            "route"
+                        ],
+                        "type": "string",
+                        "description": "Review type",
+                        "name": "review_type",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "review id",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "author id",
+                        "name": "user_id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "User is not the author",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Review not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/review/author": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "review"
+                ],
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "author id",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully",
+                        "schema": {
+                            "$ref": "#/definitions/swagger.GetByAuthor"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/review/create_on_place": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "review"
+                ],
+                "parameters": [
+                    {
+                        "description": "place review create",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.PlaceReviewCreate"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully created route review with id",
+                        "schema": {
+                            "type": "integer"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Place variety requires a check-in before review",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Place not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "Text is rejected by the content filter",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/review/create_on_route": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "review"
+                ],
+                "parameters": [
+                    {
+                        "description": "Route review create",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.RouteReviewCreate"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully created route review with id",
+                        "schema": {
+                            "type": "integer"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Route is not completed by the author while REVIEW_ROUTE_REQUIRE_VERIFIED is set",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "Text is rejected by the content filter",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/review/flag": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "review"
+                ],
+                "parameters": [
+                    {
+                        "description": "Flag",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ReviewFlagCreate"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.ReviewFlagResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Review not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Review is already flagged by the user",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/review/manager": {
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "review"
+                ],
+                "parameters": [
+                    {
+                        "description": "Manager",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.EntityManagerUpdate"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "User is not a moderator",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Place or route not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "review"
+                ],
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Moderator id",
+                        "name": "moderator_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "place",
+                            "route"
+                        ],
+                        "type": "string",
+                        "description": "Entity type",
+                        "name": "entity_type",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Place or route id",
+                        "name": "entity_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Manager id",
+                        "name": "user_id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "User is not a moderator",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Manager not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/review/managers": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "review"
+                ],
+                "parameters": [
+                    {
+                        "enum": [
+                            "place",
+                            "route"
+                        ],
+                        "type": "string",
+                        "description": "Entity type",
+                        "name": "entity_type",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Place or route id",
+                        "name": "entity_id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.EntityManager"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/review/moderate": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "review"
+                ],
+                "parameters": [
+                    {
+                        "description": "Moderation decision, reason is required for reject and hide",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ReviewModeration"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "User is not a moderator",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Review not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/review/moderation_queue": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "review"
+                ],
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Moderator id",
+                        "name": "moderator_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "pending",
+                            "flagged"
+                        ],
+                        "type": "string",
+                        "description": "Only pending or only flagged reviews",
+                        "name": "queue",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next_cursor from the previous page, empty for the first page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size, capped by MAX_PAGE_SIZE",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Count total number of reviews",
+                        "name": "with_total",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.Page-models_ReviewQueueItem"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "User is not a moderator",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/review/place": {
+            "get": {
+                "description": "Breaking change: the response is a page object (items, next_cursor, has_more, total) instead of a plain array.\nDownranking of the helpful order is evaluated once for the first page and kept in next_cursor",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "review"
+                ],
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "place id",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only reviews of authors who checked in at the place",
+                        "name": "verified_only",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "helpful",
+                            "recent",
+                            "mark_high",
+                            "mark_low"
+                        ],
+                        "type": "string",
+                        "description": "Order, helpful by default",
+                        "name": "sort_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next_cursor from the previous page, empty for the first page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size, capped by MAX_PAGE_SIZE",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Count total number of reviews",
+                        "name": "with_total",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.Page-models_PlaceReview"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/review/reply": {
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "review"
+                ],
+                "parameters": [
+                    {
+                        "description": "Reply",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ReviewReplyUpsert"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Reply after saving",
+                        "schema": {
+                            "$ref": "#/definitions/models.ReviewReply"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "User is not owner or editor",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Review not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "review"
+                ],
+                "parameters": [
+                    {
+                        "enum": [
+                            "place",
+                            "route"
+                        ],
+                        "type": "string",
+                        "description": "Review type",
+                        "name": "review_type",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "review id",
+                        "name": "review_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "owner or editor id",
+                        "name": "user_id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "User is not owner or editor",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Reply not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/review/reply/history": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "review"
+                ],
+                "parameters": [
+                    {
+                        "enum": [
+                            "place",
+                            "route"
+                        ],
+                        "type": "string",
+                        "description": "Review type",
+                        "name": "review_type",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "review id",
+                        "name": "review_id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.ReviewReplyRevision"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Reply not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/review/route": {
+            "get": {
+                "description": "Breaking change: the response is a page object (items, next_cursor, has_more, total) instead of a plain array.\nDownranking of the helpful order is evaluated once for the first page and kept in next_cursor",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "review"
+                ],
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "route id",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only reviews of authors who completed the route",
+                        "name": "verified_only",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "helpful",
+                            "recent",
+                            "mark_high",
+                            "mark_low"
+                        ],
+                        "type": "string",
+                        "description": "Order, helpful by default",
+                        "name": "sort_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next_cursor from the previous page, empty for the first page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size, capped by MAX_PAGE_SIZE",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Count total number of reviews",
+                        "name": "with_total",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.Page-models_RouteReview"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/review/update_on_place": {
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "review"
+                ],
+                "parameters": [
+                    {
+                        "description": "place review id",
+                        "name": "review",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ReviewUpdate"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "Text is rejected by the content filter",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/review/update_on_route": {
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "review"
+                ],
+                "parameters": [
+                    {
+                        "description": "route review id",
+                        "name": "review",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ReviewUpdate"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "Text is rejected by the content filter",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/review/vote": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "review"
+                ],
+                "parameters": [
+                    {
+                        "description": "Vote",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ReviewVote"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Votes of the review after voting",
+                        "schema": {
+                            "$ref": "#/definitions/models.ReviewVotes"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Review not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "review"
+                ],
+                "parameters": [
+                    {
+                        "enum": [
+                            "place",
+                            "route"
+                        ],
+                        "type": "string",
+                        "description": "Review type",
+                        "name": "review_type",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "review id",
+                        "name": "review_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "voter id",
+                        "name": "user_id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Votes of the review after retraction",
+                        "schema": {
+                            "$ref": "#/definitions/models.ReviewVotes"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Vote not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/route/by_id": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "route"
+                ],
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "id",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Response language, takes precedence over Accept-Language",
+                        "name": "lang",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Preferred response languages",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.Route"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/route/by_page": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "route"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "next_cursor from the previous page, empty for the first page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size, capped by MAX_PAGE_SIZE",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Count total number of routes",
+                        "name": "with_total",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "popular"
+                        ],
+                        "type": "string",
+                        "description": "Empty for creation order or popular",
+                        "name": "sort_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "integer"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Route must have every tag",
+                        "name": "tag_ids",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Tag names or synonyms, route must have every tag",
+                        "name": "tags",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Tag also matches its descendant tags",
+                        "name": "include_descendants",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Response language, takes precedence over Accept-Language",
+                        "name": "lang",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Preferred response languages",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.Page-models_Route"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/route/create": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "route"
+                ],
+                "parameters": [
+                    {
+                        "description": "Route with tag ids and place ids create",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.RouteCreate"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully created route with id",
+                        "schema": {
+                            "type": "integer"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/route/geojson": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "route"
+                ],
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "id",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Response language, takes precedence over Accept-Language",
+                        "name": "lang",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Preferred response languages",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully",
+                        "schema": {
+                            "$ref": "#/definitions/geojson.FeatureCollection"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/stats/city": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "stats"
+                ],
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "City id",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "First day, 2006-01-02, 30 days before to by default",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last day, 2006-01-02, today by default",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "day",
+                            "week",
+                            "month"
+                        ],
+                        "type": "string",
+                        "description": "Timeline interval",
+                        "name": "granularity",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.CityStats"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "City not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/stats/district": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "stats"
+                ],
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "District id",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "First day, 2006-01-02, 30 days before to by default",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last day, 2006-01-02, today by default",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "day",
+                            "week",
+                            "month"
+                        ],
+                        "type": "string",
+                        "description": "Timeline interval",
+                        "name": "granularity",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.AreaStats"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "District not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/tag": {
+            "delete": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tag"
+                ],
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Tag id",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Remove tag from places and routes, child tags move to its parent",
+                        "name": "cascade",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully, removed relations",
+                        "schema": {
+                            "$ref": "#/definitions/models.TagDeleteResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Tag not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Tag is used by places, routes or child tags",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/tag/category": {
+            "delete": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tag"
+                ],
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Category id",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully"
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Category not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/tag/category/create": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tag"
+                ],
+                "parameters": [
+                    {
+                        "description": "Tag category create",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.TagCategoryCreate"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully created category with id",
+                        "schema": {
+                            "type": "integer"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/tag/category/get_all": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tag"
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.TagCategory"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/tag/create": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tag"
+                ],
+                "parameters": [
+                    {
+                        "description": "Tag create",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.TagCreate"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully created tag with id",
+                        "schema": {
+                            "type": "integer"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Parent tag or category not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Name is used by a tag or synonym",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/tag/get_all": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tag"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Response language, takes precedence over Accept-Language",
+                        "name": "lang",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Preferred response languages",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.TagDetails"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/tag/hierarchy": {
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tag"
+                ],
+                "parameters": [
+                    {
+                        "description": "Tag hierarchy",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.TagHierarchy"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully"
+                    },
+                    "400": {
+                        "description": "Invalid input or parent is a descendant of the tag",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Tag, parent or category not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/tag/merge": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tag"
+                ],
+                "parameters": [
+                    {
+                        "description": "Tag merge",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.TagMergeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully, moved relations",
+                        "schema": {
+                            "$ref": "#/definitions/models.TagMergeResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input or target is a descendant of source",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Tag not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/tag/rename": {
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tag"
+                ],
+                "parameters": [
+                    {
+                        "description": "Tag rename",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.TagRename"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully"
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Tag not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Name is used by a tag or synonym",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/tag/synonym": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tag"
+                ],
+                "parameters": [
+                    {
+                        "description": "Tag synonym",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.TagSynonym"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully"
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Tag not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Name is used by a tag or synonym",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tag"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Synonym",
+                        "name": "name",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully"
+                    },
+                    "404": {
+                        "description": "Synonym not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/translation": {
+            "delete": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "translation"
+                ],
+                "parameters": [
+                    {
+                        "enum": [
+                            "tag",
+                            "place",
+                            "route"
+                        ],
+                        "type": "string",
+                        "description": "Entity type",
+                        "name": "entity_type",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Entity id",
+                        "name": "entity_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Language",
+                        "name": "lang",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "name",
+                            "description"
+                        ],
+                        "type": "string",
+                        "description": "Field",
+                        "name": "field",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully"
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Translation not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/translation/by_entity": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "translation"
+                ],
+                "parameters": [
+                    {
+                        "enum": [
+                            "tag",
+                            "place",
+                            "route"
+                        ],
+                        "type": "string",
+                        "description": "Entity type",
+                        "name": "entity_type",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Entity id",
+                        "name": "entity_id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Translation"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/translation/missing": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "translation"
+                ],
+                "parameters": [
+                    {
+                        "enum": [
+                            "tag",
+                            "place",
+                            "route"
+                        ],
+                        "type": "string",
+                        "description": "Entity type",
+                        "name": "entity_type",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Language",
+                        "name": "lang",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "next_cursor from the previous page, empty for the first page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size, capped by MAX_PAGE_SIZE",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Count total number of entities",
+                        "name": "with_total",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.Page-models_MissingTranslation"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/translation/upsert": {
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "translation"
+                ],
+                "parameters": [
+                    {
+                        "description": "Translation",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.TranslationUpsert"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully"
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Entity not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/trip/by_id": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "trip"
+                ],
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "trip id",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully created trip with id",
+                        "schema": {
+                            "$ref": "#/definitions/models.Trip"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/trip/by_user_id": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "trip"
+                ],
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "user id",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully created trip with id",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Trip"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/trip/create": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "trip"
+                ],
+                "parameters": [
+                    {
+                        "description": "trip",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.TripCreate"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully created trip with id",
+                        "schema": {
+                            "type": "integer"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/trip/diary": {
+            "get": {
+                "produces": [
+                    "application/octet-stream"
+                ],
+                "tags": [
+                    "trip"
+                ],
+                "parameters": [
+                    {
+                        "enum": [
+                            "md",
+                            "html",
+                            "epub"
+                        ],
+                        "type": "string",
+                        "name": "format",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "name": "trip_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "name": "user_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Response language, takes precedence over Accept-Language",
+                        "name": "lang",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Preferred response languages",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Diary in Markdown, self-contained HTML or EPUB",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Trip belongs to another user",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Trip not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/trip/place": {
+            "delete": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "trip"
+                ],
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "trip id",
+                        "name": "trip_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "place id",
+                        "name": "place_id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/trip/place/add": {
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "trip"
+                ],
+                "parameters": [
+                    {
+                        "description": "data",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/swagger.TripAdd"
+                        }
+                    }
+                ],
+                "responses": {
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/trip/place/change/day": {
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "trip"
+                ],
+                "parameters": [
+                    {
+                        "description": "data",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/swagger.TripChangeDay"
+                        }
+                    }
+                ],
+                "responses": {
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/trip/place/change/position": {
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "trip"
+                ],
+                "parameters": [
+                    {
+                        "description": "data",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/swagger.TripChangePosition"
+                        }
+                    }
+                ],
+                "responses": {
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/trip/route": {
+            "delete": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "trip"
+                ],
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "trip id",
+                        "name": "trip_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "route id",
+                        "name": "route_id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/trip/route/add": {
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "trip"
+                ],
+                "parameters": [
+                    {
+                        "description": "data",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/swagger.TripAdd"
+                        }
+                    }
+                ],
+                "responses": {
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/trip/route/change/day": {
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "trip"
+                ],
+                "parameters": [
+                    {
+                        "description": "data",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/swagger.TripChangeDay"
+                        }
+                    }
+                ],
+                "responses": {
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/trip/route/change/position": {
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "trip"
+                ],
+                "parameters": [
+                    {
+                        "description": "data",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/swagger.TripChangePosition"
+                        }
+                    }
+                ],
+                "responses": {
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/user/check_in": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Cipher",
+                        "name": "cipher",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "UserID",
+                        "name": "user_id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Just valid hash",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/user/checked_in": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "userID",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "places",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Place"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/user/chrono": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "userID",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "user properties json",
+                        "schema": {
+                            "$ref": "#/definitions/models.Chrono"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/user/current_route": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "userID",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "user properties json",
+                        "schema": {
+                            "$ref": "#/definitions/models.RouteDisplay"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/user/login": {
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "parameters": [
+                    {
+                        "description": "login data",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/swagger.User"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "user ID",
+                        "schema": {
+                            "type": "integer"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/user/place_check_in_flag": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "place_id",
+                        "name": "place_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "UserID",
+                        "name": "user_id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Just valid hash",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/user/properties": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "userID",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "user properties json",
+                        "schema": {
+                            "$ref": "#/definitions/swagger.UserMe"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/user/register": {
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "parameters": [
+                    {
+                        "description": "create user data",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UserCreate"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "user ID",
+                        "schema": {
+                            "type": "integer"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/user/route_check_in_flag": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "route_id",
+                        "name": "route_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "UserID",
+                        "name": "user_id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Just valid hash",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/user/update_properties": {
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "parameters": [
+                    {
+                        "description": "user data",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/swagger.UserUpdate"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "user properties json",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "Text is rejected by the content filter",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/user/validate": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "hash",
+                        "name": "hash",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Just bool",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/variety": {
+            "delete": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "variety"
+                ],
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Variety id",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully"
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Variety is used by places",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/variety/by_name": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "variety"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Variety name",
+                        "name": "name",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.Variety"
+                        }
+                    },
+                    "404": {
+                        "description": "Unknown variety",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/variety/create": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "variety"
+                ],
+                "parameters": [
+                    {
+                        "description": "Variety create",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.VarietyBase"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully created variety with id",
+                        "schema": {
+                            "type": "integer"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/variety/get_all": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "variety"
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Variety"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/variety/update": {
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "variety"
+                ],
+                "parameters": [
+                    {
+                        "description": "Variety update",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Variety"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully"
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "geojson.Feature": {
+            "type": "object",
+            "properties": {
+                "geometry": {
+                    "$ref": "#/definitions/geojson.Geometry"
+                },
+                "id": {},
+                "properties": {
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "geojson.FeatureCollection": {
+            "type": "object",
+            "properties": {
+                "features": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/geojson.Feature"
+                    }
+                },
+                "next_cursor": {
+                    "type": "string"
+                },
+                "truncated": {
+                    "type": "boolean"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "geojson.Geometry": {
+            "type": "object",
+            "properties": {
+                "coordinates": {
+                    "type": "object"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.AreaStats": {
+            "type": "object",
+            "properties": {
+                "average_rating": {
+                    "type": "number"
+                },
+                "checkins": {
+                    "type": "integer"
+                },
+                "city_id": {
+                    "type": "integer"
+                },
+                "district_id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "places_by_variety": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "reviews_count": {
+                    "type": "integer"
+                },
+                "routes_passing": {
+                    "type": "integer"
+                },
+                "timeline": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.StatsBucket"
+                    }
+                },
+                "unique_visitors": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.AttachedMedia": {
+            "type": "object",
+            "properties": {
+                "content_type": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "height": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "owner_id": {
+                    "type": "integer"
+                },
+                "position": {
+                    "type": "integer"
+                },
+                "size": {
+                    "type": "integer"
+                },
+                "thumbnail_url": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                },
+                "width": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Chrono": {
+            "type": "object",
+            "properties": {
+                "check_ins": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ChronoEntity"
+                    }
+                },
+                "liked_places": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ChronoEntity"
+                    }
+                },
+                "liked_routes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ChronoEntity"
+                    }
+                },
+                "place_reviews": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ChronoEntity"
+                    }
+                },
+                "route_logs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ChronoEntity"
+                    }
+                },
+                "route_reviews": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ChronoEntity"
+                    }
+                }
+            }
+        },
+        "models.ChronoEntity": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "timestamp": {
+                    "type": "string"
+                },
+                "trip_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.City": {
+            "type": "object",
+            "properties": {
+                "center": {
+                    "$ref": "#/definitions/models.Point"
+                },
+                "country": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "time_zone": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CityBase": {
+            "type": "object",
+            "properties": {
+                "center": {
+                    "$ref": "#/definitions/models.Point"
+                },
+                "country": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "time_zone": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CityStats": {
+            "type": "object",
+            "properties": {
+                "average_rating": {
+                    "type": "number"
+                },
+                "checkins": {
+                    "type": "integer"
+                },
+                "city_id": {
+                    "type": "integer"
+                },
+                "district_id": {
+                    "type": "integer"
+                },
+                "districts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.AreaStats"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                },
+                "places_by_variety": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "reviews_count": {
+                    "type": "integer"
+                },
+                "routes_passing": {
+                    "type": "integer"
+                },
+                "timeline": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.StatsBucket"
+                    }
+                },
+                "unique_visitors": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.CompanionRequest": {
+            "type": "object",
+            "properties": {
+                "contacts": {
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "date_from": {
+                    "type": "string"
+                },
+                "date_to": {
+                    "type": "string"
+                },
+                "entity_id": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "listing_id": {
+                    "type": "integer"
+                },
+                "listing_name": {
+                    "type": "string"
+                },
+                "listing_type": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "owner_id": {
+                    "type": "integer"
+                },
+                "requester_id": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "pending",
+                        "accepted",
+                        "declined",
+                        "cancelled"
+                    ]
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_properties": {}
+            }
+        },
+        "models.CompanionRequestAction": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.CompanionRequestCreate": {
+            "type": "object",
+            "properties": {
+                "listing_id": {
+                    "type": "integer"
+                },
+                "listing_type": {
+                    "type": "string",
+                    "enum": [
+                        "place",
+                        "route"
+                    ]
+                },
+                "message": {
+                    "type": "string"
+                },
+                "requester_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.CompanionsFilters": {
+            "type": "object",
+            "properties": {
+                "cursor": {
+                    "type": "string"
+                },
+                "date_from": {
+                    "type": "string"
+                },
+                "date_to": {
+                    "type": "string"
+                },
+                "entity_id": {
+                    "type": "integer"
+                },
+                "page_size": {
+                    "type": "integer"
+                },
+                "with_total": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.CompanionsPlace": {
+            "type": "object",
+            "properties": {
+                "city_name": {
+                    "type": "string"
+                },
+                "date_from": {
+                    "type": "string"
+                },
+                "date_to": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "place_id": {
+                    "type": "integer"
+                },
+                "place_name": {
+                    "type": "string"
+                },
+                "place_properties": {},
+                "user_id": {
+                    "type": "integer"
+                },
+                "user_properties": {}
+            }
+        },
+        "models.CompanionsPlaceCreate": {
+            "type": "object",
+            "properties": {
+                "date_from": {
+                    "type": "string"
+                },
+                "date_to": {
+                    "type": "string"
+                },
+                "place_id": {
+                    "type": "integer"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.CompanionsRoute": {
+            "type": "object",
+            "properties": {
+                "city_name": {
+                    "type": "string"
+                },
+                "date_from": {
+                    "type": "string"
+                },
+                "date_to": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "price": {
+                    "type": "integer"
+                },
+                "route_id": {
+                    "type": "integer"
+                },
+                "route_name": {
+                    "type": "string"
+                },
+                "route_properties": {},
+                "user_id": {
+                    "type": "integer"
+                },
+                "user_properties": {}
+            }
+        },
+        "models.CompanionsRouteCreate": {
+            "type": "object",
+            "properties": {
+                "date_from": {
+                    "type": "string"
+                },
+                "date_to": {
+                    "type": "string"
+                },
+                "route_id": {
+                    "type": "integer"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ContentReport": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "entity_id": {
+                    "type": "integer"
+                },
+                "entity_type": {
+                    "type": "string",
+                    "enum": [
+                        "note",
+                        "user"
+                    ]
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "rules": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ContentReportResolve": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "moderator_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.CriterionRating": {
+            "type": "object",
+            "properties": {
+                "average": {
+                    "type": "number"
+                },
+                "count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.District": {
+            "type": "object",
+            "properties": {
+                "boundary": {
+                    "type": "object"
+                },
+                "city_id": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "properties": {}
+            }
+        },
+        "models.DistrictBase": {
+            "type": "object",
+            "properties": {
+                "boundary": {
+                    "type": "object"
+                },
+                "city_id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "properties": {}
+            }
+        },
+        "models.DistrictMismatch": {
+            "type": "object",
+            "properties": {
+                "city_id": {
+                    "type": "integer"
+                },
+                "computed_district_id": {
+                    "type": "integer"
+                },
+                "place_id": {
+                    "type": "integer"
+                },
+                "place_name": {
+                    "type": "string"
+                },
+                "stored_district_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.DistrictReconciliation": {
+            "type": "object",
+            "properties": {
+                "applied": {
+                    "type": "boolean"
+                },
+                "checked": {
+                    "type": "integer"
+                },
+                "mismatches": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DistrictMismatch"
+                    }
+                },
+                "updated": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.DistrictUpdate": {
+            "type": "object",
+            "properties": {
+                "boundary": {
+                    "type": "object"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "properties": {}
+            }
+        },
+        "models.EntityManager": {
+            "type": "object",
+            "properties": {
+                "entity_id": {
+                    "type": "integer"
+                },
+                "entity_type": {
+                    "type": "string",
+                    "enum": [
+                        "place",
+                        "route"
+                    ]
+                },
+                "role": {
+                    "type": "string",
+                    "enum": [
+                        "owner",
+                        "editor"
+                    ]
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.EntityManagerUpdate": {
+            "type": "object",
+            "properties": {
+                "entity_id": {
+                    "type": "integer"
+                },
+                "entity_type": {
+                    "type": "string",
+                    "enum": [
+                        "place",
+                        "route"
+                    ]
+                },
+                "moderator_id": {
+                    "type": "integer"
+                },
+                "role": {
+                    "type": "string",
+                    "enum": [
+                        "owner",
+                        "editor"
+                    ]
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.EntityWithDayAndPosition": {
+            "type": "object",
+            "properties": {
+                "day": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "position": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ImportReport": {
+            "type": "object",
+            "properties": {
+                "applied": {
+                    "type": "boolean"
+                },
+                "created": {
+                    "type": "integer"
+                },
+                "dry_run": {
+                    "type": "boolean"
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ImportRowError"
+                    }
+                },
+                "tags_created": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                },
+                "updated": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ImportRowError": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "row": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Like": {
+            "type": "object",
+            "properties": {
+                "entity_id": {
+                    "type": "integer"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Media": {
+            "type": "object",
+            "properties": {
+                "content_type": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "height": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "owner_id": {
+                    "type": "integer"
+                },
+                "size": {
+                    "type": "integer"
+                },
+                "thumbnail_url": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                },
+                "width": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.MediaAttachment": {
+            "type": "object",
+            "properties": {
+                "entity_id": {
+                    "type": "integer"
+                },
+                "entity_type": {
+                    "type": "string",
+                    "enum": [
+                        "place",
+                        "route",
+                        "place_review",
+                        "route_review",
+                        "note"
+                    ]
+                },
+                "media_id": {
+                    "type": "integer"
+                },
+                "position": {
+                    "type": "integer"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.MissingTranslation": {
+            "type": "object",
+            "properties": {
+                "entity_id": {
+                    "type": "integer"
+                },
+                "missing": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Note": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "day": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "is_check_in": {
+                    "type": "boolean"
+                },
+                "place_id": {
+                    "type": "integer"
+                },
+                "properties": {},
+                "target_id": {
+                    "type": "integer"
+                },
+                "target_type": {
+                    "type": "string",
+                    "enum": [
+                        "place",
+                        "route",
+                        "trip",
+                        "trip_day"
+                    ]
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                },
+                "visibility": {
+                    "type": "string",
+                    "enum": [
+                        "private",
+                        "companions",
+                        "link",
+                        "public"
+                    ]
+                }
+            }
+        },
+        "models.NoteCreate": {
+            "type": "object",
+            "properties": {
+                "place_id": {
+                    "type": "integer"
+                },
+                "properties": {},
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.NoteShare": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.NoteShareToken": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "note_id": {
+                    "type": "integer"
+                },
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.NoteTargetCreate": {
+            "type": "object",
+            "properties": {
+                "day": {
+                    "type": "integer"
+                },
+                "properties": {},
+                "target_id": {
+                    "type": "integer"
+                },
+                "target_type": {
+                    "type": "string",
+                    "enum": [
+                        "place",
+                        "route",
+                        "trip",
+                        "trip_day"
+                    ]
+                },
+                "user_id": {
+                    "type": "integer"
+                },
+                "visibility": {
+                    "type": "string",
+                    "enum": [
+                        "private",
+                        "companions",
+                        "link",
+                        "public"
+                    ]
+                }
+            }
+        },
+        "models.NoteUpdate": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "properties": {},
+                "user_id": {
+                    "type": "integer"
+                },
+                "visibility": {
+                    "type": "string",
+                    "enum": [
+                        "private",
+                        "companions",
+                        "link",
+                        "public"
+                    ]
+                }
+            }
+        },
+        "models.Notification": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "payload": {
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "read": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.NotificationsRead": {
+            "type": "object",
+            "required": [
+                "user_id"
+            ],
+            "properties": {
+                "ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Page-models_CompanionRequest": {
+            "type": "object",
+            "properties": {
+                "has_more": {
+                    "type": "boolean"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CompanionRequest"
+                    }
+                },
+                "next_cursor": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Page-models_CompanionsPlace": {
+            "type": "object",
+            "properties": {
+                "has_more": {
+                    "type": "boolean"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CompanionsPlace"
+                    }
+                },
+                "next_cursor": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Page-models_CompanionsRoute": {
+            "type": "object",
+            "properties": {
+                "has_more": {
+                    "type": "boolean"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CompanionsRoute"
+                    }
+                },
+                "next_cursor": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Page-models_ContentReport": {
+            "type": "object",
+            "properties": {
+                "has_more": {
+                    "type": "boolean"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ContentReport"
+                    }
+                },
+                "next_cursor": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Page-models_MissingTranslation": {
+            "type": "object",
+            "properties": {
+                "has_more": {
+                    "type": "boolean"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.MissingTranslation"
+                    }
+                },
+                "next_cursor": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Page-models_Note": {
+            "type": "object",
+            "properties": {
+                "has_more": {
+                    "type": "boolean"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Note"
+                    }
+                },
+                "next_cursor": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Page-models_Notification": {
+            "type": "object",
+            "properties": {
+                "has_more": {
+                    "type": "boolean"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Notification"
+                    }
+                },
+                "next_cursor": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Page-models_Place": {
+            "type": "object",
+            "properties": {
+                "has_more": {
+                    "type": "boolean"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Place"
+                    }
+                },
+                "next_cursor": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Page-models_PlaceDuplicate": {
+            "type": "object",
+            "properties": {
+                "has_more": {
+                    "type": "boolean"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PlaceDuplicate"
+                    }
+                },
+                "next_cursor": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Page-models_PlaceReview": {
+            "type": "object",
+            "properties": {
+                "has_more": {
+                    "type": "boolean"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PlaceReview"
+                    }
+                },
+                "next_cursor": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Page-models_ReviewQueueItem": {
+            "type": "object",
+            "properties": {
+                "has_more": {
+                    "type": "boolean"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ReviewQueueItem"
+                    }
+                },
+                "next_cursor": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Page-models_Route": {
+            "type": "object",
+            "properties": {
+                "has_more": {
+                    "type": "boolean"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Route"
+                    }
+                },
+                "next_cursor": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Page-models_RouteReview": {
+            "type": "object",
+            "properties": {
+                "has_more": {
+                    "type": "boolean"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.RouteReview"
+                    }
+                },
+                "next_cursor": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Place": {
+            "type": "object",
+            "properties": {
+                "archived": {
+                    "type": "boolean"
+                },
+                "city_id": {
+                    "type": "integer"
+                },
+                "coordinates": {
+                    "$ref": "#/definitions/models.Point"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "district_id": {
+                    "type": "integer"
+                },
                 "id": {
                     "type": "integer"
                 },
-                "timestamp": {
+                "name": {
+                    "type": "string"
+                },
+                "popularity": {
+                    "type": "number"
+                },
+                "properties": {},
+                "rating": {
+                    "$ref": "#/definitions/models.Rating"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Tag"
+                    }
+                },
+                "variety": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.PlaceCreate": {
+            "type": "object",
+            "properties": {
+                "city_id": {
+                    "type": "integer"
+                },
+                "coordinates": {
+                    "$ref": "#/definitions/models.Point"
+                },
+                "district_id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "properties": {},
+                "tag_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "variety": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.PlaceDuplicate": {
+            "type": "object",
+            "properties": {
+                "detected_at": {
+                    "type": "string"
+                },
+                "distance": {
+                    "type": "number"
+                },
+                "duplicate_id": {
+                    "type": "integer"
+                },
+                "duplicate_name": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "place_id": {
+                    "type": "integer"
+                },
+                "place_name": {
+                    "type": "string"
+                },
+                "similarity": {
+                    "type": "number"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.PlaceIDWithPosition": {
+            "type": "object",
+            "properties": {
+                "place_id": {
+                    "type": "integer"
+                },
+                "position": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.PlaceMerge": {
+            "type": "object",
+            "properties": {
+                "counts": {
+                    "$ref": "#/definitions/models.PlaceMergeCounts"
+                },
+                "duplicate": {
+                    "type": "object"
+                },
+                "duplicate_id": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "merged_at": {
                     "type": "string"
                 },
-                "trip_id": {
+                "merged_by": {
+                    "type": "integer"
+                },
+                "survivor_id": {
                     "type": "integer"
                 }
             }
         },
-        "models.CompanionsFilters": {
+        "models.PlaceMergeCounts": {
             "type": "object",
             "properties": {
-                "date_from": {
-                    "type": "string"
+                "checkins": {
+                    "type": "integer"
                 },
-                "date_to": {
-                    "type": "string"
+                "checkins_dropped": {
+                    "type": "integer"
                 },
-                "entity_id": {
+                "companions": {
+                    "type": "integer"
+                },
+                "favourites": {
+                    "type": "integer"
+                },
+                "favourites_dropped": {
+                    "type": "integer"
+                },
+                "media": {
+                    "type": "integer"
+                },
+                "notes": {
+                    "type": "integer"
+                },
+                "reviews": {
+                    "type": "integer"
+                },
+                "reviews_dropped": {
+                    "type": "integer"
+                },
+                "route_stops": {
+                    "type": "integer"
+                },
+                "route_stops_dropped": {
+                    "type": "integer"
+                },
+                "tags": {
                     "type": "integer"
                 },
-                "page": {
+                "trip_entries": {
                     "type": "integer"
                 }
             }
         },
-        "models.CompanionsPlace": {
+        "models.PlaceMergeRequest": {
             "type": "object",
             "properties": {
-                "city_name": {
-                    "type": "string"
+                "duplicate_id": {
+                    "type": "integer"
                 },
-                "date_from": {
-                    "type": "string"
+                "survivor_id": {
+                    "type": "integer"
                 },
-                "date_to": {
-                    "type": "string"
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.PlaceReview": {
+            "type": "object",
+            "properties": {
+                "author_id": {
+                    "type": "integer"
+                },
+                "downranked": {
+                    "type": "boolean"
                 },
                 "id": {
                     "type": "integer"
                 },
+                "mark": {
+                    "type": "number"
+                },
+                "moderation_reason": {
+                    "type": "string"
+                },
                 "place_id": {
                     "type": "integer"
                 },
-                "place_name": {
+                "properties": {},
+                "reply": {
+                    "$ref": "#/definitions/models.ReviewReply"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "pending",
+                        "published",
+                        "rejected",
+                        "hidden"
+                    ]
+                },
+                "sub_marks": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "number"
+                    }
+                },
+                "timeStamp": {
                     "type": "string"
                 },
-                "place_properties": {},
-                "user_id": {
-                    "type": "integer"
+                "verified": {
+                    "type": "boolean"
                 },
-                "user_properties": {}
+                "votes": {
+                    "$ref": "#/definitions/models.ReviewVotes"
+                }
             }
         },
-        "models.CompanionsPlaceCreate": {
+        "models.PlaceReviewCreate": {
             "type": "object",
             "properties": {
-                "date_from": {
-                    "type": "string"
+                "author_id": {
+                    "type": "integer"
                 },
-                "date_to": {
-                    "type": "string"
+                "mark": {
+                    "type": "number"
                 },
                 "place_id": {
                     "type": "integer"
                 },
-                "user_id": {
-                    "type": "integer"
+                "properties": {},
+                "sub_marks": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "number"
+                    }
+                },
+                "timeStamp": {
+                    "type": "string"
                 }
             }
         },
-        "models.CompanionsRoute": {
+        "models.PlaceUpdate": {
             "type": "object",
             "properties": {
-                "city_name": {
-                    "type": "string"
+                "city_id": {
+                    "type": "integer"
                 },
-                "date_from": {
-                    "type": "string"
+                "coordinates": {
+                    "$ref": "#/definitions/models.Point"
                 },
-                "date_to": {
-                    "type": "string"
+                "district_id": {
+                    "type": "integer"
                 },
                 "id": {
                     "type": "integer"
                 },
-                "price": {
+                "name": {
+                    "type": "string"
+                },
+                "properties": {},
+                "tag_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "variety": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.PlaceWithPosition": {
+            "type": "object",
+            "properties": {
+                "place": {
+                    "$ref": "#/definitions/models.Place"
+                },
+                "position": {
                     "type": "integer"
+                }
+            }
+        },
+        "models.Point": {
+            "type": "object",
+            "properties": {
+                "lat": {
+                    "type": "number"
                 },
-                "route_id": {
+                "lon": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.Rating": {
+            "type": "object",
+            "properties": {
+                "average": {
+                    "type": "number"
+                },
+                "count": {
                     "type": "integer"
                 },
-                "route_name": {
+                "criteria": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/models.CriterionRating"
+                    }
+                },
+                "histogram": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "models.ReviewFlagCreate": {
+            "type": "object",
+            "properties": {
+                "comment": {
                     "type": "string"
                 },
-                "route_properties": {},
+                "reason": {
+                    "type": "string",
+                    "enum": [
+                        "spam",
+                        "offensive",
+                        "off_topic",
+                        "fake",
+                        "other"
+                    ]
+                },
+                "review_id": {
+                    "type": "integer"
+                },
+                "review_type": {
+                    "type": "string",
+                    "enum": [
+                        "place",
+                        "route"
+                    ]
+                },
                 "user_id": {
                     "type": "integer"
+                }
+            }
+        },
+        "models.ReviewFlagResult": {
+            "type": "object",
+            "properties": {
+                "hidden": {
+                    "type": "boolean"
                 },
-                "user_properties": {}
+                "open_flags": {
+                    "type": "integer"
+                }
             }
         },
-        "models.CompanionsRouteCreate": {
+        "models.ReviewModeration": {
             "type": "object",
             "properties": {
-                "date_from": {
-                    "type": "string"
+                "action": {
+                    "type": "string",
+                    "enum": [
+                        "approve",
+                        "reject",
+                        "hide"
+                    ]
                 },
-                "date_to": {
+                "moderator_id": {
+                    "type": "integer"
+                },
+                "reason": {
                     "type": "string"
                 },
-                "route_id": {
+                "review_id": {
                     "type": "integer"
                 },
-                "user_id": {
-                    "type": "integer"
+                "review_type": {
+                    "type": "string",
+                    "enum": [
+                        "place",
+                        "route"
+                    ]
                 }
             }
         },
-        "models.District": {
+        "models.ReviewQueueItem": {
             "type": "object",
             "properties": {
-                "city_id": {
+                "author_id": {
+                    "type": "integer"
+                },
+                "entity_id": {
                     "type": "integer"
                 },
+                "flags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
                 "id": {
                     "type": "integer"
                 },
-                "name": {
+                "mark": {
+                    "type": "number"
+                },
+                "moderation_reason": {
                     "type": "string"
                 },
-                "properties": {}
+                "properties": {},
+                "review_type": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "pending",
+                        "published",
+                        "rejected",
+                        "hidden"
+                    ]
+                },
+                "timestamp": {
+                    "type": "string"
+                }
             }
         },
-        "models.EntityWithDayAndPosition": {
+        "models.ReviewReply": {
             "type": "object",
             "properties": {
-                "day": {
+                "author_id": {
                     "type": "integer"
                 },
+                "created_at": {
+                    "type": "string"
+                },
+                "edited": {
+                    "type": "boolean"
+                },
                 "id": {
                     "type": "integer"
                 },
-                "position": {
+                "text": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ReviewReplyRevision": {
+            "type": "object",
+            "properties": {
+                "author_id": {
                     "type": "integer"
+                },
+                "text": {
+                    "type": "string"
+                },
+                "written_at": {
+                    "type": "string"
                 }
             }
         },
-        "models.Like": {
+        "models.ReviewReplyUpsert": {
             "type": "object",
             "properties": {
-                "entity_id": {
+                "review_id": {
                     "type": "integer"
                 },
+                "review_type": {
+                    "type": "string",
+                    "enum": [
+                        "place",
+                        "route"
+                    ]
+                },
+                "text": {
+                    "type": "string"
+                },
                 "user_id": {
                     "type": "integer"
                 }
             }
         },
-        "models.Note": {
+        "models.ReviewUpdate": {
             "type": "object",
             "properties": {
                 "id": {
                     "type": "integer"
                 },
-                "is_check_in": {
+                "mark": {
+                    "type": "number"
+                },
+                "properties": {},
+                "sub_marks": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "number"
+                    }
+                }
+            }
+        },
+        "models.ReviewVote": {
+            "type": "object",
+            "properties": {
+                "helpful": {
                     "type": "boolean"
                 },
-                "place_id": {
+                "review_id": {
                     "type": "integer"
                 },
-                "properties": {},
+                "review_type": {
+                    "type": "string",
+                    "enum": [
+                        "place",
+                        "route"
+                    ]
+                },
                 "user_id": {
                     "type": "integer"
                 }
             }
         },
-        "models.NoteCreate": {
+        "models.ReviewVotes": {
             "type": "object",
             "properties": {
-                "place_id": {
+                "helpful": {
                     "type": "integer"
                 },
-                "properties": {},
-                "user_id": {
+                "score": {
+                    "type": "number"
+                },
+                "unhelpful": {
                     "type": "integer"
                 }
             }
         },
-        "models.Place": {
+        "models.Route": {
             "type": "object",
             "properties": {
                 "city_id": {
                     "type": "integer"
                 },
-                "district_id": {
-                    "type": "integer"
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "places": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PlaceWithPosition"
+                    }
+                },
+                "popularity": {
+                    "type": "number"
                 },
-                "id": {
+                "price": {
                     "type": "integer"
                 },
-                "name": {
-                    "type": "string"
-                },
                 "properties": {},
+                "rating": {
+                    "$ref": "#/definitions/models.Rating"
+                },
                 "tags": {
                     "type": "array",
                     "items": {
                         "$ref": "#/definitions/models.Tag"
                     }
-                },
-                "variety": {
-                    "type": "string"
                 }
             }
         },
-        "models.PlaceCreate": {
+        "models.RouteCreate": {
             "type": "object",
             "properties": {
                 "city_id": {
                     "type": "integer"
                 },
-                "district_id": {
-                    "type": "integer"
-                },
                 "name": {
                     "type": "string"
                 },
+                "places": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PlaceIDWithPosition"
+                    }
+                },
+                "price": {
+                    "type": "integer"
+                },
                 "properties": {},
                 "tag_ids": {
                     "type": "array",
                     "items": {
                         "type": "integer"
                     }
-                },
-                "variety": {
-                    "type": "string"
                 }
             }
         },
-        "models.PlaceIDWithPosition": {
+        "models.RouteDisplay": {
             "type": "object",
             "properties": {
-                "place_id": {
+                "completed_place": {
                     "type": "integer"
                 },
-                "position": {
+                "id": {
+                    "type": "integer"
+                },
+                "next_place_id": {
                     "type": "integer"
                 }
             }
         },
-        "models.PlaceReview": {
+        "models.RouteReview": {
             "type": "object",
             "properties": {
                 "author_id": {
                     "type": "integer"
                 },
+                "downranked": {
+                    "type": "boolean"
+                },
                 "id": {
                     "type": "integer"
                 },
                 "mark": {
                     "type": "number"
                 },
-                "place_id": {
-                    "type": "integer"
+                "moderation_reason": {
+                    "type": "string"
                 },
                 "properties": {},
+                "reply": {
+                    "$ref": "#/definitions/models.ReviewReply"
+                },
+                "route_id": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "pending",
+                        "published",
+                        "rejected",
+                        "hidden"
+                    ]
+                },
+                "sub_marks": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "number"
+                    }
+                },
                 "timeStamp": {
                     "type": "string"
+                },
+                "verified": {
+                    "type": "boolean"
+                },
+                "votes": {
+                    "$ref": "#/definitions/models.ReviewVotes"
                 }
             }
         },
-        "models.PlaceReviewCreate": {
+        "models.RouteReviewCreate": {
             "type": "object",
             "properties": {
                 "author_id": {
@@ -3036,149 +10147,168 @@ const docTemplate = `{
                 "mark": {
                     "type": "number"
                 },
-                "place_id": {
+                "properties": {},
+                "route_id": {
                     "type": "integer"
                 },
-                "properties": {},
+                "sub_marks": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "number"
+                    }
+                },
                 "timeStamp": {
                     "type": "string"
                 }
             }
         },
-        "models.PlaceWithPosition": {
+        "models.StatsBucket": {
             "type": "object",
             "properties": {
-                "place": {
-                    "$ref": "#/definitions/models.Place"
+                "checkins": {
+                    "type": "integer"
                 },
-                "position": {
+                "start": {
+                    "type": "string"
+                },
+                "visitors": {
                     "type": "integer"
                 }
             }
         },
-        "models.ReviewUpdate": {
+        "models.Tag": {
             "type": "object",
             "properties": {
+                "category_id": {
+                    "type": "integer"
+                },
                 "id": {
                     "type": "integer"
                 },
-                "mark": {
-                    "type": "number"
+                "name": {
+                    "type": "string"
                 },
-                "properties": {}
+                "parent_id": {
+                    "type": "integer"
+                }
             }
         },
-        "models.Route": {
+        "models.TagCategory": {
             "type": "object",
             "properties": {
-                "city_id": {
+                "id": {
                     "type": "integer"
                 },
-                "id": {
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.TagCategoryCreate": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.TagCreate": {
+            "type": "object",
+            "properties": {
+                "category_id": {
                     "type": "integer"
                 },
                 "name": {
                     "type": "string"
                 },
+                "parent_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.TagDeleteResult": {
+            "type": "object",
+            "properties": {
                 "places": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.PlaceWithPosition"
-                    }
-                },
-                "price": {
                     "type": "integer"
                 },
-                "properties": {},
-                "tags": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.Tag"
-                    }
+                "routes": {
+                    "type": "integer"
                 }
             }
         },
-        "models.RouteCreate": {
+        "models.TagDetails": {
             "type": "object",
             "properties": {
-                "city_id": {
+                "category_id": {
+                    "type": "integer"
+                },
+                "id": {
                     "type": "integer"
                 },
                 "name": {
                     "type": "string"
                 },
-                "places": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.PlaceIDWithPosition"
-                    }
-                },
-                "price": {
+                "parent_id": {
                     "type": "integer"
                 },
-                "properties": {},
-                "tag_ids": {
+                "synonyms": {
                     "type": "array",
                     "items": {
-                        "type": "integer"
+                        "type": "string"
                     }
                 }
             }
         },
-        "models.RouteDisplay": {
+        "models.TagHierarchy": {
             "type": "object",
             "properties": {
-                "completed_place": {
+                "category_id": {
                     "type": "integer"
                 },
                 "id": {
                     "type": "integer"
                 },
-                "next_place_id": {
+                "parent_id": {
                     "type": "integer"
                 }
             }
         },
-        "models.RouteReview": {
+        "models.TagMergeRequest": {
             "type": "object",
             "properties": {
-                "author_id": {
+                "source_id": {
                     "type": "integer"
                 },
-                "id": {
-                    "type": "integer"
-                },
-                "mark": {
-                    "type": "number"
-                },
-                "properties": {},
-                "route_id": {
+                "target_id": {
                     "type": "integer"
-                },
-                "timeStamp": {
-                    "type": "string"
                 }
             }
         },
-        "models.RouteReviewCreate": {
+        "models.TagMergeResult": {
             "type": "object",
             "properties": {
-                "author_id": {
+                "children": {
                     "type": "integer"
                 },
-                "mark": {
-                    "type": "number"
+                "places_dropped": {
+                    "type": "integer"
                 },
-                "properties": {},
-                "route_id": {
+                "places_moved": {
                     "type": "integer"
                 },
-                "timeStamp": {
-                    "type": "string"
+                "routes_dropped": {
+                    "type": "integer"
+                },
+                "routes_moved": {
+                    "type": "integer"
+                },
+                "synonyms": {
+                    "type": "integer"
                 }
             }
         },
-        "models.Tag": {
+        "models.TagRename": {
             "type": "object",
             "properties": {
                 "id": {
@@ -3189,11 +10319,75 @@ const docTemplate = `{
                 }
             }
         },
-        "models.TagCreate": {
+        "models.TagSynonym": {
             "type": "object",
             "properties": {
                 "name": {
                     "type": "string"
+                },
+                "tag_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Translation": {
+            "type": "object",
+            "properties": {
+                "entity_id": {
+                    "type": "integer"
+                },
+                "entity_type": {
+                    "type": "string",
+                    "enum": [
+                        "tag",
+                        "place",
+                        "route"
+                    ]
+                },
+                "field": {
+                    "type": "string",
+                    "enum": [
+                        "name",
+                        "description"
+                    ]
+                },
+                "lang": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.TranslationUpsert": {
+            "type": "object",
+            "properties": {
+                "entity_id": {
+                    "type": "integer"
+                },
+                "entity_type": {
+                    "type": "string",
+                    "enum": [
+                        "tag",
+                        "place",
+                        "route"
+                    ]
+                },
+                "field": {
+                    "type": "string",
+                    "enum": [
+                        "name",
+                        "description"
+                    ]
+                },
+                "lang": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "string"
                 }
             }
         },
@@ -3266,6 +10460,75 @@ const docTemplate = `{
                 "properties": {}
             }
         },
+        "models.Variety": {
+            "type": "object",
+            "properties": {
+                "checkinable": {
+                    "type": "boolean"
+                },
+                "display_names": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "geofence_radius": {
+                    "description": "метры",
+                    "type": "integer"
+                },
+                "icon_key": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "require_verified_review": {
+                    "type": "boolean"
+                },
+                "review_criteria": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.VarietyBase": {
+            "type": "object",
+            "properties": {
+                "checkinable": {
+                    "type": "boolean"
+                },
+                "display_names": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "geofence_radius": {
+                    "description": "метры",
+                    "type": "integer"
+                },
+                "icon_key": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "require_verified_review": {
+                    "type": "boolean"
+                },
+                "review_criteria": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
         "swagger.Companion": {
             "type": "object",
             "properties": {
@@ -3289,23 +10552,49 @@ const docTemplate = `{
                 "city_id": {
                     "type": "integer"
                 },
+                "cursor": {
+                    "type": "string"
+                },
                 "district_id": {
                     "type": "integer"
                 },
+                "include_descendants": {
+                    "type": "boolean"
+                },
+                "min_rating": {
+                    "type": "number"
+                },
                 "name": {
                     "type": "string"
                 },
-                "pagination_page": {
+                "page_size": {
                     "type": "integer"
                 },
+                "sort_by": {
+                    "type": "string",
+                    "enum": [
+                        "rating",
+                        "reviews_count",
+                        "popular"
+                    ]
+                },
                 "tag_ids": {
                     "type": "array",
                     "items": {
                         "type": "integer"
                     }
                 },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
                 "variety": {
                     "type": "string"
+                },
+                "with_total": {
+                    "type": "boolean"
                 }
             }
         },
@@ -0,0 +1,220 @@
+package handlers
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"mth/internal/models"
+	"mth/internal/service"
+	"mth/pkg/customerr"
+	tracing "mth/pkg/trace"
+	"net/http"
+	"strconv"
+)
+
+type CityHandler struct {
+	cityService service.City
+	tracer      trace.Tracer
+}
+
+func InitCityHandler(cityService service.City, tracer trace.Tracer) CityHandler {
+	return CityHandler{
+		cityService: cityService,
+		tracer:      tracer,
+	}
+}
+
+// geoErrorStatus общий для городов и районов
+func geoErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, customerr.BadInput):
+		return http.StatusBadRequest
+	case errors.Is(err, customerr.CityNotFound), errors.Is(err, customerr.DistrictNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, customerr.CityInUse), errors.Is(err, customerr.DistrictInUse):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// Create @Summary Create city
+// @Tags city
+// @Accept  json
+// @Produce  json
+// @Param data body models.CityBase true "City create"
+// @Success 200 {object} int "Successfully created city with id"
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /city/create [post]
+func (h CityHandler) Create(c *gin.Context) {
+	ctx, span := h.tracer.Start(c.Request.Context(), CityCreate)
+	defer span.End()
+
+	var cityCreate models.CityBase
+
+	if err := c.ShouldBindJSON(&cityCreate); err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.BindType, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	span.AddEvent(tracing.CallToService)
+	id, err := h.cityService.Create(ctx, cityCreate)
+	if err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.ServiceError, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(geoErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, id)
+}
+
+// GetAll @Summary Get all cities
+// @Tags city
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} []models.City "Successfully"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /city/get_all [get]
+func (h CityHandler) GetAll(c *gin.Context) {
+	ctx, span := h.tracer.Start(c.Request.Context(), GetAllCities)
+	defer span.End()
+
+	span.AddEvent(tracing.CallToService)
+	cities, err := h.cityService.GetAll(ctx)
+	if err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.ServiceError, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(geoErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, cities)
+}
+
+// GetByID @Summary Get city by id
+// @Tags city
+// @Accept  json
+// @Produce  json
+// @Param id query int true "City id"
+// @Success 200 {object} models.City "Successfully"
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 404 {object} map[string]string "City not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /city/by_id [get]
+func (h CityHandler) GetByID(c *gin.Context) {
+	ctx, span := h.tracer.Start(c.Request.Context(), GetCityByID)
+	defer span.End()
+
+	id, err := strconv.Atoi(c.Query("id"))
+	if err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.Input, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	span.AddEvent(tracing.CallToService)
+	city, err := h.cityService.GetByID(ctx, id)
+	if err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.ServiceError, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(geoErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, city)
+}
+
+// Update @Summary Update city
+// @Tags city
+// @Accept  json
+// @Produce  json
+// @Param data body models.City true "City update"
+// @Success 200 "Successfully"
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 404 {object} map[string]string "City not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /city/update [put]
+func (h CityHandler) Update(c *gin.Context) {
+	ctx, span := h.tracer.Start(c.Request.Context(), CityUpdate)
+	defer span.End()
+
+	var cityUpd models.City
+
+	if err := c.ShouldBindJSON(&cityUpd); err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.BindType, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	span.AddEvent(tracing.CallToService)
+	err := h.cityService.Update(ctx, cityUpd)
+	if err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.ServiceError, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(geoErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// Delete @Summary Delete city, refused while it has districts, places or routes
+// @Tags city
+// @Accept  json
+// @Produce  json
+// @Param id query int true "City id"
+// @Success 200 "Successfully"
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 404 {object} map[string]string "City not found"
+// @Failure 409 {object} map[string]string "City is in use"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /city [delete]
+func (h CityHandler) Delete(c *gin.Context) {
+	ctx, span := h.tracer.Start(c.Request.Context(), CityDelete)
+	defer span.End()
+
+	id, err := strconv.Atoi(c.Query("id"))
+	if err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.Input, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	span.AddEvent(tracing.CallToService)
+	err = h.cityService.Delete(ctx, id)
+	if err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.ServiceError, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(geoErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
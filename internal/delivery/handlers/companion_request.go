@@ -0,0 +1,284 @@
+package handlers
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"mth/internal/models"
+	"mth/internal/service"
+	"mth/pkg/customerr"
+	tracing "mth/pkg/trace"
+	"net/http"
+)
+
+type CompanionRequestHandler struct {
+	requestService service.CompanionRequest
+	tracer         trace.Tracer
+}
+
+func InitCompanionRequestHandler(requestService service.CompanionRequest, tracer trace.Tracer) CompanionRequestHandler {
+	return CompanionRequestHandler{
+		requestService: requestService,
+		tracer:         tracer,
+	}
+}
+
+func companionRequestErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, customerr.BadInput), errors.Is(err, customerr.InvalidCursor):
+		return http.StatusBadRequest
+	case errors.Is(err, customerr.UserNotEntityOwner):
+		return http.StatusForbidden
+	case errors.Is(err, customerr.CompanionListingNotFound), errors.Is(err, customerr.CompanionRequestNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, customerr.CompanionRequestExists), errors.Is(err, customerr.CompanionRequestStatus):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// Create @Summary Request to join companion listing
+// @Tags companions
+// @Accept  json
+// @Produce  json
+// @Param data body models.CompanionRequestCreate true "Request"
+// @Success 200 {object} int "Successfully created request with id"
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 404 {object} map[string]string "Listing not found"
+// @Failure 409 {object} map[string]string "Active request already exists"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /companions/request [post]
+func (h CompanionRequestHandler) Create(c *gin.Context) {
+	ctx, span := h.tracer.Start(c.Request.Context(), CompanionRequestCreate)
+	defer span.End()
+
+	var request models.CompanionRequestCreate
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.BindType, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	span.AddEvent(tracing.CallToService)
+	id, err := h.requestService.Create(ctx, request)
+	if err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.ServiceError, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(companionRequestErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, id)
+}
+
+// Accept @Summary Accept companion request
+// @Tags companions
+// @Accept  json
+// @Produce  json
+// @Param data body models.CompanionRequestAction true "Request id and listing owner"
+// @Success 200
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 403 {object} map[string]string "User is not the listing owner"
+// @Failure 404 {object} map[string]string "Request not found"
+// @Failure 409 {object} map[string]string "Request status does not allow the action"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /companions/request/accept [put]
+func (h CompanionRequestHandler) Accept(c *gin.Context) {
+	ctx, span := h.tracer.Start(c.Request.Context(), CompanionRequestAccept)
+	defer span.End()
+
+	var action models.CompanionRequestAction
+
+	if err := c.ShouldBindJSON(&action); err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.BindType, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	span.AddEvent(tracing.CallToService)
+	err := h.requestService.Accept(ctx, action)
+	if err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.ServiceError, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(companionRequestErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// Decline @Summary Decline companion request
+// @Tags companions
+// @Accept  json
+// @Produce  json
+// @Param data body models.CompanionRequestAction true "Request id and listing owner"
+// @Success 200
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 403 {object} map[string]string "User is not the listing owner"
+// @Failure 404 {object} map[string]string "Request not found"
+// @Failure 409 {object} map[string]string "Request status does not allow the action"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /companions/request/decline [put]
+func (h CompanionRequestHandler) Decline(c *gin.Context) {
+	ctx, span := h.tracer.Start(c.Request.Context(), CompanionRequestDecline)
+	defer span.End()
+
+	var action models.CompanionRequestAction
+
+	if err := c.ShouldBindJSON(&action); err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.BindType, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	span.AddEvent(tracing.CallToService)
+	err := h.requestService.Decline(ctx, action)
+	if err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.ServiceError, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(companionRequestErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// Cancel @Summary Cancel own companion request
+// @Tags companions
+// @Accept  json
+// @Produce  json
+// @Param data body models.CompanionRequestAction true "Request id and request author"
+// @Success 200
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 403 {object} map[string]string "User is not the request author"
+// @Failure 404 {object} map[string]string "Request not found"
+// @Failure 409 {object} map[string]string "Request status does not allow the action"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /companions/request/cancel [put]
+func (h CompanionRequestHandler) Cancel(c *gin.Context) {
+	ctx, span := h.tracer.Start(c.Request.Context(), CompanionRequestCancel)
+	defer span.End()
+
+	var action models.CompanionRequestAction
+
+	if err := c.ShouldBindJSON(&action); err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.BindType, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	span.AddEvent(tracing.CallToService)
+	err := h.requestService.Cancel(ctx, action)
+	if err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.ServiceError, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(companionRequestErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// GetIncoming @Summary Get requests to user listings, contacts only for accepted
+// @Tags companions
+// @Accept  json
+// @Produce  json
+// @Param data query models.CompanionRequestsRequest true "User, status and page"
+// @Param lang query string false "Response language, takes precedence over Accept-Language"
+// @Param Accept-Language header string false "Preferred response languages"
+// @Success 200 {object} models.Page[models.CompanionRequest] "Successfully"
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /companions/requests/incoming [get]
+func (h CompanionRequestHandler) GetIncoming(c *gin.Context) {
+	ctx, span := h.tracer.Start(c.Request.Context(), CompanionRequestsIncoming)
+	defer span.End()
+
+	var request models.CompanionRequestsRequest
+
+	if err := c.ShouldBindQuery(&request); err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.BindType, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	span.AddEvent(tracing.CallToService)
+	page, err := h.requestService.GetIncoming(ctx, request)
+	if err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.ServiceError, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(companionRequestErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+// GetOutgoing @Summary Get requests sent by user, contacts only for accepted
+// @Tags companions
+// @Accept  json
+// @Produce  json
+// @Param data query models.CompanionRequestsRequest true "User, status and page"
+// @Param lang query string false "Response language, takes precedence over Accept-Language"
+// @Param Accept-Language header string false "Preferred response languages"
+// @Success 200 {object} models.Page[models.CompanionRequest] "Successfully"
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /companions/requests/outgoing [get]
+func (h CompanionRequestHandler) GetOutgoing(c *gin.Context) {
+	ctx, span := h.tracer.Start(c.Request.Context(), CompanionRequestsOutgoing)
+	defer span.End()
+
+	var request models.CompanionRequestsRequest
+
+	if err := c.ShouldBindQuery(&request); err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.BindType, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	span.AddEvent(tracing.CallToService)
+	page, err := h.requestService.GetOutgoing(ctx, request)
+	if err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.ServiceError, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(companionRequestErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"github.com/gin-gonic/gin"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
@@ -8,6 +9,7 @@ import (
 	"mth/internal/models"
 	"mth/internal/models/swagger"
 	"mth/internal/service"
+	"mth/pkg/customerr"
 	tracing "mth/pkg/trace"
 	"net/http"
 	"strconv"
@@ -111,6 +113,8 @@ func (ch CompanionsHandler) CreateCompanionRoute(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param id query int true "user id"
+// @Param lang query string false "Response language, takes precedence over Accept-Language"
+// @Param Accept-Language header string false "Preferred response languages"
 // @Success 200 {object} swagger.Companion "success"
 // @Failure 400 {object} map[string]string "Invalid input data"
 // @Failure 500 {object} map[string]string "Internal server error"
@@ -154,8 +158,10 @@ func (ch CompanionsHandler) GetByUser(c *gin.Context) {
 // @Tags companions
 // @Accept json
 // @Produce json
-// @Param data body models.CompanionsFilters true "user id"
-// @Success 200 {object} []models.CompanionsPlace "success"
+// @Param data body models.CompanionsFilters true "filters with cursor"
+// @Param lang query string false "Response language, takes precedence over Accept-Language"
+// @Param Accept-Language header string false "Preferred response languages"
+// @Success 200 {object} models.Page[models.CompanionsPlace] "success"
 // @Failure 400 {object} map[string]string "Invalid input data"
 // @Failure 500 {object} map[string]string "Internal server error"
 // @Router /companions/get_by_place [put]
@@ -181,6 +187,12 @@ func (ch CompanionsHandler) GetCompanionsPlace(c *gin.Context) {
 			attribute.String(tracing.ServiceError, err.Error())),
 		)
 		span.SetStatus(codes.Error, err.Error())
+
+		if errors.Is(err, customerr.InvalidCursor) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -193,8 +205,10 @@ func (ch CompanionsHandler) GetCompanionsPlace(c *gin.Context) {
 // @Tags companions
 // @Accept json
 // @Produce json
-// @Param data body models.CompanionsFilters true "user id"
-// @Success 200 {object} []models.CompanionsPlace "success"
+// @Param data body models.CompanionsFilters true "filters with cursor"
+// @Param lang query string false "Response language, takes precedence over Accept-Language"
+// @Param Accept-Language header string false "Preferred response languages"
+// @Success 200 {object} models.Page[models.CompanionsRoute] "success"
 // @Failure 400 {object} map[string]string "Invalid input data"
 // @Failure 500 {object} map[string]string "Internal server error"
 // @Router /companions/get_by_route [put]
@@ -220,6 +234,12 @@ func (ch CompanionsHandler) GetCompanionsRoute(c *gin.Context) {
 			attribute.String(tracing.ServiceError, err.Error())),
 		)
 		span.SetStatus(codes.Error, err.Error())
+
+		if errors.Is(err, customerr.InvalidCursor) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -1,8 +1,17 @@
 package handlers
 
 const (
-	CreateTag  = "Create tag"
-	GetAllTags = "Get all tags"
+	CreateTag         = "Create tag"
+	GetAllTags        = "Get all tags"
+	SetTagHierarchy   = "Set tag hierarchy"
+	AddTagSynonym     = "Add tag synonym"
+	DeleteTagSynonym  = "Delete tag synonym"
+	CreateTagCategory = "Create tag category"
+	GetTagCategories  = "Get tag categories"
+	DeleteTagCategory = "Delete tag category"
+	RenameTag         = "Rename tag"
+	DeleteTag         = "Delete tag"
+	MergeTags         = "Merge tags"
 
 	CreateRouteReview = "Create route review"
 	CreatePlaceReview = "Create place review"
@@ -12,26 +21,111 @@ const (
 	UpdateOnPlace     = "Update on place"
 	UpdateOnRoute     = "Update on route"
 
+	DeleteReview             = "Delete review"
+	FlagReview               = "Flag review"
+	ModerateReview           = "Moderate review"
+	GetReviewModerationQueue = "Get review moderation queue"
+	VoteReview               = "Vote review"
+	DeleteReviewVote         = "Delete review vote"
+	SetEntityManager         = "Set entity manager"
+	DeleteEntityManager      = "Delete entity manager"
+	GetEntityManagers        = "Get entity managers"
+	UpsertReviewReply        = "Upsert review reply"
+	DeleteReviewReply        = "Delete review reply"
+	GetReviewReplyHistory    = "Get review reply history"
+
 	PlaceCreate             = "Create place"
 	GetPlaceById            = "Get place by id"
 	GetAllPlacesWithFilters = "Get all places with filters"
+	PlaceUpdate             = "Update place"
+	PlaceDelete             = "Delete place"
+	PlaceSetArchived        = "Set place archived"
+	PlaceImport             = "Import places"
+	GetPlacesGeoJSON        = "Get places GeoJSON"
+	GetTrendingPlaces       = "Get trending places"
+	DetectPlaceDuplicates   = "Detect place duplicates"
+	GetPlaceDuplicates      = "Get place duplicates"
+	DismissPlaceDuplicate   = "Dismiss place duplicate"
+	MergePlaces             = "Merge places"
+	GetPlaceMerges          = "Get place merges"
 
 	GetDistrictByCityID = "Get district by city id"
+	DistrictCreate      = "Create district"
+	GetDistrictByID     = "Get district by id"
+	DistrictUpdate      = "Update district"
+	DistrictDelete      = "Delete district"
+	GetDistrictPolygons = "Get district polygons"
+	ReconcileDistricts  = "Reconcile place districts"
+
+	CityCreate   = "Create city"
+	GetAllCities = "Get all cities"
+	GetCityByID  = "Get city by id"
+	CityUpdate   = "Update city"
+	CityDelete   = "Delete city"
+
+	GetDistrictStats = "Get district statistics"
+	GetCityStats     = "Get city statistics"
+
+	VarietyCreate    = "Create variety"
+	GetAllVarieties  = "Get all varieties"
+	GetVarietyByName = "Get variety by name"
+	VarietyUpdate    = "Update variety"
+	VarietyDelete    = "Delete variety"
 
 	RouteCreate     = "Create route"
 	GetRouteByID    = "Get route by id"
 	GetRoutesByPage = "Get routes by page"
+	GetRouteGeoJSON = "Get route GeoJSON"
+
+	NoteCreate           = "Create note"
+	GetNoteByID          = "Get note by id"
+	GetNoteByUserID      = "Get note by user id"
+	GetNoteByIDs         = "Get note by user and place ids"
+	NoteCreateForTarget  = "Create note for target"
+	GetNotesByTarget     = "Get notes by target"
+	NoteUpdateByID       = "Update note by id"
+	NoteDelete           = "Delete note"
+	GetNotesFeed         = "Get public notes feed"
+	NoteCreateShareToken = "Create note share token"
+	GetNoteShareTokens   = "Get note share tokens"
+	NoteRevokeShareToken = "Revoke note share token"
+	GetNoteByShareToken  = "Get note by share token"
 
-	NoteCreate      = "Create note"
-	GetNoteByID     = "Get note by id"
-	GetNoteByUserID = "Get note by user id"
-	GetNoteByIDs    = "Get note by user and place ids"
+	TripDiaryExport = "Export trip diary"
 
 	CompanionsCreate = "Create companion"
 
+	CompanionRequestCreate    = "Create companion request"
+	CompanionRequestAccept    = "Accept companion request"
+	CompanionRequestDecline   = "Decline companion request"
+	CompanionRequestCancel    = "Cancel companion request"
+	CompanionRequestsIncoming = "Get incoming companion requests"
+	CompanionRequestsOutgoing = "Get outgoing companion requests"
+
 	LikePlace     = "Like place"
 	LikeRoute     = "Like route"
 	GetLiked      = "Get liked"
 	DeleteOnPlace = "Delete on place"
 	DeleteOnRoute = "Delete on route"
+
+	MediaUpload         = "Upload media"
+	MediaGetFile        = "Get media file"
+	MediaGetByID        = "Get media by id"
+	MediaGetByEntity    = "Get media by entity"
+	MediaAttach         = "Attach media"
+	MediaDetach         = "Detach media"
+	MediaChangePosition = "Change media position"
+
+	UpsertTranslation       = "Upsert translation"
+	DeleteTranslation       = "Delete translation"
+	GetTranslationsByEntity = "Get translations by entity"
+	GetMissingTranslations  = "Get missing translations"
+
+	GetNotifications      = "Get notifications"
+	MarkNotificationsRead = "Mark notifications read"
+
+	GetContentReports    = "Get content reports"
+	ResolveContentReport = "Resolve content report"
 )
+
+const geoJSONContentType = "application/geo+json"
@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"mth/internal/models"
+	"mth/internal/service"
+	"mth/pkg/customerr"
+	tracing "mth/pkg/trace"
+	"net/http"
+)
+
+type ContentReportHandler struct {
+	contentReportService service.ContentReport
+	tracer               trace.Tracer
+}
+
+func InitContentReportHandler(contentReportService service.ContentReport, tracer trace.Tracer) ContentReportHandler {
+	return ContentReportHandler{
+		contentReportService: contentReportService,
+		tracer:               tracer,
+	}
+}
+
+func contentReportErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, customerr.InvalidCursor):
+		return http.StatusBadRequest
+	case errors.Is(err, customerr.NotModerator):
+		return http.StatusForbidden
+	case errors.Is(err, customerr.ContentReportNotFound):
+		return http.StatusNotFound
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// GetOpen @Summary Get notes and user profiles sent to moderation by the content filter, oldest first
+// @Tags moderation
+// @Accept  json
+// @Produce  json
+// @Param moderator_id query int true "Moderator id"
+// @Param cursor query string false "next_cursor from the previous page, empty for the first page"
+// @Param page_size query int false "Page size, capped by MAX_PAGE_SIZE"
+// @Param with_total query bool false "Count total number of reports"
+// @Success 200 {object} models.Page[models.ContentReport] "Successfully"
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 403 {object} map[string]string "User is not a moderator"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /moderation/content [get]
+func (h ContentReportHandler) GetOpen(c *gin.Context) {
+	ctx, span := h.tracer.Start(c.Request.Context(), GetContentReports)
+	defer span.End()
+
+	var request models.ContentReportsRequest
+
+	if err := c.ShouldBindQuery(&request); err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.BindType, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	span.AddEvent(tracing.CallToService)
+	reports, err := h.contentReportService.GetOpen(ctx, request)
+	if err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.ServiceError, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(contentReportErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, reports)
+}
+
+// Resolve @Summary Close content report after moderation
+// @Tags moderation
+// @Accept  json
+// @Produce  json
+// @Param data body models.ContentReportResolve true "Report"
+// @Success 200 {object} string "Successfully"
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 403 {object} map[string]string "User is not a moderator"
+// @Failure 404 {object} map[string]string "Open report not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /moderation/content/resolve [put]
+func (h ContentReportHandler) Resolve(c *gin.Context) {
+	ctx, span := h.tracer.Start(c.Request.Context(), ResolveContentReport)
+	defer span.End()
+
+	var resolve models.ContentReportResolve
+
+	if err := c.ShouldBindJSON(&resolve); err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.BindType, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	span.AddEvent(tracing.CallToService)
+	if err := h.contentReportService.Resolve(ctx, resolve); err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.ServiceError, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(contentReportErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, "Successfully!")
+}
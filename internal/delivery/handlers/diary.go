@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"mth/internal/models"
+	"mth/internal/service"
+	"mth/pkg/customerr"
+	tracing "mth/pkg/trace"
+	"net/http"
+)
+
+type DiaryHandler struct {
+	diaryService service.Diary
+	tracer       trace.Tracer
+}
+
+func InitDiaryHandler(diaryService service.Diary, tracer trace.Tracer) DiaryHandler {
+	return DiaryHandler{
+		diaryService: diaryService,
+		tracer:       tracer,
+	}
+}
+
+func diaryErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, customerr.BadInput):
+		return http.StatusBadRequest
+	case errors.Is(err, customerr.UserNotEntityOwner):
+		return http.StatusForbidden
+	case errors.Is(err, customerr.TripNotFound):
+		return http.StatusNotFound
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// Export @Summary Export trip diary with places, routes, check-ins, notes, reviews and photos
+// @Tags trip
+// @Produce  octet-stream
+// @Param data query models.TripDiaryRequest true "Trip and format"
+// @Param lang query string false "Response language, takes precedence over Accept-Language"
+// @Param Accept-Language header string false "Preferred response languages"
+// @Success 200 {file} file "Diary in Markdown, self-contained HTML or EPUB"
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 403 {object} map[string]string "Trip belongs to another user"
+// @Failure 404 {object} map[string]string "Trip not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /trip/diary [get]
+func (d DiaryHandler) Export(c *gin.Context) {
+	ctx, span := d.tracer.Start(c.Request.Context(), TripDiaryExport)
+	defer span.End()
+
+	var request models.TripDiaryRequest
+
+	if err := c.ShouldBindQuery(&request); err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.BindType, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	span.AddEvent(tracing.CallToService)
+	file, err := d.diaryService.Export(ctx, request)
+	if err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.ServiceError, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(diaryErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", file.Name))
+	c.Data(http.StatusOK, file.ContentType, file.Data)
+}
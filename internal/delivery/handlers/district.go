@@ -5,6 +5,7 @@ import (
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/trace"
+	"mth/internal/models"
 	"mth/internal/service"
 	tracing "mth/pkg/trace"
 	"net/http"
@@ -23,7 +24,7 @@ func InitDistrictHandler(DistrictService service.District, tracer trace.Tracer)
 	}
 }
 
-// GetByID @Summary Get districts by city id
+// GetByCityID @Summary Get districts by city id
 // @Tags district
 // @Accept  json
 // @Produce  json
@@ -32,12 +33,11 @@ func InitDistrictHandler(DistrictService service.District, tracer trace.Tracer)
 // @Failure 400 {object} map[string]string "Invalid input"
 // @Failure 500 {object} map[string]string "Internal server error"
 // @Router /district/by_city_id [get]
-func (r DistrictHandler) GetByID(c *gin.Context) {
+func (r DistrictHandler) GetByCityID(c *gin.Context) {
 	ctx, span := r.tracer.Start(c.Request.Context(), GetDistrictByCityID)
 	defer span.End()
 
-	idRaw := c.Query("id")
-	id, err := strconv.Atoi(idRaw)
+	id, err := strconv.Atoi(c.Query("id"))
 	if err != nil {
 		span.RecordError(err, trace.WithAttributes(
 			attribute.String(tracing.Input, err.Error())),
@@ -48,15 +48,246 @@ func (r DistrictHandler) GetByID(c *gin.Context) {
 	}
 
 	span.AddEvent(tracing.CallToService)
-	districts, err := r.DistrictService.GetByID(ctx, id)
+	districts, err := r.DistrictService.GetByCityID(ctx, id)
 	if err != nil {
 		span.RecordError(err, trace.WithAttributes(
 			attribute.String(tracing.ServiceError, err.Error())),
 		)
 		span.SetStatus(codes.Error, err.Error())
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(geoErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
 	c.JSON(http.StatusOK, districts)
 }
+
+// Create @Summary Create district in existing city
+// @Tags district
+// @Accept  json
+// @Produce  json
+// @Param data body models.DistrictBase true "District create"
+// @Success 200 {object} int "Successfully created district with id"
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 404 {object} map[string]string "City not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /district/create [post]
+func (r DistrictHandler) Create(c *gin.Context) {
+	ctx, span := r.tracer.Start(c.Request.Context(), DistrictCreate)
+	defer span.End()
+
+	var districtCreate models.DistrictBase
+
+	if err := c.ShouldBindJSON(&districtCreate); err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.BindType, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	span.AddEvent(tracing.CallToService)
+	id, err := r.DistrictService.Create(ctx, districtCreate)
+	if err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.ServiceError, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(geoErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, id)
+}
+
+// GetByID @Summary Get district by id
+// @Tags district
+// @Accept  json
+// @Produce  json
+// @Param id query int true "District id"
+// @Success 200 {object} models.District "Successfully"
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 404 {object} map[string]string "District not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /district/by_id [get]
+func (r DistrictHandler) GetByID(c *gin.Context) {
+	ctx, span := r.tracer.Start(c.Request.Context(), GetDistrictByID)
+	defer span.End()
+
+	id, err := strconv.Atoi(c.Query("id"))
+	if err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.Input, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	span.AddEvent(tracing.CallToService)
+	district, err := r.DistrictService.GetByID(ctx, id)
+	if err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.ServiceError, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(geoErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, district)
+}
+
+// Update @Summary Update district name and properties
+// @Tags district
+// @Accept  json
+// @Produce  json
+// @Param data body models.DistrictUpdate true "District update"
+// @Success 200 "Successfully"
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 404 {object} map[string]string "District not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /district/update [put]
+func (r DistrictHandler) Update(c *gin.Context) {
+	ctx, span := r.tracer.Start(c.Request.Context(), DistrictUpdate)
+	defer span.End()
+
+	var districtUpd models.DistrictUpdate
+
+	if err := c.ShouldBindJSON(&districtUpd); err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.BindType, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	span.AddEvent(tracing.CallToService)
+	err := r.DistrictService.Update(ctx, districtUpd)
+	if err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.ServiceError, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(geoErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// Delete @Summary Delete district, refused while it has places
+// @Tags district
+// @Accept  json
+// @Produce  json
+// @Param id query int true "District id"
+// @Success 200 "Successfully"
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 404 {object} map[string]string "District not found"
+// @Failure 409 {object} map[string]string "District has places"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /district [delete]
+func (r DistrictHandler) Delete(c *gin.Context) {
+	ctx, span := r.tracer.Start(c.Request.Context(), DistrictDelete)
+	defer span.End()
+
+	id, err := strconv.Atoi(c.Query("id"))
+	if err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.Input, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	span.AddEvent(tracing.CallToService)
+	err = r.DistrictService.Delete(ctx, id)
+	if err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.ServiceError, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(geoErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// GetPolygons @Summary Get district boundaries as GeoJSON for map overlays
+// @Tags district
+// @Produce  application/geo+json
+// @Param city_id query int false "City id, all cities if omitted"
+// @Success 200 {object} geojson.FeatureCollection "Successfully"
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /district/polygons [get]
+func (r DistrictHandler) GetPolygons(c *gin.Context) {
+	ctx, span := r.tracer.Start(c.Request.Context(), GetDistrictPolygons)
+	defer span.End()
+
+	cityID, err := optionalQueryInt(c, "city_id")
+	if err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.Input, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	span.AddEvent(tracing.CallToService)
+	collection, err := r.DistrictService.GetPolygons(ctx, cityID)
+	if err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.ServiceError, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(geoErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Content-Type", geoJSONContentType)
+	c.JSON(http.StatusOK, collection)
+}
+
+// Reconcile @Summary Report places whose district disagrees with their coordinates, optionally fix them
+// @Tags district
+// @Produce  json
+// @Param city_id query int false "City id, all cities if omitted"
+// @Param apply query bool false "Move places into the district computed from coordinates"
+// @Success 200 {object} models.DistrictReconciliation "Successfully"
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /district/reconcile [post]
+func (r DistrictHandler) Reconcile(c *gin.Context) {
+	ctx, span := r.tracer.Start(c.Request.Context(), ReconcileDistricts)
+	defer span.End()
+
+	cityID, err := optionalQueryInt(c, "city_id")
+	if err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.Input, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	apply := c.Query("apply") == "true"
+
+	span.AddEvent(tracing.CallToService)
+	report, err := r.DistrictService.Reconcile(ctx, cityID, apply)
+	if err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.ServiceError, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(geoErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,214 @@
+package handlers
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"mth/internal/models"
+	"mth/internal/service"
+	"mth/pkg/customerr"
+	tracing "mth/pkg/trace"
+	"net/http"
+	"strconv"
+)
+
+type PlaceDuplicateHandler struct {
+	duplicateService service.PlaceDuplicate
+	tracer           trace.Tracer
+}
+
+func InitPlaceDuplicateHandler(duplicateService service.PlaceDuplicate, tracer trace.Tracer) PlaceDuplicateHandler {
+	return PlaceDuplicateHandler{
+		duplicateService: duplicateService,
+		tracer:           tracer,
+	}
+}
+
+// Detect @Summary Run place duplicates detection now instead of waiting for the scheduled job
+// @Tags place
+// @Produce  json
+// @Success 200 {object} int "Number of found pairs"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /place/duplicates/detect [post]
+func (d PlaceDuplicateHandler) Detect(c *gin.Context) {
+	ctx, span := d.tracer.Start(c.Request.Context(), DetectPlaceDuplicates)
+	defer span.End()
+
+	span.AddEvent(tracing.CallToService)
+	found, err := d.duplicateService.Detect(ctx)
+	if err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.ServiceError, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, found)
+}
+
+// GetPending @Summary Get likely duplicate places waiting for review
+// @Tags place
+// @Produce  json
+// @Param cursor query string false "Cursor from previous page"
+// @Param page_size query int false "Page size"
+// @Param with_total query bool false "Count total number of pairs"
+// @Success 200 {object} models.Page[models.PlaceDuplicate] "Successfully"
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /place/duplicates [get]
+func (d PlaceDuplicateHandler) GetPending(c *gin.Context) {
+	ctx, span := d.tracer.Start(c.Request.Context(), GetPlaceDuplicates)
+	defer span.End()
+
+	var page models.PageRequest
+
+	if err := c.ShouldBindQuery(&page); err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.BindType, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	span.AddEvent(tracing.CallToService)
+	duplicates, err := d.duplicateService.GetPending(ctx, page)
+	if err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.ServiceError, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+
+		if errors.Is(err, customerr.InvalidCursor) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, duplicates)
+}
+
+// Dismiss @Summary Mark pair as not duplicates, detection will not suggest it again
+// @Tags place
+// @Produce  json
+// @Param id query int true "Duplicate pair id"
+// @Success 200 "success"
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /place/duplicates/dismiss [put]
+func (d PlaceDuplicateHandler) Dismiss(c *gin.Context) {
+	ctx, span := d.tracer.Start(c.Request.Context(), DismissPlaceDuplicate)
+	defer span.End()
+
+	id, err := strconv.Atoi(c.Query("id"))
+	if err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.Input, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	span.AddEvent(tracing.CallToService)
+	if err = d.duplicateService.Dismiss(ctx, id); err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.ServiceError, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// Merge @Summary Merge duplicate place into survivor: tags, reviews, notes, check-ins, favourites, route stops, trip entries and companions are moved, duplicate is deleted
+// @Tags place
+// @Accept  json
+// @Produce  json
+// @Param data body models.PlaceMergeRequest true "Survivor and duplicate ids"
+// @Success 200 {object} models.PlaceMerge "Audit record of the merge"
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 404 {object} map[string]string "Place not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /place/merge [post]
+func (d PlaceDuplicateHandler) Merge(c *gin.Context) {
+	ctx, span := d.tracer.Start(c.Request.Context(), MergePlaces)
+	defer span.End()
+
+	var request models.PlaceMergeRequest
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.BindType, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	span.AddEvent(tracing.CallToService)
+	merge, err := d.duplicateService.Merge(ctx, request)
+	if err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.ServiceError, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+
+		switch {
+		case errors.Is(err, customerr.MergeSamePlace):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, customerr.PlaceNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, merge)
+}
+
+// GetMerges @Summary Get audit records of places merged into the given one
+// @Tags place
+// @Produce  json
+// @Param survivor_id query int true "Survivor place id"
+// @Success 200 {object} []models.PlaceMerge "Successfully"
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /place/merges [get]
+func (d PlaceDuplicateHandler) GetMerges(c *gin.Context) {
+	ctx, span := d.tracer.Start(c.Request.Context(), GetPlaceMerges)
+	defer span.End()
+
+	survivorID, err := strconv.Atoi(c.Query("survivor_id"))
+	if err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.Input, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	span.AddEvent(tracing.CallToService)
+	merges, err := d.duplicateService.GetMerges(ctx, survivorID)
+	if err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.ServiceError, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, merges)
+}
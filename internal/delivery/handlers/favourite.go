@@ -106,6 +106,8 @@ func (r FavouriteHandler) LikeRoute(c *gin.Context) {
 // @Accept  json
 // @Produce  json
 // @Param id query int true "UserID"
+// @Param lang query string false "Response language, takes precedence over Accept-Language"
+// @Param Accept-Language header string false "Preferred response languages"
 // @Success 200 {object} string "Successfully!"
 // @Failure 400 {object} map[string]string "Invalid input"
 // @Failure 500 {object} map[string]string "Internal server error"
@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"mth/internal/models"
+	"mth/internal/service"
+	"mth/pkg/customerr"
+	tracing "mth/pkg/trace"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+type ImportHandler struct {
+	importService service.Import
+	tracer        trace.Tracer
+}
+
+func InitImportHandler(importService service.Import, tracer trace.Tracer) ImportHandler {
+	return ImportHandler{
+		importService: importService,
+		tracer:        tracer,
+	}
+}
+
+// importFormat формат из параметра, иначе по расширению файла
+func importFormat(format, filename string) string {
+	if format != "" {
+		return strings.ToLower(format)
+	}
+
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".csv":
+		return models.ImportFormatCSV
+	case ".geojson", ".json":
+		return models.ImportFormatGeoJSON
+	}
+
+	return ""
+}
+
+// ImportPlaces @Summary Bulk import places from CSV or GeoJSON FeatureCollection
+// @Description CSV columns: name, variety, city, district, tags (separated by ;), lat, lon, other columns go to properties.
+// @Description GeoJSON: Point geometry, the same keys in feature properties, tags may be an array.
+// @Description Places are upserted by name in one transaction; nothing is applied on dry run or when any row is invalid.
+// @Tags place
+// @Accept  multipart/form-data
+// @Produce  json
+// @Param file formData file true "CSV or GeoJSON file"
+// @Param format query string false "csv or geojson, by default taken from file extension"
+// @Param dry_run query bool false "Only validate"
+// @Success 200 {object} models.ImportReport "Import report"
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 413 {object} map[string]string "File is too large"
+// @Failure 422 {object} models.ImportReport "Some rows are invalid, nothing applied"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /place/import [post]
+func (i ImportHandler) ImportPlaces(c *gin.Context) {
+	ctx, span := i.tracer.Start(c.Request.Context(), PlaceImport)
+	defer span.End()
+
+	dryRun := false
+	if dryRunRaw := c.Query("dry_run"); dryRunRaw != "" {
+		var err error
+		dryRun, err = strconv.ParseBool(dryRunRaw)
+		if err != nil {
+			span.RecordError(err, trace.WithAttributes(
+				attribute.String(tracing.Input, err.Error())),
+			)
+			span.SetStatus(codes.Error, err.Error())
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.BindType, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.BindType, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	defer file.Close()
+
+	span.AddEvent(tracing.CallToService)
+	report, err := i.importService.ImportPlaces(ctx, importFormat(c.Query("format"), fileHeader.Filename), file, dryRun)
+	if err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.ServiceError, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+
+		switch {
+		case errors.Is(err, customerr.ImportTooLarge):
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": err.Error()})
+		case errors.Is(err, customerr.ImportBadFile), errors.Is(err, customerr.BadInput):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	if len(report.Errors) > 0 {
+		c.JSON(http.StatusUnprocessableEntity, report)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
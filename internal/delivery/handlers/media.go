@@ -0,0 +1,285 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"mime"
+	"mth/internal/models"
+	"mth/internal/service"
+	"mth/pkg/customerr"
+	tracing "mth/pkg/trace"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+type MediaHandler struct {
+	mediaService service.Media
+	tracer       trace.Tracer
+}
+
+func InitMediaHandler(mediaService service.Media, tracer trace.Tracer) MediaHandler {
+	return MediaHandler{
+		mediaService: mediaService,
+		tracer:       tracer,
+	}
+}
+
+func mediaErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, customerr.MediaTooLarge):
+		return http.StatusRequestEntityTooLarge
+	case errors.Is(err, customerr.MediaUnsupportedType):
+		return http.StatusUnsupportedMediaType
+	case errors.Is(err, customerr.UserNotEntityOwner):
+		return http.StatusForbidden
+	case errors.Is(err, customerr.BadInput):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// Upload @Summary Upload image (jpeg, png, gif), thumbnail is generated automatically
+// @Tags media
+// @Accept  multipart/form-data
+// @Produce  json
+// @Param file formData file true "Image"
+// @Param user_id formData int true "Owner user id"
+// @Success 200 {object} models.Media "Successfully uploaded"
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 413 {object} map[string]string "File is too large"
+// @Failure 415 {object} map[string]string "Unsupported file type"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /media/upload [post]
+func (m MediaHandler) Upload(c *gin.Context) {
+	ctx, span := m.tracer.Start(c.Request.Context(), MediaUpload)
+	defer span.End()
+
+	userID, err := strconv.Atoi(c.PostForm("user_id"))
+	if err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.Input, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.BindType, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.BindType, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	defer file.Close()
+
+	span.AddEvent(tracing.CallToService)
+	media, err := m.mediaService.Upload(ctx, userID, file)
+	if err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.ServiceError, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(mediaErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, media)
+}
+
+// GetFile @Summary Get media file from local storage
+// @Tags media
+// @Produce  octet-stream
+// @Param key path string true "Storage key"
+// @Success 200 {file} file "File"
+// @Failure 404 {object} map[string]string "Not found"
+// @Router /media/file/{key} [get]
+func (m MediaHandler) GetFile(c *gin.Context) {
+	ctx, span := m.tracer.Start(c.Request.Context(), MediaGetFile)
+	defer span.End()
+
+	key := strings.TrimPrefix(c.Param("key"), "/")
+
+	span.AddEvent(tracing.CallToService)
+	file, err := m.mediaService.Open(ctx, key)
+	if err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.ServiceError, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	defer file.Close()
+
+	contentType := mime.TypeByExtension(filepath.Ext(key))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	c.DataFromReader(http.StatusOK, -1, contentType, file, nil)
+}
+
+// GetByID @Summary Get media by id
+// @Tags media
+// @Produce  json
+// @Param id query int true "Media id"
+// @Success 200 {object} models.Media "Successfully"
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /media/by_id [get]
+func (m MediaHandler) GetByID(c *gin.Context) {
+	ctx, span := m.tracer.Start(c.Request.Context(), MediaGetByID)
+	defer span.End()
+
+	id, err := strconv.Atoi(c.Query("id"))
+	if err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.Input, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	span.AddEvent(tracing.CallToService)
+	media, err := m.mediaService.GetByID(ctx, id)
+	if err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.ServiceError, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, media)
+}
+
+// GetByEntity @Summary Get media attached to entity ordered by position
+// @Tags media
+// @Produce  json
+// @Param entity_type query string true "place, route, place_review, route_review or note"
+// @Param entity_id query int true "Entity id"
+// @Success 200 {object} []models.AttachedMedia "Successfully"
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /media/by_entity [get]
+func (m MediaHandler) GetByEntity(c *gin.Context) {
+	ctx, span := m.tracer.Start(c.Request.Context(), MediaGetByEntity)
+	defer span.End()
+
+	entityType := c.Query("entity_type")
+	entityID, err := strconv.Atoi(c.Query("entity_id"))
+	if err != nil || entityType == "" {
+		err := customerr.BadInput
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.Input, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	span.AddEvent(tracing.CallToService)
+	attached, err := m.mediaService.GetByEntity(ctx, entityType, entityID)
+	if err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.ServiceError, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, attached)
+}
+
+func (m MediaHandler) changeAttachment(c *gin.Context, spanName string, change func(ctx context.Context, attachment models.MediaAttachment) error) {
+	ctx, span := m.tracer.Start(c.Request.Context(), spanName)
+	defer span.End()
+
+	var attachment models.MediaAttachment
+
+	if err := c.ShouldBindJSON(&attachment); err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.BindType, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	span.AddEvent(tracing.CallToService)
+	if err := change(ctx, attachment); err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.ServiceError, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(mediaErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// Attach @Summary Attach own media to own review or note, or to place or route managed by user (moderators - to any)
+// @Tags media
+// @Accept  json
+// @Produce  json
+// @Param data body models.MediaAttachment true "Attachment"
+// @Success 200 "success"
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 403 {object} map[string]string "User is not owner"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /media/attach [post]
+func (m MediaHandler) Attach(c *gin.Context) {
+	m.changeAttachment(c, MediaAttach, m.mediaService.Attach)
+}
+
+// Detach @Summary Detach media from entity
+// @Tags media
+// @Accept  json
+// @Produce  json
+// @Param data body models.MediaAttachment true "Attachment, position is ignored"
+// @Success 200 "success"
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 403 {object} map[string]string "User is not owner"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /media/attach [delete]
+func (m MediaHandler) Detach(c *gin.Context) {
+	m.changeAttachment(c, MediaDetach, m.mediaService.Detach)
+}
+
+// ChangePosition @Summary Change position of attached media
+// @Tags media
+// @Accept  json
+// @Produce  json
+// @Param data body models.MediaAttachment true "Attachment with new position"
+// @Success 200 "success"
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 403 {object} map[string]string "User is not owner"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /media/attach/position [put]
+func (m MediaHandler) ChangePosition(c *gin.Context) {
+	m.changeAttachment(c, MediaChangePosition, m.mediaService.ChangePosition)
+}
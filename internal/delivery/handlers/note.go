@@ -1,12 +1,14 @@
 package handlers
 
 import (
+	"errors"
 	"github.com/gin-gonic/gin"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/trace"
 	"mth/internal/models"
 	"mth/internal/service"
+	"mth/pkg/customerr"
 	tracing "mth/pkg/trace"
 	"net/http"
 	"strconv"
@@ -24,6 +26,26 @@ func InitNoteHandler(NoteService service.Note, tracer trace.Tracer) NoteHandler
 	}
 }
 
+func noteErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, customerr.ContentRejected):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, customerr.BadInput):
+		return http.StatusBadRequest
+	case errors.Is(err, customerr.UserNotEntityOwner):
+		return http.StatusForbidden
+	case errors.Is(err, customerr.NoteNotFound), errors.Is(err, customerr.NoteTargetNotFound),
+		errors.Is(err, customerr.NoteShareTokenNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, customerr.NoteNotShareable):
+		return http.StatusConflict
+	case errors.Is(err, customerr.InvalidCursor):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
 // Create @Summary Create note
 // @Tags note
 // @Accept  json
@@ -31,6 +53,7 @@ func InitNoteHandler(NoteService service.Note, tracer trace.Tracer) NoteHandler
 // @Param data body models.NoteCreate true "Note"
 // @Success 200 {object} int "Successfully created note with id"
 // @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 422 {object} map[string]string "Text is rejected by the content filter"
 // @Failure 500 {object} map[string]string "Internal server error"
 // @Router /note/create [post]
 func (r NoteHandler) Create(c *gin.Context) {
@@ -55,7 +78,7 @@ func (r NoteHandler) Create(c *gin.Context) {
 			attribute.String(tracing.ServiceError, err.Error())),
 		)
 		span.SetStatus(codes.Error, err.Error())
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(noteErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
@@ -68,6 +91,7 @@ func (r NoteHandler) Create(c *gin.Context) {
 // @Produce  json
 // @Param user_id query int true "user_id"
 // @Param place_id query int true "place_id"
+// @Param viewer_id query int false "Who is looking, notes of others are filtered by visibility, without it only public notes are returned"
 // @Success 200 {object} models.Note "Successfully"
 // @Failure 400 {object} map[string]string "Invalid input"
 // @Failure 500 {object} map[string]string "Internal server error"
@@ -76,10 +100,14 @@ func (r NoteHandler) GetByIDs(c *gin.Context) {
 	ctx, span := r.tracer.Start(c.Request.Context(), GetNoteByIDs)
 	defer span.End()
 
+	var viewerID int
 	userIDRaw := c.Query("user_id")
 	userID, err := strconv.Atoi(userIDRaw)
 	placeIDRaw := c.Query("place_id")
 	placeID, err := strconv.Atoi(placeIDRaw)
+	if err == nil {
+		viewerID, err = optionalQueryInt(c, "viewer_id")
+	}
 	if err != nil {
 		span.RecordError(err, trace.WithAttributes(
 			attribute.String(tracing.Input, err.Error())),
@@ -90,7 +118,7 @@ func (r NoteHandler) GetByIDs(c *gin.Context) {
 	}
 
 	span.AddEvent(tracing.CallToService)
-	note, err := r.NoteService.GetByIDs(ctx, userID, placeID)
+	note, err := r.NoteService.GetByIDs(ctx, viewerID, userID, placeID)
 	if err != nil {
 		span.RecordError(err, trace.WithAttributes(
 			attribute.String(tracing.ServiceError, err.Error())),
@@ -108,6 +136,7 @@ func (r NoteHandler) GetByIDs(c *gin.Context) {
 // @Accept  json
 // @Produce  json
 // @Param id query int true "User id"
+// @Param viewer_id query int false "Who is looking, notes of others are filtered by visibility, without it only public notes are returned"
 // @Success 200 {object} []models.Note "Successfully"
 // @Failure 400 {object} map[string]string "Invalid input"
 // @Failure 500 {object} map[string]string "Internal server error"
@@ -116,8 +145,12 @@ func (r NoteHandler) GetByUserID(c *gin.Context) {
 	ctx, span := r.tracer.Start(c.Request.Context(), GetNoteByUserID)
 	defer span.End()
 
+	var viewerID int
 	idRaw := c.Query("id")
 	id, err := strconv.Atoi(idRaw)
+	if err == nil {
+		viewerID, err = optionalQueryInt(c, "viewer_id")
+	}
 	if err != nil {
 		span.RecordError(err, trace.WithAttributes(
 			attribute.String(tracing.Input, err.Error())),
@@ -128,7 +161,7 @@ func (r NoteHandler) GetByUserID(c *gin.Context) {
 	}
 
 	span.AddEvent(tracing.CallToService)
-	place, err := r.NoteService.GetByUser(ctx, id)
+	place, err := r.NoteService.GetByUser(ctx, viewerID, id)
 	if err != nil {
 		span.RecordError(err, trace.WithAttributes(
 			attribute.String(tracing.ServiceError, err.Error())),
@@ -146,16 +179,22 @@ func (r NoteHandler) GetByUserID(c *gin.Context) {
 // @Accept  json
 // @Produce  json
 // @Param id query int true "Note id"
+// @Param viewer_id query int true "Who is looking, notes of others are filtered by visibility"
 // @Success 200 {object} models.Note "Successfully"
 // @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 404 {object} map[string]string "Note not found"
 // @Failure 500 {object} map[string]string "Internal server error"
 // @Router /note/by_id [get]
 func (r NoteHandler) GetByNoteID(c *gin.Context) {
 	ctx, span := r.tracer.Start(c.Request.Context(), GetNoteByID)
 	defer span.End()
 
+	var viewerID int
 	idRaw := c.Query("id")
 	id, err := strconv.Atoi(idRaw)
+	if err == nil {
+		viewerID, err = optionalQueryInt(c, "viewer_id")
+	}
 	if err != nil {
 		span.RecordError(err, trace.WithAttributes(
 			attribute.String(tracing.Input, err.Error())),
@@ -166,13 +205,13 @@ func (r NoteHandler) GetByNoteID(c *gin.Context) {
 	}
 
 	span.AddEvent(tracing.CallToService)
-	place, err := r.NoteService.GetByID(ctx, id)
+	place, err := r.NoteService.GetByID(ctx, viewerID, id)
 	if err != nil {
 		span.RecordError(err, trace.WithAttributes(
 			attribute.String(tracing.ServiceError, err.Error())),
 		)
 		span.SetStatus(codes.Error, err.Error())
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(noteErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
@@ -185,6 +224,7 @@ func (r NoteHandler) GetByNoteID(c *gin.Context) {
 // @Produce  json
 // @Param data body models.NoteCreate true "Note id"
 // @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 422 {object} map[string]string "Text is rejected by the content filter"
 // @Failure 500 {object} map[string]string "Internal server error"
 // @Router /note/update [put]
 func (r NoteHandler) Update(c *gin.Context) {
@@ -209,9 +249,366 @@ func (r NoteHandler) Update(c *gin.Context) {
 			attribute.String(tracing.ServiceError, err.Error())),
 		)
 		span.SetStatus(codes.Error, err.Error())
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(noteErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
 	c.Status(http.StatusOK)
 }
+
+// CreateForTarget @Summary Create note for place, route, trip or trip day
+// @Tags note
+// @Accept  json
+// @Produce  json
+// @Param data body models.NoteTargetCreate true "Note"
+// @Success 200 {object} int "Successfully created note with id"
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 403 {object} map[string]string "Trip belongs to another user"
+// @Failure 404 {object} map[string]string "Target not found"
+// @Failure 422 {object} map[string]string "Text is rejected by the content filter"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /note [post]
+func (r NoteHandler) CreateForTarget(c *gin.Context) {
+	ctx, span := r.tracer.Start(c.Request.Context(), NoteCreateForTarget)
+	defer span.End()
+
+	var noteCreate models.NoteTargetCreate
+
+	if err := c.ShouldBindJSON(&noteCreate); err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.BindType, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	span.AddEvent(tracing.CallToService)
+	id, err := r.NoteService.CreateForTarget(ctx, noteCreate)
+	if err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.ServiceError, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(noteErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, id)
+}
+
+// GetByTarget @Summary Get user notes for place, route, trip or trip day
+// @Tags note
+// @Accept  json
+// @Produce  json
+// @Param data query models.NotesByTarget true "Target"
+// @Success 200 {object} []models.Note "Successfully"
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /note/by_target [get]
+func (r NoteHandler) GetByTarget(c *gin.Context) {
+	ctx, span := r.tracer.Start(c.Request.Context(), GetNotesByTarget)
+	defer span.End()
+
+	var notesByTarget models.NotesByTarget
+
+	if err := c.ShouldBindQuery(&notesByTarget); err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.BindType, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	span.AddEvent(tracing.CallToService)
+	notes, err := r.NoteService.GetByTarget(ctx, notesByTarget)
+	if err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.ServiceError, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(noteErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, notes)
+}
+
+// UpdateByID @Summary Update note by id
+// @Tags note
+// @Accept  json
+// @Produce  json
+// @Param data body models.NoteUpdate true "Note"
+// @Success 200
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 403 {object} map[string]string "Note belongs to another user"
+// @Failure 404 {object} map[string]string "Note not found"
+// @Failure 422 {object} map[string]string "Text is rejected by the content filter"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /note [put]
+func (r NoteHandler) UpdateByID(c *gin.Context) {
+	ctx, span := r.tracer.Start(c.Request.Context(), NoteUpdateByID)
+	defer span.End()
+
+	var noteUpdate models.NoteUpdate
+
+	if err := c.ShouldBindJSON(&noteUpdate); err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.BindType, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	span.AddEvent(tracing.CallToService)
+	err := r.NoteService.UpdateByID(ctx, noteUpdate)
+	if err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.ServiceError, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(noteErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// Delete @Summary Delete note
+// @Tags note
+// @Accept  json
+// @Produce  json
+// @Param data query models.NoteDelete true "Note"
+// @Success 200
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 403 {object} map[string]string "Note belongs to another user"
+// @Failure 404 {object} map[string]string "Note not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /note [delete]
+func (r NoteHandler) Delete(c *gin.Context) {
+	ctx, span := r.tracer.Start(c.Request.Context(), NoteDelete)
+	defer span.End()
+
+	var noteDelete models.NoteDelete
+
+	if err := c.ShouldBindQuery(&noteDelete); err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.BindType, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	span.AddEvent(tracing.CallToService)
+	err := r.NoteService.Delete(ctx, noteDelete)
+	if err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.ServiceError, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(noteErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// GetFeed @Summary Get public notes of travellers for place or route
+// @Tags note
+// @Accept  json
+// @Produce  json
+// @Param data query models.NotesFeed true "Target and page"
+// @Success 200 {object} models.Page[models.Note] "Successfully"
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /note/feed [get]
+func (r NoteHandler) GetFeed(c *gin.Context) {
+	ctx, span := r.tracer.Start(c.Request.Context(), GetNotesFeed)
+	defer span.End()
+
+	var feed models.NotesFeed
+
+	if err := c.ShouldBindQuery(&feed); err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.BindType, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	span.AddEvent(tracing.CallToService)
+	page, err := r.NoteService.GetFeed(ctx, feed)
+	if err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.ServiceError, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(noteErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+// CreateShareToken @Summary Create share link token for note
+// @Tags note
+// @Accept  json
+// @Produce  json
+// @Param data body models.NoteShare true "Note"
+// @Success 200 {object} string "Share token"
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 403 {object} map[string]string "Note belongs to another user"
+// @Failure 404 {object} map[string]string "Note not found"
+// @Failure 409 {object} map[string]string "Note is not link or public"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /note/share [post]
+func (r NoteHandler) CreateShareToken(c *gin.Context) {
+	ctx, span := r.tracer.Start(c.Request.Context(), NoteCreateShareToken)
+	defer span.End()
+
+	var share models.NoteShare
+
+	if err := c.ShouldBindJSON(&share); err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.BindType, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	span.AddEvent(tracing.CallToService)
+	token, err := r.NoteService.CreateShareToken(ctx, share)
+	if err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.ServiceError, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(noteErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, token)
+}
+
+// GetShareTokens @Summary Get active share link tokens of note
+// @Tags note
+// @Accept  json
+// @Produce  json
+// @Param data query models.NoteSharesRequest true "Note"
+// @Success 200 {object} []models.NoteShareToken "Successfully"
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 403 {object} map[string]string "Note belongs to another user"
+// @Failure 404 {object} map[string]string "Note not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /note/shares [get]
+func (r NoteHandler) GetShareTokens(c *gin.Context) {
+	ctx, span := r.tracer.Start(c.Request.Context(), GetNoteShareTokens)
+	defer span.End()
+
+	var request models.NoteSharesRequest
+
+	if err := c.ShouldBindQuery(&request); err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.BindType, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	span.AddEvent(tracing.CallToService)
+	tokens, err := r.NoteService.GetShareTokens(ctx, request)
+	if err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.ServiceError, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(noteErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, tokens)
+}
+
+// RevokeShareToken @Summary Revoke share link token
+// @Tags note
+// @Accept  json
+// @Produce  json
+// @Param data query models.NoteShareRevoke true "Token"
+// @Success 200
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 404 {object} map[string]string "Token not found or already revoked"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /note/share [delete]
+func (r NoteHandler) RevokeShareToken(c *gin.Context) {
+	ctx, span := r.tracer.Start(c.Request.Context(), NoteRevokeShareToken)
+	defer span.End()
+
+	var revoke models.NoteShareRevoke
+
+	if err := c.ShouldBindQuery(&revoke); err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.BindType, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	span.AddEvent(tracing.CallToService)
+	err := r.NoteService.RevokeShareToken(ctx, revoke)
+	if err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.ServiceError, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(noteErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// GetByShareToken @Summary Get note by share link token
+// @Tags note
+// @Accept  json
+// @Produce  json
+// @Param token query string true "Share token"
+// @Success 200 {object} models.Note "Successfully"
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 404 {object} map[string]string "Token not found or revoked"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /note/shared [get]
+func (r NoteHandler) GetByShareToken(c *gin.Context) {
+	ctx, span := r.tracer.Start(c.Request.Context(), GetNoteByShareToken)
+	defer span.End()
+
+	token := c.Query("token")
+	if token == "" {
+		err := errors.New("token is required")
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.Input, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	span.AddEvent(tracing.CallToService)
+	note, err := r.NoteService.GetByShareToken(ctx, token)
+	if err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.ServiceError, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(noteErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, note)
+}
@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"mth/internal/models"
+	"mth/internal/service"
+	"mth/pkg/customerr"
+	tracing "mth/pkg/trace"
+	"net/http"
+)
+
+type NotificationHandler struct {
+	notificationService service.Notification
+	tracer              trace.Tracer
+}
+
+func InitNotificationHandler(notificationService service.Notification, tracer trace.Tracer) NotificationHandler {
+	return NotificationHandler{
+		notificationService: notificationService,
+		tracer:              tracer,
+	}
+}
+
+// GetByUser @Summary Get user notifications, newest first
+// @Tags notification
+// @Accept  json
+// @Produce  json
+// @Param user_id query int true "User id"
+// @Param unread_only query bool false "Only unread notifications"
+// @Param cursor query string false "next_cursor from the previous page, empty for the first page"
+// @Param page_size query int false "Page size, capped by MAX_PAGE_SIZE"
+// @Param with_total query bool false "Count total number of notifications"
+// @Success 200 {object} models.Page[models.Notification] "Successfully"
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /notification [get]
+func (n NotificationHandler) GetByUser(c *gin.Context) {
+	ctx, span := n.tracer.Start(c.Request.Context(), GetNotifications)
+	defer span.End()
+
+	var request models.NotificationsRequest
+
+	if err := c.ShouldBindQuery(&request); err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.BindType, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	span.AddEvent(tracing.CallToService)
+	notifications, err := n.notificationService.GetByUser(ctx, request)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, customerr.InvalidCursor) {
+			status = http.StatusBadRequest
+		}
+
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.ServiceError, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, notifications)
+}
+
+// MarkRead @Summary Mark notifications as read, all unread ones if ids are empty
+// @Tags notification
+// @Accept  json
+// @Produce  json
+// @Param data body models.NotificationsRead true "Notifications"
+// @Success 200 {object} int "Number of notifications marked as read"
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /notification/read [put]
+func (n NotificationHandler) MarkRead(c *gin.Context) {
+	ctx, span := n.tracer.Start(c.Request.Context(), MarkNotificationsRead)
+	defer span.End()
+
+	var read models.NotificationsRead
+
+	if err := c.ShouldBindJSON(&read); err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.BindType, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	span.AddEvent(tracing.CallToService)
+	count, err := n.notificationService.MarkRead(ctx, read)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, customerr.BadInput) {
+			status = http.StatusBadRequest
+		}
+
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.ServiceError, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, count)
+}
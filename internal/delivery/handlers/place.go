@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"github.com/gin-gonic/gin"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
@@ -8,6 +9,7 @@ import (
 	"mth/internal/models"
 	"mth/internal/models/swagger"
 	"mth/internal/service"
+	"mth/pkg/customerr"
 	tracing "mth/pkg/trace"
 	"net/http"
 	"strconv"
@@ -25,6 +27,19 @@ func InitPlaceHandler(PlaceService service.Place, tracer trace.Tracer) PlaceHand
 	}
 }
 
+func placeErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, customerr.DistrictNotInCity), errors.Is(err, customerr.UnknownVariety):
+		return http.StatusBadRequest
+	case errors.Is(err, customerr.PlaceNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, customerr.PlaceHasHistory):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
 // Create @Summary Create place with tags
 // @Tags place
 // @Accept  json
@@ -56,6 +71,12 @@ func (r PlaceHandler) Create(c *gin.Context) {
 			attribute.String(tracing.ServiceError, err.Error())),
 		)
 		span.SetStatus(codes.Error, err.Error())
+
+		if errors.Is(err, customerr.UnknownVariety) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -64,11 +85,14 @@ func (r PlaceHandler) Create(c *gin.Context) {
 }
 
 // GetAllWithFilter @Summary Get places by filter (or without)
+// @Description Keyset pagination: pass next_cursor from the previous page as cursor, keep the same filters and sort_by
 // @Tags place
 // @Accept  json
 // @Produce  json
 // @Param data body swagger.Filters true "Filters"
-// @Success 200 {object} []models.Place "Successfully"
+// @Param lang query string false "Response language, takes precedence over Accept-Language"
+// @Param Accept-Language header string false "Preferred response languages"
+// @Success 200 {object} models.Page[models.Place] "Successfully"
 // @Failure 400 {object} map[string]string "Invalid input"
 // @Failure 500 {object} map[string]string "Internal server error"
 // @Router /place/get_all_with_filter [put]
@@ -94,6 +118,12 @@ func (r PlaceHandler) GetAllWithFilter(c *gin.Context) {
 			attribute.String(tracing.ServiceError, err.Error())),
 		)
 		span.SetStatus(codes.Error, err.Error())
+
+		if errors.Is(err, customerr.InvalidCursor) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -106,6 +136,8 @@ func (r PlaceHandler) GetAllWithFilter(c *gin.Context) {
 // @Accept  json
 // @Produce  json
 // @Param id query int true "Place id"
+// @Param lang query string false "Response language, takes precedence over Accept-Language"
+// @Param Accept-Language header string false "Preferred response languages"
 // @Success 200 {object} models.Place "Successfully"
 // @Failure 400 {object} map[string]string "Invalid input"
 // @Failure 500 {object} map[string]string "Internal server error"
@@ -138,3 +170,237 @@ func (r PlaceHandler) GetByID(c *gin.Context) {
 
 	c.JSON(http.StatusOK, place)
 }
+
+// Update @Summary Update place, its tags set is fully replaced by tag_ids
+// @Tags place
+// @Accept  json
+// @Produce  json
+// @Param data body models.PlaceUpdate true "Place with tag ids update"
+// @Success 200 {object} string "Successfully"
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 404 {object} map[string]string "Place not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /place/update [put]
+func (r PlaceHandler) Update(c *gin.Context) {
+	ctx, span := r.tracer.Start(c.Request.Context(), PlaceUpdate)
+	defer span.End()
+
+	var placeUpdate models.PlaceUpdate
+
+	if err := c.ShouldBindJSON(&placeUpdate); err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.BindType, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	span.AddEvent(tracing.CallToService)
+	err := r.PlaceService.Update(ctx, placeUpdate)
+	if err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.ServiceError, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(placeErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, "Successfully!")
+}
+
+// Delete @Summary Delete place, refused with 409 if place has user history (archive it instead)
+// @Tags place
+// @Accept  json
+// @Produce  json
+// @Param id query int true "Place id"
+// @Success 200 "success"
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 404 {object} map[string]string "Place not found"
+// @Failure 409 {object} map[string]string "Place has history"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /place [delete]
+func (r PlaceHandler) Delete(c *gin.Context) {
+	ctx, span := r.tracer.Start(c.Request.Context(), PlaceDelete)
+	defer span.End()
+
+	idRaw := c.Query("id")
+	id, err := strconv.Atoi(idRaw)
+	if err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.Input, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	span.AddEvent(tracing.CallToService)
+	err = r.PlaceService.Delete(ctx, id)
+	if err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.ServiceError, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(placeErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// SetArchived @Summary Archive or unarchive place, archived places are hidden from search
+// @Tags place
+// @Accept  json
+// @Produce  json
+// @Param id query int true "Place id"
+// @Param archived query bool true "Archived flag"
+// @Success 200 "success"
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 404 {object} map[string]string "Place not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /place/archive [put]
+func (r PlaceHandler) SetArchived(c *gin.Context) {
+	ctx, span := r.tracer.Start(c.Request.Context(), PlaceSetArchived)
+	defer span.End()
+
+	idRaw := c.Query("id")
+	id, err := strconv.Atoi(idRaw)
+	if err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.Input, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	archivedRaw := c.Query("archived")
+	archived, err := strconv.ParseBool(archivedRaw)
+	if err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.Input, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	span.AddEvent(tracing.CallToService)
+	err = r.PlaceService.SetArchived(ctx, id, archived)
+	if err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.ServiceError, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(placeErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// GetGeoJSON @Summary Get places by filter as GeoJSON FeatureCollection for map markers
+// @Description Same filters as get_all_with_filter, places without coordinates are skipped, page size is GEOJSON_MAX_FEATURES.
+// @Description When more places match, truncated is true and next_cursor requests the next page
+// @Tags place
+// @Accept  json
+// @Produce  json
+// @Param data body swagger.Filters true "Filters"
+// @Param lang query string false "Response language, takes precedence over Accept-Language"
+// @Param Accept-Language header string false "Preferred response languages"
+// @Success 200 {object} geojson.FeatureCollection "Successfully"
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /place/geojson [put]
+func (r PlaceHandler) GetGeoJSON(c *gin.Context) {
+	ctx, span := r.tracer.Start(c.Request.Context(), GetPlacesGeoJSON)
+	defer span.End()
+
+	var filters swagger.Filters
+
+	if err := c.ShouldBindJSON(&filters); err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.BindType, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	span.AddEvent(tracing.CallToService)
+	collection, err := r.PlaceService.GetGeoJSON(ctx, filters)
+	if err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.ServiceError, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+
+		if errors.Is(err, customerr.InvalidCursor) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Content-Type", geoJSONContentType)
+	c.JSON(http.StatusOK, collection)
+}
+
+// optionalQueryInt пустой параметр считается нулём
+func optionalQueryInt(c *gin.Context, key string) (int, error) {
+	raw := c.Query(key)
+	if raw == "" {
+		return 0, nil
+	}
+
+	return strconv.Atoi(raw)
+}
+
+// GetTrending @Summary Get most popular places of city or district
+// @Tags place
+// @Accept  json
+// @Produce  json
+// @Param city_id query int false "City id"
+// @Param district_id query int false "District id"
+// @Param limit query int false "Number of places, TRENDING_LIMIT by default"
+// @Param lang query string false "Response language, takes precedence over Accept-Language"
+// @Param Accept-Language header string false "Preferred response languages"
+// @Success 200 {object} []models.Place "Successfully"
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /place/trending [get]
+func (r PlaceHandler) GetTrending(c *gin.Context) {
+	ctx, span := r.tracer.Start(c.Request.Context(), GetTrendingPlaces)
+	defer span.End()
+
+	var params [3]int
+	for i, key := range []string{"city_id", "district_id", "limit"} {
+		value, err := optionalQueryInt(c, key)
+		if err != nil {
+			span.RecordError(err, trace.WithAttributes(
+				attribute.String(tracing.Input, err.Error())),
+			)
+			span.SetStatus(codes.Error, err.Error())
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		params[i] = value
+	}
+
+	span.AddEvent(tracing.CallToService)
+	places, err := r.PlaceService.GetTrending(ctx, params[0], params[1], params[2])
+	if err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.ServiceError, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, places)
+}
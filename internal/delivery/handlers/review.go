@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"github.com/gin-gonic/gin"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
@@ -26,6 +27,26 @@ func InitReviewHandler(ReviewService service.Review, tracer trace.Tracer) Review
 	}
 }
 
+func reviewErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, customerr.BadInput), errors.Is(err, customerr.InvalidCursor):
+		return http.StatusBadRequest
+	case errors.Is(err, customerr.UserNotEntityOwner), errors.Is(err, customerr.NotModerator),
+		errors.Is(err, customerr.ReviewNotVerified), errors.Is(err, customerr.NotEntityManager):
+		return http.StatusForbidden
+	case errors.Is(err, customerr.ReviewNotFound), errors.Is(err, customerr.PlaceNotFound),
+		errors.Is(err, customerr.ReviewVoteNotFound), errors.Is(err, customerr.ReviewReplyNotFound),
+		errors.Is(err, customerr.ManagedEntityNotFound), errors.Is(err, customerr.EntityManagerNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, customerr.ReviewAlreadyFlagged):
+		return http.StatusConflict
+	case errors.Is(err, customerr.ContentRejected):
+		return http.StatusUnprocessableEntity
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
 // CreateOnRoute @Summary Create route review
 // @Tags review
 // @Accept  json
@@ -33,6 +54,8 @@ func InitReviewHandler(ReviewService service.Review, tracer trace.Tracer) Review
 // @Param data body models.RouteReviewCreate true "Route review create"
 // @Success 200 {object} int "Successfully created route review with id"
 // @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 403 {object} map[string]string "Route is not completed by the author while REVIEW_ROUTE_REQUIRE_VERIFIED is set"
+// @Failure 422 {object} map[string]string "Text is rejected by the content filter"
 // @Failure 500 {object} map[string]string "Internal server error"
 // @Router /review/create_on_route [post]
 func (r ReviewHandler) CreateOnRoute(c *gin.Context) {
@@ -57,7 +80,7 @@ func (r ReviewHandler) CreateOnRoute(c *gin.Context) {
 			attribute.String(tracing.ServiceError, err.Error())),
 		)
 		span.SetStatus(codes.Error, err.Error())
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(reviewErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
@@ -71,6 +94,9 @@ func (r ReviewHandler) CreateOnRoute(c *gin.Context) {
 // @Param data body models.PlaceReviewCreate true "place review create"
 // @Success 200 {object} int "Successfully created route review with id"
 // @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 403 {object} map[string]string "Place variety requires a check-in before review"
+// @Failure 404 {object} map[string]string "Place not found"
+// @Failure 422 {object} map[string]string "Text is rejected by the content filter"
 // @Failure 500 {object} map[string]string "Internal server error"
 // @Router /review/create_on_place [post]
 func (r ReviewHandler) CreateOnPlace(c *gin.Context) {
@@ -95,7 +121,7 @@ func (r ReviewHandler) CreateOnPlace(c *gin.Context) {
 			attribute.String(tracing.ServiceError, err.Error())),
 		)
 		span.SetStatus(codes.Error, err.Error())
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(reviewErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
@@ -144,12 +170,19 @@ func (r ReviewHandler) GetByAuthor(c *gin.Context) {
 	})
 }
 
-// GetByPlace @Summary Get reviews by place
+// GetByPlace @Summary Get published reviews by place
+// @Description Breaking change: the response is a page object (items, next_cursor, has_more, total) instead of a plain array.
+// @Description Downranking of the helpful order is evaluated once for the first page and kept in next_cursor
 // @Tags review
 // @Accept  json
 // @Produce  json
 // @Param id query int true "place id"
-// @Success 200 {object} []models.PlaceReview "Successfully"
+// @Param verified_only query bool false "Only reviews of authors who checked in at the place"
+// @Param sort_by query string false "Order, helpful by default" Enums(helpful, recent, mark_high, mark_low)
+// @Param cursor query string false "next_cursor from the previous page, empty for the first page"
+// @Param page_size query int false "Page size, capped by MAX_PAGE_SIZE"
+// @Param with_total query bool false "Count total number of reviews"
+// @Success 200 {object} models.Page[models.PlaceReview] "Successfully"
 // @Failure 400 {object} map[string]string "Invalid input"
 // @Failure 500 {object} map[string]string "Internal server error"
 // @Router /review/place [get]
@@ -157,12 +190,11 @@ func (r ReviewHandler) GetByPlace(c *gin.Context) {
 	ctx, span := r.tracer.Start(c.Request.Context(), GetByPlace)
 	defer span.End()
 
-	placeIDRaw := c.Query("id")
-	placeID, err := strconv.Atoi(placeIDRaw)
-	if err != nil {
-		err := customerr.BadInput
+	var request models.ReviewListRequest
+
+	if err := c.ShouldBindQuery(&request); err != nil {
 		span.RecordError(err, trace.WithAttributes(
-			attribute.String(tracing.Input, err.Error())),
+			attribute.String(tracing.BindType, err.Error())),
 		)
 		span.SetStatus(codes.Error, err.Error())
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
@@ -170,25 +202,32 @@ func (r ReviewHandler) GetByPlace(c *gin.Context) {
 	}
 
 	span.AddEvent(tracing.CallToService)
-	placeReviews, err := r.ReviewService.GetByPlace(ctx, placeID)
+	placeReviews, err := r.ReviewService.GetByPlace(ctx, request)
 	if err != nil {
 		span.RecordError(err, trace.WithAttributes(
 			attribute.String(tracing.ServiceError, err.Error())),
 		)
 		span.SetStatus(codes.Error, err.Error())
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(reviewErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
 	c.JSON(http.StatusOK, placeReviews)
 }
 
-// GetByRoute @Summary Get reviews by route
+// GetByRoute @Summary Get published reviews by route
+// @Description Breaking change: the response is a page object (items, next_cursor, has_more, total) instead of a plain array.
+// @Description Downranking of the helpful order is evaluated once for the first page and kept in next_cursor
 // @Tags review
 // @Accept  json
 // @Produce  json
 // @Param id query int true "route id"
-// @Success 200 {object} []models.RouteReview "Successfully"
+// @Param verified_only query bool false "Only reviews of authors who completed the route"
+// @Param sort_by query string false "Order, helpful by default" Enums(helpful, recent, mark_high, mark_low)
+// @Param cursor query string false "next_cursor from the previous page, empty for the first page"
+// @Param page_size query int false "Page size, capped by MAX_PAGE_SIZE"
+// @Param with_total query bool false "Count total number of reviews"
+// @Success 200 {object} models.Page[models.RouteReview] "Successfully"
 // @Failure 400 {object} map[string]string "Invalid input"
 // @Failure 500 {object} map[string]string "Internal server error"
 // @Router /review/route [get]
@@ -196,12 +235,11 @@ func (r ReviewHandler) GetByRoute(c *gin.Context) {
 	ctx, span := r.tracer.Start(c.Request.Context(), GetByRoute)
 	defer span.End()
 
-	placeIDRaw := c.Query("id")
-	placeID, err := strconv.Atoi(placeIDRaw)
-	if err != nil {
-		err := customerr.BadInput
+	var request models.ReviewListRequest
+
+	if err := c.ShouldBindQuery(&request); err != nil {
 		span.RecordError(err, trace.WithAttributes(
-			attribute.String(tracing.Input, err.Error())),
+			attribute.String(tracing.BindType, err.Error())),
 		)
 		span.SetStatus(codes.Error, err.Error())
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
@@ -209,17 +247,17 @@ func (r ReviewHandler) GetByRoute(c *gin.Context) {
 	}
 
 	span.AddEvent(tracing.CallToService)
-	placeReviews, err := r.ReviewService.GetByRoute(ctx, placeID)
+	routeReviews, err := r.ReviewService.GetByRoute(ctx, request)
 	if err != nil {
 		span.RecordError(err, trace.WithAttributes(
 			attribute.String(tracing.ServiceError, err.Error())),
 		)
 		span.SetStatus(codes.Error, err.Error())
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(reviewErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, placeReviews)
+	c.JSON(http.StatusOK, routeReviews)
 }
 
 // UpdateOnPlace @Summary Update review on place
@@ -229,6 +267,7 @@ func (r ReviewHandler) GetByRoute(c *gin.Context) {
 // @Param review body models.ReviewUpdate true "place review id"
 // @Success 200 {object} string "Successfully"
 // @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 422 {object} map[string]string "Text is rejected by the content filter"
 // @Failure 500 {object} map[string]string "Internal server error"
 // @Router /review/update_on_place [put]
 func (r ReviewHandler) UpdateOnPlace(c *gin.Context) {
@@ -253,7 +292,7 @@ func (r ReviewHandler) UpdateOnPlace(c *gin.Context) {
 			attribute.String(tracing.ServiceError, err.Error())),
 		)
 		span.SetStatus(codes.Error, err.Error())
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(reviewErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
@@ -267,6 +306,7 @@ func (r ReviewHandler) UpdateOnPlace(c *gin.Context) {
 // @Param review body models.ReviewUpdate true "route review id"
 // @Success 200 {object} string "Successfully"
 // @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 422 {object} map[string]string "Text is rejected by the content filter"
 // @Failure 500 {object} map[string]string "Internal server error"
 // @Router /review/update_on_route [put]
 func (r ReviewHandler) UpdateOnRoute(c *gin.Context) {
@@ -291,9 +331,498 @@ func (r ReviewHandler) UpdateOnRoute(c *gin.Context) {
 			attribute.String(tracing.ServiceError, err.Error())),
 		)
 		span.SetStatus(codes.Error, err.Error())
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(reviewErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, "Successfully!")
+}
+
+// Delete @Summary Delete own review
+// @Tags review
+// @Accept  json
+// @Produce  json
+// @Param review_type query string true "Review type" Enums(place, route)
+// @Param id query int true "review id"
+// @Param user_id query int true "author id"
+// @Success 200 {object} string "Successfully"
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 403 {object} map[string]string "User is not the author"
+// @Failure 404 {object} map[string]string "Review not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /review [delete]
+func (r ReviewHandler) Delete(c *gin.Context) {
+	ctx, span := r.tracer.Start(c.Request.Context(), DeleteReview)
+	defer span.End()
+
+	var reviewDelete models.ReviewDelete
+
+	if err := c.ShouldBindQuery(&reviewDelete); err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.BindType, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	span.AddEvent(tracing.CallToService)
+	err := r.ReviewService.Delete(ctx, reviewDelete)
+	if err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.ServiceError, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(reviewErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, "Successfully!")
+}
+
+// Flag @Summary Flag review, published review with REVIEW_FLAGS_TO_HIDE open flags is hidden until moderation
+// @Tags review
+// @Accept  json
+// @Produce  json
+// @Param data body models.ReviewFlagCreate true "Flag"
+// @Success 200 {object} models.ReviewFlagResult "Successfully"
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 404 {object} map[string]string "Review not found"
+// @Failure 409 {object} map[string]string "Review is already flagged by the user"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /review/flag [post]
+func (r ReviewHandler) Flag(c *gin.Context) {
+	ctx, span := r.tracer.Start(c.Request.Context(), FlagReview)
+	defer span.End()
+
+	var flag models.ReviewFlagCreate
+
+	if err := c.ShouldBindJSON(&flag); err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.BindType, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	span.AddEvent(tracing.CallToService)
+	result, err := r.ReviewService.Flag(ctx, flag)
+	if err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.ServiceError, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(reviewErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// Moderate @Summary Approve, reject or hide review, open flags are resolved
+// @Tags review
+// @Accept  json
+// @Produce  json
+// @Param data body models.ReviewModeration true "Moderation decision, reason is required for reject and hide"
+// @Success 200 {object} string "Successfully"
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 403 {object} map[string]string "User is not a moderator"
+// @Failure 404 {object} map[string]string "Review not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /review/moderate [post]
+func (r ReviewHandler) Moderate(c *gin.Context) {
+	ctx, span := r.tracer.Start(c.Request.Context(), ModerateReview)
+	defer span.End()
+
+	var moderation models.ReviewModeration
+
+	if err := c.ShouldBindJSON(&moderation); err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.BindType, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	span.AddEvent(tracing.CallToService)
+	err := r.ReviewService.Moderate(ctx, moderation)
+	if err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.ServiceError, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(reviewErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, "Successfully!")
+}
+
+// GetModerationQueue @Summary Get pending reviews and reviews with open flags
+// @Tags review
+// @Accept  json
+// @Produce  json
+// @Param moderator_id query int true "Moderator id"
+// @Param queue query string false "Only pending or only flagged reviews" Enums(pending, flagged)
+// @Param cursor query string false "next_cursor from the previous page, empty for the first page"
+// @Param page_size query int false "Page size, capped by MAX_PAGE_SIZE"
+// @Param with_total query bool false "Count total number of reviews"
+// @Success 200 {object} models.Page[models.ReviewQueueItem] "Successfully"
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 403 {object} map[string]string "User is not a moderator"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /review/moderation_queue [get]
+func (r ReviewHandler) GetModerationQueue(c *gin.Context) {
+	ctx, span := r.tracer.Start(c.Request.Context(), GetReviewModerationQueue)
+	defer span.End()
+
+	var request models.ReviewQueueRequest
+
+	if err := c.ShouldBindQuery(&request); err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.BindType, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	span.AddEvent(tracing.CallToService)
+	queue, err := r.ReviewService.GetModerationQueue(ctx, request)
+	if err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.ServiceError, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(reviewErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, queue)
+}
+
+// Vote @Summary Vote review helpful or unhelpful, repeated vote replaces the previous one
+// @Tags review
+// @Accept  json
+// @Produce  json
+// @Param data body models.ReviewVote true "Vote"
+// @Success 200 {object} models.ReviewVotes "Votes of the review after voting"
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 404 {object} map[string]string "Review not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /review/vote [post]
+func (r ReviewHandler) Vote(c *gin.Context) {
+	ctx, span := r.tracer.Start(c.Request.Context(), VoteReview)
+	defer span.End()
+
+	var vote models.ReviewVote
+
+	if err := c.ShouldBindJSON(&vote); err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.BindType, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	span.AddEvent(tracing.CallToService)
+	votes, err := r.ReviewService.Vote(ctx, vote)
+	if err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.ServiceError, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(reviewErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, votes)
+}
+
+// DeleteVote @Summary Retract vote for review
+// @Tags review
+// @Accept  json
+// @Produce  json
+// @Param review_type query string true "Review type" Enums(place, route)
+// @Param review_id query int true "review id"
+// @Param user_id query int true "voter id"
+// @Success 200 {object} models.ReviewVotes "Votes of the review after retraction"
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 404 {object} map[string]string "Vote not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /review/vote [delete]
+func (r ReviewHandler) DeleteVote(c *gin.Context) {
+	ctx, span := r.tracer.Start(c.Request.Context(), DeleteReviewVote)
+	defer span.End()
+
+	var vote models.ReviewVoteDelete
+
+	if err := c.ShouldBindQuery(&vote); err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.BindType, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	span.AddEvent(tracing.CallToService)
+	votes, err := r.ReviewService.DeleteVote(ctx, vote)
+	if err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.ServiceError, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(reviewErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, votes)
+}
+
+// SetManager @Summary Link user to place or route as owner or editor, repeated link changes the role
+// @Tags review
+// @Accept  json
+// @Produce  json
+// @Param data body models.EntityManagerUpdate true "Manager"
+// @Success 200 {object} string "Successfully"
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 403 {object} map[string]string "User is not a moderator"
+// @Failure 404 {object} map[string]string "Place or route not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /review/manager [put]
+func (r ReviewHandler) SetManager(c *gin.Context) {
+	ctx, span := r.tracer.Start(c.Request.Context(), SetEntityManager)
+	defer span.End()
+
+	var request models.EntityManagerUpdate
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.BindType, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	span.AddEvent(tracing.CallToService)
+	err := r.ReviewService.SetManager(ctx, request)
+	if err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.ServiceError, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(reviewErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, "Successfully!")
+}
+
+// DeleteManager @Summary Unlink owner or editor from place or route
+// @Tags review
+// @Accept  json
+// @Produce  json
+// @Param moderator_id query int true "Moderator id"
+// @Param entity_type query string true "Entity type" Enums(place, route)
+// @Param entity_id query int true "Place or route id"
+// @Param user_id query int true "Manager id"
+// @Success 200 {object} string "Successfully"
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 403 {object} map[string]string "User is not a moderator"
+// @Failure 404 {object} map[string]string "Manager not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /review/manager [delete]
+func (r ReviewHandler) DeleteManager(c *gin.Context) {
+	ctx, span := r.tracer.Start(c.Request.Context(), DeleteEntityManager)
+	defer span.End()
+
+	var request models.EntityManagerDelete
+
+	if err := c.ShouldBindQuery(&request); err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.BindType, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	span.AddEvent(tracing.CallToService)
+	err := r.ReviewService.DeleteManager(ctx, request)
+	if err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.ServiceError, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(reviewErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, "Successfully!")
+}
+
+// GetManagers @Summary Get owners and editors of place or route
+// @Tags review
+// @Accept  json
+// @Produce  json
+// @Param entity_type query string true "Entity type" Enums(place, route)
+// @Param entity_id query int true "Place or route id"
+// @Success 200 {object} []models.EntityManager "Successfully"
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /review/managers [get]
+func (r ReviewHandler) GetManagers(c *gin.Context) {
+	ctx, span := r.tracer.Start(c.Request.Context(), GetEntityManagers)
+	defer span.End()
+
+	id, err := strconv.Atoi(c.Query("entity_id"))
+	if err != nil {
+		err := customerr.BadInput
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.Input, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	span.AddEvent(tracing.CallToService)
+	result, err := r.ReviewService.GetManagers(ctx, c.Query("entity_type"), id)
+	if err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.ServiceError, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(reviewErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// UpsertReply @Summary Official reply of owner or editor to review, repeated reply replaces the text and keeps the previous one in history
+// @Tags review
+// @Accept  json
+// @Produce  json
+// @Param data body models.ReviewReplyUpsert true "Reply"
+// @Success 200 {object} models.ReviewReply "Reply after saving"
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 403 {object} map[string]string "User is not owner or editor"
+// @Failure 404 {object} map[string]string "Review not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /review/reply [put]
+func (r ReviewHandler) UpsertReply(c *gin.Context) {
+	ctx, span := r.tracer.Start(c.Request.Context(), UpsertReviewReply)
+	defer span.End()
+
+	var request models.ReviewReplyUpsert
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.BindType, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	span.AddEvent(tracing.CallToService)
+	result, err := r.ReviewService.UpsertReply(ctx, request)
+	if err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.ServiceError, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(reviewErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// DeleteReply @Summary Delete official reply to review with its history
+// @Tags review
+// @Accept  json
+// @Produce  json
+// @Param review_type query string true "Review type" Enums(place, route)
+// @Param review_id query int true "review id"
+// @Param user_id query int true "owner or editor id"
+// @Success 200 {object} string "Successfully"
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 403 {object} map[string]string "User is not owner or editor"
+// @Failure 404 {object} map[string]string "Reply not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /review/reply [delete]
+func (r ReviewHandler) DeleteReply(c *gin.Context) {
+	ctx, span := r.tracer.Start(c.Request.Context(), DeleteReviewReply)
+	defer span.End()
+
+	var request models.ReviewReplyKey
+
+	if err := c.ShouldBindQuery(&request); err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.BindType, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	span.AddEvent(tracing.CallToService)
+	err := r.ReviewService.DeleteReply(ctx, request)
+	if err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.ServiceError, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(reviewErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
 	c.JSON(http.StatusOK, "Successfully!")
 }
+
+// GetReplyHistory @Summary Get previous versions of official reply, oldest first
+// @Tags review
+// @Accept  json
+// @Produce  json
+// @Param review_type query string true "Review type" Enums(place, route)
+// @Param review_id query int true "review id"
+// @Success 200 {object} []models.ReviewReplyRevision "Successfully"
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 404 {object} map[string]string "Reply not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /review/reply/history [get]
+func (r ReviewHandler) GetReplyHistory(c *gin.Context) {
+	ctx, span := r.tracer.Start(c.Request.Context(), GetReviewReplyHistory)
+	defer span.End()
+
+	id, err := strconv.Atoi(c.Query("review_id"))
+	if err != nil {
+		err := customerr.BadInput
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.Input, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	span.AddEvent(tracing.CallToService)
+	result, err := r.ReviewService.GetReplyHistory(ctx, c.Query("review_type"), id)
+	if err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.ServiceError, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(reviewErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
@@ -1,12 +1,14 @@
 package handlers
 
 import (
+	"errors"
 	"github.com/gin-gonic/gin"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/trace"
 	"mth/internal/models"
 	"mth/internal/service"
+	"mth/pkg/customerr"
 	tracing "mth/pkg/trace"
 	"net/http"
 	"strconv"
@@ -67,6 +69,8 @@ func (r RouteHandler) Create(c *gin.Context) {
 // @Accept  json
 // @Produce  json
 // @Param id query int true "id"
+// @Param lang query string false "Response language, takes precedence over Accept-Language"
+// @Param Accept-Language header string false "Preferred response languages"
 // @Success 200 {object} models.Route "Successfully"
 // @Failure 400 {object} map[string]string "Invalid input"
 // @Failure 500 {object} map[string]string "Internal server error"
@@ -101,12 +105,20 @@ func (r RouteHandler) GetRouteByID(c *gin.Context) {
 	c.JSON(http.StatusOK, route)
 }
 
-// GetRouteByPage @Summary Get route by page
+// GetRouteByPage @Summary Get routes page by cursor
 // @Tags route
 // @Accept  json
 // @Produce  json
-// @Param page query int true "id"
-// @Success 200 {object} []models.Route "Successfully"
+// @Param cursor query string false "next_cursor from the previous page, empty for the first page"
+// @Param page_size query int false "Page size, capped by MAX_PAGE_SIZE"
+// @Param with_total query bool false "Count total number of routes"
+// @Param sort_by query string false "Empty for creation order or popular" Enums(popular)
+// @Param tag_ids query []int false "Route must have every tag" collectionFormat(multi)
+// @Param tags query []string false "Tag names or synonyms, route must have every tag" collectionFormat(multi)
+// @Param include_descendants query bool false "Tag also matches its descendant tags"
+// @Param lang query string false "Response language, takes precedence over Accept-Language"
+// @Param Accept-Language header string false "Preferred response languages"
+// @Success 200 {object} models.Page[models.Route] "Successfully"
 // @Failure 400 {object} map[string]string "Invalid input"
 // @Failure 500 {object} map[string]string "Internal server error"
 // @Router /route/by_page [get]
@@ -114,10 +126,9 @@ func (r RouteHandler) GetRouteByPage(c *gin.Context) {
 	ctx, span := r.tracer.Start(c.Request.Context(), GetRoutesByPage)
 	defer span.End()
 
-	pageRaw := c.Query("page")
+	var filters models.RouteFilters
 
-	page, err := strconv.Atoi(pageRaw)
-	if err != nil {
+	if err := c.ShouldBindQuery(&filters); err != nil {
 		span.RecordError(err, trace.WithAttributes(
 			attribute.String(tracing.BindType, err.Error())),
 		)
@@ -127,15 +138,63 @@ func (r RouteHandler) GetRouteByPage(c *gin.Context) {
 	}
 
 	span.AddEvent(tracing.CallToService)
-	routes, err := r.RouteService.GetAll(ctx, page)
+	routes, err := r.RouteService.GetAll(ctx, filters)
 	if err != nil {
 		span.RecordError(err, trace.WithAttributes(
 			attribute.String(tracing.ServiceError, err.Error())),
 		)
 		span.SetStatus(codes.Error, err.Error())
+
+		if errors.Is(err, customerr.InvalidCursor) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
 	c.JSON(http.StatusOK, routes)
 }
+
+// GetGeoJSON @Summary Get route as GeoJSON: LineString through ordered stops and Point for every stop
+// @Tags route
+// @Accept  json
+// @Produce  json
+// @Param id query int true "id"
+// @Param lang query string false "Response language, takes precedence over Accept-Language"
+// @Param Accept-Language header string false "Preferred response languages"
+// @Success 200 {object} geojson.FeatureCollection "Successfully"
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /route/geojson [get]
+func (r RouteHandler) GetGeoJSON(c *gin.Context) {
+	ctx, span := r.tracer.Start(c.Request.Context(), GetRouteGeoJSON)
+	defer span.End()
+
+	idRaw := c.Query("id")
+
+	id, err := strconv.Atoi(idRaw)
+	if err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.BindType, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	span.AddEvent(tracing.CallToService)
+	collection, err := r.RouteService.GetGeoJSON(ctx, id)
+	if err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.ServiceError, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Content-Type", geoJSONContentType)
+	c.JSON(http.StatusOK, collection)
+}
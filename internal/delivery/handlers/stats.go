@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"mth/internal/models"
+	"mth/internal/service"
+	tracing "mth/pkg/trace"
+	"net/http"
+)
+
+type StatsHandler struct {
+	statsService service.Stats
+	tracer       trace.Tracer
+}
+
+func InitStatsHandler(statsService service.Stats, tracer trace.Tracer) StatsHandler {
+	return StatsHandler{
+		statsService: statsService,
+		tracer:       tracer,
+	}
+}
+
+// GetDistrict @Summary Get district statistics: places by variety, routes passing through, check-ins, visitors and rating over the window
+// @Tags stats
+// @Produce  json
+// @Param id query int true "District id"
+// @Param from query string false "First day, 2006-01-02, 30 days before to by default"
+// @Param to query string false "Last day, 2006-01-02, today by default"
+// @Param granularity query string false "Timeline interval" Enums(day, week, month)
+// @Success 200 {object} models.AreaStats "Successfully"
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 404 {object} map[string]string "District not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /stats/district [get]
+func (s StatsHandler) GetDistrict(c *gin.Context) {
+	ctx, span := s.tracer.Start(c.Request.Context(), GetDistrictStats)
+	defer span.End()
+
+	var request models.StatsRequest
+
+	if err := c.ShouldBindQuery(&request); err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.BindType, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	span.AddEvent(tracing.CallToService)
+	stats, err := s.statsService.GetDistrict(ctx, request)
+	if err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.ServiceError, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(geoErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
+
+// GetCity @Summary Get city statistics with breakdown by district
+// @Tags stats
+// @Produce  json
+// @Param id query int true "City id"
+// @Param from query string false "First day, 2006-01-02, 30 days before to by default"
+// @Param to query string false "Last day, 2006-01-02, today by default"
+// @Param granularity query string false "Timeline interval" Enums(day, week, month)
+// @Success 200 {object} models.CityStats "Successfully"
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 404 {object} map[string]string "City not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /stats/city [get]
+func (s StatsHandler) GetCity(c *gin.Context) {
+	ctx, span := s.tracer.Start(c.Request.Context(), GetCityStats)
+	defer span.End()
+
+	var request models.StatsRequest
+
+	if err := c.ShouldBindQuery(&request); err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.BindType, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	span.AddEvent(tracing.CallToService)
+	stats, err := s.statsService.GetCity(ctx, request)
+	if err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.ServiceError, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(geoErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
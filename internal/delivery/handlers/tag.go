@@ -1,14 +1,17 @@
 package handlers
 
 import (
+	"errors"
 	"github.com/gin-gonic/gin"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/trace"
 	"mth/internal/models"
 	"mth/internal/service"
+	"mth/pkg/customerr"
 	tracing "mth/pkg/trace"
 	"net/http"
+	"strconv"
 )
 
 type TagHandler struct {
@@ -23,13 +26,28 @@ func InitTagHandler(tagService service.Tag, tracer trace.Tracer) TagHandler {
 	}
 }
 
-// Create @Summary Create tag
+func tagErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, customerr.BadInput), errors.Is(err, customerr.TagCycle):
+		return http.StatusBadRequest
+	case errors.Is(err, customerr.TagNotFound), errors.Is(err, customerr.TagCategoryNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, customerr.TagNameTaken), errors.Is(err, customerr.TagInUse):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// Create @Summary Create tag, optionally under a parent tag and in a category
 // @Tags tag
 // @Accept  json
 // @Produce  json
 // @Param data body models.TagCreate true "Tag create"
 // @Success 200 {object} int "Successfully created tag with id"
 // @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 404 {object} map[string]string "Parent tag or category not found"
+// @Failure 409 {object} map[string]string "Name is used by a tag or synonym"
 // @Failure 500 {object} map[string]string "Internal server error"
 // @Router /tag/create [post]
 func (t TagHandler) Create(c *gin.Context) {
@@ -54,18 +72,20 @@ func (t TagHandler) Create(c *gin.Context) {
 			attribute.String(tracing.ServiceError, err.Error())),
 		)
 		span.SetStatus(codes.Error, err.Error())
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(tagErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
 	c.JSON(http.StatusOK, id)
 }
 
-// Create @Summary Get all tags
+// GetAll @Summary Get all tags with parents, categories and synonyms
 // @Tags tag
 // @Accept  json
 // @Produce  json
-// @Success 200 {object} []models.Tag "Successfully created tag with id"
+// @Param lang query string false "Response language, takes precedence over Accept-Language"
+// @Param Accept-Language header string false "Preferred response languages"
+// @Success 200 {object} []models.TagDetails "Successfully"
 // @Failure 500 {object} map[string]string "Internal server error"
 // @Router /tag/get_all [get]
 func (t TagHandler) GetAll(c *gin.Context) {
@@ -85,3 +105,331 @@ func (t TagHandler) GetAll(c *gin.Context) {
 
 	c.JSON(http.StatusOK, tags)
 }
+
+// SetHierarchy @Summary Set tag parent and category, 0 clears them
+// @Tags tag
+// @Accept  json
+// @Produce  json
+// @Param data body models.TagHierarchy true "Tag hierarchy"
+// @Success 200 "Successfully"
+// @Failure 400 {object} map[string]string "Invalid input or parent is a descendant of the tag"
+// @Failure 404 {object} map[string]string "Tag, parent or category not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /tag/hierarchy [put]
+func (t TagHandler) SetHierarchy(c *gin.Context) {
+	ctx, span := t.tracer.Start(c.Request.Context(), SetTagHierarchy)
+	defer span.End()
+
+	var hierarchy models.TagHierarchy
+
+	if err := c.ShouldBindJSON(&hierarchy); err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.BindType, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	span.AddEvent(tracing.CallToService)
+	err := t.tagService.SetHierarchy(ctx, hierarchy)
+	if err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.ServiceError, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(tagErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// AddSynonym @Summary Add tag synonym, filters by name also match synonyms
+// @Tags tag
+// @Accept  json
+// @Produce  json
+// @Param data body models.TagSynonym true "Tag synonym"
+// @Success 200 "Successfully"
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 404 {object} map[string]string "Tag not found"
+// @Failure 409 {object} map[string]string "Name is used by a tag or synonym"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /tag/synonym [post]
+func (t TagHandler) AddSynonym(c *gin.Context) {
+	ctx, span := t.tracer.Start(c.Request.Context(), AddTagSynonym)
+	defer span.End()
+
+	var synonym models.TagSynonym
+
+	if err := c.ShouldBindJSON(&synonym); err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.BindType, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	span.AddEvent(tracing.CallToService)
+	err := t.tagService.AddSynonym(ctx, synonym)
+	if err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.ServiceError, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(tagErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// DeleteSynonym @Summary Delete tag synonym
+// @Tags tag
+// @Accept  json
+// @Produce  json
+// @Param name query string true "Synonym"
+// @Success 200 "Successfully"
+// @Failure 404 {object} map[string]string "Synonym not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /tag/synonym [delete]
+func (t TagHandler) DeleteSynonym(c *gin.Context) {
+	ctx, span := t.tracer.Start(c.Request.Context(), DeleteTagSynonym)
+	defer span.End()
+
+	span.AddEvent(tracing.CallToService)
+	err := t.tagService.DeleteSynonym(ctx, c.Query("name"))
+	if err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.ServiceError, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(tagErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// CreateCategory @Summary Create tag category
+// @Tags tag
+// @Accept  json
+// @Produce  json
+// @Param data body models.TagCategoryCreate true "Tag category create"
+// @Success 200 {object} int "Successfully created category with id"
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /tag/category/create [post]
+func (t TagHandler) CreateCategory(c *gin.Context) {
+	ctx, span := t.tracer.Start(c.Request.Context(), CreateTagCategory)
+	defer span.End()
+
+	var categoryCreate models.TagCategoryCreate
+
+	if err := c.ShouldBindJSON(&categoryCreate); err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.BindType, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	span.AddEvent(tracing.CallToService)
+	id, err := t.tagService.CreateCategory(ctx, categoryCreate)
+	if err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.ServiceError, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(tagErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, id)
+}
+
+// GetCategories @Summary Get all tag categories
+// @Tags tag
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} []models.TagCategory "Successfully"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /tag/category/get_all [get]
+func (t TagHandler) GetCategories(c *gin.Context) {
+	ctx, span := t.tracer.Start(c.Request.Context(), GetTagCategories)
+	defer span.End()
+
+	span.AddEvent(tracing.CallToService)
+	categories, err := t.tagService.GetCategories(ctx)
+	if err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.ServiceError, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, categories)
+}
+
+// DeleteCategory @Summary Delete tag category, its tags are left without category
+// @Tags tag
+// @Accept  json
+// @Produce  json
+// @Param id query int true "Category id"
+// @Success 200 "Successfully"
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 404 {object} map[string]string "Category not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /tag/category [delete]
+func (t TagHandler) DeleteCategory(c *gin.Context) {
+	ctx, span := t.tracer.Start(c.Request.Context(), DeleteTagCategory)
+	defer span.End()
+
+	id, err := strconv.Atoi(c.Query("id"))
+	if err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.Input, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	span.AddEvent(tracing.CallToService)
+	err = t.tagService.DeleteCategory(ctx, id)
+	if err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.ServiceError, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(tagErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// Rename @Summary Rename tag
+// @Tags tag
+// @Accept  json
+// @Produce  json
+// @Param data body models.TagRename true "Tag rename"
+// @Success 200 "Successfully"
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 404 {object} map[string]string "Tag not found"
+// @Failure 409 {object} map[string]string "Name is used by a tag or synonym"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /tag/rename [put]
+func (t TagHandler) Rename(c *gin.Context) {
+	ctx, span := t.tracer.Start(c.Request.Context(), RenameTag)
+	defer span.End()
+
+	var rename models.TagRename
+
+	if err := c.ShouldBindJSON(&rename); err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.BindType, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	span.AddEvent(tracing.CallToService)
+	err := t.tagService.Rename(ctx, rename)
+	if err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.ServiceError, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(tagErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// Delete @Summary Delete tag, refused while it is used unless cascade removes it from places and routes
+// @Tags tag
+// @Accept  json
+// @Produce  json
+// @Param id query int true "Tag id"
+// @Param cascade query bool false "Remove tag from places and routes, child tags move to its parent"
+// @Success 200 {object} models.TagDeleteResult "Successfully, removed relations"
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 404 {object} map[string]string "Tag not found"
+// @Failure 409 {object} map[string]string "Tag is used by places, routes or child tags"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /tag [delete]
+func (t TagHandler) Delete(c *gin.Context) {
+	ctx, span := t.tracer.Start(c.Request.Context(), DeleteTag)
+	defer span.End()
+
+	id, err := strconv.Atoi(c.Query("id"))
+	if err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.Input, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	cascade := c.Query("cascade") == "true"
+
+	span.AddEvent(tracing.CallToService)
+	result, err := t.tagService.Delete(ctx, id, cascade)
+	if err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.ServiceError, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(tagErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// Merge @Summary Merge source tag into target, source name becomes a synonym of target
+// @Tags tag
+// @Accept  json
+// @Produce  json
+// @Param data body models.TagMergeRequest true "Tag merge"
+// @Success 200 {object} models.TagMergeResult "Successfully, moved relations"
+// @Failure 400 {object} map[string]string "Invalid input or target is a descendant of source"
+// @Failure 404 {object} map[string]string "Tag not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /tag/merge [post]
+func (t TagHandler) Merge(c *gin.Context) {
+	ctx, span := t.tracer.Start(c.Request.Context(), MergeTags)
+	defer span.End()
+
+	var request models.TagMergeRequest
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.BindType, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	span.AddEvent(tracing.CallToService)
+	result, err := t.tagService.Merge(ctx, request)
+	if err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.ServiceError, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(tagErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
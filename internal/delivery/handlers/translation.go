@@ -0,0 +1,199 @@
+package handlers
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"mth/internal/models"
+	"mth/internal/service"
+	"mth/pkg/customerr"
+	tracing "mth/pkg/trace"
+	"net/http"
+	"strconv"
+)
+
+type TranslationHandler struct {
+	translationService service.Translation
+	tracer             trace.Tracer
+}
+
+func InitTranslationHandler(translationService service.Translation, tracer trace.Tracer) TranslationHandler {
+	return TranslationHandler{
+		translationService: translationService,
+		tracer:             tracer,
+	}
+}
+
+func translationErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, customerr.BadInput), errors.Is(err, customerr.InvalidCursor):
+		return http.StatusBadRequest
+	case errors.Is(err, customerr.TranslationNotFound), errors.Is(err, customerr.TranslationEntityNotFound):
+		return http.StatusNotFound
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// Upsert @Summary Create or replace translation of tag, place or route field
+// @Tags translation
+// @Accept  json
+// @Produce  json
+// @Param data body models.TranslationUpsert true "Translation"
+// @Success 200 "Successfully"
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 404 {object} map[string]string "Entity not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /translation/upsert [put]
+func (t TranslationHandler) Upsert(c *gin.Context) {
+	ctx, span := t.tracer.Start(c.Request.Context(), UpsertTranslation)
+	defer span.End()
+
+	var translation models.TranslationUpsert
+
+	if err := c.ShouldBindJSON(&translation); err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.BindType, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	span.AddEvent(tracing.CallToService)
+	err := t.translationService.Upsert(ctx, translation)
+	if err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.ServiceError, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(translationErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// Delete @Summary Delete translation
+// @Tags translation
+// @Accept  json
+// @Produce  json
+// @Param entity_type query string true "Entity type" Enums(tag, place, route)
+// @Param entity_id query int true "Entity id"
+// @Param lang query string true "Language"
+// @Param field query string true "Field" Enums(name, description)
+// @Success 200 "Successfully"
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 404 {object} map[string]string "Translation not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /translation [delete]
+func (t TranslationHandler) Delete(c *gin.Context) {
+	ctx, span := t.tracer.Start(c.Request.Context(), DeleteTranslation)
+	defer span.End()
+
+	var key models.TranslationKey
+
+	if err := c.ShouldBindQuery(&key); err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.BindType, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	span.AddEvent(tracing.CallToService)
+	err := t.translationService.Delete(ctx, key)
+	if err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.ServiceError, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(translationErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// GetByEntity @Summary Get all translations of entity
+// @Tags translation
+// @Accept  json
+// @Produce  json
+// @Param entity_type query string true "Entity type" Enums(tag, place, route)
+// @Param entity_id query int true "Entity id"
+// @Success 200 {object} []models.Translation "Successfully"
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /translation/by_entity [get]
+func (t TranslationHandler) GetByEntity(c *gin.Context) {
+	ctx, span := t.tracer.Start(c.Request.Context(), GetTranslationsByEntity)
+	defer span.End()
+
+	entityID, err := strconv.Atoi(c.Query("entity_id"))
+	if err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.Input, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	span.AddEvent(tracing.CallToService)
+	translations, err := t.translationService.GetByEntity(ctx, c.Query("entity_type"), entityID)
+	if err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.ServiceError, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(translationErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, translations)
+}
+
+// GetMissing @Summary Get entities without translation of some field into the language
+// @Tags translation
+// @Accept  json
+// @Produce  json
+// @Param entity_type query string true "Entity type" Enums(tag, place, route)
+// @Param lang query string true "Language"
+// @Param cursor query string false "next_cursor from the previous page, empty for the first page"
+// @Param page_size query int false "Page size, capped by MAX_PAGE_SIZE"
+// @Param with_total query bool false "Count total number of entities"
+// @Success 200 {object} models.Page[models.MissingTranslation] "Successfully"
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /translation/missing [get]
+func (t TranslationHandler) GetMissing(c *gin.Context) {
+	ctx, span := t.tracer.Start(c.Request.Context(), GetMissingTranslations)
+	defer span.End()
+
+	var request models.MissingTranslationsRequest
+
+	if err := c.ShouldBindQuery(&request); err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.BindType, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	span.AddEvent(tracing.CallToService)
+	missing, err := t.translationService.GetMissing(ctx, request)
+	if err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.ServiceError, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(translationErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, missing)
+}
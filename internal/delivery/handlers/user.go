@@ -9,6 +9,7 @@ import (
 	"mth/internal/models"
 	"mth/internal/models/swagger"
 	"mth/internal/service"
+	"mth/pkg/customerr"
 	tracing "mth/pkg/trace"
 	"net/http"
 	"strconv"
@@ -301,6 +302,7 @@ func (u UserHandler) GetMyProperties(c *gin.Context) {
 // @Param data body swagger.UserUpdate true "user data"
 // @Success 200 {object} string "user properties json"
 // @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 422 {object} map[string]string "Text is rejected by the content filter"
 // @Failure 500 {object} map[string]string "Internal server error"
 // @Router /user/update_properties [put]
 func (u UserHandler) UpdateProperties(c *gin.Context) {
@@ -323,6 +325,8 @@ func (u UserHandler) UpdateProperties(c *gin.Context) {
 		var status int
 		if strings.Contains(err.Error(), "user not found") {
 			status = http.StatusUnauthorized
+		} else if errors.Is(err, customerr.ContentRejected) {
+			status = http.StatusUnprocessableEntity
 		} else {
 			status = http.StatusInternalServerError
 		}
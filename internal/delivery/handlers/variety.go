@@ -0,0 +1,217 @@
+package handlers
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"mth/internal/models"
+	"mth/internal/service"
+	"mth/pkg/customerr"
+	tracing "mth/pkg/trace"
+	"net/http"
+	"strconv"
+)
+
+type VarietyHandler struct {
+	varietyService service.Variety
+	tracer         trace.Tracer
+}
+
+func InitVarietyHandler(varietyService service.Variety, tracer trace.Tracer) VarietyHandler {
+	return VarietyHandler{
+		varietyService: varietyService,
+		tracer:         tracer,
+	}
+}
+
+// Create @Summary Create place variety
+// @Tags variety
+// @Accept  json
+// @Produce  json
+// @Param data body models.VarietyBase true "Variety create"
+// @Success 200 {object} int "Successfully created variety with id"
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /variety/create [post]
+func (v VarietyHandler) Create(c *gin.Context) {
+	ctx, span := v.tracer.Start(c.Request.Context(), VarietyCreate)
+	defer span.End()
+
+	var varietyCreate models.VarietyBase
+
+	if err := c.ShouldBindJSON(&varietyCreate); err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.BindType, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	span.AddEvent(tracing.CallToService)
+	id, err := v.varietyService.Create(ctx, varietyCreate)
+	if err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.ServiceError, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+
+		if errors.Is(err, customerr.BadInput) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, id)
+}
+
+// GetAll @Summary Get all place varieties
+// @Tags variety
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} []models.Variety "Successfully"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /variety/get_all [get]
+func (v VarietyHandler) GetAll(c *gin.Context) {
+	ctx, span := v.tracer.Start(c.Request.Context(), GetAllVarieties)
+	defer span.End()
+
+	span.AddEvent(tracing.CallToService)
+	varieties, err := v.varietyService.GetAll(ctx)
+	if err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.ServiceError, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, varieties)
+}
+
+// GetByName @Summary Get place variety by name
+// @Tags variety
+// @Accept  json
+// @Produce  json
+// @Param name query string true "Variety name"
+// @Success 200 {object} models.Variety "Successfully"
+// @Failure 404 {object} map[string]string "Unknown variety"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /variety/by_name [get]
+func (v VarietyHandler) GetByName(c *gin.Context) {
+	ctx, span := v.tracer.Start(c.Request.Context(), GetVarietyByName)
+	defer span.End()
+
+	span.AddEvent(tracing.CallToService)
+	variety, err := v.varietyService.GetByName(ctx, c.Query("name"))
+	if err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.ServiceError, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+
+		if errors.Is(err, customerr.UnknownVariety) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, variety)
+}
+
+// Update @Summary Update place variety, renaming moves places to the new name
+// @Tags variety
+// @Accept  json
+// @Produce  json
+// @Param data body models.Variety true "Variety update"
+// @Success 200 "Successfully"
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /variety/update [put]
+func (v VarietyHandler) Update(c *gin.Context) {
+	ctx, span := v.tracer.Start(c.Request.Context(), VarietyUpdate)
+	defer span.End()
+
+	var varietyUpd models.Variety
+
+	if err := c.ShouldBindJSON(&varietyUpd); err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.BindType, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	span.AddEvent(tracing.CallToService)
+	err := v.varietyService.Update(ctx, varietyUpd)
+	if err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.ServiceError, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+
+		if errors.Is(err, customerr.BadInput) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// Delete @Summary Delete place variety, refused while places use it
+// @Tags variety
+// @Accept  json
+// @Produce  json
+// @Param id query int true "Variety id"
+// @Success 200 "Successfully"
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 409 {object} map[string]string "Variety is used by places"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /variety [delete]
+func (v VarietyHandler) Delete(c *gin.Context) {
+	ctx, span := v.tracer.Start(c.Request.Context(), VarietyDelete)
+	defer span.End()
+
+	id, err := strconv.Atoi(c.Query("id"))
+	if err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.Input, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	span.AddEvent(tracing.CallToService)
+	err = v.varietyService.Delete(ctx, id)
+	if err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.ServiceError, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+
+		if errors.Is(err, customerr.VarietyInUse) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
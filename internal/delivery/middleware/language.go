@@ -0,0 +1,25 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"mth/pkg/locale"
+)
+
+// ResponseLanguageParam параметр запроса с языком ответа. Эндпоинты переводов не переводят ответ,
+// поэтому их собственный параметр lang с ним не конфликтует
+const ResponseLanguageParam = "lang"
+
+// LanguageMiddleware кладёт в контекст запроса языки из параметра lang и заголовка Accept-Language.
+// Ответ зависит от Accept-Language, поэтому он указывается в Vary для кэшей
+func (m Middleware) LanguageMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Writer.Header().Add("Vary", "Accept-Language")
+
+		langs := locale.Parse(c.Query(ResponseLanguageParam), c.GetHeader("Accept-Language"))
+		if len(langs) > 0 {
+			c.Request = c.Request.WithContext(locale.WithLanguages(c.Request.Context(), langs))
+		}
+
+		c.Next()
+	}
+}
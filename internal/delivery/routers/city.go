@@ -0,0 +1,28 @@
+package routers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/jmoiron/sqlx"
+	"go.opentelemetry.io/otel/trace"
+	"mth/internal/delivery/handlers"
+	"mth/internal/repository"
+	"mth/internal/service"
+	"mth/pkg/log"
+)
+
+func RegisterCityRouter(r *gin.Engine, db *sqlx.DB, logger *log.Logs, tracer trace.Tracer) *gin.RouterGroup {
+	cityRouter := r.Group("/city")
+
+	cityRepo := repository.InitCityRepo(db)
+
+	cityService := service.InitCityService(cityRepo, logger)
+	cityHandler := handlers.InitCityHandler(cityService, tracer)
+
+	cityRouter.POST("/create", cityHandler.Create)
+	cityRouter.GET("/get_all", cityHandler.GetAll)
+	cityRouter.GET("/by_id", cityHandler.GetByID)
+	cityRouter.PUT("/update", cityHandler.Update)
+	cityRouter.DELETE("", cityHandler.Delete)
+
+	return cityRouter
+}
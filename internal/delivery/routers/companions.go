@@ -14,10 +14,15 @@ func RegisterCompanionsRouter(r *gin.Engine, db *sqlx.DB, logger *log.Logs, trac
 	companionsRouter := r.Group("/companions")
 
 	companionsRepo := repository.InitCompanionsRepo(db)
+	translationRepo := repository.InitTranslationRepo(db)
 
-	companionsService := service.InitCompanionsService(companionsRepo, logger)
+	companionsService := service.InitCompanionsService(companionsRepo, translationRepo, logger)
 	companionsHandler := handlers.InitCompanionsHandler(companionsService, tracer)
 
+	requestRepo := repository.InitCompanionRequestRepo(db)
+	requestService := service.InitCompanionRequestService(requestRepo, translationRepo, logger)
+	requestHandler := handlers.InitCompanionRequestHandler(requestService, tracer)
+
 	companionsRouter.POST("/create_place_companion", companionsHandler.CreateCompanionPlace)
 	companionsRouter.POST("/create_route_companion", companionsHandler.CreateCompanionRoute)
 	companionsRouter.GET("/by_user", companionsHandler.GetByUser)
@@ -26,5 +31,12 @@ func RegisterCompanionsRouter(r *gin.Engine, db *sqlx.DB, logger *log.Logs, trac
 	companionsRouter.DELETE("/place", companionsHandler.DeleteFromPlace)
 	companionsRouter.DELETE("/route", companionsHandler.DeleteFromRoute)
 
+	companionsRouter.POST("/request", requestHandler.Create)
+	companionsRouter.PUT("/request/accept", requestHandler.Accept)
+	companionsRouter.PUT("/request/decline", requestHandler.Decline)
+	companionsRouter.PUT("/request/cancel", requestHandler.Cancel)
+	companionsRouter.GET("/requests/incoming", requestHandler.GetIncoming)
+	companionsRouter.GET("/requests/outgoing", requestHandler.GetOutgoing)
+
 	return companionsRouter
 }
@@ -0,0 +1,25 @@
+package routers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/jmoiron/sqlx"
+	"go.opentelemetry.io/otel/trace"
+	"mth/internal/delivery/handlers"
+	"mth/internal/repository"
+	"mth/internal/service"
+	"mth/pkg/log"
+)
+
+func RegisterContentReportRouter(r *gin.Engine, db *sqlx.DB, logger *log.Logs, tracer trace.Tracer) *gin.RouterGroup {
+	moderationRouter := r.Group("/moderation")
+
+	contentReportRepo := repository.InitContentReportRepo(db)
+
+	contentReportService := service.InitContentReportService(contentReportRepo, logger)
+	contentReportHandler := handlers.InitContentReportHandler(contentReportService, tracer)
+
+	moderationRouter.GET("/content", contentReportHandler.GetOpen)
+	moderationRouter.PUT("/content/resolve", contentReportHandler.Resolve)
+
+	return moderationRouter
+}
@@ -18,7 +18,13 @@ func RegisterDistrictRouter(r *gin.Engine, db *sqlx.DB, logger *log.Logs, tracer
 	districtService := service.InitDistrictService(districtRepo, logger)
 	districtHandler := handlers.InitDistrictHandler(districtService, tracer)
 
-	districtRouter.GET("/by_city_id", districtHandler.GetByID)
+	districtRouter.GET("/by_city_id", districtHandler.GetByCityID)
+	districtRouter.POST("/create", districtHandler.Create)
+	districtRouter.GET("/by_id", districtHandler.GetByID)
+	districtRouter.PUT("/update", districtHandler.Update)
+	districtRouter.DELETE("", districtHandler.Delete)
+	districtRouter.GET("/polygons", districtHandler.GetPolygons)
+	districtRouter.POST("/reconcile", districtHandler.Reconcile)
 
 	return districtRouter
 }
@@ -17,7 +17,8 @@ func RegisterFavouriteRouter(r *gin.Engine, db *sqlx.DB, logger *log.Logs, trace
 	routeRepo := repository.InitRouteRepo(db)
 	favouriteRepo := repository.InitFavouriteRepo(db)
 
-	favouriteService := service.InitFavouriteService(favouriteRepo, placeRepo, routeRepo, logger)
+	favouriteService := service.InitFavouriteService(favouriteRepo, placeRepo, routeRepo,
+		repository.InitTranslationRepo(db), logger)
 	favouriteHandler := handlers.InitFavouriteHandler(favouriteService, tracer)
 
 	favouriteRouter.POST("/like_place", favouriteHandler.LikePlace)
@@ -0,0 +1,41 @@
+package routers
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"github.com/jmoiron/sqlx"
+	"github.com/spf13/viper"
+	"go.opentelemetry.io/otel/trace"
+	"mth/internal/delivery/handlers"
+	"mth/internal/repository"
+	"mth/internal/service"
+	"mth/pkg/config"
+	"mth/pkg/log"
+	"mth/pkg/storage"
+)
+
+func RegisterMediaRouter(ctx context.Context, r *gin.Engine, db *sqlx.DB, logger *log.Logs, tracer trace.Tracer) *gin.RouterGroup {
+	mediaRouter := r.Group("/media")
+
+	mediaStorage, err := storage.InitLocalStorage(viper.GetString(config.MediaRoot), viper.GetString(config.MediaBaseURL))
+	if err != nil {
+		panic(err.Error())
+	}
+
+	mediaRepo := repository.InitMediaRepo(db)
+
+	mediaService := service.InitMediaService(mediaRepo, mediaStorage, logger)
+	mediaHandler := handlers.InitMediaHandler(mediaService, tracer)
+
+	mediaService.StartOrphanCollector(ctx)
+
+	mediaRouter.POST("/upload", mediaHandler.Upload)
+	mediaRouter.GET("/file/*key", mediaHandler.GetFile)
+	mediaRouter.GET("/by_id", mediaHandler.GetByID)
+	mediaRouter.GET("/by_entity", mediaHandler.GetByEntity)
+	mediaRouter.POST("/attach", mediaHandler.Attach)
+	mediaRouter.DELETE("/attach", mediaHandler.Detach)
+	mediaRouter.PUT("/attach/position", mediaHandler.ChangePosition)
+
+	return mediaRouter
+}
@@ -14,8 +14,9 @@ func RegisterNoteRouter(r *gin.Engine, db *sqlx.DB, logger *log.Logs, tracer tra
 	noteRouter := r.Group("/note")
 
 	noteRepo := repository.InitNoteRepo(db)
+	contentReportRepo := repository.InitContentReportRepo(db)
 
-	noteService := service.InitNoteService(noteRepo, logger)
+	noteService := service.InitNoteService(noteRepo, contentReportRepo, logger)
 	noteHandler := handlers.InitNoteHandler(noteService, tracer)
 
 	noteRouter.POST("/create", noteHandler.Create)
@@ -23,6 +24,15 @@ func RegisterNoteRouter(r *gin.Engine, db *sqlx.DB, logger *log.Logs, tracer tra
 	noteRouter.GET("/by_user_id", noteHandler.GetByUserID)
 	noteRouter.GET("/by_id", noteHandler.GetByNoteID)
 	noteRouter.PUT("/update", noteHandler.Update)
+	noteRouter.POST("", noteHandler.CreateForTarget)
+	noteRouter.GET("/by_target", noteHandler.GetByTarget)
+	noteRouter.PUT("", noteHandler.UpdateByID)
+	noteRouter.DELETE("", noteHandler.Delete)
+	noteRouter.GET("/feed", noteHandler.GetFeed)
+	noteRouter.POST("/share", noteHandler.CreateShareToken)
+	noteRouter.GET("/shares", noteHandler.GetShareTokens)
+	noteRouter.DELETE("/share", noteHandler.RevokeShareToken)
+	noteRouter.GET("/shared", noteHandler.GetByShareToken)
 
 	return noteRouter
 }
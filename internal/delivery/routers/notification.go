@@ -0,0 +1,25 @@
+package routers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/jmoiron/sqlx"
+	"go.opentelemetry.io/otel/trace"
+	"mth/internal/delivery/handlers"
+	"mth/internal/repository"
+	"mth/internal/service"
+	"mth/pkg/log"
+)
+
+func RegisterNotificationRouter(r *gin.Engine, db *sqlx.DB, logger *log.Logs, tracer trace.Tracer) *gin.RouterGroup {
+	notificationRouter := r.Group("/notification")
+
+	notificationRepo := repository.InitNotificationRepo(db)
+
+	notificationService := service.InitNotificationService(notificationRepo, logger)
+	notificationHandler := handlers.InitNotificationHandler(notificationService, tracer)
+
+	notificationRouter.GET("", notificationHandler.GetByUser)
+	notificationRouter.PUT("/read", notificationHandler.MarkRead)
+
+	return notificationRouter
+}
@@ -1,6 +1,7 @@
 package routers
 
 import (
+	"context"
 	"github.com/gin-gonic/gin"
 	"github.com/jmoiron/sqlx"
 	"go.opentelemetry.io/otel/trace"
@@ -10,17 +11,49 @@ import (
 	"mth/pkg/log"
 )
 
-func RegisterPlaceRouter(r *gin.Engine, db *sqlx.DB, logger *log.Logs, tracer trace.Tracer) *gin.RouterGroup {
+func RegisterPlaceRouter(ctx context.Context, r *gin.Engine, db *sqlx.DB, logger *log.Logs, tracer trace.Tracer) *gin.RouterGroup {
 	placeRouter := r.Group("/place")
 
 	placeRepo := repository.InitPlaceRepo(db)
 
-	placeService := service.InitPlaceService(placeRepo, logger)
+	varietyRepo := repository.InitVarietyRepo(db)
+
+	districtRepo := repository.InitDistrictRepo(db)
+
+	tagRepo := repository.InitTagRepo(db)
+
+	translationRepo := repository.InitTranslationRepo(db)
+
+	placeService := service.InitPlaceService(placeRepo, varietyRepo, districtRepo, tagRepo, translationRepo, logger)
 	placeHandler := handlers.InitPlaceHandler(placeService, tracer)
 
+	importRepo := repository.InitImportRepo(db)
+	importService := service.InitImportService(importRepo, districtRepo, logger)
+	importHandler := handlers.InitImportHandler(importService, tracer)
+
+	popularityRepo := repository.InitPopularityRepo(db)
+	popularityService := service.InitPopularityService(popularityRepo, logger)
+	popularityService.StartScoring(ctx)
+
+	duplicateRepo := repository.InitPlaceDuplicateRepo(db, service.MarkScale())
+	duplicateService := service.InitPlaceDuplicateService(duplicateRepo, logger)
+	duplicateHandler := handlers.InitPlaceDuplicateHandler(duplicateService, tracer)
+	duplicateService.StartDetection(ctx)
+
 	placeRouter.POST("/create", placeHandler.Create)
 	placeRouter.GET("/by_id", placeHandler.GetByID)
 	placeRouter.PUT("/get_all_with_filter", placeHandler.GetAllWithFilter)
+	placeRouter.PUT("/geojson", placeHandler.GetGeoJSON)
+	placeRouter.GET("/trending", placeHandler.GetTrending)
+	placeRouter.PUT("/update", placeHandler.Update)
+	placeRouter.PUT("/archive", placeHandler.SetArchived)
+	placeRouter.DELETE("", placeHandler.Delete)
+	placeRouter.POST("/import", importHandler.ImportPlaces)
+	placeRouter.GET("/duplicates", duplicateHandler.GetPending)
+	placeRouter.POST("/duplicates/detect", duplicateHandler.Detect)
+	placeRouter.PUT("/duplicates/dismiss", duplicateHandler.Dismiss)
+	placeRouter.POST("/merge", duplicateHandler.Merge)
+	placeRouter.GET("/merges", duplicateHandler.GetMerges)
 
 	return placeRouter
 }
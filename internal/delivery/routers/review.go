@@ -13,9 +13,10 @@ import (
 func RegisterReviewRouter(r *gin.Engine, db *sqlx.DB, logger *log.Logs, tracer trace.Tracer) *gin.RouterGroup {
 	reviewRouter := r.Group("/review")
 
-	reviewRepo := repository.InitReviewRepo(db)
+	reviewRepo := repository.InitReviewRepo(db, service.MarkScale())
+	contentReportRepo := repository.InitContentReportRepo(db)
 
-	reviewService := service.InitReviewService(reviewRepo, logger)
+	reviewService := service.InitReviewService(reviewRepo, contentReportRepo, logger)
 	reviewHandler := handlers.InitReviewHandler(reviewService, tracer)
 
 	reviewRouter.POST("/create_on_route", reviewHandler.CreateOnRoute)
@@ -25,6 +26,18 @@ func RegisterReviewRouter(r *gin.Engine, db *sqlx.DB, logger *log.Logs, tracer t
 	reviewRouter.GET("/route", reviewHandler.GetByRoute)
 	reviewRouter.PUT("/update_on_place", reviewHandler.UpdateOnPlace)
 	reviewRouter.PUT("/update_on_route", reviewHandler.UpdateOnRoute)
+	reviewRouter.DELETE("", reviewHandler.Delete)
+	reviewRouter.POST("/flag", reviewHandler.Flag)
+	reviewRouter.POST("/moderate", reviewHandler.Moderate)
+	reviewRouter.GET("/moderation_queue", reviewHandler.GetModerationQueue)
+	reviewRouter.POST("/vote", reviewHandler.Vote)
+	reviewRouter.DELETE("/vote", reviewHandler.DeleteVote)
+	reviewRouter.PUT("/manager", reviewHandler.SetManager)
+	reviewRouter.DELETE("/manager", reviewHandler.DeleteManager)
+	reviewRouter.GET("/managers", reviewHandler.GetManagers)
+	reviewRouter.PUT("/reply", reviewHandler.UpsertReply)
+	reviewRouter.DELETE("/reply", reviewHandler.DeleteReply)
+	reviewRouter.GET("/reply/history", reviewHandler.GetReplyHistory)
 
 	return reviewRouter
 }
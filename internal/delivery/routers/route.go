@@ -15,13 +15,16 @@ func RegisterRouteRouter(r *gin.Engine, db *sqlx.DB, logger *log.Logs, tracer tr
 
 	routeRepo := repository.InitRouteRepo(db)
 	placeRepo := repository.InitPlaceRepo(db)
+	tagRepo := repository.InitTagRepo(db)
+	translationRepo := repository.InitTranslationRepo(db)
 
-	routeService := service.InitRouteService(routeRepo, placeRepo, logger)
+	routeService := service.InitRouteService(routeRepo, placeRepo, tagRepo, translationRepo, logger)
 	routeHandler := handlers.InitRouteHandler(routeService, tracer)
 
 	routeRouter.POST("/create", routeHandler.Create)
 	routeRouter.GET("/by_id", routeHandler.GetRouteByID)
 	routeRouter.GET("/by_page", routeHandler.GetRouteByPage)
+	routeRouter.GET("/geojson", routeHandler.GetGeoJSON)
 
 	return routeRouter
 }
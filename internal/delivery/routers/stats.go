@@ -0,0 +1,27 @@
+package routers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/jmoiron/sqlx"
+	"go.opentelemetry.io/otel/trace"
+	"mth/internal/delivery/handlers"
+	"mth/internal/repository"
+	"mth/internal/service"
+	"mth/pkg/log"
+)
+
+func RegisterStatsRouter(r *gin.Engine, db *sqlx.DB, logger *log.Logs, tracer trace.Tracer) *gin.RouterGroup {
+	statsRouter := r.Group("/stats")
+
+	statsRepo := repository.InitStatsRepo(db)
+	cityRepo := repository.InitCityRepo(db)
+	districtRepo := repository.InitDistrictRepo(db)
+
+	statsService := service.InitStatsService(statsRepo, cityRepo, districtRepo, logger)
+	statsHandler := handlers.InitStatsHandler(statsService, tracer)
+
+	statsRouter.GET("/district", statsHandler.GetDistrict)
+	statsRouter.GET("/city", statsHandler.GetCity)
+
+	return statsRouter
+}
@@ -15,11 +15,22 @@ func RegisterTagRouter(r *gin.Engine, db *sqlx.DB, logger *log.Logs, tracer trac
 
 	tagRepo := repository.InitTagRepo(db)
 
-	tagService := service.InitTagService(tagRepo, logger)
+	translationRepo := repository.InitTranslationRepo(db)
+
+	tagService := service.InitTagService(tagRepo, translationRepo, logger)
 	tagHandler := handlers.InitTagHandler(tagService, tracer)
 
 	tagRouter.POST("/create", tagHandler.Create)
 	tagRouter.GET("/get_all", tagHandler.GetAll)
+	tagRouter.PUT("/hierarchy", tagHandler.SetHierarchy)
+	tagRouter.PUT("/rename", tagHandler.Rename)
+	tagRouter.DELETE("", tagHandler.Delete)
+	tagRouter.POST("/merge", tagHandler.Merge)
+	tagRouter.POST("/synonym", tagHandler.AddSynonym)
+	tagRouter.DELETE("/synonym", tagHandler.DeleteSynonym)
+	tagRouter.POST("/category/create", tagHandler.CreateCategory)
+	tagRouter.GET("/category/get_all", tagHandler.GetCategories)
+	tagRouter.DELETE("/category", tagHandler.DeleteCategory)
 
 	return tagRouter
 }
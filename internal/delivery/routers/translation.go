@@ -0,0 +1,27 @@
+package routers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/jmoiron/sqlx"
+	"go.opentelemetry.io/otel/trace"
+	"mth/internal/delivery/handlers"
+	"mth/internal/repository"
+	"mth/internal/service"
+	"mth/pkg/log"
+)
+
+func RegisterTranslationRouter(r *gin.Engine, db *sqlx.DB, logger *log.Logs, tracer trace.Tracer) *gin.RouterGroup {
+	translationRouter := r.Group("/translation")
+
+	translationRepo := repository.InitTranslationRepo(db)
+
+	translationService := service.InitTranslationService(translationRepo, logger)
+	translationHandler := handlers.InitTranslationHandler(translationService, tracer)
+
+	translationRouter.PUT("/upsert", translationHandler.Upsert)
+	translationRouter.DELETE("", translationHandler.Delete)
+	translationRouter.GET("/by_entity", translationHandler.GetByEntity)
+	translationRouter.GET("/missing", translationHandler.GetMissing)
+
+	return translationRouter
+}
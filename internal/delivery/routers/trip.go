@@ -3,11 +3,14 @@ package routers
 import (
 	"github.com/gin-gonic/gin"
 	"github.com/jmoiron/sqlx"
+	"github.com/spf13/viper"
 	"go.opentelemetry.io/otel/trace"
 	"mth/internal/delivery/handlers"
 	"mth/internal/repository"
 	"mth/internal/service"
+	"mth/pkg/config"
 	"mth/pkg/log"
+	"mth/pkg/storage"
 )
 
 func RegisterTripRouter(r *gin.Engine, db *sqlx.DB, logger *log.Logs, tracer trace.Tracer) *gin.RouterGroup {
@@ -18,9 +21,19 @@ func RegisterTripRouter(r *gin.Engine, db *sqlx.DB, logger *log.Logs, tracer tra
 	tripService := service.InitTripService(tripRepo, logger)
 	tripHandler := handlers.InitTripHandler(tripService, tracer)
 
+	mediaStorage, err := storage.InitLocalStorage(viper.GetString(config.MediaRoot), viper.GetString(config.MediaBaseURL))
+	if err != nil {
+		panic(err.Error())
+	}
+
+	diaryService := service.InitDiaryService(tripRepo, repository.InitNoteRepo(db), repository.InitReviewRepo(db, service.MarkScale()),
+		repository.InitMediaRepo(db), repository.InitTranslationRepo(db), mediaStorage, logger)
+	diaryHandler := handlers.InitDiaryHandler(diaryService, tracer)
+
 	tripRouter.POST("/create", tripHandler.Create)
 	tripRouter.GET("/by_id", tripHandler.GetByID)
 	tripRouter.GET("/by_user_id", tripHandler.GetByUser)
+	tripRouter.GET("/diary", diaryHandler.Export)
 
 	tripRouter.PUT("/route/add", tripHandler.AddRoute)
 	tripRouter.PUT("/route/change/day", tripHandler.ChangeRouteDay)
@@ -18,9 +18,12 @@ func RegisterUserRouter(r *gin.Engine, db *sqlx.DB, logger *log.Logs, tracer tra
 	routeRepo := repository.InitRouteRepo(db)
 	placeRepo := repository.InitPlaceRepo(db)
 	tripRepo := repository.InitTripRepo(db)
-	reviewRepo := repository.InitReviewRepo(db)
+	reviewRepo := repository.InitReviewRepo(db, service.MarkScale())
+	varietyRepo := repository.InitVarietyRepo(db)
+	contentReportRepo := repository.InitContentReportRepo(db)
 
-	userService := service.InitUserService(userRepo, logger, favouriteRepo, routeRepo, placeRepo, tripRepo, reviewRepo)
+	userService := service.InitUserService(userRepo, logger, favouriteRepo, routeRepo, placeRepo, tripRepo, reviewRepo, varietyRepo,
+		contentReportRepo)
 	userHandler := handlers.InitUserHandler(userService, tracer)
 
 	userRouter.POST("/check_in", userHandler.CheckIn)
@@ -0,0 +1,28 @@
+package routers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/jmoiron/sqlx"
+	"go.opentelemetry.io/otel/trace"
+	"mth/internal/delivery/handlers"
+	"mth/internal/repository"
+	"mth/internal/service"
+	"mth/pkg/log"
+)
+
+func RegisterVarietyRouter(r *gin.Engine, db *sqlx.DB, logger *log.Logs, tracer trace.Tracer) *gin.RouterGroup {
+	varietyRouter := r.Group("/variety")
+
+	varietyRepo := repository.InitVarietyRepo(db)
+
+	varietyService := service.InitVarietyService(varietyRepo, logger)
+	varietyHandler := handlers.InitVarietyHandler(varietyService, tracer)
+
+	varietyRouter.POST("/create", varietyHandler.Create)
+	varietyRouter.GET("/get_all", varietyHandler.GetAll)
+	varietyRouter.GET("/by_name", varietyHandler.GetByName)
+	varietyRouter.PUT("/update", varietyHandler.Update)
+	varietyRouter.DELETE("", varietyHandler.Delete)
+
+	return varietyRouter
+}
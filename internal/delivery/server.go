@@ -1,6 +1,8 @@
 package delivery
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"github.com/gin-gonic/gin"
 	"github.com/jmoiron/sqlx"
@@ -9,12 +11,24 @@ import (
 	"mth/internal/delivery/middleware"
 	"mth/internal/delivery/routers"
 	"mth/pkg/log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 )
 
+// shutdownTimeout время на завершение запросов после сигнала остановки
+const shutdownTimeout = 10 * time.Second
+
+// Start фоновые задачи сервисов останавливаются вместе с сервером по SIGINT или SIGTERM
 func Start(db *sqlx.DB, tracer trace.Tracer, logger *log.Logs) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
 	r := gin.Default()
 
 	docs.SwaggerInfo.BasePath = "/"
@@ -23,10 +37,23 @@ func Start(db *sqlx.DB, tracer trace.Tracer, logger *log.Logs) {
 	mdw := middleware.InitMiddleware(logger)
 
 	r.Use(mdw.CORSMiddleware())
+	r.Use(mdw.LanguageMiddleware())
+
+	routers.InitRouting(ctx, r, db, logger, tracer)
+
+	server := &http.Server{Addr: "0.0.0.0:8080", Handler: r}
+	go func() {
+		<-ctx.Done()
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
 
-	routers.InitRouting(r, db, logger, tracer)
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			logger.Error(fmt.Sprintf("error shutting down client: %v", err.Error()))
+		}
+	}()
 
-	if err := r.Run("0.0.0.0:8080"); err != nil {
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
 		panic(fmt.Sprintf("error running client: %v", err.Error()))
 	}
 }
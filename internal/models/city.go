@@ -0,0 +1,15 @@
+package models
+
+// CityBase TimeZone в формате IANA (Europe/Moscow), Currency - код ISO 4217
+type CityBase struct {
+	Name     string `json:"name"`
+	Country  string `json:"country"`
+	TimeZone string `json:"time_zone"`
+	Center   *Point `json:"center,omitempty"`
+	Currency string `json:"currency"`
+}
+
+type City struct {
+	ID int `json:"id"`
+	CityBase
+}
@@ -3,10 +3,10 @@ package models
 import "time"
 
 type CompanionsFilters struct {
-	Page     int       `json:"page"`
 	EntityID int       `json:"entity_id"`
 	DateFrom time.Time `json:"date_from"`
 	DateTo   time.Time `json:"date_to"`
+	PageRequest
 }
 
 type CompanionCreateBase struct {
@@ -48,3 +48,56 @@ type CompanionsRoute struct {
 	RouteProperties interface{} `json:"route_properties"`
 	CompanionBase
 }
+
+const (
+	CompanionListingPlace = "place"
+	CompanionListingRoute = "route"
+)
+
+const (
+	CompanionRequestPending   = "pending"
+	CompanionRequestAccepted  = "accepted"
+	CompanionRequestDeclined  = "declined"
+	CompanionRequestCancelled = "cancelled"
+)
+
+// CompanionRequestCreate ListingID - id записи companions_places или companions_routes
+type CompanionRequestCreate struct {
+	ListingType string `json:"listing_type" enums:"place,route"`
+	ListingID   int    `json:"listing_id"`
+	RequesterID int    `json:"requester_id"`
+	Message     string `json:"message"`
+}
+
+// CompanionRequestAction UserID - автор объявления для accept и decline, автор запроса для cancel
+type CompanionRequestAction struct {
+	ID     int `json:"id"`
+	UserID int `json:"user_id"`
+}
+
+// CompanionRequestsRequest пустой Status - запросы во всех статусах
+type CompanionRequestsRequest struct {
+	UserID int    `form:"user_id" binding:"required"`
+	Status string `form:"status" enums:"pending,accepted,declined,cancelled"`
+	PageRequest
+}
+
+// CompanionRequest EntityID - место или маршрут объявления, UserProperties - профиль второй стороны без контактов,
+// Contacts заполняются только у принятых запросов
+type CompanionRequest struct {
+	ID             int                    `json:"id"`
+	ListingType    string                 `json:"listing_type"`
+	ListingID      int                    `json:"listing_id"`
+	EntityID       int                    `json:"entity_id"`
+	ListingName    string                 `json:"listing_name"`
+	DateFrom       time.Time              `json:"date_from"`
+	DateTo         time.Time              `json:"date_to"`
+	RequesterID    int                    `json:"requester_id"`
+	OwnerID        int                    `json:"owner_id"`
+	Status         string                 `json:"status" enums:"pending,accepted,declined,cancelled"`
+	Message        string                 `json:"message"`
+	UserProperties interface{}            `json:"user_properties"`
+	Contacts       map[string]interface{} `json:"contacts,omitempty"`
+	CreatedAt      time.Time              `json:"created_at"`
+	UpdatedAt      time.Time              `json:"updated_at"`
+}
@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+const (
+	ContentReportNote = "note"
+	ContentReportUser = "user"
+)
+
+// ContentReport текст, отправленный фильтром на модерацию, Rules - сработавшие правила
+type ContentReport struct {
+	ID         int       `json:"id"`
+	EntityType string    `json:"entity_type" enums:"note,user"`
+	EntityID   int       `json:"entity_id"`
+	UserID     int       `json:"user_id"`
+	Rules      []string  `json:"rules"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type ContentReportsRequest struct {
+	ModeratorID int `form:"moderator_id" binding:"required"`
+	PageRequest
+}
+
+type ContentReportResolve struct {
+	ModeratorID int `json:"moderator_id"`
+	ID          int `json:"id"`
+}
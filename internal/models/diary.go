@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// TripDiaryRequest дневник выгружает только владелец поездки
+type TripDiaryRequest struct {
+	TripID int    `form:"trip_id" binding:"required"`
+	UserID int    `form:"user_id" binding:"required"`
+	Format string `form:"format" binding:"required" enums:"md,html,epub"`
+}
+
+// TripStop место или маршрут поездки, CheckedInAt - отметка владельца поездки в месте в даты поездки
+type TripStop struct {
+	Day         int
+	Position    int
+	EntityType  string
+	EntityID    int
+	Name        string
+	CheckedInAt *time.Time
+}
@@ -1,8 +1,41 @@
 package models
 
+import "mth/pkg/geojson"
+
+// DistrictBase Boundary - GeoJSON Polygon или MultiPolygon, по нему району назначаются места с координатами
+type DistrictBase struct {
+	Name       string            `json:"name"`
+	CityID     int               `json:"city_id"`
+	Properties interface{}       `json:"properties"`
+	Boundary   *geojson.Geometry `json:"boundary,omitempty" swaggertype:"object"`
+}
+
 type District struct {
-	ID         int         `json:"id"`
-	Name       string      `json:"name"`
-	CityID     int         `json:"city_id"`
-	Properties interface{} `json:"properties"`
+	ID int `json:"id"`
+	DistrictBase
+}
+
+// DistrictUpdate город района не меняется, иначе места района окажутся в чужом городе
+type DistrictUpdate struct {
+	ID         int               `json:"id"`
+	Name       string            `json:"name"`
+	Properties interface{}       `json:"properties"`
+	Boundary   *geojson.Geometry `json:"boundary,omitempty" swaggertype:"object"`
+}
+
+// DistrictMismatch ComputedDistrictID равен 0, если точка не попала ни в один район города
+type DistrictMismatch struct {
+	PlaceID            int    `json:"place_id"`
+	PlaceName          string `json:"place_name"`
+	CityID             int    `json:"city_id"`
+	StoredDistrictID   int    `json:"stored_district_id"`
+	ComputedDistrictID int    `json:"computed_district_id"`
+}
+
+// DistrictReconciliation проверяются только места с координатами в городах, у которых есть границы районов
+type DistrictReconciliation struct {
+	Checked    int                `json:"checked"`
+	Mismatches []DistrictMismatch `json:"mismatches"`
+	Applied    bool               `json:"applied"`
+	Updated    int                `json:"updated"`
 }
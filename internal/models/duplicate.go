@@ -0,0 +1,68 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	DuplicateStatusPending   = "pending"
+	DuplicateStatusDismissed = "dismissed"
+)
+
+// PlaceLocation минимум данных места для поиска дублей и сверки районов
+type PlaceLocation struct {
+	ID          int
+	Name        string
+	CityID      int
+	DistrictID  int
+	Coordinates *Point
+}
+
+// PlaceDuplicate PlaceID всегда меньше DuplicateID, Distance в метрах, если у обоих мест есть координаты
+type PlaceDuplicate struct {
+	ID            int       `json:"id"`
+	PlaceID       int       `json:"place_id"`
+	PlaceName     string    `json:"place_name"`
+	DuplicateID   int       `json:"duplicate_id"`
+	DuplicateName string    `json:"duplicate_name"`
+	Similarity    float64   `json:"similarity"`
+	Distance      *float64  `json:"distance,omitempty"`
+	Status        string    `json:"status"`
+	DetectedAt    time.Time `json:"detected_at"`
+}
+
+// PlaceMergeRequest всё, что ссылается на DuplicateID, переносится на SurvivorID, дубль удаляется
+type PlaceMergeRequest struct {
+	SurvivorID  int `json:"survivor_id"`
+	DuplicateID int `json:"duplicate_id"`
+	UserID      int `json:"user_id"`
+}
+
+// PlaceMergeCounts сколько записей перенесено и сколько отброшено из-за конфликтов уникальности
+type PlaceMergeCounts struct {
+	Tags              int `json:"tags"`
+	Reviews           int `json:"reviews"`
+	ReviewsDropped    int `json:"reviews_dropped"`
+	Notes             int `json:"notes"`
+	CheckIns          int `json:"checkins"`
+	CheckInsDropped   int `json:"checkins_dropped"`
+	Favourites        int `json:"favourites"`
+	FavouritesDropped int `json:"favourites_dropped"`
+	RouteStops        int `json:"route_stops"`
+	RouteStopsDropped int `json:"route_stops_dropped"`
+	TripEntries       int `json:"trip_entries"`
+	Companions        int `json:"companions"`
+	Media             int `json:"media"`
+}
+
+// PlaceMerge запись журнала слияний, Duplicate - снимок удалённого места
+type PlaceMerge struct {
+	ID          int              `json:"id"`
+	SurvivorID  int              `json:"survivor_id"`
+	DuplicateID int              `json:"duplicate_id"`
+	MergedBy    *int             `json:"merged_by,omitempty"`
+	Duplicate   json.RawMessage  `json:"duplicate" swaggertype:"object"`
+	Counts      PlaceMergeCounts `json:"counts"`
+	MergedAt    time.Time        `json:"merged_at"`
+}
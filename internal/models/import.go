@@ -0,0 +1,36 @@
+package models
+
+const (
+	ImportFormatCSV     = "csv"
+	ImportFormatGeoJSON = "geojson"
+)
+
+// PlaceImport место из файла импорта, город, район и теги указаны по названию.
+// Row номер строки CSV (заголовок первая строка) или номер объекта FeatureCollection начиная с 1
+type PlaceImport struct {
+	Row         int
+	Name        string
+	Variety     string
+	City        string
+	District    string
+	Tags        []string
+	Coordinates *Point
+	Properties  map[string]interface{}
+}
+
+type ImportRowError struct {
+	Row   int    `json:"row"`
+	Name  string `json:"name,omitempty"`
+	Error string `json:"error"`
+}
+
+// ImportReport итог импорта, при dry_run или ошибках валидации изменения не применяются
+type ImportReport struct {
+	DryRun      bool             `json:"dry_run"`
+	Applied     bool             `json:"applied"`
+	Total       int              `json:"total"`
+	Created     int              `json:"created"`
+	Updated     int              `json:"updated"`
+	TagsCreated int              `json:"tags_created"`
+	Errors      []ImportRowError `json:"errors"`
+}
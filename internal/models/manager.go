@@ -0,0 +1,26 @@
+package models
+
+const (
+	ManagerRoleOwner  = "owner"
+	ManagerRoleEditor = "editor"
+)
+
+// EntityManager владелец или редактор места или маршрута, может официально отвечать на отзывы
+type EntityManager struct {
+	EntityType string `json:"entity_type" form:"entity_type" enums:"place,route"`
+	EntityID   int    `json:"entity_id" form:"entity_id"`
+	UserID     int    `json:"user_id" form:"user_id"`
+	Role       string `json:"role" form:"role" enums:"owner,editor"`
+}
+
+type EntityManagerUpdate struct {
+	ModeratorID int `json:"moderator_id"`
+	EntityManager
+}
+
+type EntityManagerDelete struct {
+	ModeratorID int    `form:"moderator_id"`
+	EntityType  string `form:"entity_type" enums:"place,route"`
+	EntityID    int    `form:"entity_id"`
+	UserID      int    `form:"user_id"`
+}
@@ -0,0 +1,48 @@
+package models
+
+import "time"
+
+const (
+	MediaEntityPlace       = "place"
+	MediaEntityRoute       = "route"
+	MediaEntityPlaceReview = "place_review"
+	MediaEntityRouteReview = "route_review"
+	MediaEntityNote        = "note"
+)
+
+type MediaCreate struct {
+	OwnerID      int    `json:"owner_id"`
+	StorageKey   string `json:"-"`
+	ThumbnailKey string `json:"-"`
+	ContentType  string `json:"content_type"`
+	Size         int64  `json:"size"`
+	Width        int    `json:"width"`
+	Height       int    `json:"height"`
+}
+
+type Media struct {
+	ID           int       `json:"id"`
+	URL          string    `json:"url"`
+	ThumbnailURL string    `json:"thumbnail_url"`
+	CreatedAt    time.Time `json:"created_at"`
+	MediaCreate
+}
+
+type MediaAttachment struct {
+	MediaID    int    `json:"media_id"`
+	UserID     int    `json:"user_id"`
+	EntityType string `json:"entity_type" enums:"place,route,place_review,route_review,note"`
+	EntityID   int    `json:"entity_id"`
+	Position   int    `json:"position"`
+}
+
+// MediaEntityKey сущность, к которой прикреплены медиа
+type MediaEntityKey struct {
+	EntityType string
+	EntityID   int
+}
+
+type AttachedMedia struct {
+	Position int `json:"position"`
+	Media
+}
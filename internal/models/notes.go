@@ -1,18 +1,100 @@
 package models
 
+import "time"
+
+const (
+	NoteTargetPlace   = "place"
+	NoteTargetRoute   = "route"
+	NoteTargetTrip    = "trip"
+	NoteTargetTripDay = "trip_day"
+)
+
+// NoteVisibilityLink заметку видит только владелец и те, у кого есть действующая ссылка
+const (
+	NoteVisibilityPrivate    = "private"
+	NoteVisibilityCompanions = "companions"
+	NoteVisibilityLink       = "link"
+	NoteVisibilityPublic     = "public"
+)
+
+// NoteCreate заметка к месту, для старых методов /note/create и /note/update
 type NoteCreate struct {
 	UserID     int         `json:"user_id"`
 	PlaceID    int         `json:"place_id"`
 	Properties interface{} `json:"properties"`
 }
 
+// NoteTarget к чему относится заметка: TargetID - id места, маршрута или поездки, Day - день поездки с 1 для trip_day
+type NoteTarget struct {
+	TargetType string `json:"target_type" form:"target_type" enums:"place,route,trip,trip_day"`
+	TargetID   int    `json:"target_id" form:"target_id"`
+	Day        int    `json:"day,omitempty" form:"day"`
+}
+
+// NoteTargetCreate пустой Visibility означает private
+type NoteTargetCreate struct {
+	UserID int `json:"user_id"`
+	NoteTarget
+	Visibility string      `json:"visibility" enums:"private,companions,link,public"`
+	Properties interface{} `json:"properties"`
+}
+
+// Note PlaceID заполнен только у заметок к месту, IsCheckIn - у автора есть отметка в этом месте
 type Note struct {
 	ID        int  `json:"id"`
 	IsCheckIn bool `json:"is_check_in"`
 	NoteCreate
+	NoteTarget
+	Visibility string    `json:"visibility" enums:"private,companions,link,public"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
 }
 
+// NoteUpdate пустой Visibility оставляет видимость без изменений
 type NoteUpdate struct {
 	ID         int         `json:"id"`
+	UserID     int         `json:"user_id"`
+	Visibility string      `json:"visibility" enums:"private,companions,link,public"`
 	Properties interface{} `json:"properties"`
 }
+
+type NoteDelete struct {
+	ID     int `form:"id"`
+	UserID int `form:"user_id"`
+}
+
+// NotesByTarget ViewerID - кто смотрит заметки UserID, 0 для анонима
+type NotesByTarget struct {
+	UserID   int `form:"user_id" binding:"required"`
+	ViewerID int `form:"viewer_id"`
+	NoteTarget
+}
+
+// NotesFeed публичные заметки путешественников к месту или маршруту, новые первыми
+type NotesFeed struct {
+	TargetType string `form:"target_type" binding:"required" enums:"place,route"`
+	TargetID   int    `form:"target_id" binding:"required"`
+	PageRequest
+}
+
+type NoteShare struct {
+	ID     int `json:"id"`
+	UserID int `json:"user_id"`
+}
+
+type NoteShareRevoke struct {
+	Token  string `form:"token" binding:"required"`
+	UserID int    `form:"user_id" binding:"required"`
+}
+
+type NoteSharesRequest struct {
+	ID     int `form:"id" binding:"required"`
+	UserID int `form:"user_id" binding:"required"`
+}
+
+// NoteShareToken действующая ссылка на заметку
+type NoteShareToken struct {
+	Token     string    `json:"token"`
+	NoteID    int       `json:"note_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+const (
+	NotificationReviewReply      = "review_reply"
+	NotificationCompanionRequest = "companion_request"
+)
+
+// Notification Payload зависит от Kind, для review_reply: review_type, review_id, reply_id,
+// для companion_request: request_id, listing_type, listing_id, status
+type Notification struct {
+	ID        int                    `json:"id"`
+	Kind      string                 `json:"kind"`
+	Payload   map[string]interface{} `json:"payload"`
+	CreatedAt time.Time              `json:"created_at"`
+	Read      bool                   `json:"read"`
+}
+
+type NotificationsRequest struct {
+	UserID     int  `form:"user_id" binding:"required"`
+	UnreadOnly bool `form:"unread_only"`
+	PageRequest
+}
+
+// NotificationsRead пустой IDs отмечает прочитанными все уведомления пользователя
+type NotificationsRead struct {
+	UserID int   `json:"user_id" binding:"required"`
+	IDs    []int `json:"ids,omitempty"`
+}
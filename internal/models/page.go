@@ -0,0 +1,16 @@
+package models
+
+// PageRequest пустой Cursor означает первую страницу, PageSize ограничивается MAX_PAGE_SIZE
+type PageRequest struct {
+	Cursor    string `json:"cursor,omitempty" form:"cursor"`
+	PageSize  int    `json:"page_size,omitempty" form:"page_size"`
+	WithTotal bool   `json:"with_total,omitempty" form:"with_total"`
+}
+
+// Page Total заполняется только при запросе with_total
+type Page[T any] struct {
+	Items      []T    `json:"items"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	HasMore    bool   `json:"has_more"`
+	Total      *int   `json:"total,omitempty"`
+}
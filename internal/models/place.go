@@ -1,11 +1,23 @@
 package models
 
+// Point координаты в WGS84
+type Point struct {
+	Lat float64 `json:"lat"`
+	Lon float64 `json:"lon"`
+}
+
+// Valid проверяет попадание в допустимые диапазоны широты и долготы
+func (p Point) Valid() bool {
+	return p.Lat >= -90 && p.Lat <= 90 && p.Lon >= -180 && p.Lon <= 180
+}
+
 type PlaceBase struct {
-	Properties interface{} `json:"properties"`
-	CityID     int         `json:"city_id"`
-	DistrictID int         `json:"district_id"`
-	Name       string      `json:"name"`
-	Variety    string      `json:"variety"`
+	Properties  interface{} `json:"properties"`
+	CityID      int         `json:"city_id"`
+	DistrictID  int         `json:"district_id"`
+	Name        string      `json:"name"`
+	Variety     string      `json:"variety"`
+	Coordinates *Point      `json:"coordinates,omitempty"`
 }
 
 type PlaceCreate struct {
@@ -13,8 +25,42 @@ type PlaceCreate struct {
 	PlaceBase
 }
 
+// PlaceUpdate TagIDs полностью заменяют текущий набор тегов места
+type PlaceUpdate struct {
+	ID     int   `json:"id"`
+	TagIDs []int `json:"tag_ids"`
+	PlaceBase
+}
+
+// Place Description заполняется из переводов на языке запроса
 type Place struct {
-	ID   int   `json:"id"`
-	Tags []Tag `json:"tags"`
+	ID          int     `json:"id"`
+	Tags        []Tag   `json:"tags"`
+	Archived    bool    `json:"archived"`
+	Rating      Rating  `json:"rating"`
+	Popularity  float64 `json:"popularity"`
+	Description string  `json:"description,omitempty"`
 	PlaceBase
 }
+
+const (
+	PlaceSortByRating       = "rating"
+	PlaceSortByReviewsCount = "reviews_count"
+	PlaceSortByPopularity   = "popular"
+)
+
+// PlaceFilters Limit 0 означает размер страницы из PLACES_ON_PAGE.
+// TagGroups место должно иметь хотя бы один тег из каждой группы
+type PlaceFilters struct {
+	DistrictID      int
+	CityID          int
+	TagGroups       [][]int
+	Cursor          string
+	Limit           int
+	WithTotal       bool
+	Name            string
+	Variety         string
+	MinRating       float32
+	SortBy          string
+	WithCoordinates bool
+}
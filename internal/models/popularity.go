@@ -0,0 +1,11 @@
+package models
+
+// PopularityWeights вес одного события, вклад события убывает вдвое каждые HalfLifeHours
+type PopularityWeights struct {
+	CheckIn       float64
+	Favourite     float64
+	Review        float64
+	RouteStart    float64
+	RouteComplete float64
+	HalfLifeHours float64
+}
@@ -0,0 +1,60 @@
+package models
+
+import "math"
+
+// Rating Histogram[0] - количество оценок, округлённых до нижней границы шкалы, далее по одной на целое значение.
+// Criteria - отдельные агрегаты по критериям вида места или маршрута
+type Rating struct {
+	Average   float32                    `json:"average"`
+	Count     int                        `json:"count"`
+	Histogram []int                      `json:"histogram"`
+	Criteria  map[string]CriterionRating `json:"criteria,omitempty"`
+}
+
+type CriterionRating struct {
+	Average float32 `json:"average"`
+	Count   int     `json:"count"`
+}
+
+func RatingFromRaw(average float32, count int, histogramRaw []int64) Rating {
+	rating := Rating{
+		Average:   average,
+		Count:     count,
+		Histogram: make([]int, len(histogramRaw)),
+	}
+
+	for i := range histogramRaw {
+		rating.Histogram[i] = int(histogramRaw[i])
+	}
+
+	return rating
+}
+
+// MarkScale допустимые оценки: от Min до Max с шагом Step, нулевой шаг - любое значение
+type MarkScale struct {
+	Min  float64
+	Max  float64
+	Step float64
+}
+
+// markEpsilon погрешность float32, в котором оценка приходит от клиента
+const markEpsilon = 1e-4
+
+func (s MarkScale) Valid(mark float32) bool {
+	value := float64(mark)
+	if math.IsNaN(value) || value < s.Min-markEpsilon || value > s.Max+markEpsilon {
+		return false
+	}
+
+	if s.Step <= 0 {
+		return true
+	}
+
+	steps := (value - s.Min) / s.Step
+	return math.Abs(steps-math.Round(steps)) < markEpsilon
+}
+
+// Buckets целые значения шкалы, по которым строится гистограмма
+func (s MarkScale) Buckets() (int, int) {
+	return int(math.Ceil(s.Min - markEpsilon)), int(math.Floor(s.Max + markEpsilon))
+}
@@ -2,17 +2,52 @@ package models
 
 import "time"
 
+const (
+	ReviewTypePlace = "place"
+	ReviewTypeRoute = "route"
+
+	ReviewStatusPending   = "pending"
+	ReviewStatusPublished = "published"
+	ReviewStatusRejected  = "rejected"
+	ReviewStatusHidden    = "hidden"
+
+	ReviewActionApprove = "approve"
+	ReviewActionReject  = "reject"
+	ReviewActionHide    = "hide"
+
+	ReviewQueuePending = "pending"
+	ReviewQueueFlagged = "flagged"
+
+	ReviewSortHelpful  = "helpful"
+	ReviewSortRecent   = "recent"
+	ReviewSortMarkHigh = "mark_high"
+	ReviewSortMarkLow  = "mark_low"
+)
+
+// ReviewFlagReasons допустимые причины жалобы на отзыв
+var ReviewFlagReasons = []string{"spam", "offensive", "off_topic", "fake", "other"}
+
+// ReviewBase Mark в пределах шкалы REVIEW_MARK_MIN..REVIEW_MARK_MAX, SubMarks - необязательные оценки
+// по критериям вида места или маршрута в той же шкале
 type ReviewBase struct {
-	AuthorID   int         `json:"author_id"`
-	Properties interface{} `json:"properties"`
-	Mark       float32     `json:"mark"`
+	AuthorID   int                `json:"author_id"`
+	Properties interface{}        `json:"properties"`
+	Mark       float32            `json:"mark"`
+	SubMarks   map[string]float32 `json:"sub_marks,omitempty"`
 	TimeStamp  time.Time
 }
 
 type ReviewUpdate struct {
-	ID         int         `json:"id"`
-	Properties interface{} `json:"properties"`
-	Mark       float32     `json:"mark"`
+	ID         int                `json:"id"`
+	Properties interface{}        `json:"properties"`
+	Mark       float32            `json:"mark"`
+	SubMarks   map[string]float32 `json:"sub_marks,omitempty"`
+}
+
+// PlaceReviewPolicy требования вида места к отзывам
+type PlaceReviewPolicy struct {
+	RequireVerified bool
+	Criteria        []string
 }
 
 type PlaceReviewCreate struct {
@@ -25,12 +60,140 @@ type RouteReviewCreate struct {
 	ReviewBase
 }
 
+// ReviewModerationState статус отзыва, причина заполняется при отклонении или скрытии
+type ReviewModerationState struct {
+	Status           string `json:"status" enums:"pending,published,rejected,hidden"`
+	ModerationReason string `json:"moderation_reason,omitempty"`
+}
+
+// ReviewVotes Score - нижняя граница интервала Вильсона для доли голосов "полезно"
+type ReviewVotes struct {
+	Helpful   int     `json:"helpful"`
+	Unhelpful int     `json:"unhelpful"`
+	Score     float64 `json:"score"`
+}
+
+// PlaceReview Verified - у автора есть отметка в месте.
+// Downranked - крайняя оценка от нового аккаунта, у которого все оценки одинаковые. Reply - официальный ответ места
 type PlaceReview struct {
 	ID int `json:"id"`
 	PlaceReviewCreate
+	ReviewModerationState
+	Verified   bool         `json:"verified"`
+	Votes      ReviewVotes  `json:"votes"`
+	Downranked bool         `json:"downranked,omitempty"`
+	Reply      *ReviewReply `json:"reply,omitempty"`
 }
 
+// RouteReview Verified - автор прошёл маршрут до конца
 type RouteReview struct {
 	ID int `json:"id"`
 	RouteReviewCreate
+	ReviewModerationState
+	Verified   bool         `json:"verified"`
+	Votes      ReviewVotes  `json:"votes"`
+	Downranked bool         `json:"downranked,omitempty"`
+	Reply      *ReviewReply `json:"reply,omitempty"`
+}
+
+// ReviewListRequest ID - место или маршрут, отзывы которого запрашиваются, пустой SortBy - по полезности
+type ReviewListRequest struct {
+	ID           int    `form:"id" binding:"required"`
+	VerifiedOnly bool   `form:"verified_only"`
+	SortBy       string `form:"sort_by" enums:"helpful,recent,mark_high,mark_low"`
+	PageRequest
+}
+
+// ReviewVote повторный голос пользователя заменяет прежний
+type ReviewVote struct {
+	ReviewType string `json:"review_type" enums:"place,route"`
+	ReviewID   int    `json:"review_id"`
+	UserID     int    `json:"user_id"`
+	Helpful    bool   `json:"helpful"`
+}
+
+type ReviewVoteDelete struct {
+	ReviewType string `form:"review_type" enums:"place,route"`
+	ReviewID   int    `form:"review_id"`
+	UserID     int    `form:"user_id"`
+}
+
+type ReviewFlagCreate struct {
+	ReviewType string `json:"review_type" enums:"place,route"`
+	ReviewID   int    `json:"review_id"`
+	UserID     int    `json:"user_id"`
+	Reason     string `json:"reason" enums:"spam,offensive,off_topic,fake,other"`
+	Comment    string `json:"comment,omitempty"`
+}
+
+// ReviewFlagResult Hidden - отзыв скрыт до модерации, так как набрал REVIEW_FLAGS_TO_HIDE жалоб
+type ReviewFlagResult struct {
+	OpenFlags int  `json:"open_flags"`
+	Hidden    bool `json:"hidden"`
+}
+
+// ReviewModeration причина обязательна для reject и hide
+type ReviewModeration struct {
+	ReviewType  string `json:"review_type" enums:"place,route"`
+	ReviewID    int    `json:"review_id"`
+	ModeratorID int    `json:"moderator_id"`
+	Action      string `json:"action" enums:"approve,reject,hide"`
+	Reason      string `json:"reason,omitempty"`
+}
+
+type ReviewDelete struct {
+	ReviewType string `form:"review_type" enums:"place,route"`
+	ReviewID   int    `form:"id"`
+	UserID     int    `form:"user_id"`
+}
+
+// ReviewQueueRequest пустой Queue - и новые отзывы, и отзывы с необработанными жалобами
+type ReviewQueueRequest struct {
+	ModeratorID int    `form:"moderator_id"`
+	Queue       string `form:"queue" enums:"pending,flagged"`
+	PageRequest
+}
+
+// ReviewQueueItem Flags - необработанные жалобы в виде "причина: комментарий"
+type ReviewQueueItem struct {
+	ReviewType string      `json:"review_type"`
+	ID         int         `json:"id"`
+	EntityID   int         `json:"entity_id"`
+	AuthorID   int         `json:"author_id"`
+	Properties interface{} `json:"properties"`
+	Mark       float32     `json:"mark"`
+	TimeStamp  time.Time   `json:"timestamp"`
+	ReviewModerationState
+	Flags []string `json:"flags"`
+}
+
+// ReviewReply официальный ответ владельца или редактора, Edited - у ответа есть прежние версии
+type ReviewReply struct {
+	ID        int       `json:"id"`
+	AuthorID  int       `json:"author_id"`
+	Text      string    `json:"text"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Edited    bool      `json:"edited"`
+}
+
+// ReviewReplyUpsert создаёт ответ на отзыв или заменяет его текст, прежний текст сохраняется в истории
+type ReviewReplyUpsert struct {
+	ReviewType string `json:"review_type" enums:"place,route"`
+	ReviewID   int    `json:"review_id"`
+	UserID     int    `json:"user_id"`
+	Text       string `json:"text"`
+}
+
+type ReviewReplyKey struct {
+	ReviewType string `form:"review_type" enums:"place,route"`
+	ReviewID   int    `form:"review_id"`
+	UserID     int    `form:"user_id"`
+}
+
+// ReviewReplyRevision прежняя версия ответа, WrittenAt - когда эта версия была написана
+type ReviewReplyRevision struct {
+	AuthorID  int       `json:"author_id"`
+	Text      string    `json:"text"`
+	WrittenAt time.Time `json:"written_at"`
 }
@@ -27,6 +27,8 @@ type RouteRaw struct {
 	ID                   int                   `json:"id"`
 	Tags                 []Tag                 `json:"tags"`
 	PlaceIDsWithPosition []PlaceIDWithPosition `json:"place_ids"`
+	Rating               Rating                `json:"rating"`
+	Popularity           float64               `json:"popularity"`
 	RouteBase
 }
 
@@ -36,9 +38,24 @@ type RouteDisplay struct {
 	CompletedPlace int `json:"completed_place"`
 }
 
+// Route Description заполняется из переводов на языке запроса
 type Route struct {
-	ID     int                 `json:"id"`
-	Tags   []Tag               `json:"tags"`
-	Places []PlaceWithPosition `json:"places"`
+	ID          int                 `json:"id"`
+	Tags        []Tag               `json:"tags"`
+	Places      []PlaceWithPosition `json:"places"`
+	Rating      Rating              `json:"rating"`
+	Popularity  float64             `json:"popularity"`
+	Description string              `json:"description,omitempty"`
 	RouteBase
 }
+
+const RouteSortByPopularity = "popular"
+
+// RouteFilters теги как в фильтре мест: id или названия/синонимы, маршрут должен иметь каждый из них
+type RouteFilters struct {
+	PageRequest
+	SortBy             string   `form:"sort_by"`
+	TagIDs             []int    `form:"tag_ids"`
+	Tags               []string `form:"tags"`
+	IncludeDescendants bool     `form:"include_descendants"`
+}
@@ -0,0 +1,50 @@
+package models
+
+import "time"
+
+const (
+	GranularityDay   = "day"
+	GranularityWeek  = "week"
+	GranularityMonth = "month"
+)
+
+// StatsRequest From и To включительно в формате 2006-01-02, по умолчанию последние 30 дней по день
+type StatsRequest struct {
+	ID          int    `form:"id"`
+	From        string `form:"from"`
+	To          string `form:"to"`
+	Granularity string `form:"granularity" enums:"day,week,month"`
+}
+
+// StatsWindow разобранный StatsRequest, To не включается
+type StatsWindow struct {
+	From        time.Time
+	To          time.Time
+	Granularity string
+}
+
+type StatsBucket struct {
+	Start    time.Time `json:"start"`
+	CheckIns int       `json:"checkins"`
+	Visitors int       `json:"visitors"`
+}
+
+// AreaStats DistrictID равен 0 для статистики по городу целиком.
+// Отметки, посетители и оценки считаются за окно, места и маршруты - на текущий момент
+type AreaStats struct {
+	CityID          int            `json:"city_id"`
+	DistrictID      int            `json:"district_id,omitempty"`
+	Name            string         `json:"name"`
+	PlacesByVariety map[string]int `json:"places_by_variety"`
+	RoutesPassing   int            `json:"routes_passing"`
+	CheckIns        int            `json:"checkins"`
+	UniqueVisitors  int            `json:"unique_visitors"`
+	Timeline        []StatsBucket  `json:"timeline"`
+	ReviewsCount    int            `json:"reviews_count"`
+	AverageRating   float32        `json:"average_rating"`
+}
+
+type CityStats struct {
+	AreaStats
+	Districts []AreaStats `json:"districts"`
+}
@@ -1,10 +1,17 @@
 package swagger
 
+import "mth/internal/models"
+
+// Filters Tags названия или синонимы тегов, место должно иметь все теги из TagIDs и Tags
 type Filters struct {
-	DistrictID     int    `json:"district_id,omitempty"`
-	CityID         int    `json:"city_id,omitempty"`
-	TagIDs         []int  `json:"tag_ids,omitempty"`
-	PaginationPage int    `json:"pagination_page"`
-	Name           string `json:"name,omitempty"`
-	Variety        string `json:"variety"`
+	DistrictID         int      `json:"district_id,omitempty"`
+	CityID             int      `json:"city_id,omitempty"`
+	TagIDs             []int    `json:"tag_ids,omitempty"`
+	Tags               []string `json:"tags,omitempty"`
+	IncludeDescendants bool     `json:"include_descendants,omitempty"`
+	Name               string   `json:"name,omitempty"`
+	Variety            string   `json:"variety"`
+	MinRating          float32  `json:"min_rating,omitempty"`
+	SortBy             string   `json:"sort_by,omitempty" enums:"rating,reviews_count,popular"`
+	models.PageRequest
 }
@@ -1,10 +1,75 @@
 package models
 
+// TagCreate ParentID и CategoryID 0 означают тег верхнего уровня без категории
 type TagCreate struct {
-	Name string `json:"name"`
+	Name       string `json:"name"`
+	ParentID   int    `json:"parent_id,omitempty"`
+	CategoryID int    `json:"category_id,omitempty"`
 }
 
 type Tag struct {
 	ID int `json:"id"`
 	TagCreate
 }
+
+// TagDetails тег вместе с синонимами, по которым он находится в фильтрах
+type TagDetails struct {
+	Tag
+	Synonyms []string `json:"synonyms"`
+}
+
+// TagHierarchy ParentID 0 делает тег корневым, CategoryID 0 снимает категорию
+type TagHierarchy struct {
+	ID         int `json:"id"`
+	ParentID   int `json:"parent_id"`
+	CategoryID int `json:"category_id"`
+}
+
+type TagSynonym struct {
+	TagID int    `json:"tag_id"`
+	Name  string `json:"name"`
+}
+
+type TagCategoryCreate struct {
+	Name string `json:"name"`
+}
+
+type TagCategory struct {
+	ID int `json:"id"`
+	TagCategoryCreate
+}
+
+// TagFilter теги задаются id или названием/синонимом, место должно иметь каждый из них.
+// С IncludeDescendants тег также совпадает с любым из своих потомков
+type TagFilter struct {
+	TagIDs             []int
+	Names              []string
+	IncludeDescendants bool
+}
+
+type TagRename struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// TagDeleteResult сколько связей с местами и маршрутами удалено вместе с тегом
+type TagDeleteResult struct {
+	Places int `json:"places"`
+	Routes int `json:"routes"`
+}
+
+// TagMergeRequest тег SourceID вливается в TargetID и удаляется
+type TagMergeRequest struct {
+	SourceID int `json:"source_id"`
+	TargetID int `json:"target_id"`
+}
+
+// TagMergeResult Moved связи перевешены на целевой тег, Dropped удалены, так как у места или маршрута целевой тег уже был
+type TagMergeResult struct {
+	PlacesMoved   int `json:"places_moved"`
+	PlacesDropped int `json:"places_dropped"`
+	RoutesMoved   int `json:"routes_moved"`
+	RoutesDropped int `json:"routes_dropped"`
+	Children      int `json:"children"`
+	Synonyms      int `json:"synonyms"`
+}
@@ -0,0 +1,50 @@
+package models
+
+import "time"
+
+const (
+	TranslationEntityTag   = "tag"
+	TranslationEntityPlace = "place"
+	TranslationEntityRoute = "route"
+
+	TranslationFieldName        = "name"
+	TranslationFieldDescription = "description"
+)
+
+// TranslatableFields поля, которые можно перевести у каждого типа сущности
+var TranslatableFields = map[string][]string{
+	TranslationEntityTag:   {TranslationFieldName},
+	TranslationEntityPlace: {TranslationFieldName, TranslationFieldDescription},
+	TranslationEntityRoute: {TranslationFieldName, TranslationFieldDescription},
+}
+
+type TranslationKey struct {
+	EntityType string `json:"entity_type" form:"entity_type" enums:"tag,place,route"`
+	EntityID   int    `json:"entity_id" form:"entity_id"`
+	Lang       string `json:"lang" form:"lang"`
+	Field      string `json:"field" form:"field" enums:"name,description"`
+}
+
+type TranslationUpsert struct {
+	TranslationKey
+	Value string `json:"value"`
+}
+
+type Translation struct {
+	TranslationUpsert
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// MissingTranslationsRequest сущности типа EntityType без перевода на Lang хотя бы одного поля
+type MissingTranslationsRequest struct {
+	EntityType string `form:"entity_type" enums:"tag,place,route"`
+	Lang       string `form:"lang"`
+	PageRequest
+}
+
+// MissingTranslation Name исходное название, Missing непереведённые поля
+type MissingTranslation struct {
+	EntityID int      `json:"entity_id"`
+	Name     string   `json:"name"`
+	Missing  []string `json:"missing"`
+}
@@ -0,0 +1,20 @@
+package models
+
+// VarietyBase Name совпадает со значением places.variety, DisplayNames по коду языка.
+// Для мест с Checkinable = false отметка ставится автоматически при прохождении маршрута.
+// RequireVerifiedReview - отзыв можно оставить только после отметки в месте.
+// ReviewCriteria - критерии, по которым в отзыве можно поставить отдельные оценки
+type VarietyBase struct {
+	Name                  string            `json:"name"`
+	DisplayNames          map[string]string `json:"display_names"`
+	IconKey               string            `json:"icon_key"`
+	Checkinable           bool              `json:"checkinable"`
+	GeofenceRadius        int               `json:"geofence_radius"` // метры
+	RequireVerifiedReview bool              `json:"require_verified_review"`
+	ReviewCriteria        []string          `json:"review_criteria"`
+}
+
+type Variety struct {
+	ID int `json:"id"`
+	VarietyBase
+}
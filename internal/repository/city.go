@@ -0,0 +1,171 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/guregu/null/v5"
+	"github.com/jmoiron/sqlx"
+	"mth/internal/models"
+	"mth/pkg/customerr"
+)
+
+type cityRepo struct {
+	db *sqlx.DB
+}
+
+func InitCityRepo(db *sqlx.DB) City {
+	return cityRepo{
+		db: db,
+	}
+}
+
+func (c cityRepo) Create(ctx context.Context, city models.CityBase) (int, error) {
+	latitude, longitude := pointToNull(city.Center)
+
+	createCityQuery := `INSERT INTO city (name, country, time_zone, latitude, longitude, currency)
+						VALUES ($1, $2, $3, $4, $5, $6) RETURNING id;`
+
+	var createdID int
+	err := c.db.QueryRowxContext(ctx, createCityQuery, city.Name, city.Country, city.TimeZone, latitude, longitude,
+		city.Currency).Scan(&createdID)
+	if err != nil {
+		return 0, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ScanErr, Err: err})
+	}
+
+	return createdID, nil
+}
+
+func scanCity(row interface{ Scan(dest ...any) error }) (models.City, error) {
+	var city models.City
+	var name null.String
+	var latitude, longitude null.Float
+
+	err := row.Scan(&city.ID, &name, &city.Country, &city.TimeZone, &latitude, &longitude, &city.Currency)
+	if err != nil {
+		return models.City{}, err
+	}
+
+	city.Name = name.String
+	city.Center = pointFromNull(latitude, longitude)
+
+	return city, nil
+}
+
+func (c cityRepo) GetAll(ctx context.Context) ([]models.City, error) {
+	query := `SELECT id, name, country, time_zone, latitude, longitude, currency FROM city ORDER BY name, id;`
+
+	rows, err := c.db.QueryContext(ctx, query)
+	if err != nil {
+		return []models.City{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ExecErr, Err: err})
+	}
+	defer rows.Close()
+
+	cities := []models.City{}
+	for rows.Next() {
+		city, err := scanCity(rows)
+		if err != nil {
+			return []models.City{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ScanErr, Err: err})
+		}
+
+		cities = append(cities, city)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return []models.City{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.RowsErr, Err: err})
+	}
+
+	return cities, nil
+}
+
+// GetByID возвращает customerr.CityNotFound, если города нет
+func (c cityRepo) GetByID(ctx context.Context, cityID int) (models.City, error) {
+	query := `SELECT id, name, country, time_zone, latitude, longitude, currency FROM city WHERE id = $1;`
+
+	city, err := scanCity(c.db.QueryRowContext(ctx, query, cityID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.City{}, customerr.CityNotFound
+	}
+	if err != nil {
+		return models.City{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ScanErr, Err: err})
+	}
+
+	return city, nil
+}
+
+func (c cityRepo) Update(ctx context.Context, city models.City) error {
+	latitude, longitude := pointToNull(city.Center)
+
+	updateCityQuery := `UPDATE city SET name = $2, country = $3, time_zone = $4, latitude = $5, longitude = $6, currency = $7
+						WHERE id = $1;`
+
+	res, err := c.db.ExecContext(ctx, updateCityQuery, city.ID, city.Name, city.Country, city.TimeZone, latitude, longitude,
+		city.Currency)
+	if err != nil {
+		return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ExecErr, Err: err})
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.RowsErr, Err: err})
+	}
+	if count != 1 {
+		return customerr.CityNotFound
+	}
+
+	return nil
+}
+
+// Delete возвращает customerr.CityInUse, если в городе есть районы, места или маршруты
+func (c cityRepo) Delete(ctx context.Context, cityID int) error {
+	tx, err := c.db.Beginx()
+	if err != nil {
+		return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.TransactionErr, Err: err})
+	}
+
+	usageQuery := `SELECT
+						(SELECT COUNT(*) FROM district WHERE city_id = $1) +
+						(SELECT COUNT(*) FROM places WHERE city_id = $1) +
+						(SELECT COUNT(*) FROM routes WHERE city_id = $1);`
+
+	var usage int
+	if err = tx.QueryRowxContext(ctx, usageQuery, cityID).Scan(&usage); err != nil {
+		return rollbackWithErr(tx, customerr.ScanErr, err)
+	}
+
+	if usage > 0 {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.RollbackErr, Err: rbErr})
+		}
+
+		return customerr.CityInUse
+	}
+
+	res, err := tx.ExecContext(ctx, `DELETE FROM city WHERE id = $1;`, cityID)
+	if err != nil {
+		return rollbackWithErr(tx, customerr.ExecErr, err)
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return rollbackWithErr(tx, customerr.RowsErr, err)
+	}
+	if count != 1 {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return customerr.ErrNormalizer(
+				customerr.ErrorPair{Message: customerr.ExecErr, Err: fmt.Errorf(customerr.CountErr, count)},
+				customerr.ErrorPair{Message: customerr.RollbackErr, Err: rbErr},
+			)
+		}
+
+		return customerr.CityNotFound
+	}
+
+	if err = tx.Commit(); err != nil {
+		return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.CommitErr, Err: err})
+	}
+
+	return nil
+}
@@ -0,0 +1,271 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"mth/internal/models"
+	"mth/pkg/customerr"
+	"mth/pkg/pagination"
+)
+
+type companionRequestRepo struct {
+	db *sqlx.DB
+}
+
+func InitCompanionRequestRepo(db *sqlx.DB) CompanionRequest {
+	return companionRequestRepo{db: db}
+}
+
+// companionListingTables таблица объявлений по типу запроса
+var companionListingTables = map[string]string{
+	models.CompanionListingPlace: "companions_places",
+	models.CompanionListingRoute: "companions_routes",
+}
+
+// isUniqueViolation нарушение уникального индекса, код 23505
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505"
+}
+
+func (c companionRequestRepo) Create(ctx context.Context, request models.CompanionRequestCreate) (int, error) {
+	table, ok := companionListingTables[request.ListingType]
+	if !ok {
+		return 0, fmt.Errorf("%w: unknown listing type %v", customerr.BadInput, request.ListingType)
+	}
+
+	tx, err := c.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.TransactionErr, Err: err})
+	}
+
+	var ownerID int
+	err = tx.QueryRowxContext(ctx, `SELECT user_id FROM `+table+` WHERE id = $1;`, request.ListingID).Scan(&ownerID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, rollbackKeepErr(tx, customerr.CompanionListingNotFound)
+	}
+	if err != nil {
+		return 0, rollbackWithErr(tx, customerr.ScanErr, err)
+	}
+
+	if ownerID == request.RequesterID {
+		return 0, rollbackKeepErr(tx, fmt.Errorf("%w: request to own listing", customerr.BadInput))
+	}
+
+	var exists bool
+	err = tx.QueryRowxContext(ctx, `SELECT EXISTS(SELECT 1 FROM companion_requests
+										WHERE listing_type = $1 AND listing_id = $2 AND requester_id = $3
+											AND status IN ('pending', 'accepted'));`,
+		request.ListingType, request.ListingID, request.RequesterID).Scan(&exists)
+	if err != nil {
+		return 0, rollbackWithErr(tx, customerr.ScanErr, err)
+	}
+	if exists {
+		return 0, rollbackKeepErr(tx, customerr.CompanionRequestExists)
+	}
+
+	createQuery := `INSERT INTO companion_requests (listing_type, listing_id, requester_id, owner_id, message)
+						VALUES ($1, $2, $3, $4, $5) RETURNING id;`
+
+	var createdID int
+	err = tx.QueryRowxContext(ctx, createQuery, request.ListingType, request.ListingID, request.RequesterID, ownerID,
+		request.Message).Scan(&createdID)
+	if isUniqueViolation(err) {
+		// параллельный запрос успел создать действующий запрос после проверки выше
+		return 0, rollbackKeepErr(tx, customerr.CompanionRequestExists)
+	}
+	if err != nil {
+		return 0, rollbackWithErr(tx, customerr.ScanErr, err)
+	}
+
+	err = notify(ctx, tx, ownerID, models.NotificationCompanionRequest, map[string]interface{}{
+		"request_id":   createdID,
+		"listing_type": request.ListingType,
+		"listing_id":   request.ListingID,
+		"status":       models.CompanionRequestPending,
+	})
+	if err != nil {
+		return 0, rollbackKeepErr(tx, err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return 0, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.CommitErr, Err: err})
+	}
+
+	return createdID, nil
+}
+
+// companionTransition кто может перевести запрос в статус и из каких статусов
+type companionTransition struct {
+	byOwner bool
+	from    []string
+}
+
+var companionTransitions = map[string]companionTransition{
+	models.CompanionRequestAccepted:  {byOwner: true, from: []string{models.CompanionRequestPending}},
+	models.CompanionRequestDeclined:  {byOwner: true, from: []string{models.CompanionRequestPending}},
+	models.CompanionRequestCancelled: {byOwner: false, from: []string{models.CompanionRequestPending, models.CompanionRequestAccepted}},
+}
+
+// SetStatus принимает и отклоняет автор объявления, отменяет автор запроса; вторая сторона получает уведомление
+func (c companionRequestRepo) SetStatus(ctx context.Context, action models.CompanionRequestAction, status string) error {
+	transition, ok := companionTransitions[status]
+	if !ok {
+		return fmt.Errorf("%w: unknown companion request status %v", customerr.BadInput, status)
+	}
+
+	tx, err := c.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.TransactionErr, Err: err})
+	}
+
+	var listingType, current string
+	var listingID, requesterID, ownerID int
+	err = tx.QueryRowxContext(ctx, `SELECT listing_type, listing_id, requester_id, owner_id, status
+										FROM companion_requests WHERE id = $1 FOR UPDATE;`, action.ID).
+		Scan(&listingType, &listingID, &requesterID, &ownerID, &current)
+	if errors.Is(err, sql.ErrNoRows) {
+		return rollbackKeepErr(tx, customerr.CompanionRequestNotFound)
+	}
+	if err != nil {
+		return rollbackWithErr(tx, customerr.ScanErr, err)
+	}
+
+	actorID, recipientID := requesterID, ownerID
+	if transition.byOwner {
+		actorID, recipientID = ownerID, requesterID
+	}
+	if actorID != action.UserID {
+		return rollbackKeepErr(tx, customerr.UserNotEntityOwner)
+	}
+
+	allowed := false
+	for _, from := range transition.from {
+		allowed = allowed || from == current
+	}
+	if !allowed {
+		return rollbackKeepErr(tx, fmt.Errorf("%w: %v -> %v", customerr.CompanionRequestStatus, current, status))
+	}
+
+	_, err = tx.ExecContext(ctx, `UPDATE companion_requests SET status = $2, updated_at = current_timestamp WHERE id = $1;`,
+		action.ID, status)
+	if err != nil {
+		return rollbackWithErr(tx, customerr.ExecErr, err)
+	}
+
+	err = notify(ctx, tx, recipientID, models.NotificationCompanionRequest, map[string]interface{}{
+		"request_id":   action.ID,
+		"listing_type": listingType,
+		"listing_id":   listingID,
+		"status":       status,
+	})
+	if err != nil {
+		return rollbackKeepErr(tx, err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.CommitErr, Err: err})
+	}
+
+	return nil
+}
+
+// list запросы, где пользователь на стороне side, вместе с профилем второй стороны counterpart.
+// Новые первыми, request.PageSize уже ограничен сервисом
+func (c companionRequestRepo) list(ctx context.Context, side, counterpart string,
+	request models.CompanionRequestsRequest) (models.Page[models.CompanionRequest], error) {
+	var cursor idCursor
+	if err := pagination.DecodeCursor(request.Cursor, &cursor); err != nil {
+		return models.Page[models.CompanionRequest]{}, err
+	}
+
+	condition := `r.` + side + ` = $1 AND ($2 = '' OR r.status = $2)`
+	query := `SELECT r.id, r.listing_type, r.listing_id, r.requester_id, r.owner_id, r.status, r.message,
+					r.created_at, r.updated_at, COALESCE(p.name, rt.name, ''), COALESCE(p.id, rt.id, 0),
+					COALESCE(cp.date_from, cr.date_from), COALESCE(cp.date_to, cr.date_to), u.properties
+				FROM companion_requests r
+				LEFT JOIN companions_places cp ON r.listing_type = 'place' AND cp.id = r.listing_id
+				LEFT JOIN places p ON p.id = cp.place_id
+				LEFT JOIN companions_routes cr ON r.listing_type = 'route' AND cr.id = r.listing_id
+				LEFT JOIN routes rt ON rt.id = cr.route_id
+				LEFT JOIN users u ON u.id = r.` + counterpart + `
+				WHERE ` + condition + ` AND ($3 = 0 OR r.id < $3)
+				ORDER BY r.id DESC
+				LIMIT $4;`
+
+	rows, err := c.db.QueryContext(ctx, query, request.UserID, request.Status, cursor.ID, request.PageSize+1)
+	if err != nil {
+		return models.Page[models.CompanionRequest]{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.QueryErr, Err: err})
+	}
+	defer rows.Close()
+
+	requests := []models.CompanionRequest{}
+	for rows.Next() {
+		var companionRequest models.CompanionRequest
+		var dateFrom, dateTo sql.NullTime
+		var propertiesRaw []byte
+
+		err = rows.Scan(&companionRequest.ID, &companionRequest.ListingType, &companionRequest.ListingID,
+			&companionRequest.RequesterID, &companionRequest.OwnerID, &companionRequest.Status, &companionRequest.Message,
+			&companionRequest.CreatedAt, &companionRequest.UpdatedAt, &companionRequest.ListingName, &companionRequest.EntityID,
+			&dateFrom, &dateTo, &propertiesRaw)
+		if err != nil {
+			return models.Page[models.CompanionRequest]{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ScanErr, Err: err})
+		}
+
+		companionRequest.DateFrom = dateFrom.Time
+		companionRequest.DateTo = dateTo.Time
+
+		if len(propertiesRaw) > 0 {
+			if err = json.Unmarshal(propertiesRaw, &companionRequest.UserProperties); err != nil {
+				return models.Page[models.CompanionRequest]{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.BindErr, Err: err})
+			}
+		}
+
+		requests = append(requests, companionRequest)
+	}
+
+	if err = rows.Err(); err != nil {
+		return models.Page[models.CompanionRequest]{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.RowsErr, Err: err})
+	}
+
+	page := models.Page[models.CompanionRequest]{Items: requests}
+	if len(requests) > request.PageSize {
+		page.Items = requests[:request.PageSize]
+		page.HasMore = true
+
+		nextCursor, err := pagination.EncodeCursor(idCursor{ID: page.Items[request.PageSize-1].ID})
+		if err != nil {
+			return models.Page[models.CompanionRequest]{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.BindErr, Err: err})
+		}
+		page.NextCursor = nextCursor
+	}
+
+	if request.WithTotal {
+		var total int
+		err = c.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM companion_requests r WHERE `+condition+`;`,
+			request.UserID, request.Status).Scan(&total)
+		if err != nil {
+			return models.Page[models.CompanionRequest]{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ScanErr, Err: err})
+		}
+
+		page.Total = &total
+	}
+
+	return page, nil
+}
+
+// GetIncoming запросы к объявлениям пользователя с профилями их авторов
+func (c companionRequestRepo) GetIncoming(ctx context.Context, request models.CompanionRequestsRequest) (models.Page[models.CompanionRequest], error) {
+	return c.list(ctx, "owner_id", "requester_id", request)
+}
+
+// GetOutgoing запросы пользователя с профилями авторов объявлений
+func (c companionRequestRepo) GetOutgoing(ctx context.Context, request models.CompanionRequestsRequest) (models.Page[models.CompanionRequest], error) {
+	return c.list(ctx, "requester_id", "owner_id", request)
+}
@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"mth/internal/models"
+	"mth/pkg/customerr"
+	"testing"
+	"time"
+)
+
+func TestCompanionRequestRepo_DeleteListingNotifiesRequesters(t *testing.T) {
+	db := testDB(t)
+	requests := InitCompanionRequestRepo(db)
+	notifications := InitNotificationRepo(db)
+	placeID, userIDs := createRatedPlace(t, db, 2)
+	ownerID, requesterID := userIDs[0], userIDs[1]
+
+	var listingID int
+	err := db.QueryRow(`INSERT INTO companions_places (user_id, place_id, date_from, date_to) VALUES ($1, $2, $3, $3) RETURNING id;`,
+		ownerID, placeID, time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)).Scan(&listingID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_, _ = db.Exec(`DELETE FROM companion_requests WHERE listing_type = 'place' AND listing_id = $1;`, listingID)
+		_, _ = db.Exec(`DELETE FROM companions_places WHERE id = $1;`, listingID)
+	})
+
+	create := models.CompanionRequestCreate{ListingType: models.CompanionListingPlace, ListingID: listingID, RequesterID: requesterID}
+	requestID, err := requests.Create(context.TODO(), create)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = requests.Create(context.TODO(), create); !errors.Is(err, customerr.CompanionRequestExists) {
+		t.Errorf("second active request: expected CompanionRequestExists, got %v", err)
+	}
+
+	if err = InitCompanionsRepo(db).DeleteCompanionsPlace(context.TODO(), listingID); err != nil {
+		t.Fatal(err)
+	}
+
+	var status string
+	if err = db.QueryRow(`SELECT status FROM companion_requests WHERE id = $1;`, requestID).Scan(&status); err != nil ||
+		status != models.CompanionRequestCancelled {
+		t.Errorf("request to deleted listing must be cancelled, got %q, %v", status, err)
+	}
+	if count := countNotifications(t, notifications, requesterID); count != 1 {
+		t.Errorf("requester must be notified about the cancellation, got %v notifications", count)
+	}
+}
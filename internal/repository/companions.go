@@ -5,10 +5,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/jmoiron/sqlx"
-	"github.com/spf13/viper"
 	"mth/internal/models"
-	"mth/pkg/config"
 	"mth/pkg/customerr"
+	"mth/pkg/pagination"
 )
 
 type companionsRepo struct {
@@ -189,22 +188,77 @@ func (c companionsRepo) GetByUser(ctx context.Context, userID int) ([]models.Com
 	return places, routes, nil
 }
 
-func (c companionsRepo) GetCompanionsPlace(ctx context.Context, filters models.CompanionsFilters) ([]models.CompanionsPlace, error) {
-	var companions []models.CompanionsPlace
+// idCursor курсор для списков, упорядоченных только по id
+type idCursor struct {
+	ID int `json:"id"`
+}
+
+// companionsPage обрезает лишнюю запись, строит курсор и при необходимости считает total
+func companionsPage[T models.CompanionsPlace | models.CompanionsRoute](ctx context.Context, c companionsRepo, companions []T,
+	idOf func(T) int, filters models.CompanionsFilters, table, entityColumn string) (models.Page[T], error) {
+	page := models.Page[T]{Items: companions}
+
+	if len(companions) > filters.PageSize {
+		page.Items = companions[:filters.PageSize]
+		page.HasMore = true
+
+		nextCursor, err := pagination.EncodeCursor(idCursor{ID: idOf(page.Items[filters.PageSize-1])})
+		if err != nil {
+			return models.Page[T]{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.BindErr, Err: err})
+		}
+		page.NextCursor = nextCursor
+	}
+
+	if filters.WithTotal {
+		total, err := c.countCompanions(ctx, table, entityColumn, filters)
+		if err != nil {
+			return models.Page[T]{}, err
+		}
+
+		page.Total = &total
+	}
+
+	return page, nil
+}
+
+// countCompanions число попутчиков по тем же условиям, что и в выдаче страницы
+func (c companionsRepo) countCompanions(ctx context.Context, table, entityColumn string, filters models.CompanionsFilters) (int, error) {
+	countQuery := fmt.Sprintf(`SELECT COUNT(*) FROM %s c WHERE NOT (c.date_from > $2 OR c.date_to < $1) AND c.%s = $3;`,
+		table, entityColumn)
+
+	var total int
+	err := c.db.QueryRowContext(ctx, countQuery, filters.DateFrom, filters.DateTo, filters.EntityID).Scan(&total)
+	if err != nil {
+		return 0, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ScanErr, Err: err})
+	}
+
+	return total, nil
+}
+
+// GetCompanionsPlace filters.PageSize уже ограничен сервисом, порядок по id записи
+func (c companionsRepo) GetCompanionsPlace(ctx context.Context, filters models.CompanionsFilters) (models.Page[models.CompanionsPlace], error) {
+	var cursor idCursor
+	if err := pagination.DecodeCursor(filters.Cursor, &cursor); err != nil {
+		return models.Page[models.CompanionsPlace]{}, err
+	}
+
+	companions := []models.CompanionsPlace{}
 
 	selectQuery := `SELECT c.date_from, c.date_to, u.properties, p.name, p.properties, city.name, u.id, p.id, c.id
 					FROM companions_places c
 					LEFT JOIN users u ON c.user_id = u.id
 					LEFT JOIN places p ON c.place_id = p.id
 					LEFT JOIN city ON p.city_id = city.id
-					WHERE NOT (c.date_from > $2 OR c.date_to < $1) AND c.place_id = $3
-					OFFSET $4 LIMIT $5;`
+					WHERE NOT (c.date_from > $2 OR c.date_to < $1) AND c.place_id = $3 AND c.id > $4
+					ORDER BY c.id
+					LIMIT $5;`
 
 	rows, err := c.db.QueryxContext(ctx, selectQuery, filters.DateFrom, filters.DateTo, filters.EntityID,
-		filters.Page*viper.GetInt(config.CompanionsOnPage), viper.GetInt(config.CompanionsOnPage))
+		cursor.ID, filters.PageSize+1)
 	if err != nil {
-		return []models.CompanionsPlace{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.QueryErr, Err: err})
+		return models.Page[models.CompanionsPlace]{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.QueryErr, Err: err})
 	}
+	defer rows.Close()
 
 	for rows.Next() {
 		var companion models.CompanionsPlace
@@ -214,41 +268,54 @@ func (c companionsRepo) GetCompanionsPlace(ctx context.Context, filters models.C
 		err := rows.Scan(&companion.DateFrom, &companion.DateTo, &userPropertiesRaw, &companion.PlaceName,
 			&propertiesRaw, &companion.CityName, &companion.UserID, &companion.PlaceID, &companion.ID)
 		if err != nil {
-			return []models.CompanionsPlace{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ScanErr, Err: err})
+			return models.Page[models.CompanionsPlace]{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ScanErr, Err: err})
 		}
 
 		err = json.Unmarshal(userPropertiesRaw, &companion.UserProperties)
 		if err != nil {
-			return []models.CompanionsPlace{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.BindErr, Err: err})
+			return models.Page[models.CompanionsPlace]{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.BindErr, Err: err})
 		}
 
 		err = json.Unmarshal(propertiesRaw, &companion.PlaceProperties)
 		if err != nil {
-			return []models.CompanionsPlace{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.BindErr, Err: err})
+			return models.Page[models.CompanionsPlace]{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.BindErr, Err: err})
 		}
 
 		companions = append(companions, companion)
 	}
 
-	return companions, nil
+	err = rows.Err()
+	if err != nil {
+		return models.Page[models.CompanionsPlace]{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.RowsErr, Err: err})
+	}
+
+	return companionsPage(ctx, c, companions, func(companion models.CompanionsPlace) int { return companion.ID }, filters, "companions_places", "place_id")
 }
 
-func (c companionsRepo) GetCompanionsRoute(ctx context.Context, filters models.CompanionsFilters) ([]models.CompanionsRoute, error) {
-	var companions []models.CompanionsRoute
+// GetCompanionsRoute filters.PageSize уже ограничен сервисом, порядок по id записи
+func (c companionsRepo) GetCompanionsRoute(ctx context.Context, filters models.CompanionsFilters) (models.Page[models.CompanionsRoute], error) {
+	var cursor idCursor
+	if err := pagination.DecodeCursor(filters.Cursor, &cursor); err != nil {
+		return models.Page[models.CompanionsRoute]{}, err
+	}
+
+	companions := []models.CompanionsRoute{}
 
 	selectQuery := `SELECT c.date_from, c.date_to, u.properties, r.name, r.price, r.properties, city.name, u.id, r.id, c.id
 					FROM companions_routes c
 					LEFT JOIN users u ON c.user_id = u.id
 					LEFT JOIN routes r ON c.route_id = r.id
 					LEFT JOIN city ON r.city_id = city.id
-					WHERE NOT (c.date_from > $2 OR c.date_to < $1) AND c.route_id = $3
-					OFFSET $4 LIMIT $5;`
+					WHERE NOT (c.date_from > $2 OR c.date_to < $1) AND c.route_id = $3 AND c.id > $4
+					ORDER BY c.id
+					LIMIT $5;`
 
 	rows, err := c.db.QueryxContext(ctx, selectQuery, filters.DateFrom, filters.DateTo, filters.EntityID,
-		filters.Page*viper.GetInt(config.CompanionsOnPage), viper.GetInt(config.CompanionsOnPage))
+		cursor.ID, filters.PageSize+1)
 	if err != nil {
-		return []models.CompanionsRoute{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.QueryErr, Err: err})
+		return models.Page[models.CompanionsRoute]{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.QueryErr, Err: err})
 	}
+	defer rows.Close()
 
 	for rows.Next() {
 		var companion models.CompanionsRoute
@@ -258,23 +325,71 @@ func (c companionsRepo) GetCompanionsRoute(ctx context.Context, filters models.C
 		err := rows.Scan(&companion.DateFrom, &companion.DateTo, &userPropertiesRaw, &companion.RouteName,
 			&companion.Price, &routePropertiesRaw, &companion.CityName, &companion.UserID, &companion.RouteID, &companion.ID)
 		if err != nil {
-			return []models.CompanionsRoute{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ScanErr, Err: err})
+			return models.Page[models.CompanionsRoute]{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ScanErr, Err: err})
 		}
 
 		err = json.Unmarshal(userPropertiesRaw, &companion.UserProperties)
 		if err != nil {
-			return []models.CompanionsRoute{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.BindErr, Err: err})
+			return models.Page[models.CompanionsRoute]{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.BindErr, Err: err})
 		}
 
 		err = json.Unmarshal(routePropertiesRaw, &companion.RouteProperties)
 		if err != nil {
-			return []models.CompanionsRoute{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.BindErr, Err: err})
+			return models.Page[models.CompanionsRoute]{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.BindErr, Err: err})
 		}
 
 		companions = append(companions, companion)
 	}
 
-	return companions, nil
+	err = rows.Err()
+	if err != nil {
+		return models.Page[models.CompanionsRoute]{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.RowsErr, Err: err})
+	}
+
+	return companionsPage(ctx, c, companions, func(companion models.CompanionsRoute) int { return companion.ID }, filters, "companions_routes", "route_id")
+}
+
+// cancelListingRequests у удалённого объявления действующие запросы попутчиков отменяются,
+// их авторы получают уведомление, как при отмене владельцем
+func cancelListingRequests(ctx context.Context, tx *sqlx.Tx, listingType string, listingID int) error {
+	cancelQuery := `UPDATE companion_requests SET status = 'cancelled', updated_at = current_timestamp
+						WHERE listing_type = $1 AND listing_id = $2 AND status IN ('pending', 'accepted')
+						RETURNING id, requester_id;`
+
+	rows, err := tx.QueryContext(ctx, cancelQuery, listingType, listingID)
+	if err != nil {
+		return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ExecErr, Err: err})
+	}
+
+	var cancelled [][2]int
+	for rows.Next() {
+		var requestID, requesterID int
+		if err = rows.Scan(&requestID, &requesterID); err != nil {
+			rows.Close()
+			return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ScanErr, Err: err})
+		}
+
+		cancelled = append(cancelled, [2]int{requestID, requesterID})
+	}
+	rows.Close()
+
+	if err = rows.Err(); err != nil {
+		return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.RowsErr, Err: err})
+	}
+
+	for _, request := range cancelled {
+		err = notify(ctx, tx, request[1], models.NotificationCompanionRequest, map[string]interface{}{
+			"request_id":   request[0],
+			"listing_type": listingType,
+			"listing_id":   listingID,
+			"status":       models.CompanionRequestCancelled,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
 func (c companionsRepo) DeleteCompanionsPlace(ctx context.Context, id int) error {
@@ -315,6 +430,10 @@ func (c companionsRepo) DeleteCompanionsPlace(ctx context.Context, id int) error
 		return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.RowsErr, Err: fmt.Errorf(customerr.CountErr, count)})
 	}
 
+	if err = cancelListingRequests(ctx, tx, models.CompanionListingPlace, id); err != nil {
+		return rollbackKeepErr(tx, err)
+	}
+
 	if err = tx.Commit(); err != nil {
 		return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.CommitErr, Err: err})
 	}
@@ -360,6 +479,10 @@ func (c companionsRepo) DeleteCompanionsRoute(ctx context.Context, id int) error
 		return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.RowsErr, Err: fmt.Errorf(customerr.CountErr, count)})
 	}
 
+	if err = cancelListingRequests(ctx, tx, models.CompanionListingRoute, id); err != nil {
+		return rollbackKeepErr(tx, err)
+	}
+
 	if err = tx.Commit(); err != nil {
 		return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.CommitErr, Err: err})
 	}
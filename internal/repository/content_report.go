@@ -0,0 +1,153 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"mth/internal/models"
+	"mth/pkg/customerr"
+	"mth/pkg/pagination"
+)
+
+type contentReportRepo struct {
+	db *sqlx.DB
+}
+
+func InitContentReportRepo(db *sqlx.DB) ContentReport {
+	return contentReportRepo{
+		db: db,
+	}
+}
+
+// Create открытая жалоба на сущность одна, повторная заменяет правила и время
+func (c contentReportRepo) Create(ctx context.Context, report models.ContentReport) error {
+	query := `INSERT INTO content_reports (entity_type, entity_id, user_id, rules) VALUES ($1, $2, $3, $4)
+				ON CONFLICT (entity_type, entity_id) WHERE resolved_at IS NULL
+				DO UPDATE SET rules = EXCLUDED.rules, user_id = EXCLUDED.user_id, created_at = current_timestamp;`
+
+	_, err := c.db.ExecContext(ctx, query, report.EntityType, report.EntityID, report.UserID, pq.Array(report.Rules))
+	if err != nil {
+		return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ExecErr, Err: err})
+	}
+
+	return nil
+}
+
+// GetOpen нерешённые жалобы от старых к новым
+func (c contentReportRepo) GetOpen(ctx context.Context, request models.ContentReportsRequest) (models.Page[models.ContentReport], error) {
+	var cursor idCursor
+	if err := pagination.DecodeCursor(request.Cursor, &cursor); err != nil {
+		return models.Page[models.ContentReport]{}, err
+	}
+
+	query := `SELECT id, entity_type, entity_id, user_id, rules, created_at FROM content_reports
+				WHERE resolved_at IS NULL AND id > $1
+				ORDER BY id LIMIT $2;`
+
+	rows, err := c.db.QueryContext(ctx, query, cursor.ID, request.PageSize+1)
+	if err != nil {
+		return models.Page[models.ContentReport]{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ExecErr, Err: err})
+	}
+	defer rows.Close()
+
+	result := models.Page[models.ContentReport]{Items: []models.ContentReport{}}
+	for rows.Next() {
+		var report models.ContentReport
+
+		err = rows.Scan(&report.ID, &report.EntityType, &report.EntityID, &report.UserID, pq.Array(&report.Rules), &report.CreatedAt)
+		if err != nil {
+			return models.Page[models.ContentReport]{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ScanErr, Err: err})
+		}
+
+		result.Items = append(result.Items, report)
+	}
+
+	if err = rows.Err(); err != nil {
+		return models.Page[models.ContentReport]{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.RowsErr, Err: err})
+	}
+
+	if len(result.Items) > request.PageSize {
+		result.Items = result.Items[:request.PageSize]
+		result.HasMore = true
+
+		result.NextCursor, err = pagination.EncodeCursor(idCursor{ID: result.Items[request.PageSize-1].ID})
+		if err != nil {
+			return models.Page[models.ContentReport]{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.BindErr, Err: err})
+		}
+	}
+
+	if request.WithTotal {
+		var total int
+		countQuery := `SELECT COUNT(*) FROM content_reports WHERE resolved_at IS NULL;`
+		if err = c.db.QueryRowContext(ctx, countQuery).Scan(&total); err != nil {
+			return models.Page[models.ContentReport]{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ScanErr, Err: err})
+		}
+
+		result.Total = &total
+	}
+
+	return result, nil
+}
+
+func (c contentReportRepo) Resolve(ctx context.Context, resolve models.ContentReportResolve) error {
+	query := `UPDATE content_reports SET resolved_at = current_timestamp, resolved_by = $2
+				WHERE id = $1 AND resolved_at IS NULL;`
+
+	res, err := c.db.ExecContext(ctx, query, resolve.ID, resolve.ModeratorID)
+	if err != nil {
+		return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ExecErr, Err: err})
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.RowsErr, Err: err})
+	}
+	if count == 0 {
+		return customerr.ContentReportNotFound
+	}
+
+	return nil
+}
+
+// RecentProperties свойства заметок и отзывов автора, изменённых за окно, кроме относящихся к excludeTarget.
+// Цель записывается так же, как в фильтре содержимого: note:<тип>:<id>:<день>, place:<id>, route:<id>
+func (c contentReportRepo) RecentProperties(ctx context.Context, authorID int, windowMinutes int, excludeTarget string) ([]interface{}, error) {
+	query := `SELECT properties FROM (
+					SELECT 'note:' || target_type || ':' || COALESCE(place_id, route_id, trip_id) || ':' ||
+						COALESCE(trip_day, 0) AS target, properties, updated_at AS changed_at
+					FROM notes WHERE user_id = $1
+				UNION ALL
+					SELECT 'place:' || place_id, properties, timestamp FROM places_reviews WHERE author_id = $1
+				UNION ALL
+					SELECT 'route:' || route_id, properties, timestamp FROM route_reviews WHERE author_id = $1
+				) recent
+				WHERE changed_at > current_timestamp - make_interval(mins => $2) AND target <> $3 AND properties IS NOT NULL;`
+
+	rows, err := c.db.QueryContext(ctx, query, authorID, windowMinutes, excludeTarget)
+	if err != nil {
+		return []interface{}{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ExecErr, Err: err})
+	}
+	defer rows.Close()
+
+	result := []interface{}{}
+	for rows.Next() {
+		var raw []byte
+		if err = rows.Scan(&raw); err != nil {
+			return []interface{}{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ScanErr, Err: err})
+		}
+
+		var properties interface{}
+		if err = json.Unmarshal(raw, &properties); err != nil {
+			return []interface{}{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.BindErr, Err: err})
+		}
+
+		result = append(result, properties)
+	}
+
+	if err = rows.Err(); err != nil {
+		return []interface{}{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.RowsErr, Err: err})
+	}
+
+	return result, nil
+}
@@ -0,0 +1,34 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"mth/internal/models"
+	"testing"
+)
+
+func TestContentReportRepo_RecentProperties(t *testing.T) {
+	db := testDB(t)
+	repo := InitContentReportRepo(db)
+	placeID, userIDs := createRatedPlace(t, db, 2)
+	author, other := userIDs[0], userIDs[1]
+
+	review := models.PlaceReviewCreate{PlaceID: placeID, ReviewBase: models.ReviewBase{
+		AuthorID: author, Mark: 5, Properties: map[string]interface{}{"comment": "всем советую"},
+	}}
+	if _, err := InitReviewRepo(db, testMarkScale).CreateOnPlace(context.TODO(), review, models.ReviewStatusPending); err != nil {
+		t.Fatal(err)
+	}
+
+	recent, err := repo.RecentProperties(context.TODO(), author, 60, "place:0")
+	if err != nil || fmt.Sprint(recent) != "[map[comment:всем советую]]" {
+		t.Errorf("expected the stored review, got %v, %v", recent, err)
+	}
+
+	for name, userID := range map[string]int{"same target": author, "other author": other} {
+		recent, err = repo.RecentProperties(context.TODO(), userID, 60, fmt.Sprintf("place:%v", placeID))
+		if err != nil || len(recent) != 0 {
+			t.Errorf("%v: expected no texts, got %v, %v", name, recent, err)
+		}
+	}
+}
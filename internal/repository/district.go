@@ -2,10 +2,15 @@ package repository
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/guregu/null/v5"
 	"github.com/jmoiron/sqlx"
 	"mth/internal/models"
 	"mth/pkg/customerr"
+	"mth/pkg/geojson"
 )
 
 type districtRepo struct {
@@ -18,28 +23,52 @@ func InitDistrictRepo(db *sqlx.DB) District {
 	}
 }
 
-func (d districtRepo) GetByCityID(ctx context.Context, cityID int) ([]models.District, error) {
-	query := `SELECT id, name, city_id, properties FROM district WHERE city_id = $1;`
+func boundaryToRaw(boundary *geojson.Geometry) ([]byte, error) {
+	if boundary == nil {
+		return nil, nil
+	}
 
-	rows, err := d.db.QueryContext(ctx, query, cityID)
+	return json.Marshal(boundary)
+}
+
+func scanDistrict(row interface{ Scan(dest ...any) error }) (models.District, error) {
+	var district models.District
+	var propertiesRaw, boundaryRaw []byte
+
+	err := row.Scan(&district.ID, &district.Name, &district.CityID, &propertiesRaw, &boundaryRaw)
+	if err != nil {
+		return models.District{}, err
+	}
+
+	err = json.Unmarshal(propertiesRaw, &district.Properties)
+	if err != nil {
+		return models.District{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.BindErr, Err: err})
+	}
+
+	if boundaryRaw != nil {
+		district.Boundary = &geojson.Geometry{}
+		if err = json.Unmarshal(boundaryRaw, district.Boundary); err != nil {
+			return models.District{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.BindErr, Err: err})
+		}
+	}
+
+	return district, nil
+}
+
+func (d districtRepo) queryDistricts(ctx context.Context, query string, args ...any) ([]models.District, error) {
+	rows, err := d.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return []models.District{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ExecErr, Err: err})
 	}
+	defer rows.Close()
 
-	var districts []models.District
+	districts := []models.District{}
 	for rows.Next() {
-		var district models.District
-		var propertiesRaw []byte
-		err = rows.Scan(&district.ID, &district.Name, &district.CityID, &propertiesRaw)
+		district, err := scanDistrict(rows)
 		if err != nil {
 			return []models.District{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ScanErr, Err: err})
 		}
 
-		err = json.Unmarshal(propertiesRaw, &district.Properties)
-		if err != nil {
-			return []models.District{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.BindErr, Err: err})
-		}
-
 		districts = append(districts, district)
 	}
 
@@ -50,3 +79,221 @@ func (d districtRepo) GetByCityID(ctx context.Context, cityID int) ([]models.Dis
 
 	return districts, nil
 }
+
+func (d districtRepo) GetByCityID(ctx context.Context, cityID int) ([]models.District, error) {
+	query := `SELECT id, name, city_id, properties, boundary FROM district WHERE city_id = $1 ORDER BY id;`
+
+	return d.queryDistricts(ctx, query, cityID)
+}
+
+// GetWithBoundaries районы с границами, при cityID = 0 по всем городам
+func (d districtRepo) GetWithBoundaries(ctx context.Context, cityID int) ([]models.District, error) {
+	query := `SELECT id, name, city_id, properties, boundary FROM district
+				WHERE boundary IS NOT NULL AND ($1 = 0 OR city_id = $1)
+				ORDER BY id;`
+
+	return d.queryDistricts(ctx, query, cityID)
+}
+
+// Create возвращает customerr.CityNotFound, если города нет
+func (d districtRepo) Create(ctx context.Context, district models.DistrictBase) (int, error) {
+	propertiesRaw, err := json.Marshal(district.Properties)
+	if err != nil {
+		return 0, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.BindErr, Err: err})
+	}
+
+	boundaryRaw, err := boundaryToRaw(district.Boundary)
+	if err != nil {
+		return 0, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.BindErr, Err: err})
+	}
+
+	tx, err := d.db.Beginx()
+	if err != nil {
+		return 0, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.TransactionErr, Err: err})
+	}
+
+	var cityExists bool
+	err = tx.QueryRowxContext(ctx, `SELECT EXISTS (SELECT 1 FROM city WHERE id = $1);`, district.CityID).Scan(&cityExists)
+	if err != nil {
+		return 0, rollbackWithErr(tx, customerr.ScanErr, err)
+	}
+
+	if !cityExists {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return 0, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.RollbackErr, Err: rbErr})
+		}
+
+		return 0, customerr.CityNotFound
+	}
+
+	createDistrictQuery := `INSERT INTO district (name, city_id, properties, boundary) VALUES ($1, $2, $3, $4) RETURNING id;`
+
+	var createdID int
+	err = tx.QueryRowxContext(ctx, createDistrictQuery, district.Name, district.CityID, propertiesRaw, boundaryRaw).Scan(&createdID)
+	if err != nil {
+		return 0, rollbackWithErr(tx, customerr.ScanErr, err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return 0, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.CommitErr, Err: err})
+	}
+
+	return createdID, nil
+}
+
+// GetByID возвращает customerr.DistrictNotFound, если района нет
+func (d districtRepo) GetByID(ctx context.Context, districtID int) (models.District, error) {
+	query := `SELECT id, name, city_id, properties, boundary FROM district WHERE id = $1;`
+
+	district, err := scanDistrict(d.db.QueryRowContext(ctx, query, districtID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.District{}, customerr.DistrictNotFound
+	}
+	if err != nil {
+		return models.District{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ScanErr, Err: err})
+	}
+
+	return district, nil
+}
+
+func (d districtRepo) Update(ctx context.Context, district models.DistrictUpdate) error {
+	propertiesRaw, err := json.Marshal(district.Properties)
+	if err != nil {
+		return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.BindErr, Err: err})
+	}
+
+	boundaryRaw, err := boundaryToRaw(district.Boundary)
+	if err != nil {
+		return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.BindErr, Err: err})
+	}
+
+	updateDistrictQuery := `UPDATE district SET name = $2, properties = $3, boundary = $4 WHERE id = $1;`
+
+	res, err := d.db.ExecContext(ctx, updateDistrictQuery, district.ID, district.Name, propertiesRaw, boundaryRaw)
+	if err != nil {
+		return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ExecErr, Err: err})
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.RowsErr, Err: err})
+	}
+	if count != 1 {
+		return customerr.DistrictNotFound
+	}
+
+	return nil
+}
+
+// Delete возвращает customerr.DistrictInUse, если в районе есть места
+func (d districtRepo) Delete(ctx context.Context, districtID int) error {
+	tx, err := d.db.Beginx()
+	if err != nil {
+		return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.TransactionErr, Err: err})
+	}
+
+	var usage int
+	err = tx.QueryRowxContext(ctx, `SELECT COUNT(*) FROM places WHERE district_id = $1;`, districtID).Scan(&usage)
+	if err != nil {
+		return rollbackWithErr(tx, customerr.ScanErr, err)
+	}
+
+	if usage > 0 {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.RollbackErr, Err: rbErr})
+		}
+
+		return customerr.DistrictInUse
+	}
+
+	res, err := tx.ExecContext(ctx, `DELETE FROM district WHERE id = $1;`, districtID)
+	if err != nil {
+		return rollbackWithErr(tx, customerr.ExecErr, err)
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return rollbackWithErr(tx, customerr.RowsErr, err)
+	}
+	if count != 1 {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return customerr.ErrNormalizer(
+				customerr.ErrorPair{Message: customerr.ExecErr, Err: fmt.Errorf(customerr.CountErr, count)},
+				customerr.ErrorPair{Message: customerr.RollbackErr, Err: rbErr},
+			)
+		}
+
+		return customerr.DistrictNotFound
+	}
+
+	if err = tx.Commit(); err != nil {
+		return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.CommitErr, Err: err})
+	}
+
+	return nil
+}
+
+// GetPlaceLocations места с координатами, при cityID = 0 по всем городам
+func (d districtRepo) GetPlaceLocations(ctx context.Context, cityID int) ([]models.PlaceLocation, error) {
+	query := `SELECT id, name, COALESCE(city_id, 0), COALESCE(district_id, 0), latitude, longitude FROM places
+				WHERE latitude IS NOT NULL AND longitude IS NOT NULL AND ($1 = 0 OR city_id = $1)
+				ORDER BY id;`
+
+	rows, err := d.db.QueryContext(ctx, query, cityID)
+	if err != nil {
+		return nil, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ExecErr, Err: err})
+	}
+	defer rows.Close()
+
+	var places []models.PlaceLocation
+	for rows.Next() {
+		var place models.PlaceLocation
+		var name null.String
+		var latitude, longitude null.Float
+
+		err = rows.Scan(&place.ID, &name, &place.CityID, &place.DistrictID, &latitude, &longitude)
+		if err != nil {
+			return nil, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ScanErr, Err: err})
+		}
+
+		place.Name = name.String
+		place.Coordinates = pointFromNull(latitude, longitude)
+		places = append(places, place)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.RowsErr, Err: err})
+	}
+
+	return places, nil
+}
+
+// AssignPlaces переназначает районы местам одной транзакцией, ключ - id места, значение - id района, 0 снимает район
+func (d districtRepo) AssignPlaces(ctx context.Context, assignments map[int]int) (int, error) {
+	tx, err := d.db.Beginx()
+	if err != nil {
+		return 0, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.TransactionErr, Err: err})
+	}
+
+	var updated int
+	for placeID, districtID := range assignments {
+		res, err := tx.ExecContext(ctx, `UPDATE places SET district_id = $2 WHERE id = $1;`, placeID,
+			null.NewInt(int64(districtID), districtID != 0))
+		if err != nil {
+			return 0, rollbackWithErr(tx, customerr.ExecErr, err)
+		}
+
+		count, err := res.RowsAffected()
+		if err != nil {
+			return 0, rollbackWithErr(tx, customerr.RowsErr, err)
+		}
+
+		updated += int(count)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return 0, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.CommitErr, Err: err})
+	}
+
+	return updated, nil
+}
@@ -0,0 +1,370 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/guregu/null/v5"
+	"github.com/jmoiron/sqlx"
+	"mth/internal/models"
+	"mth/pkg/customerr"
+	"mth/pkg/pagination"
+)
+
+type placeDuplicateRepo struct {
+	db    *sqlx.DB
+	scale models.MarkScale
+}
+
+// InitPlaceDuplicateRepo scale нужна для пересчёта рейтинга места после слияния
+func InitPlaceDuplicateRepo(db *sqlx.DB, scale models.MarkScale) PlaceDuplicate {
+	return placeDuplicateRepo{
+		db:    db,
+		scale: scale,
+	}
+}
+
+func (d placeDuplicateRepo) GetLocations(ctx context.Context) ([]models.PlaceLocation, error) {
+	query := `SELECT id, name, COALESCE(city_id, 0), latitude, longitude FROM places WHERE NOT archived ORDER BY id;`
+
+	rows, err := d.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ExecErr, Err: err})
+	}
+	defer rows.Close()
+
+	var places []models.PlaceLocation
+	for rows.Next() {
+		var place models.PlaceLocation
+		var name null.String
+		var latitude, longitude null.Float
+
+		if err = rows.Scan(&place.ID, &name, &place.CityID, &latitude, &longitude); err != nil {
+			return nil, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ScanErr, Err: err})
+		}
+
+		place.Name = name.String
+		place.Coordinates = pointFromNull(latitude, longitude)
+		places = append(places, place)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.RowsErr, Err: err})
+	}
+
+	return places, nil
+}
+
+// Save добавляет новые пары и обновляет оценку у ещё не рассмотренных, отклонённые пары не трогает
+func (d placeDuplicateRepo) Save(ctx context.Context, duplicates []models.PlaceDuplicate) error {
+	tx, err := d.db.Beginx()
+	if err != nil {
+		return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.TransactionErr, Err: err})
+	}
+
+	saveQuery := `INSERT INTO place_duplicates (place_id, duplicate_id, similarity, distance) VALUES ($1, $2, $3, $4)
+					ON CONFLICT (place_id, duplicate_id) DO UPDATE
+					SET similarity = EXCLUDED.similarity, distance = EXCLUDED.distance
+					WHERE place_duplicates.status = 'pending';`
+
+	for _, duplicate := range duplicates {
+		_, err = tx.ExecContext(ctx, saveQuery, duplicate.PlaceID, duplicate.DuplicateID, duplicate.Similarity,
+			null.FloatFromPtr(duplicate.Distance))
+		if err != nil {
+			return rollbackWithErr(tx, customerr.ExecErr, err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.CommitErr, Err: err})
+	}
+
+	return nil
+}
+
+// GetPending page.PageSize уже ограничен сервисом, порядок по id пары
+func (d placeDuplicateRepo) GetPending(ctx context.Context, page models.PageRequest) (models.Page[models.PlaceDuplicate], error) {
+	var cursor idCursor
+	if err := pagination.DecodeCursor(page.Cursor, &cursor); err != nil {
+		return models.Page[models.PlaceDuplicate]{}, err
+	}
+
+	query := `SELECT pd.id, pd.place_id, p.name, pd.duplicate_id, dp.name, pd.similarity, pd.distance, pd.status, pd.detected_at
+				FROM place_duplicates pd
+				JOIN places p ON p.id = pd.place_id
+				JOIN places dp ON dp.id = pd.duplicate_id
+				WHERE pd.status = 'pending' AND pd.id > $1
+				ORDER BY pd.id
+				LIMIT $2;`
+
+	rows, err := d.db.QueryContext(ctx, query, cursor.ID, page.PageSize+1)
+	if err != nil {
+		return models.Page[models.PlaceDuplicate]{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ExecErr, Err: err})
+	}
+	defer rows.Close()
+
+	result := models.Page[models.PlaceDuplicate]{Items: []models.PlaceDuplicate{}}
+	for rows.Next() {
+		var duplicate models.PlaceDuplicate
+		var placeName, duplicateName null.String
+		var distance null.Float
+
+		err = rows.Scan(&duplicate.ID, &duplicate.PlaceID, &placeName, &duplicate.DuplicateID, &duplicateName,
+			&duplicate.Similarity, &distance, &duplicate.Status, &duplicate.DetectedAt)
+		if err != nil {
+			return models.Page[models.PlaceDuplicate]{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ScanErr, Err: err})
+		}
+
+		duplicate.PlaceName = placeName.String
+		duplicate.DuplicateName = duplicateName.String
+		duplicate.Distance = distance.Ptr()
+		result.Items = append(result.Items, duplicate)
+	}
+
+	if err = rows.Err(); err != nil {
+		return models.Page[models.PlaceDuplicate]{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.RowsErr, Err: err})
+	}
+
+	if len(result.Items) > page.PageSize {
+		result.Items = result.Items[:page.PageSize]
+		result.HasMore = true
+
+		result.NextCursor, err = pagination.EncodeCursor(idCursor{ID: result.Items[page.PageSize-1].ID})
+		if err != nil {
+			return models.Page[models.PlaceDuplicate]{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.BindErr, Err: err})
+		}
+	}
+
+	if page.WithTotal {
+		var total int
+		err = d.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM place_duplicates WHERE status = 'pending';`).Scan(&total)
+		if err != nil {
+			return models.Page[models.PlaceDuplicate]{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ScanErr, Err: err})
+		}
+
+		result.Total = &total
+	}
+
+	return result, nil
+}
+
+func (d placeDuplicateRepo) Dismiss(ctx context.Context, duplicateID int) error {
+	query := `UPDATE place_duplicates SET status = 'dismissed' WHERE id = $1;`
+
+	res, err := d.db.ExecContext(ctx, query, duplicateID)
+	if err != nil {
+		return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ExecErr, Err: err})
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.RowsErr, Err: err})
+	}
+
+	if count != 1 {
+		return fmt.Errorf(customerr.CountErr, count)
+	}
+
+	return nil
+}
+
+// mergeStep запрос слияния с $1 - id сохраняемого места и $2 - id дубля, число затронутых строк прибавляется к count
+type mergeStep struct {
+	query string
+	count *int
+}
+
+// mergeSteps порядок важен: сначала разрешаются конфликты уникальности, затем переносятся оставшиеся строки.
+// Из двух отзывов одного автора остаётся более поздний, заметки переносятся все, у отметок и избранного остаётся
+// самое раннее время, из двух остановок одного маршрута - остановка сохраняемого места. Медиа, переводы и
+// редакторы дубля переносятся, если у сохраняемого места их ещё нет
+func mergeSteps(counts *models.PlaceMergeCounts) []mergeStep {
+	var ignored int
+
+	return []mergeStep{
+		{`INSERT INTO places_tags (place_id, tag_id) SELECT $1, tag_id FROM places_tags WHERE place_id = $2
+			ON CONFLICT (tag_id, place_id) DO NOTHING;`, &counts.Tags},
+
+		{`DELETE FROM places_reviews s USING places_reviews d
+			WHERE s.place_id = $1 AND d.place_id = $2 AND s.author_id = d.author_id
+			AND (COALESCE(d.timestamp, '-infinity'), d.id) > (COALESCE(s.timestamp, '-infinity'), s.id);`, &counts.ReviewsDropped},
+		{`DELETE FROM places_reviews d USING places_reviews s
+			WHERE d.place_id = $2 AND s.place_id = $1 AND d.author_id = s.author_id;`, &counts.ReviewsDropped},
+		{`UPDATE places_reviews SET place_id = $1 WHERE place_id = $2;`, &counts.Reviews},
+
+		{`UPDATE notes SET place_id = $1 WHERE place_id = $2;`, &counts.Notes},
+
+		{`UPDATE users_place_checkin s SET timestamp = LEAST(s.timestamp, d.timestamp)
+			FROM users_place_checkin d WHERE s.place_id = $1 AND d.place_id = $2 AND s.user_id = d.user_id;`, &ignored},
+		{`DELETE FROM users_place_checkin d USING users_place_checkin s
+			WHERE d.place_id = $2 AND s.place_id = $1 AND d.user_id = s.user_id;`, &counts.CheckInsDropped},
+		{`UPDATE users_place_checkin SET place_id = $1 WHERE place_id = $2;`, &counts.CheckIns},
+
+		{`UPDATE users_favourite_places s SET timestamp = LEAST(s.timestamp, d.timestamp)
+			FROM users_favourite_places d WHERE s.place_id = $1 AND d.place_id = $2 AND s.user_id = d.user_id;`, &ignored},
+		{`DELETE FROM users_favourite_places d USING users_favourite_places s
+			WHERE d.place_id = $2 AND s.place_id = $1 AND d.user_id = s.user_id;`, &counts.FavouritesDropped},
+		{`UPDATE users_favourite_places SET place_id = $1 WHERE place_id = $2;`, &counts.Favourites},
+
+		{`UPDATE routes_places rp SET position = rp.position - 1
+			FROM routes_places d JOIN routes_places s ON s.route_id = d.route_id AND s.place_id = $1
+			WHERE d.place_id = $2 AND rp.route_id = d.route_id AND rp.position > d.position;`, &ignored},
+		{`DELETE FROM routes_places d USING routes_places s
+			WHERE d.place_id = $2 AND s.place_id = $1 AND d.route_id = s.route_id;`, &counts.RouteStopsDropped},
+		{`UPDATE routes_places SET place_id = $1 WHERE place_id = $2;`, &counts.RouteStops},
+
+		{`UPDATE trip_places SET place_id = $1 WHERE place_id = $2;`, &counts.TripEntries},
+		{`UPDATE companions_places SET place_id = $1 WHERE place_id = $2;`, &counts.Companions},
+
+		{`DELETE FROM media_attachments d USING media_attachments s
+			WHERE d.entity_type = 'place' AND d.entity_id = $2 AND s.entity_type = 'place' AND s.entity_id = $1
+			AND d.media_id = s.media_id;`, &ignored},
+		{`UPDATE media_attachments SET entity_id = $1 WHERE entity_type = 'place' AND entity_id = $2;`, &counts.Media},
+
+		{`INSERT INTO translations (entity_type, entity_id, lang, field, value)
+			SELECT entity_type, $1, lang, field, value FROM translations WHERE entity_type = 'place' AND entity_id = $2
+			ON CONFLICT (entity_type, entity_id, lang, field) DO NOTHING;`, &ignored},
+		{`DELETE FROM translations WHERE entity_type = 'place' AND entity_id = $2;`, &ignored},
+
+		{`INSERT INTO entity_managers (entity_type, entity_id, user_id, role)
+			SELECT entity_type, $1, user_id, role FROM entity_managers WHERE entity_type = 'place' AND entity_id = $2
+			ON CONFLICT (entity_type, entity_id, user_id) DO NOTHING;`, &ignored},
+		{`DELETE FROM entity_managers WHERE entity_type = 'place' AND entity_id = $2;`, &ignored},
+	}
+}
+
+// Merge переносит всё, что ссылается на дубль, на сохраняемое место, удаляет дубль и пишет запись в журнал
+// одной транзакцией. Пары дублей с удалённым местом удаляются каскадно
+func (d placeDuplicateRepo) Merge(ctx context.Context, request models.PlaceMergeRequest) (models.PlaceMerge, error) {
+	tx, err := d.db.Beginx()
+	if err != nil {
+		return models.PlaceMerge{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.TransactionErr, Err: err})
+	}
+
+	lockQuery := `SELECT COUNT(*) FROM (SELECT id FROM places WHERE id IN ($1, $2) ORDER BY id FOR UPDATE) locked;`
+
+	var found int
+	if err = tx.QueryRowxContext(ctx, lockQuery, request.SurvivorID, request.DuplicateID).Scan(&found); err != nil {
+		return models.PlaceMerge{}, rollbackWithErr(tx, customerr.ScanErr, err)
+	}
+
+	if found != 2 {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return models.PlaceMerge{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.RollbackErr, Err: rbErr})
+		}
+
+		return models.PlaceMerge{}, customerr.PlaceNotFound
+	}
+
+	merge := models.PlaceMerge{
+		SurvivorID:  request.SurvivorID,
+		DuplicateID: request.DuplicateID,
+	}
+
+	snapshotQuery := `SELECT to_jsonb(p) || jsonb_build_object('tags', COALESCE(
+						(SELECT jsonb_agg(t.name ORDER BY t.name) FROM places_tags pt JOIN tags t ON t.id = pt.tag_id WHERE pt.place_id = p.id),
+						'[]'::JSONB))
+					FROM places p WHERE p.id = $1;`
+
+	if err = tx.QueryRowxContext(ctx, snapshotQuery, request.DuplicateID).Scan(&merge.Duplicate); err != nil {
+		return models.PlaceMerge{}, rollbackWithErr(tx, customerr.ScanErr, err)
+	}
+
+	for _, step := range mergeSteps(&merge.Counts) {
+		res, err := tx.ExecContext(ctx, step.query, request.SurvivorID, request.DuplicateID)
+		if err != nil {
+			return models.PlaceMerge{}, rollbackWithErr(tx, customerr.ExecErr, err)
+		}
+
+		count, err := res.RowsAffected()
+		if err != nil {
+			return models.PlaceMerge{}, rollbackWithErr(tx, customerr.RowsErr, err)
+		}
+
+		*step.count += int(count)
+	}
+
+	res, err := tx.ExecContext(ctx, `DELETE FROM places WHERE id = $1;`, request.DuplicateID)
+	if err != nil {
+		return models.PlaceMerge{}, rollbackWithErr(tx, customerr.ExecErr, err)
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return models.PlaceMerge{}, rollbackWithErr(tx, customerr.RowsErr, err)
+	}
+
+	if count != 1 {
+		return models.PlaceMerge{}, rollbackWithErr(tx, customerr.ExecErr, fmt.Errorf(customerr.CountErr, count))
+	}
+
+	if _, err = tx.ExecContext(ctx, placeRatingQuery, ratingArgs(d.scale, request.SurvivorID)...); err != nil {
+		return models.PlaceMerge{}, rollbackWithErr(tx, customerr.ExecErr, err)
+	}
+
+	countsRaw, err := json.Marshal(merge.Counts)
+	if err != nil {
+		return models.PlaceMerge{}, rollbackWithErr(tx, customerr.BindErr, err)
+	}
+
+	mergedBy := null.NewInt(int64(request.UserID), request.UserID != 0)
+	auditQuery := `INSERT INTO place_merges (survivor_id, duplicate_id, merged_by, duplicate, counts)
+					VALUES ($1, $2, $3, $4, $5) RETURNING id, merged_at;`
+
+	err = tx.QueryRowxContext(ctx, auditQuery, request.SurvivorID, request.DuplicateID, mergedBy, []byte(merge.Duplicate), countsRaw).
+		Scan(&merge.ID, &merge.MergedAt)
+	if err != nil {
+		return models.PlaceMerge{}, rollbackWithErr(tx, customerr.ScanErr, err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return models.PlaceMerge{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.CommitErr, Err: err})
+	}
+
+	if mergedBy.Valid {
+		merge.MergedBy = &request.UserID
+	}
+
+	return merge, nil
+}
+
+func (d placeDuplicateRepo) GetMerges(ctx context.Context, survivorID int) ([]models.PlaceMerge, error) {
+	query := `SELECT id, survivor_id, duplicate_id, merged_by, duplicate, counts, merged_at FROM place_merges
+				WHERE survivor_id = $1 ORDER BY merged_at DESC, id DESC;`
+
+	rows, err := d.db.QueryContext(ctx, query, survivorID)
+	if err != nil {
+		return nil, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ExecErr, Err: err})
+	}
+	defer rows.Close()
+
+	merges := []models.PlaceMerge{}
+	for rows.Next() {
+		var merge models.PlaceMerge
+		var mergedBy null.Int
+		var duplicateRaw, countsRaw []byte
+
+		err = rows.Scan(&merge.ID, &merge.SurvivorID, &merge.DuplicateID, &mergedBy, &duplicateRaw, &countsRaw, &merge.MergedAt)
+		if err != nil {
+			return nil, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ScanErr, Err: err})
+		}
+
+		if mergedBy.Valid {
+			userID := int(mergedBy.Int64)
+			merge.MergedBy = &userID
+		}
+		merge.Duplicate = duplicateRaw
+
+		if err = json.Unmarshal(countsRaw, &merge.Counts); err != nil {
+			return nil, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.BindErr, Err: err})
+		}
+
+		merges = append(merges, merge)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.RowsErr, Err: err})
+	}
+
+	return merges, nil
+}
@@ -0,0 +1,309 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/guregu/null/v5"
+	"github.com/jmoiron/sqlx"
+	"mth/internal/models"
+	"mth/pkg/customerr"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+type importRepo struct {
+	db *sqlx.DB
+}
+
+func InitImportRepo(db *sqlx.DB) Import {
+	return importRepo{
+		db: db,
+	}
+}
+
+type importDistrict struct {
+	id     int
+	cityID int
+}
+
+// resolvedPlace строка импорта с найденными идентификаторами
+type resolvedPlace struct {
+	cityID     int
+	districtID int
+	place      models.PlaceImport
+}
+
+func rollbackWithErr(tx *sqlx.Tx, message string, err error) error {
+	if rbErr := tx.Rollback(); rbErr != nil {
+		return customerr.ErrNormalizer(
+			customerr.ErrorPair{Message: message, Err: err},
+			customerr.ErrorPair{Message: customerr.RollbackErr, Err: rbErr},
+		)
+	}
+
+	return customerr.ErrNormalizer(customerr.ErrorPair{Message: message, Err: err})
+}
+
+func (i importRepo) loadNameIDs(ctx context.Context, tx *sqlx.Tx, query string) (map[string]int, error) {
+	rows, err := tx.QueryContext(ctx, query)
+	if err != nil {
+		return nil, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ExecErr, Err: err})
+	}
+	defer rows.Close()
+
+	ids := make(map[string]int)
+	for rows.Next() {
+		var id int
+		var name string
+
+		if err = rows.Scan(&id, &name); err != nil {
+			return nil, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ScanErr, Err: err})
+		}
+
+		ids[name] = id
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.RowsErr, Err: err})
+	}
+
+	return ids, nil
+}
+
+func (i importRepo) loadDistricts(ctx context.Context, tx *sqlx.Tx) (map[string]importDistrict, error) {
+	rows, err := tx.QueryContext(ctx, `SELECT id, name, city_id FROM district;`)
+	if err != nil {
+		return nil, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ExecErr, Err: err})
+	}
+	defer rows.Close()
+
+	districts := make(map[string]importDistrict)
+	for rows.Next() {
+		var district importDistrict
+		var name string
+
+		if err = rows.Scan(&district.id, &name, &district.cityID); err != nil {
+			return nil, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ScanErr, Err: err})
+		}
+
+		districts[name] = district
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.RowsErr, Err: err})
+	}
+
+	return districts, nil
+}
+
+func (i importRepo) existingPlaceNames(ctx context.Context, tx *sqlx.Tx, places []models.PlaceImport) (map[string]bool, error) {
+	names := make([]string, 0, len(places))
+	for _, place := range places {
+		names = append(names, place.Name)
+	}
+
+	rows, err := tx.QueryContext(ctx, `SELECT name FROM places WHERE name = ANY($1);`, pq.Array(names))
+	if err != nil {
+		return nil, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ExecErr, Err: err})
+	}
+	defer rows.Close()
+
+	existing := make(map[string]bool)
+	for rows.Next() {
+		var name string
+
+		if err = rows.Scan(&name); err != nil {
+			return nil, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ScanErr, Err: err})
+		}
+
+		existing[name] = true
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.RowsErr, Err: err})
+	}
+
+	return existing, nil
+}
+
+// ImportPlaces проверяет все строки и, если ошибок нет и это не dryRun, применяет их одной транзакцией.
+// Места обновляются по уникальному имени, отсутствующие теги создаются. Строкам с координатами район назначает assignDistrict
+func (i importRepo) ImportPlaces(ctx context.Context, places []models.PlaceImport, dryRun bool,
+	assignDistrict func(place *models.PlaceBase)) (models.ImportReport, error) {
+	report := models.ImportReport{
+		DryRun: dryRun,
+		Total:  len(places),
+		Errors: []models.ImportRowError{},
+	}
+
+	tx, err := i.db.Beginx()
+	if err != nil {
+		return models.ImportReport{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.TransactionErr, Err: err})
+	}
+
+	cities, err := i.loadNameIDs(ctx, tx, `SELECT id, name FROM city;`)
+	if err != nil {
+		return models.ImportReport{}, rollbackWithErr(tx, "load cities: %v", err)
+	}
+
+	districts, err := i.loadDistricts(ctx, tx)
+	if err != nil {
+		return models.ImportReport{}, rollbackWithErr(tx, "load districts: %v", err)
+	}
+
+	// синонимы указывают на свой тег, чтобы импорт не заводил дубли
+	tagIDs, err := i.loadNameIDs(ctx, tx, `SELECT tag_id, name FROM tag_synonyms UNION ALL SELECT id, name FROM tags;`)
+	if err != nil {
+		return models.ImportReport{}, rollbackWithErr(tx, "load tags: %v", err)
+	}
+
+	// названия тегов уникальны без учёта регистра, ключ - название в нижнем регистре
+	tags := make(map[string]int, len(tagIDs))
+	for name, id := range tagIDs {
+		tags[strings.ToLower(name)] = id
+	}
+
+	varieties, err := i.loadNameIDs(ctx, tx, `SELECT id, name FROM varieties;`)
+	if err != nil {
+		return models.ImportReport{}, rollbackWithErr(tx, "load varieties: %v", err)
+	}
+
+	existing, err := i.existingPlaceNames(ctx, tx, places)
+	if err != nil {
+		return models.ImportReport{}, rollbackWithErr(tx, "load places: %v", err)
+	}
+
+	resolved := make([]resolvedPlace, 0, len(places))
+	newTags := make(map[string]string)
+
+	for _, place := range places {
+		rowErr := func(message string) {
+			report.Errors = append(report.Errors, models.ImportRowError{Row: place.Row, Name: place.Name, Error: message})
+		}
+
+		current := resolvedPlace{place: place}
+
+		if _, ok := varieties[place.Variety]; !ok {
+			rowErr(fmt.Sprintf("%s %q", customerr.UnknownVariety.Error(), place.Variety))
+			continue
+		}
+
+		if place.City != "" {
+			cityID, ok := cities[place.City]
+			if !ok {
+				rowErr(fmt.Sprintf("unknown city %q", place.City))
+				continue
+			}
+			current.cityID = cityID
+		}
+
+		if place.District != "" {
+			district, ok := districts[place.District]
+			if !ok {
+				rowErr(fmt.Sprintf("unknown district %q", place.District))
+				continue
+			}
+			if current.cityID != 0 && current.cityID != district.cityID {
+				rowErr(customerr.DistrictNotInCity.Error())
+				continue
+			}
+			current.districtID = district.id
+			current.cityID = district.cityID
+		}
+
+		if current.cityID == 0 {
+			rowErr("city or district is required")
+			continue
+		}
+
+		if place.Coordinates != nil {
+			base := models.PlaceBase{CityID: current.cityID, DistrictID: current.districtID, Coordinates: place.Coordinates}
+			assignDistrict(&base)
+			current.cityID, current.districtID = base.CityID, base.DistrictID
+		}
+
+		for _, tag := range place.Tags {
+			key := strings.ToLower(tag)
+			if _, ok := tags[key]; !ok && newTags[key] == "" {
+				newTags[key] = tag
+			}
+		}
+
+		if existing[place.Name] {
+			report.Updated++
+		} else {
+			report.Created++
+		}
+
+		resolved = append(resolved, current)
+	}
+
+	report.TagsCreated = len(newTags)
+
+	if dryRun || len(report.Errors) > 0 {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return models.ImportReport{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.RollbackErr, Err: rbErr})
+		}
+
+		return report, nil
+	}
+
+	createTagQuery := `INSERT INTO tags (name) VALUES ($1) RETURNING id;`
+	for key, tag := range newTags {
+		var tagID int
+		if err = tx.QueryRowxContext(ctx, createTagQuery, tag).Scan(&tagID); err != nil {
+			return models.ImportReport{}, rollbackWithErr(tx, customerr.ScanErr, err)
+		}
+
+		tags[key] = tagID
+	}
+
+	upsertPlaceQuery := `INSERT INTO places (city_id, district_id, properties, name, variety, latitude, longitude)
+							VALUES ($1, $2, $3, $4, $5, $6, $7)
+							ON CONFLICT (name) DO UPDATE SET
+								city_id = EXCLUDED.city_id,
+								district_id = EXCLUDED.district_id,
+								properties = EXCLUDED.properties,
+								variety = EXCLUDED.variety,
+								latitude = COALESCE(EXCLUDED.latitude, places.latitude),
+								longitude = COALESCE(EXCLUDED.longitude, places.longitude)
+							RETURNING id;`
+	deletePlaceTagsQuery := `DELETE FROM places_tags WHERE place_id = $1;`
+	createPlaceTagQuery := `INSERT INTO places_tags (place_id, tag_id) VALUES ($1, $2) ON CONFLICT DO NOTHING;`
+
+	for _, current := range resolved {
+		jsonProperties, err := json.Marshal(current.place.Properties)
+		if err != nil {
+			return models.ImportReport{}, rollbackWithErr(tx, customerr.BindErr, err)
+		}
+
+		latitude, longitude := pointToNull(current.place.Coordinates)
+
+		var placeID int
+		err = tx.QueryRowxContext(ctx, upsertPlaceQuery, current.cityID,
+			null.NewInt(int64(current.districtID), current.districtID != 0), jsonProperties, current.place.Name, current.place.Variety, latitude, longitude).Scan(&placeID)
+		if err != nil {
+			return models.ImportReport{}, rollbackWithErr(tx, customerr.ScanErr, err)
+		}
+
+		if _, err = tx.ExecContext(ctx, deletePlaceTagsQuery, placeID); err != nil {
+			return models.ImportReport{}, rollbackWithErr(tx, customerr.ExecErr, err)
+		}
+
+		for _, tag := range current.place.Tags {
+			if _, err = tx.ExecContext(ctx, createPlaceTagQuery, placeID, tags[strings.ToLower(tag)]); err != nil {
+				return models.ImportReport{}, rollbackWithErr(tx, customerr.ExecErr, err)
+			}
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return models.ImportReport{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.CommitErr, Err: err})
+	}
+
+	report.Applied = true
+
+	return report, nil
+}
@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"context"
+	"github.com/jmoiron/sqlx"
+	"mth/internal/models"
+	"mth/pkg/customerr"
+)
+
+// managedTables таблицы сущностей, к которым привязываются владельцы и редакторы
+var managedTables = map[string]string{
+	models.ReviewTypePlace: "places",
+	models.ReviewTypeRoute: "routes",
+}
+
+// isManager является ли пользователь владельцем или редактором сущности
+func isManager(ctx context.Context, tx *sqlx.Tx, entityType string, entityID int, userID int) (bool, error) {
+	query := `SELECT EXISTS(SELECT 1 FROM entity_managers WHERE entity_type = $1 AND entity_id = $2 AND user_id = $3);`
+
+	var manager bool
+	if err := tx.QueryRowxContext(ctx, query, entityType, entityID, userID).Scan(&manager); err != nil {
+		return false, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ScanErr, Err: err})
+	}
+
+	return manager, nil
+}
+
+// SetManager привязывает пользователя к месту или маршруту, повторная привязка меняет роль
+func (r reviewRepo) SetManager(ctx context.Context, manager models.EntityManager) error {
+	table, ok := managedTables[manager.EntityType]
+	if !ok {
+		return customerr.BadInput
+	}
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.TransactionErr, Err: err})
+	}
+
+	var exists bool
+	err = tx.QueryRowxContext(ctx, `SELECT EXISTS(SELECT 1 FROM `+table+` WHERE id = $1);`, manager.EntityID).Scan(&exists)
+	if err != nil {
+		return rollbackWithErr(tx, customerr.ScanErr, err)
+	}
+	if !exists {
+		return rollbackKeepErr(tx, customerr.ManagedEntityNotFound)
+	}
+
+	_, err = tx.ExecContext(ctx, `INSERT INTO entity_managers (entity_type, entity_id, user_id, role) VALUES ($1, $2, $3, $4)
+									ON CONFLICT (entity_type, entity_id, user_id) DO UPDATE SET role = EXCLUDED.role;`,
+		manager.EntityType, manager.EntityID, manager.UserID, manager.Role)
+	if err != nil {
+		return rollbackWithErr(tx, customerr.ExecErr, err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.CommitErr, Err: err})
+	}
+
+	return nil
+}
+
+func (r reviewRepo) DeleteManager(ctx context.Context, manager models.EntityManagerDelete) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM entity_managers WHERE entity_type = $1 AND entity_id = $2 AND user_id = $3;`,
+		manager.EntityType, manager.EntityID, manager.UserID)
+	if err != nil {
+		return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ExecErr, Err: err})
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.RowsErr, Err: err})
+	}
+	if count == 0 {
+		return customerr.EntityManagerNotFound
+	}
+
+	return nil
+}
+
+func (r reviewRepo) GetManagers(ctx context.Context, entityType string, entityID int) ([]models.EntityManager, error) {
+	query := `SELECT entity_type, entity_id, user_id, role FROM entity_managers
+				WHERE entity_type = $1 AND entity_id = $2 ORDER BY role DESC, user_id;`
+
+	rows, err := r.db.QueryContext(ctx, query, entityType, entityID)
+	if err != nil {
+		return []models.EntityManager{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ExecErr, Err: err})
+	}
+	defer rows.Close()
+
+	managers := []models.EntityManager{}
+	for rows.Next() {
+		var manager models.EntityManager
+		if err = rows.Scan(&manager.EntityType, &manager.EntityID, &manager.UserID, &manager.Role); err != nil {
+			return []models.EntityManager{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ScanErr, Err: err})
+		}
+
+		managers = append(managers, manager)
+	}
+
+	if err = rows.Err(); err != nil {
+		return []models.EntityManager{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.RowsErr, Err: err})
+	}
+
+	return managers, nil
+}
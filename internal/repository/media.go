@@ -0,0 +1,294 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"github.com/guregu/null/v5"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"mth/internal/models"
+	"mth/pkg/customerr"
+	"time"
+)
+
+type mediaRepo struct {
+	db *sqlx.DB
+}
+
+func InitMediaRepo(db *sqlx.DB) Media {
+	return mediaRepo{
+		db: db,
+	}
+}
+
+func (m mediaRepo) Create(ctx context.Context, media models.MediaCreate) (int, error) {
+	tx, err := m.db.Beginx()
+	if err != nil {
+		return 0, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.TransactionErr, Err: err})
+	}
+
+	createMediaQuery := `INSERT INTO media (owner_id, storage_key, thumbnail_key, content_type, size, width, height, created_at)
+							VALUES ($1, $2, $3, $4, $5, $6, $7, current_timestamp) RETURNING id;`
+
+	var createdID int
+	err = tx.QueryRowxContext(ctx, createMediaQuery, media.OwnerID, media.StorageKey, null.NewString(media.ThumbnailKey, media.ThumbnailKey != ""),
+		media.ContentType, media.Size, media.Width, media.Height).Scan(&createdID)
+	if err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return 0, customerr.ErrNormalizer(
+				customerr.ErrorPair{Message: customerr.ScanErr, Err: err},
+				customerr.ErrorPair{Message: customerr.RollbackErr, Err: rbErr},
+			)
+		}
+
+		return 0, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ScanErr, Err: err})
+	}
+
+	if err = tx.Commit(); err != nil {
+		return 0, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.CommitErr, Err: err})
+	}
+
+	return createdID, nil
+}
+
+func (m mediaRepo) GetByID(ctx context.Context, mediaID int) (models.Media, error) {
+	query := `SELECT id, owner_id, storage_key, thumbnail_key, content_type, size, width, height, created_at
+				FROM media WHERE id = $1;`
+
+	var media models.Media
+	var thumbnailKey null.String
+	err := m.db.QueryRowContext(ctx, query, mediaID).Scan(&media.ID, &media.OwnerID, &media.StorageKey, &thumbnailKey,
+		&media.ContentType, &media.Size, &media.Width, &media.Height, &media.CreatedAt)
+	if err != nil {
+		return models.Media{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ScanErr, Err: err})
+	}
+
+	media.ThumbnailKey = thumbnailKey.String
+
+	return media, nil
+}
+
+// GetEntityOwner возвращает автора отзыва или заметки
+func (m mediaRepo) GetEntityOwner(ctx context.Context, entityType string, entityID int) (int, error) {
+	var query string
+
+	switch entityType {
+	case models.MediaEntityPlaceReview:
+		query = `SELECT author_id FROM places_reviews WHERE id = $1;`
+	case models.MediaEntityRouteReview:
+		query = `SELECT author_id FROM route_reviews WHERE id = $1;`
+	case models.MediaEntityNote:
+		query = `SELECT user_id FROM notes WHERE id = $1;`
+	default:
+		return 0, customerr.BadInput
+	}
+
+	var ownerID null.Int
+	err := m.db.QueryRowContext(ctx, query, entityID).Scan(&ownerID)
+	if err != nil {
+		return 0, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ScanErr, Err: err})
+	}
+
+	return int(ownerID.Int64), nil
+}
+
+// IsEntityManager является ли пользователь владельцем или редактором места или маршрута, сущность должна существовать
+func (m mediaRepo) IsEntityManager(ctx context.Context, entityType string, entityID int, userID int) (bool, error) {
+	table, ok := managedTables[entityType]
+	if !ok {
+		return false, customerr.BadInput
+	}
+
+	query := `SELECT EXISTS(SELECT 1 FROM entity_managers WHERE entity_type = $1 AND entity_id = $2 AND user_id = $3)
+				FROM ` + table + ` WHERE id = $2;`
+
+	var manager bool
+	if err := m.db.QueryRowContext(ctx, query, entityType, entityID, userID).Scan(&manager); err != nil {
+		return false, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ScanErr, Err: err})
+	}
+
+	return manager, nil
+}
+
+func (m mediaRepo) Attach(ctx context.Context, attachment models.MediaAttachment) error {
+	tx, err := m.db.Beginx()
+	if err != nil {
+		return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.TransactionErr, Err: err})
+	}
+
+	query := `INSERT INTO media_attachments (media_id, entity_type, entity_id, position) VALUES ($1, $2, $3, $4);`
+
+	_, err = tx.ExecContext(ctx, query, attachment.MediaID, attachment.EntityType, attachment.EntityID, attachment.Position)
+	if err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return customerr.ErrNormalizer(
+				customerr.ErrorPair{Message: customerr.ExecErr, Err: err},
+				customerr.ErrorPair{Message: customerr.RollbackErr, Err: rbErr},
+			)
+		}
+
+		return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ExecErr, Err: err})
+	}
+
+	if err = tx.Commit(); err != nil {
+		return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.CommitErr, Err: err})
+	}
+
+	return nil
+}
+
+func (m mediaRepo) execSingle(ctx context.Context, query string, args ...interface{}) error {
+	tx, err := m.db.Beginx()
+	if err != nil {
+		return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.TransactionErr, Err: err})
+	}
+
+	res, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return customerr.ErrNormalizer(
+				customerr.ErrorPair{Message: customerr.ExecErr, Err: err},
+				customerr.ErrorPair{Message: customerr.RollbackErr, Err: rbErr},
+			)
+		}
+
+		return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ExecErr, Err: err})
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return customerr.ErrNormalizer(
+				customerr.ErrorPair{Message: customerr.RowsErr, Err: err},
+				customerr.ErrorPair{Message: customerr.RollbackErr, Err: rbErr},
+			)
+		}
+
+		return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.RowsErr, Err: err})
+	}
+	if count != 1 {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return customerr.ErrNormalizer(
+				customerr.ErrorPair{Message: customerr.RowsErr, Err: fmt.Errorf(customerr.CountErr, count)},
+				customerr.ErrorPair{Message: customerr.RollbackErr, Err: rbErr},
+			)
+		}
+
+		return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.RowsErr, Err: fmt.Errorf(customerr.CountErr, count)})
+	}
+
+	if err = tx.Commit(); err != nil {
+		return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.CommitErr, Err: err})
+	}
+
+	return nil
+}
+
+func (m mediaRepo) Detach(ctx context.Context, attachment models.MediaAttachment) error {
+	query := `DELETE FROM media_attachments WHERE media_id = $1 AND entity_type = $2 AND entity_id = $3;`
+	return m.execSingle(ctx, query, attachment.MediaID, attachment.EntityType, attachment.EntityID)
+}
+
+func (m mediaRepo) ChangePosition(ctx context.Context, attachment models.MediaAttachment) error {
+	query := `UPDATE media_attachments SET position = $4 WHERE media_id = $1 AND entity_type = $2 AND entity_id = $3;`
+	return m.execSingle(ctx, query, attachment.MediaID, attachment.EntityType, attachment.EntityID, attachment.Position)
+}
+
+func (m mediaRepo) GetByEntity(ctx context.Context, entityType string, entityID int) ([]models.AttachedMedia, error) {
+	key := models.MediaEntityKey{EntityType: entityType, EntityID: entityID}
+
+	attached, err := m.GetByEntities(ctx, []models.MediaEntityKey{key})
+	if err != nil {
+		return []models.AttachedMedia{}, err
+	}
+
+	return attached[key], nil
+}
+
+// GetByEntities медиа нескольких сущностей одним запросом, внутри сущности в порядке позиций
+func (m mediaRepo) GetByEntities(ctx context.Context, entities []models.MediaEntityKey) (map[models.MediaEntityKey][]models.AttachedMedia, error) {
+	entityTypes := make([]string, len(entities))
+	entityIDs := make([]int64, len(entities))
+	for i, entity := range entities {
+		entityTypes[i] = entity.EntityType
+		entityIDs[i] = int64(entity.EntityID)
+	}
+
+	query := `SELECT ma.entity_type, ma.entity_id, ma.position, m.id, m.owner_id, m.storage_key, m.thumbnail_key, m.content_type,
+       			m.size, m.width, m.height, m.created_at
+				FROM media_attachments ma
+				JOIN unnest($1::TEXT[], $2::INTEGER[]) AS e(entity_type, entity_id)
+					ON ma.entity_type = e.entity_type AND ma.entity_id = e.entity_id
+				JOIN media m ON ma.media_id = m.id
+				ORDER BY ma.entity_type, ma.entity_id, ma.position, m.id;`
+
+	rows, err := m.db.QueryContext(ctx, query, pq.Array(entityTypes), pq.Array(entityIDs))
+	if err != nil {
+		return nil, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ExecErr, Err: err})
+	}
+	defer rows.Close()
+
+	attached := make(map[models.MediaEntityKey][]models.AttachedMedia)
+	for rows.Next() {
+		var key models.MediaEntityKey
+		var media models.AttachedMedia
+		var thumbnailKey null.String
+
+		err = rows.Scan(&key.EntityType, &key.EntityID, &media.Position, &media.ID, &media.OwnerID, &media.StorageKey,
+			&thumbnailKey, &media.ContentType, &media.Size, &media.Width, &media.Height, &media.CreatedAt)
+		if err != nil {
+			return nil, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ScanErr, Err: err})
+		}
+
+		media.ThumbnailKey = thumbnailKey.String
+
+		attached[key] = append(attached[key], media)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.RowsErr, Err: err})
+	}
+
+	return attached, nil
+}
+
+// GetOrphaned медиа без единого прикрепления, загруженные раньше createdBefore
+func (m mediaRepo) GetOrphaned(ctx context.Context, createdBefore time.Time) ([]models.Media, error) {
+	query := `SELECT m.id, m.owner_id, m.storage_key, m.thumbnail_key, m.content_type, m.size, m.width, m.height, m.created_at
+				FROM media m
+				WHERE m.created_at < $1 AND NOT EXISTS (SELECT 1 FROM media_attachments ma WHERE ma.media_id = m.id);`
+
+	rows, err := m.db.QueryContext(ctx, query, createdBefore)
+	if err != nil {
+		return []models.Media{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ExecErr, Err: err})
+	}
+
+	var orphaned []models.Media
+	for rows.Next() {
+		var media models.Media
+		var thumbnailKey null.String
+
+		err = rows.Scan(&media.ID, &media.OwnerID, &media.StorageKey, &thumbnailKey,
+			&media.ContentType, &media.Size, &media.Width, &media.Height, &media.CreatedAt)
+		if err != nil {
+			return []models.Media{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ScanErr, Err: err})
+		}
+
+		media.ThumbnailKey = thumbnailKey.String
+
+		orphaned = append(orphaned, media)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return []models.Media{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.RowsErr, Err: err})
+	}
+
+	return orphaned, nil
+}
+
+// Delete удаляет запись о медиа, только если к нему так и не появилось прикреплений
+func (m mediaRepo) Delete(ctx context.Context, mediaID int) error {
+	query := `DELETE FROM media m WHERE m.id = $1 AND NOT EXISTS (SELECT 1 FROM media_attachments ma WHERE ma.media_id = m.id);`
+	return m.execSingle(ctx, query, mediaID)
+}
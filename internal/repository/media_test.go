@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"context"
+	"mth/internal/models"
+	"testing"
+	"time"
+)
+
+// Прикрепления удалённого места не должны удерживать файлы от очистки
+func TestPlaceRepo_DeleteReleasesMedia(t *testing.T) {
+	db := testDB(t)
+	media := InitMediaRepo(db)
+	placeID, userIDs := createRatedPlace(t, db, 1)
+
+	mediaID, err := media.Create(context.TODO(), models.MediaCreate{OwnerID: userIDs[0], StorageKey: "test/place-delete", ContentType: "image/jpeg"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_, _ = db.Exec(`DELETE FROM media_attachments WHERE media_id = $1;`, mediaID)
+		_, _ = db.Exec(`DELETE FROM media WHERE id = $1;`, mediaID)
+	})
+
+	attachment := models.MediaAttachment{MediaID: mediaID, EntityType: models.MediaEntityPlace, EntityID: placeID}
+	if err = media.Attach(context.TODO(), attachment); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = InitPlaceRepo(db).Delete(context.TODO(), placeID); err != nil {
+		t.Fatal(err)
+	}
+
+	orphaned, err := media.GetOrphaned(context.TODO(), time.Now().Add(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, m := range orphaned {
+		if m.ID == mediaID {
+			return
+		}
+	}
+	t.Errorf("media of deleted place must be orphaned, got %v", orphaned)
+}
@@ -2,10 +2,15 @@ package repository
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/guregu/null/v5"
 	"github.com/jmoiron/sqlx"
 	"mth/internal/models"
 	"mth/pkg/customerr"
+	"mth/pkg/pagination"
 )
 
 type noteRepo struct {
@@ -18,30 +23,156 @@ func InitNoteRepo(db *sqlx.DB) Note {
 	}
 }
 
+// noteTargetColumns колонка с id цели заметки и таблица, где эта цель хранится
+var noteTargetColumns = map[string]struct {
+	column string
+	table  string
+}{
+	models.NoteTargetPlace:   {column: "place_id", table: "places"},
+	models.NoteTargetRoute:   {column: "route_id", table: "routes"},
+	models.NoteTargetTrip:    {column: "trip_id", table: "trips"},
+	models.NoteTargetTripDay: {column: "trip_id", table: "trips"},
+}
+
+// noteColumns поля заметки n в порядке scanNote
+const noteColumns = `n.id, n.user_id, n.target_type, n.place_id, n.route_id, n.trip_id, n.trip_day, n.properties,
+					n.visibility, n.created_at, n.updated_at,
+					EXISTS(SELECT 1 FROM users_place_checkin upc WHERE upc.place_id = n.place_id AND upc.user_id = n.user_id)`
+
+func scanNote(row interface{ Scan(dest ...any) error }) (models.Note, error) {
+	var note models.Note
+	var placeID, routeID, tripID, tripDay null.Int
+	var propertiesRaw []byte
+
+	err := row.Scan(&note.ID, &note.UserID, &note.TargetType, &placeID, &routeID, &tripID, &tripDay, &propertiesRaw,
+		&note.Visibility, &note.CreatedAt, &note.UpdatedAt, &note.IsCheckIn)
+	if err != nil {
+		return models.Note{}, err
+	}
+
+	switch note.TargetType {
+	case models.NoteTargetPlace:
+		note.PlaceID = int(placeID.Int64)
+		note.TargetID = note.PlaceID
+	case models.NoteTargetRoute:
+		note.TargetID = int(routeID.Int64)
+	default:
+		note.TargetID = int(tripID.Int64)
+		note.Day = int(tripDay.Int64)
+	}
+
+	if len(propertiesRaw) > 0 {
+		if err = json.Unmarshal(propertiesRaw, &note.Properties); err != nil {
+			return models.Note{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.BindErr, Err: err})
+		}
+	}
+
+	return note, nil
+}
+
+// noteVisibleCond заметка n видна пользователю из параметра viewerParam: своя, публичная или для попутчиков,
+// если между автором и смотрящим есть принятый запрос попутчика. Заметки по ссылке открываются только через GetByShareToken
+func noteVisibleCond(viewerParam string) string {
+	return `(n.user_id = ` + viewerParam + ` OR n.visibility = 'public' OR (n.visibility = 'companions' AND
+				EXISTS(SELECT 1 FROM companion_requests cr WHERE cr.status = 'accepted' AND
+						((cr.owner_id = n.user_id AND cr.requester_id = ` + viewerParam + `) OR
+						(cr.requester_id = n.user_id AND cr.owner_id = ` + viewerParam + `)))))`
+}
+
+func (n noteRepo) getMany(ctx context.Context, query string, args ...any) ([]models.Note, error) {
+	rows, err := n.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return []models.Note{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ExecErr, Err: err})
+	}
+	defer rows.Close()
+
+	notes := []models.Note{}
+	for rows.Next() {
+		note, err := scanNote(rows)
+		if err != nil {
+			return []models.Note{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ScanErr, Err: err})
+		}
+
+		notes = append(notes, note)
+	}
+
+	if err = rows.Err(); err != nil {
+		return []models.Note{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.RowsErr, Err: err})
+	}
+
+	return notes, nil
+}
+
+// checkNoteTarget цель должна существовать, поездка - принадлежать автору заметки,
+// день - входить в поездку, если её даты известны
+func checkNoteTarget(ctx context.Context, tx *sqlx.Tx, userID int, target models.NoteTarget) error {
+	targetColumns, ok := noteTargetColumns[target.TargetType]
+	if !ok {
+		return fmt.Errorf("%w: unknown note target %v", customerr.BadInput, target.TargetType)
+	}
+
+	var ownerID, days null.Int
+	query := `SELECT NULL::INTEGER, NULL::INTEGER FROM ` + targetColumns.table + ` WHERE id = $1;`
+	if targetColumns.table == "trips" {
+		query = `SELECT user_id, date_end - date_start + 1 FROM trips WHERE id = $1;`
+	}
+
+	err := tx.QueryRowxContext(ctx, query, target.TargetID).Scan(&ownerID, &days)
+	if errors.Is(err, sql.ErrNoRows) {
+		return customerr.NoteTargetNotFound
+	}
+	if err != nil {
+		return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ScanErr, Err: err})
+	}
+
+	if ownerID.Valid && int(ownerID.Int64) != userID {
+		return customerr.UserNotEntityOwner
+	}
+
+	if target.TargetType == models.NoteTargetTripDay && days.Valid && int64(target.Day) > days.Int64 {
+		return fmt.Errorf("%w: trip has only %v days", customerr.BadInput, days.Int64)
+	}
+
+	return nil
+}
+
 func (n noteRepo) Create(ctx context.Context, noteCreate models.NoteCreate) (int, error) {
-	tx, err := n.db.Beginx()
+	return n.CreateForTarget(ctx, models.NoteTargetCreate{
+		UserID:     noteCreate.UserID,
+		NoteTarget: models.NoteTarget{TargetType: models.NoteTargetPlace, TargetID: noteCreate.PlaceID},
+		Visibility: models.NoteVisibilityPrivate,
+		Properties: noteCreate.Properties,
+	})
+}
+
+func (n noteRepo) CreateForTarget(ctx context.Context, noteCreate models.NoteTargetCreate) (int, error) {
+	tx, err := n.db.BeginTxx(ctx, nil)
 	if err != nil {
 		return 0, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.TransactionErr, Err: err})
 	}
 
+	if err = checkNoteTarget(ctx, tx, noteCreate.UserID, noteCreate.NoteTarget); err != nil {
+		return 0, rollbackKeepErr(tx, err)
+	}
+
 	jsonProperties, err := json.Marshal(noteCreate.Properties)
 	if err != nil {
-		return 0, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.BindErr, Err: err})
+		return 0, rollbackWithErr(tx, customerr.BindErr, err)
 	}
 
-	createNoteQuery := `INSERT INTO notes (user_id, place_id, properties) VALUES ($1, $2, $3) RETURNING id;`
+	var tripDay null.Int
+	if noteCreate.TargetType == models.NoteTargetTripDay {
+		tripDay = null.IntFrom(int64(noteCreate.Day))
+	}
+
+	createNoteQuery := `INSERT INTO notes (user_id, target_type, ` + noteTargetColumns[noteCreate.TargetType].column + `, trip_day, properties, visibility)
+							VALUES ($1, $2, $3, $4, $5, $6) RETURNING id;`
 
 	var createdID int
-	err = tx.QueryRowxContext(ctx, createNoteQuery, noteCreate.UserID, noteCreate.PlaceID, jsonProperties).Scan(&createdID)
+	err = tx.QueryRowxContext(ctx, createNoteQuery, noteCreate.UserID, noteCreate.TargetType, noteCreate.TargetID, tripDay,
+		jsonProperties, noteCreate.Visibility).Scan(&createdID)
 	if err != nil {
-		if rbErr := tx.Rollback(); rbErr != nil {
-			return 0, customerr.ErrNormalizer(
-				customerr.ErrorPair{Message: customerr.ScanErr, Err: err},
-				customerr.ErrorPair{Message: customerr.RollbackErr, Err: rbErr},
-			)
-		}
-
-		return 0, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ScanErr, Err: err})
+		return 0, rollbackWithErr(tx, customerr.ScanErr, err)
 	}
 
 	if err = tx.Commit(); err != nil {
@@ -51,143 +182,315 @@ func (n noteRepo) Create(ctx context.Context, noteCreate models.NoteCreate) (int
 	return createdID, nil
 }
 
-func (n noteRepo) GetByIDs(ctx context.Context, userID int, placeID int) (models.Note, error) {
-	query := `SELECT n.id, n.user_id, n.place_id, n.properties, 
-       			CASE WHEN upc.place_id IS NOT NULL THEN true ELSE false END as joined
-			FROM notes n
-			LEFT JOIN users_place_checkin upc ON n.place_id = upc.place_id AND n.user_id = upc.user_id
-            WHERE n.user_id = $1 AND n.place_id = $2;`
+// GetByIDs последняя видимая viewerID заметка пользователя к месту, пустая заметка, если их нет
+func (n noteRepo) GetByIDs(ctx context.Context, viewerID int, userID int, placeID int) (models.Note, error) {
+	query := `SELECT ` + noteColumns + ` FROM notes n
+				WHERE n.user_id = $1 AND n.target_type = 'place' AND n.place_id = $2 AND ` + noteVisibleCond("$3") + `
+				ORDER BY n.created_at DESC, n.id DESC LIMIT 1;`
 
-	rows, err := n.db.QueryContext(ctx, query, userID, placeID)
+	note, err := scanNote(n.db.QueryRowContext(ctx, query, userID, placeID, viewerID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.Note{}, nil
+	}
 	if err != nil {
-		return models.Note{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ExecErr, Err: err})
+		return models.Note{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ScanErr, Err: err})
 	}
 
-	var note models.Note
-	for rows.Next() {
-		var propertiesRow []byte
-		err = rows.Scan(&note.ID, &note.UserID, &note.PlaceID, &propertiesRow, &note.IsCheckIn)
-		if err != nil {
-			return models.Note{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ScanErr, Err: err})
-		}
+	return note, nil
+}
 
-		err = json.Unmarshal(propertiesRow, &note.Properties)
-		if err != nil {
-			return models.Note{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.BindErr, Err: err})
-		}
+// GetByID невидимая viewerID заметка не отличается от отсутствующей
+func (n noteRepo) GetByID(ctx context.Context, viewerID int, noteID int) (models.Note, error) {
+	query := `SELECT ` + noteColumns + ` FROM notes n WHERE n.id = $1 AND ` + noteVisibleCond("$2") + `;`
 
+	note, err := scanNote(n.db.QueryRowContext(ctx, query, noteID, viewerID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.Note{}, customerr.NoteNotFound
 	}
-
-	err = rows.Err()
 	if err != nil {
-		return models.Note{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.RowsErr, Err: err})
+		return models.Note{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ScanErr, Err: err})
 	}
 
 	return note, nil
 }
 
-func (n noteRepo) GetByID(ctx context.Context, noteID int) (models.Note, error) {
-	query := `SELECT n.id, n.user_id, n.place_id, n.properties, 
-       			CASE WHEN upc.place_id IS NOT NULL THEN true ELSE false END as joined
-			FROM notes n
-			LEFT JOIN users_place_checkin upc ON n.place_id = upc.place_id AND n.user_id = upc.user_id
-            WHERE id = $1;`
+// GetByUser видимые viewerID заметки пользователя в порядке создания
+func (n noteRepo) GetByUser(ctx context.Context, viewerID int, userID int) ([]models.Note, error) {
+	query := `SELECT ` + noteColumns + ` FROM notes n WHERE n.user_id = $1 AND ` + noteVisibleCond("$2") + `
+				ORDER BY n.created_at, n.id;`
+
+	return n.getMany(ctx, query, userID, viewerID)
+}
+
+// GetByTarget видимые viewerID заметки пользователя к одной цели в порядке создания
+func (n noteRepo) GetByTarget(ctx context.Context, viewerID int, userID int, target models.NoteTarget) ([]models.Note, error) {
+	targetColumns, ok := noteTargetColumns[target.TargetType]
+	if !ok {
+		return []models.Note{}, fmt.Errorf("%w: unknown note target %v", customerr.BadInput, target.TargetType)
+	}
+
+	query := `SELECT ` + noteColumns + ` FROM notes n
+				WHERE n.user_id = $1 AND n.target_type = $2 AND n.` + targetColumns.column + ` = $3
+					AND ($2 <> 'trip_day' OR n.trip_day = $4) AND ` + noteVisibleCond("$5") + `
+				ORDER BY n.created_at, n.id;`
+
+	return n.getMany(ctx, query, userID, target.TargetType, target.TargetID, target.Day, viewerID)
+}
 
-	rows, err := n.db.QueryContext(ctx, query, noteID)
+// Update меняет последнюю заметку пользователя к месту, как раньше, когда заметка к месту была одна
+func (n noteRepo) Update(ctx context.Context, noteUpd models.NoteCreate) error {
+	jsonProperties, err := json.Marshal(noteUpd.Properties)
 	if err != nil {
-		return models.Note{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ExecErr, Err: err})
+		return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.BindErr, Err: err})
 	}
 
-	var note models.Note
-	for rows.Next() {
-		var propertiesRow []byte
-		err = rows.Scan(&note.ID, &note.UserID, &note.PlaceID, &propertiesRow, &note.IsCheckIn)
-		if err != nil {
-			return models.Note{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ScanErr, Err: err})
-		}
+	query := `UPDATE notes SET properties = $3, updated_at = current_timestamp
+				WHERE id = (SELECT id FROM notes WHERE user_id = $1 AND target_type = 'place' AND place_id = $2
+							ORDER BY created_at DESC, id DESC LIMIT 1);`
 
-		err = json.Unmarshal(propertiesRow, &note.Properties)
-		if err != nil {
-			return models.Note{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.BindErr, Err: err})
-		}
+	_, err = n.db.ExecContext(ctx, query, noteUpd.UserID, noteUpd.PlaceID, jsonProperties)
+	if err != nil {
+		return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ExecErr, Err: err})
+	}
+
+	return nil
+}
 
+// lockOwnNote блокирует заметку и проверяет, что её автор userID
+func lockOwnNote(ctx context.Context, tx *sqlx.Tx, noteID int, userID int) error {
+	var ownerID null.Int
+	err := tx.QueryRowxContext(ctx, `SELECT user_id FROM notes WHERE id = $1 FOR UPDATE;`, noteID).Scan(&ownerID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return customerr.NoteNotFound
+	}
+	if err != nil {
+		return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ScanErr, Err: err})
 	}
 
-	err = rows.Err()
+	if int(ownerID.Int64) != userID {
+		return customerr.UserNotEntityOwner
+	}
+
+	return nil
+}
+
+func (n noteRepo) UpdateByID(ctx context.Context, noteUpd models.NoteUpdate) error {
+	jsonProperties, err := json.Marshal(noteUpd.Properties)
 	if err != nil {
-		return models.Note{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.RowsErr, Err: err})
+		return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.BindErr, Err: err})
 	}
 
-	return note, nil
+	tx, err := n.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.TransactionErr, Err: err})
+	}
+
+	if err = lockOwnNote(ctx, tx, noteUpd.ID, noteUpd.UserID); err != nil {
+		return rollbackKeepErr(tx, err)
+	}
+
+	_, err = tx.ExecContext(ctx, `UPDATE notes SET properties = $2, visibility = COALESCE(NULLIF($3, ''), visibility),
+									updated_at = current_timestamp WHERE id = $1;`,
+		noteUpd.ID, jsonProperties, noteUpd.Visibility)
+	if err != nil {
+		return rollbackWithErr(tx, customerr.ExecErr, err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.CommitErr, Err: err})
+	}
+
+	return nil
 }
 
-func (n noteRepo) GetByUser(ctx context.Context, userID int) ([]models.Note, error) {
-	query := `SELECT n.id
-			FROM notes n
-            WHERE n.user_id = $1;`
+// Delete удаляет заметку вместе с прикреплёнными файлами и открытой жалобой фильтра
+func (n noteRepo) Delete(ctx context.Context, noteDelete models.NoteDelete) error {
+	tx, err := n.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.TransactionErr, Err: err})
+	}
 
-	rows, err := n.db.QueryContext(ctx, query, userID)
+	if err = lockOwnNote(ctx, tx, noteDelete.ID, noteDelete.UserID); err != nil {
+		return rollbackKeepErr(tx, err)
+	}
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM media_attachments WHERE entity_type = $1 AND entity_id = $2;`,
+		models.MediaEntityNote, noteDelete.ID)
 	if err != nil {
-		return []models.Note{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ExecErr, Err: err})
+		return rollbackWithErr(tx, customerr.ExecErr, err)
 	}
 
-	var noteIDs []int
-	var noteID int
-	for rows.Next() {
-		err = rows.Scan(&noteID)
+	_, err = tx.ExecContext(ctx, `DELETE FROM content_reports WHERE entity_type = $1 AND entity_id = $2 AND resolved_at IS NULL;`,
+		models.ContentReportNote, noteDelete.ID)
+	if err != nil {
+		return rollbackWithErr(tx, customerr.ExecErr, err)
+	}
+
+	if _, err = tx.ExecContext(ctx, `DELETE FROM notes WHERE id = $1;`, noteDelete.ID); err != nil {
+		return rollbackWithErr(tx, customerr.ExecErr, err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.CommitErr, Err: err})
+	}
+
+	return nil
+}
+
+// GetFeed публичные заметки к месту или маршруту, порядок по убыванию id
+func (n noteRepo) GetFeed(ctx context.Context, feed models.NotesFeed) (models.Page[models.Note], error) {
+	var cursor idCursor
+	if err := pagination.DecodeCursor(feed.Cursor, &cursor); err != nil {
+		return models.Page[models.Note]{}, err
+	}
+
+	targetColumns, ok := noteTargetColumns[feed.TargetType]
+	if !ok || feed.TargetType == models.NoteTargetTrip || feed.TargetType == models.NoteTargetTripDay {
+		return models.Page[models.Note]{}, fmt.Errorf("%w: no public feed for %v notes", customerr.BadInput, feed.TargetType)
+	}
+
+	feedCond := `n.target_type = $1 AND n.` + targetColumns.column + ` = $2 AND n.visibility = 'public'`
+	query := `SELECT ` + noteColumns + ` FROM notes n
+				WHERE ` + feedCond + ` AND ($3 = 0 OR n.id < $3)
+				ORDER BY n.id DESC
+				LIMIT $4;`
+
+	notes, err := n.getMany(ctx, query, feed.TargetType, feed.TargetID, cursor.ID, feed.PageSize+1)
+	if err != nil {
+		return models.Page[models.Note]{}, err
+	}
+
+	page := models.Page[models.Note]{Items: notes}
+	if len(notes) > feed.PageSize {
+		page.Items = notes[:feed.PageSize]
+		page.HasMore = true
+
+		nextCursor, err := pagination.EncodeCursor(idCursor{ID: page.Items[feed.PageSize-1].ID})
 		if err != nil {
-			return []models.Note{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ScanErr, Err: err})
+			return models.Page[models.Note]{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.BindErr, Err: err})
 		}
-
-		noteIDs = append(noteIDs, noteID)
+		page.NextCursor = nextCursor
 	}
 
-	var notes []models.Note
-	for _, noteID := range noteIDs {
-		note, err := n.GetByID(ctx, noteID)
+	if feed.WithTotal {
+		var total int
+		err = n.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM notes n WHERE `+feedCond+`;`, feed.TargetType, feed.TargetID).Scan(&total)
 		if err != nil {
-			return []models.Note{}, err
+			return models.Page[models.Note]{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ScanErr, Err: err})
 		}
 
-		notes = append(notes, note)
+		page.Total = &total
 	}
 
-	err = rows.Err()
+	return page, nil
+}
+
+// CreateShareToken ссылкой можно поделиться только заметкой с видимостью link или public
+func (n noteRepo) CreateShareToken(ctx context.Context, share models.NoteShare, token string) error {
+	tx, err := n.db.BeginTxx(ctx, nil)
 	if err != nil {
-		return []models.Note{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.RowsErr, Err: err})
+		return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.TransactionErr, Err: err})
 	}
 
-	return notes, nil
-}
+	if err = lockOwnNote(ctx, tx, share.ID, share.UserID); err != nil {
+		return rollbackKeepErr(tx, err)
+	}
 
-func (n noteRepo) Update(ctx context.Context, noteUpd models.NoteCreate) error {
-	tx, err := n.db.Beginx()
+	var visibility string
+	err = tx.QueryRowxContext(ctx, `SELECT visibility FROM notes WHERE id = $1;`, share.ID).Scan(&visibility)
 	if err != nil {
-		return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.TransactionErr, Err: err})
+		return rollbackWithErr(tx, customerr.ScanErr, err)
 	}
 
-	jsonProperties, err := json.Marshal(noteUpd.Properties)
+	if visibility != models.NoteVisibilityLink && visibility != models.NoteVisibilityPublic {
+		return rollbackKeepErr(tx, customerr.NoteNotShareable)
+	}
+
+	_, err = tx.ExecContext(ctx, `INSERT INTO note_share_tokens (token, note_id) VALUES ($1, $2);`, token, share.ID)
 	if err != nil {
-		return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.BindErr, Err: err})
+		return rollbackWithErr(tx, customerr.ExecErr, err)
 	}
 
-	query := `UPDATE notes SET properties = $3 WHERE user_id = $1 AND place_id = $2;`
+	if err = tx.Commit(); err != nil {
+		return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.CommitErr, Err: err})
+	}
+
+	return nil
+}
+
+// GetShareTokens действующие ссылки на заметку, видны только её автору
+func (n noteRepo) GetShareTokens(ctx context.Context, request models.NoteSharesRequest) ([]models.NoteShareToken, error) {
+	var ownerID int
+	err := n.db.QueryRowContext(ctx, `SELECT user_id FROM notes WHERE id = $1;`, request.ID).Scan(&ownerID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return []models.NoteShareToken{}, customerr.NoteNotFound
+	}
+	if err != nil {
+		return []models.NoteShareToken{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ScanErr, Err: err})
+	}
+
+	if ownerID != request.UserID {
+		return []models.NoteShareToken{}, customerr.UserNotEntityOwner
+	}
 
-	_, err = tx.ExecContext(ctx, query, noteUpd.UserID, noteUpd.PlaceID, jsonProperties)
+	rows, err := n.db.QueryContext(ctx, `SELECT token, note_id, created_at FROM note_share_tokens
+											WHERE note_id = $1 AND revoked_at IS NULL ORDER BY created_at;`, request.ID)
 	if err != nil {
-		if rbErr := tx.Rollback(); rbErr != nil {
-			return customerr.ErrNormalizer(
-				customerr.ErrorPair{Message: customerr.ScanErr, Err: err},
-				customerr.ErrorPair{Message: customerr.RollbackErr, Err: rbErr},
-			)
+		return []models.NoteShareToken{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ExecErr, Err: err})
+	}
+	defer rows.Close()
+
+	tokens := []models.NoteShareToken{}
+	for rows.Next() {
+		var token models.NoteShareToken
+		if err = rows.Scan(&token.Token, &token.NoteID, &token.CreatedAt); err != nil {
+			return []models.NoteShareToken{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ScanErr, Err: err})
 		}
 
-		return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ScanErr, Err: err})
+		tokens = append(tokens, token)
 	}
 
-	if err = tx.Commit(); err != nil {
-		return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.CommitErr, Err: err})
+	if err = rows.Err(); err != nil {
+		return []models.NoteShareToken{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.RowsErr, Err: err})
+	}
+
+	return tokens, nil
+}
+
+// RevokeShareToken чужая или уже отозванная ссылка не отличается от отсутствующей
+func (n noteRepo) RevokeShareToken(ctx context.Context, revoke models.NoteShareRevoke) error {
+	query := `UPDATE note_share_tokens SET revoked_at = current_timestamp
+				WHERE token = $1 AND revoked_at IS NULL AND note_id IN (SELECT id FROM notes WHERE user_id = $2);`
+
+	res, err := n.db.ExecContext(ctx, query, revoke.Token, revoke.UserID)
+	if err != nil {
+		return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ExecErr, Err: err})
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.RowsErr, Err: err})
+	}
+	if count == 0 {
+		return customerr.NoteShareTokenNotFound
 	}
 
 	return nil
 }
+
+// GetByShareToken ссылка перестаёт работать после отзыва или если автор сделал заметку личной
+func (n noteRepo) GetByShareToken(ctx context.Context, token string) (models.Note, error) {
+	query := `SELECT ` + noteColumns + ` FROM notes n
+				JOIN note_share_tokens t ON t.note_id = n.id
+				WHERE t.token = $1 AND t.revoked_at IS NULL AND n.visibility IN ('link', 'public');`
+
+	note, err := scanNote(n.db.QueryRowContext(ctx, query, token))
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.Note{}, customerr.NoteShareTokenNotFound
+	}
+	if err != nil {
+		return models.Note{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ScanErr, Err: err})
+	}
+
+	return note, nil
+}
@@ -2,11 +2,16 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"mth/internal/models"
 	"mth/pkg/config"
+	"mth/pkg/customerr"
 	"mth/pkg/database"
 	"testing"
+	"time"
 )
 
 func createCity(tx *sqlx.Tx) {
@@ -90,7 +95,7 @@ func initTesNoteData(db *sqlx.DB) {
 }
 
 func testCase1CheckIn1Not(repo Note) {
-	notes, err := repo.GetByUser(context.TODO(), 1)
+	notes, err := repo.GetByUser(context.TODO(), 1, 1)
 	if err != nil {
 		panic(fmt.Sprintf("error on get notes, err: %v", err))
 	}
@@ -123,3 +128,89 @@ func TestNoteRepo_GetByUser(t *testing.T) {
 
 	testNoteCases(noteRepo)
 }
+
+func TestNoteRepo_TripDayRange(t *testing.T) {
+	db := testDB(t)
+	repo := InitNoteRepo(db)
+
+	var userID, tripID int
+	if err := db.QueryRow(`INSERT INTO users (properties) VALUES (null) RETURNING id;`).Scan(&userID); err != nil {
+		t.Fatal(err)
+	}
+	err := db.QueryRow(`INSERT INTO trips (user_id, date_start, date_end) VALUES ($1, '2024-05-01', '2024-05-03') RETURNING id;`,
+		userID).Scan(&tripID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_, _ = db.Exec(`DELETE FROM notes WHERE trip_id = $1;`, tripID)
+		_, _ = db.Exec(`DELETE FROM trips WHERE id = $1;`, tripID)
+		_, _ = db.Exec(`DELETE FROM users WHERE id = $1;`, userID)
+	})
+
+	create := func(day int) error {
+		_, err := repo.CreateForTarget(context.TODO(), models.NoteTargetCreate{
+			UserID:     userID,
+			NoteTarget: models.NoteTarget{TargetType: models.NoteTargetTripDay, TargetID: tripID, Day: day},
+			Visibility: models.NoteVisibilityPrivate,
+		})
+		return err
+	}
+
+	if err = create(3); err != nil {
+		t.Errorf("last day of the trip: unexpected error %v", err)
+	}
+	if err = create(4); !errors.Is(err, customerr.BadInput) {
+		t.Errorf("day after the trip: expected BadInput, got %v", err)
+	}
+}
+
+// Встречные объявления к одному месту может создать кто угодно, доступ к заметкам даёт только принятый запрос
+func TestNoteRepo_CompanionsVisibility(t *testing.T) {
+	db := testDB(t)
+	repo := InitNoteRepo(db)
+	placeID, userIDs := createRatedPlace(t, db, 2)
+	authorID, viewerID := userIDs[0], userIDs[1]
+
+	listingIDs := make([]int, len(userIDs))
+	for i, userID := range userIDs {
+		err := db.QueryRow(`INSERT INTO companions_places (user_id, place_id, date_from, date_to) VALUES ($1, $2, $3, $3) RETURNING id;`,
+			userID, placeID, time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)).Scan(&listingIDs[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Cleanup(func() {
+		_, _ = db.Exec(`DELETE FROM notes WHERE place_id = $1;`, placeID)
+		_, _ = db.Exec(`DELETE FROM companion_requests WHERE listing_type = 'place' AND listing_id = ANY($1);`, pq.Array(listingIDs))
+		_, _ = db.Exec(`DELETE FROM companions_places WHERE id = ANY($1);`, pq.Array(listingIDs))
+	})
+
+	_, err := repo.CreateForTarget(context.TODO(), models.NoteTargetCreate{
+		UserID:     authorID,
+		NoteTarget: models.NoteTarget{TargetType: models.NoteTargetPlace, TargetID: placeID},
+		Visibility: models.NoteVisibilityCompanions,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if notes, err := repo.GetByUser(context.TODO(), viewerID, authorID); err != nil || len(notes) != 0 {
+		t.Errorf("overlapping listings must not open companions notes, got %v, %v", notes, err)
+	}
+
+	requests := InitCompanionRequestRepo(db)
+	requestID, err := requests.Create(context.TODO(),
+		models.CompanionRequestCreate{ListingType: models.CompanionListingPlace, ListingID: listingIDs[0], RequesterID: viewerID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = requests.SetStatus(context.TODO(), models.CompanionRequestAction{ID: requestID, UserID: authorID}, models.CompanionRequestAccepted)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if notes, err := repo.GetByUser(context.TODO(), viewerID, authorID); err != nil || len(notes) != 1 {
+		t.Errorf("accepted request must open companions notes, got %v, %v", notes, err)
+	}
+}
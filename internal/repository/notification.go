@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/guregu/null/v5"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"mth/internal/models"
+	"mth/pkg/customerr"
+	"mth/pkg/pagination"
+)
+
+type notificationRepo struct {
+	db *sqlx.DB
+}
+
+func InitNotificationRepo(db *sqlx.DB) Notification {
+	return notificationRepo{
+		db: db,
+	}
+}
+
+// notify создаёт уведомление в транзакции события, которое его вызвало
+func notify(ctx context.Context, tx *sqlx.Tx, userID int, kind string, payload map[string]interface{}) error {
+	jsonPayload, err := json.Marshal(payload)
+	if err != nil {
+		return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.BindErr, Err: err})
+	}
+
+	_, err = tx.ExecContext(ctx, `INSERT INTO notifications (user_id, kind, payload) VALUES ($1, $2, $3);`,
+		userID, kind, jsonPayload)
+	if err != nil {
+		return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ExecErr, Err: err})
+	}
+
+	return nil
+}
+
+// GetByUser новые уведомления первыми, request.PageSize уже ограничен сервисом
+func (n notificationRepo) GetByUser(ctx context.Context, request models.NotificationsRequest) (models.Page[models.Notification], error) {
+	var cursor idCursor
+	if err := pagination.DecodeCursor(request.Cursor, &cursor); err != nil {
+		return models.Page[models.Notification]{}, err
+	}
+
+	condition := `user_id = $1`
+	if request.UnreadOnly {
+		condition += ` AND read_at IS NULL`
+	}
+
+	query := `SELECT id, kind, payload, created_at, read_at FROM notifications
+				WHERE ` + condition + ` AND ($2 = 0 OR id < $2)
+				ORDER BY id DESC LIMIT $3;`
+
+	rows, err := n.db.QueryContext(ctx, query, request.UserID, cursor.ID, request.PageSize+1)
+	if err != nil {
+		return models.Page[models.Notification]{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ExecErr, Err: err})
+	}
+	defer rows.Close()
+
+	result := models.Page[models.Notification]{Items: []models.Notification{}}
+	for rows.Next() {
+		var notification models.Notification
+		var payloadRaw []byte
+		var readAt null.Time
+
+		err = rows.Scan(&notification.ID, &notification.Kind, &payloadRaw, &notification.CreatedAt, &readAt)
+		if err != nil {
+			return models.Page[models.Notification]{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ScanErr, Err: err})
+		}
+
+		if err = json.Unmarshal(payloadRaw, &notification.Payload); err != nil {
+			return models.Page[models.Notification]{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.BindErr, Err: err})
+		}
+
+		notification.Read = readAt.Valid
+		result.Items = append(result.Items, notification)
+	}
+
+	if err = rows.Err(); err != nil {
+		return models.Page[models.Notification]{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.RowsErr, Err: err})
+	}
+
+	if len(result.Items) > request.PageSize {
+		result.Items = result.Items[:request.PageSize]
+		result.HasMore = true
+
+		result.NextCursor, err = pagination.EncodeCursor(idCursor{ID: result.Items[request.PageSize-1].ID})
+		if err != nil {
+			return models.Page[models.Notification]{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.BindErr, Err: err})
+		}
+	}
+
+	if request.WithTotal {
+		var total int
+		countQuery := `SELECT COUNT(*) FROM notifications WHERE ` + condition + `;`
+		if err = n.db.QueryRowContext(ctx, countQuery, request.UserID).Scan(&total); err != nil {
+			return models.Page[models.Notification]{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ScanErr, Err: err})
+		}
+
+		result.Total = &total
+	}
+
+	return result, nil
+}
+
+// MarkRead возвращает число уведомлений, которые стали прочитанными
+func (n notificationRepo) MarkRead(ctx context.Context, read models.NotificationsRead) (int, error) {
+	query := `UPDATE notifications SET read_at = current_timestamp
+				WHERE user_id = $1 AND read_at IS NULL AND (cardinality($2::INTEGER[]) = 0 OR id = ANY($2));`
+
+	res, err := n.db.ExecContext(ctx, query, read.UserID, pq.Array(read.IDs))
+	if err != nil {
+		return 0, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ExecErr, Err: err})
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return 0, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.RowsErr, Err: err})
+	}
+
+	return int(count), nil
+}
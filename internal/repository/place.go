@@ -12,8 +12,9 @@ import (
 	"mth/internal/models"
 	"mth/pkg/config"
 	"mth/pkg/customerr"
+	"mth/pkg/pagination"
 
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 )
 
 type placeRepo struct {
@@ -37,11 +38,15 @@ func (p placeRepo) Create(ctx context.Context, placeCreate models.PlaceCreate) (
 		return 0, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.BindErr, Err: err})
 	}
 
-	createPlaceQuery := `INSERT INTO places (city_id, district_id, properties, name, variety) VALUES ($1, $2, $3, $4, $5) RETURNING id;`
+	createPlaceQuery := `INSERT INTO places (city_id, district_id, properties, name, variety, latitude, longitude)
+							VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id;`
+
+	latitude, longitude := pointToNull(placeCreate.Coordinates)
 
 	var createdID int
-	err = tx.QueryRowxContext(ctx, createPlaceQuery, placeCreate.CityID, placeCreate.DistrictID, jsonProperties,
-		placeCreate.Name, placeCreate.Variety).Scan(&createdID)
+	err = tx.QueryRowxContext(ctx, createPlaceQuery, placeCreate.CityID,
+		null.NewInt(int64(placeCreate.DistrictID), placeCreate.DistrictID != 0), jsonProperties,
+		placeCreate.Name, placeCreate.Variety, latitude, longitude).Scan(&createdID)
 	if err != nil {
 		if rbErr := tx.Rollback(); rbErr != nil {
 			return 0, customerr.ErrNormalizer(
@@ -75,6 +80,22 @@ func (p placeRepo) Create(ctx context.Context, placeCreate models.PlaceCreate) (
 	return createdID, nil
 }
 
+func pointToNull(point *models.Point) (null.Float, null.Float) {
+	if point == nil {
+		return null.Float{}, null.Float{}
+	}
+
+	return null.FloatFrom(point.Lat), null.FloatFrom(point.Lon)
+}
+
+func pointFromNull(latitude, longitude null.Float) *models.Point {
+	if !latitude.Valid || !longitude.Valid {
+		return nil
+	}
+
+	return &models.Point{Lat: latitude.Float64, Lon: longitude.Float64}
+}
+
 func (p placeRepo) getPlaceTags(ctx context.Context, place *models.Place) error {
 	if place == nil {
 		return errors.New("you passing nil pointer to the getPlaceTags!")
@@ -107,71 +128,223 @@ func (p placeRepo) getPlaceTags(ctx context.Context, place *models.Place) error
 	return nil
 }
 
-// GetAllWithFilter todo: implement tagIDs and pagination
-func (p placeRepo) GetAllWithFilter(ctx context.Context, districtID int, cityID int, tagIDs []int, page int, name string, variety string) ([]models.Place, error) {
+// getPlacesTags теги всех мест страницы одним запросом
+func (p placeRepo) getPlacesTags(ctx context.Context, places []models.Place) error {
+	if len(places) == 0 {
+		return nil
+	}
+
+	index := make(map[int]int, len(places))
+	placeIDs := make([]int64, 0, len(places))
+	for i, place := range places {
+		index[place.ID] = i
+		placeIDs = append(placeIDs, int64(place.ID))
+	}
+
+	query := `SELECT pt.place_id, tags.id, tags.name FROM places_tags pt
+				JOIN tags ON tags.id = pt.tag_id
+				WHERE pt.place_id = ANY($1)
+				ORDER BY pt.place_id, tags.id;`
+
+	rows, err := p.db.QueryContext(ctx, query, pq.Array(placeIDs))
+	if err != nil {
+		return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ExecErr, Err: err})
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var placeID int
+		var tag models.Tag
+
+		if err = rows.Scan(&placeID, &tag.ID, &tag.Name); err != nil {
+			return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ScanErr, Err: err})
+		}
+
+		places[index[placeID]].Tags = append(places[index[placeID]].Tags, tag)
+	}
+
+	if err = rows.Err(); err != nil {
+		return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.RowsErr, Err: err})
+	}
+
+	return nil
+}
+
+// placeCursor ключи последнего места страницы, Sort должен совпадать с сортировкой запроса
+type placeCursor struct {
+	ID         int     `json:"id"`
+	Average    float32 `json:"a,omitempty"`
+	Count      int     `json:"c,omitempty"`
+	Popularity float64 `json:"p,omitempty"`
+	Generation int64   `json:"g,omitempty"`
+	Sort       string  `json:"s,omitempty"`
+}
+
+func applyPlaceFilters(queryBuilder squirrel.SelectBuilder, filters models.PlaceFilters) squirrel.SelectBuilder {
+	queryBuilder = queryBuilder.Where(squirrel.Eq{"places.archived": false})
+
+	for _, group := range filters.TagGroups {
+		queryBuilder = queryBuilder.Where(
+			"EXISTS (SELECT 1 FROM places_tags pt WHERE pt.place_id = places.id AND pt.tag_id = ANY(?))", pq.Array(group))
+	}
+	if filters.DistrictID != 0 {
+		queryBuilder = queryBuilder.Where(squirrel.Eq{"district_id": filters.DistrictID})
+	}
+	if filters.CityID != 0 {
+		queryBuilder = queryBuilder.Where(squirrel.Eq{"city_id": filters.CityID})
+	}
+	if filters.Name != "" {
+		queryBuilder = queryBuilder.Where(squirrel.Like{"places.name": "%" + filters.Name + "%"})
+	}
+	if filters.Variety != "" {
+		queryBuilder = queryBuilder.Where(squirrel.Eq{"places.variety": filters.Variety})
+	}
+	if filters.MinRating > 0 {
+		queryBuilder = queryBuilder.Where(squirrel.GtOrEq{"places.reviews_average": filters.MinRating})
+	}
+	if filters.WithCoordinates {
+		queryBuilder = queryBuilder.Where(squirrel.NotEq{"places.latitude": nil, "places.longitude": nil})
+	}
+
+	return queryBuilder
+}
+
+// applyPlaceKeyset сортировка и условие "после курсора" для выбранного порядка, id всегда последний ключ.
+// popularity - колонка популярности поколения курсора
+func applyPlaceKeyset(queryBuilder squirrel.SelectBuilder, sortBy string, cursor *placeCursor, popularity string) squirrel.SelectBuilder {
+	switch sortBy {
+	case models.PlaceSortByRating:
+		if cursor != nil {
+			queryBuilder = queryBuilder.Where(squirrel.Or{
+				squirrel.Lt{"places.reviews_average": cursor.Average},
+				squirrel.And{squirrel.Eq{"places.reviews_average": cursor.Average}, squirrel.Lt{"places.reviews_count": cursor.Count}},
+				squirrel.And{squirrel.Eq{"places.reviews_average": cursor.Average, "places.reviews_count": cursor.Count}, squirrel.Gt{"places.id": cursor.ID}},
+			})
+		}
+		return queryBuilder.OrderBy("places.reviews_average DESC", "places.reviews_count DESC", "places.id")
+	case models.PlaceSortByReviewsCount:
+		if cursor != nil {
+			queryBuilder = queryBuilder.Where(squirrel.Or{
+				squirrel.Lt{"places.reviews_count": cursor.Count},
+				squirrel.And{squirrel.Eq{"places.reviews_count": cursor.Count}, squirrel.Lt{"places.reviews_average": cursor.Average}},
+				squirrel.And{squirrel.Eq{"places.reviews_count": cursor.Count, "places.reviews_average": cursor.Average}, squirrel.Gt{"places.id": cursor.ID}},
+			})
+		}
+		return queryBuilder.OrderBy("places.reviews_count DESC", "places.reviews_average DESC", "places.id")
+	case models.PlaceSortByPopularity:
+		if cursor != nil {
+			queryBuilder = queryBuilder.Where(squirrel.Or{
+				squirrel.Lt{popularity: cursor.Popularity},
+				squirrel.And{squirrel.Eq{popularity: cursor.Popularity}, squirrel.Gt{"places.id": cursor.ID}},
+			})
+		}
+		return queryBuilder.OrderBy(popularity+" DESC", "places.id")
+	default:
+		if cursor != nil {
+			queryBuilder = queryBuilder.Where(squirrel.Gt{"places.id": cursor.ID})
+		}
+		return queryBuilder.OrderBy("places.id")
+	}
+}
+
+func (p placeRepo) countWithFilter(ctx context.Context, filters models.PlaceFilters) (int, error) {
 	psql := squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)
-	queryBuilder := psql.Select("places.id", "city_id", "district_id", "properties", "places.name", "places.variety").
-		From("places")
+	filtered := applyPlaceFilters(psql.Select("places.id").From("places"), filters)
 
-	if len(tagIDs) > 0 {
-		queryBuilder = queryBuilder.
-			Join("places_tags ON places.id = places_tags.place_id").
-			Join("tags ON places_tags.tag_id = tags.id").
-			Where(squirrel.Eq{"places_tags.tag_id": tagIDs}).
-			GroupBy("places.id").
-			Having("COUNT(DISTINCT places_tags.tag_id) >= ?", len(tagIDs))
+	query, args, err := psql.Select("COUNT(*)").FromSelect(filtered, "filtered").ToSql()
+	if err != nil {
+		return 0, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.QueryBuild, Err: err})
 	}
-	if districtID != 0 {
-		queryBuilder = queryBuilder.Where(squirrel.Eq{"district_id": districtID})
+
+	var total int
+	if err = p.db.QueryRowContext(ctx, query, args...).Scan(&total); err != nil {
+		return 0, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ScanErr, Err: err})
 	}
-	if cityID != 0 {
-		queryBuilder = queryBuilder.Where(squirrel.Eq{"city_id": cityID})
+
+	return total, nil
+}
+
+// GetAllWithFilter постраничная выдача по курсору, filters.Limit уже ограничен сервисом
+func (p placeRepo) GetAllWithFilter(ctx context.Context, filters models.PlaceFilters) (models.Page[models.Place], error) {
+	var cursor *placeCursor
+	if filters.Cursor != "" {
+		cursor = &placeCursor{}
+		if err := pagination.DecodeCursor(filters.Cursor, cursor); err != nil {
+			return models.Page[models.Place]{}, err
+		}
+		if cursor.Sort != filters.SortBy {
+			return models.Page[models.Place]{}, customerr.InvalidCursor
+		}
 	}
-	if name != "" {
-		queryBuilder = queryBuilder.Where(squirrel.Like{"places.name": "%" + name + "%"})
+
+	limit := filters.Limit
+	if limit <= 0 {
+		limit = viper.GetInt(config.PlacesOnPage)
 	}
-	if variety != "" {
-		queryBuilder = queryBuilder.Where(squirrel.Eq{"places.variety": variety})
+
+	// продолжение выдачи по популярности идёт по значениям поколения, в котором она началась
+	popularity, generation := "popularity", int64(0)
+	continued := filters.SortBy == models.PlaceSortByPopularity && cursor != nil
+	if continued {
+		var err error
+		if popularity, generation, err = popularitySnapshot(ctx, p.db, cursor.Generation); err != nil {
+			return models.Page[models.Place]{}, err
+		}
 	}
 
-	// OFFSET с 0 нада бээмс
-	queryBuilder = queryBuilder.Limit(uint64(viper.GetInt(config.PlacesOnPage))).Offset(uint64(viper.GetInt(config.PlacesOnPage) * page))
+	psql := squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)
+	queryBuilder := psql.Select("places.id", "city_id", "COALESCE(district_id, 0)", "properties", "places.name", "places.variety", "places.archived",
+		"places.reviews_average", "places.reviews_count", "places.reviews_histogram", "places.reviews_criteria",
+		"places.latitude", "places.longitude", "places.popularity", "places."+popularity, popularityGenerationExpr).
+		From("places")
+	queryBuilder = applyPlaceFilters(queryBuilder, filters)
+	queryBuilder = applyPlaceKeyset(queryBuilder, filters.SortBy, cursor, "places."+popularity)
+
+	// одна лишняя запись показывает, есть ли следующая страница
+	queryBuilder = queryBuilder.Limit(uint64(limit + 1))
 
 	query, args, err := queryBuilder.ToSql()
 	if err != nil {
-		return []models.Place{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.QueryBuild, Err: err})
+		return models.Page[models.Place]{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.QueryBuild, Err: err})
 	}
 
 	rows, err := p.db.QueryContext(ctx, query, args...)
 	if err != nil {
-		return []models.Place{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ExecErr, Err: err})
+		return models.Page[models.Place]{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ExecErr, Err: err})
 	}
+	defer rows.Close()
 
-	var places []models.Place
+	places := []models.Place{}
+	var sortPopularity []float64
+	var rowGeneration int64
 
 	for rows.Next() {
 		var place models.Place
+		var placePopularity float64
 		var propertiesRaw []byte
-
-		err = rows.Scan(&place.ID, &place.CityID, &place.DistrictID, &propertiesRaw, &place.Name, &place.Variety)
+		var reviewsAverage float32
+		var reviewsCount int
+		var reviewsHistogram []int64
+		var reviewsCriteria []byte
+		var latitude, longitude null.Float
+
+		err = rows.Scan(&place.ID, &place.CityID, &place.DistrictID, &propertiesRaw, &place.Name, &place.Variety, &place.Archived,
+			&reviewsAverage, &reviewsCount, pq.Array(&reviewsHistogram), &reviewsCriteria, &latitude, &longitude, &place.Popularity,
+			&placePopularity, &rowGeneration)
 		if err != nil {
-			return []models.Place{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ScanErr, Err: err})
+			return models.Page[models.Place]{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ScanErr, Err: err})
 		}
+		sortPopularity = append(sortPopularity, placePopularity)
 
-		if len(places) > 0 {
-			if places[len(places)-1].ID == place.ID {
-				continue
-			}
+		place.Rating = models.RatingFromRaw(reviewsAverage, reviewsCount, reviewsHistogram)
+		if place.Rating.Criteria, err = parseCriteriaRating(reviewsCriteria); err != nil {
+			return models.Page[models.Place]{}, err
 		}
+		place.Coordinates = pointFromNull(latitude, longitude)
 
 		err = json.Unmarshal(propertiesRaw, &place.Properties)
 		if err != nil {
-			return []models.Place{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.BindErr, Err: err})
-		}
-
-		err = p.getPlaceTags(ctx, &place)
-		if err != nil {
-			return []models.Place{}, fmt.Errorf("get place tags, err: %v", err)
+			return models.Page[models.Place]{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.BindErr, Err: err})
 		}
 
 		places = append(places, place)
@@ -179,14 +352,60 @@ func (p placeRepo) GetAllWithFilter(ctx context.Context, districtID int, cityID
 
 	err = rows.Err()
 	if err != nil {
-		return []models.Place{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.RowsErr, Err: err})
+		return models.Page[models.Place]{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.RowsErr, Err: err})
+	}
+
+	// пересчёт успел закоммитить между выбором колонки и запросом
+	if continued && len(places) > 0 && rowGeneration != generation {
+		return models.Page[models.Place]{}, customerr.InvalidCursor
+	}
+
+	page := models.Page[models.Place]{Items: places}
+
+	if len(places) > limit {
+		page.Items = places[:limit]
+		page.HasMore = true
+
+		last := page.Items[limit-1]
+		next := placeCursor{
+			ID:         last.ID,
+			Average:    last.Rating.Average,
+			Count:      last.Rating.Count,
+			Popularity: sortPopularity[limit-1],
+			Sort:       filters.SortBy,
+		}
+		if filters.SortBy == models.PlaceSortByPopularity {
+			next.Generation = rowGeneration
+			if continued {
+				next.Generation = cursor.Generation
+			}
+		}
+
+		page.NextCursor, err = pagination.EncodeCursor(next)
+		if err != nil {
+			return models.Page[models.Place]{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.BindErr, Err: err})
+		}
+	}
+
+	if err = p.getPlacesTags(ctx, page.Items); err != nil {
+		return models.Page[models.Place]{}, fmt.Errorf("get place tags, err: %v", err)
 	}
 
-	return places, nil
+	if filters.WithTotal {
+		total, err := p.countWithFilter(ctx, filters)
+		if err != nil {
+			return models.Page[models.Place]{}, err
+		}
+
+		page.Total = &total
+	}
+
+	return page, nil
 }
 
 func (p placeRepo) GetByID(ctx context.Context, placeID int) (models.Place, error) {
-	query := `SELECT places.id, city_id, district_id, properties, places.name, variety, t.id, t.name FROM places
+	query := `SELECT places.id, city_id, COALESCE(district_id, 0), properties, places.name, variety, archived,
+       			reviews_average, reviews_count, reviews_histogram, reviews_criteria, latitude, longitude, popularity, t.id, t.name FROM places
 				LEFT JOIN places_tags pt on places.id = pt.place_id
 				LEFT JOIN tags t on pt.tag_id = t.id
 				WHERE places.id = $1;`
@@ -200,12 +419,25 @@ func (p placeRepo) GetByID(ctx context.Context, placeID int) (models.Place, erro
 	var propertiesRow []byte
 	var tagID null.Int
 	var tagName null.String
+	var reviewsAverage float32
+	var reviewsCount int
+	var reviewsHistogram []int64
+	var reviewsCriteria []byte
+	var latitude, longitude null.Float
 	for rows.Next() {
-		err = rows.Scan(&place.ID, &place.CityID, &place.DistrictID, &propertiesRow, &place.Name, &place.Variety, &tagID, &tagName)
+		err = rows.Scan(&place.ID, &place.CityID, &place.DistrictID, &propertiesRow, &place.Name, &place.Variety, &place.Archived,
+			&reviewsAverage, &reviewsCount, pq.Array(&reviewsHistogram), &reviewsCriteria, &latitude, &longitude, &place.Popularity,
+			&tagID, &tagName)
 		if err != nil {
 			return models.Place{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ScanErr, Err: err})
 		}
 
+		place.Rating = models.RatingFromRaw(reviewsAverage, reviewsCount, reviewsHistogram)
+		if place.Rating.Criteria, err = parseCriteriaRating(reviewsCriteria); err != nil {
+			return models.Place{}, err
+		}
+		place.Coordinates = pointFromNull(latitude, longitude)
+
 		err = json.Unmarshal(propertiesRow, &place.Properties)
 		if err != nil {
 			return models.Place{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.BindErr, Err: err})
@@ -226,3 +458,286 @@ func (p placeRepo) GetByID(ctx context.Context, placeID int) (models.Place, erro
 
 	return place, nil
 }
+
+func (p placeRepo) Update(ctx context.Context, placeUpd models.PlaceUpdate) error {
+	jsonProperties, err := json.Marshal(placeUpd.Properties)
+	if err != nil {
+		return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.BindErr, Err: err})
+	}
+
+	tx, err := p.db.Beginx()
+	if err != nil {
+		return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.TransactionErr, Err: err})
+	}
+
+	if placeUpd.DistrictID != 0 {
+		districtCityQuery := `SELECT city_id FROM district WHERE id = $1;`
+
+		var districtCityID int
+		err = tx.QueryRowxContext(ctx, districtCityQuery, placeUpd.DistrictID).Scan(&districtCityID)
+		if err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				return customerr.ErrNormalizer(
+					customerr.ErrorPair{Message: customerr.ScanErr, Err: err},
+					customerr.ErrorPair{Message: customerr.RollbackErr, Err: rbErr},
+				)
+			}
+
+			return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ScanErr, Err: err})
+		}
+
+		if districtCityID != placeUpd.CityID {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.RollbackErr, Err: rbErr})
+			}
+
+			return customerr.DistrictNotInCity
+		}
+	}
+
+	updatePlaceQuery := `UPDATE places SET city_id = $2, district_id = $3, properties = $4, name = $5, variety = $6,
+                  			latitude = $7, longitude = $8 WHERE id = $1;`
+
+	latitude, longitude := pointToNull(placeUpd.Coordinates)
+
+	res, err := tx.ExecContext(ctx, updatePlaceQuery, placeUpd.ID, placeUpd.CityID,
+		null.NewInt(int64(placeUpd.DistrictID), placeUpd.DistrictID != 0), jsonProperties,
+		placeUpd.Name, placeUpd.Variety, latitude, longitude)
+	if err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return customerr.ErrNormalizer(
+				customerr.ErrorPair{Message: customerr.ExecErr, Err: err},
+				customerr.ErrorPair{Message: customerr.RollbackErr, Err: rbErr},
+			)
+		}
+
+		return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ExecErr, Err: err})
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return customerr.ErrNormalizer(
+				customerr.ErrorPair{Message: customerr.RowsErr, Err: err},
+				customerr.ErrorPair{Message: customerr.RollbackErr, Err: rbErr},
+			)
+		}
+
+		return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.RowsErr, Err: err})
+	}
+	if count == 0 {
+		return rollbackKeepErr(tx, customerr.PlaceNotFound)
+	}
+	if count != 1 {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return customerr.ErrNormalizer(
+				customerr.ErrorPair{Message: customerr.RowsErr, Err: fmt.Errorf(customerr.CountErr, count)},
+				customerr.ErrorPair{Message: customerr.RollbackErr, Err: rbErr},
+			)
+		}
+
+		return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.RowsErr, Err: fmt.Errorf(customerr.CountErr, count)})
+	}
+
+	deletePlaceTagsQuery := `DELETE FROM places_tags WHERE place_id = $1;`
+
+	_, err = tx.ExecContext(ctx, deletePlaceTagsQuery, placeUpd.ID)
+	if err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return customerr.ErrNormalizer(
+				customerr.ErrorPair{Message: customerr.ExecErr, Err: err},
+				customerr.ErrorPair{Message: customerr.RollbackErr, Err: rbErr},
+			)
+		}
+
+		return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ExecErr, Err: err})
+	}
+
+	createPlaceTagQuery := `INSERT INTO places_tags (place_id, tag_id) VALUES ($1, $2) ON CONFLICT DO NOTHING;`
+	for _, tagID := range placeUpd.TagIDs {
+		_, err = tx.ExecContext(ctx, createPlaceTagQuery, placeUpd.ID, tagID)
+		if err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				return customerr.ErrNormalizer(
+					customerr.ErrorPair{Message: customerr.ExecErr, Err: err},
+					customerr.ErrorPair{Message: customerr.RollbackErr, Err: rbErr},
+				)
+			}
+
+			return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ExecErr, Err: err})
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.CommitErr, Err: err})
+	}
+
+	return nil
+}
+
+// Delete удаляет место только если у него нет пользовательской истории, иначе возвращает customerr.PlaceHasHistory.
+// Места из маршрутов удаляются каскадом, позиции оставшихся мест маршрута сдвигаются, открепившиеся медиа собирает очистка
+func (p placeRepo) Delete(ctx context.Context, placeID int) error {
+	tx, err := p.db.Beginx()
+	if err != nil {
+		return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.TransactionErr, Err: err})
+	}
+
+	historyQuery := `SELECT
+						(SELECT COUNT(*) FROM places_reviews WHERE place_id = $1) +
+						(SELECT COUNT(*) FROM notes WHERE place_id = $1) +
+						(SELECT COUNT(*) FROM users_place_checkin WHERE place_id = $1) +
+						(SELECT COUNT(*) FROM users_favourite_places WHERE place_id = $1) +
+						(SELECT COUNT(*) FROM trip_places WHERE place_id = $1) +
+						(SELECT COUNT(*) FROM companions_places WHERE place_id = $1);`
+
+	var historyCount int
+	err = tx.QueryRowxContext(ctx, historyQuery, placeID).Scan(&historyCount)
+	if err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return customerr.ErrNormalizer(
+				customerr.ErrorPair{Message: customerr.ScanErr, Err: err},
+				customerr.ErrorPair{Message: customerr.RollbackErr, Err: rbErr},
+			)
+		}
+
+		return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ScanErr, Err: err})
+	}
+
+	if historyCount > 0 {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.RollbackErr, Err: rbErr})
+		}
+
+		return customerr.PlaceHasHistory
+	}
+
+	shiftRoutePositionsQuery := `UPDATE routes_places rp SET position = rp.position - 1
+									FROM routes_places deleted
+									WHERE deleted.place_id = $1 AND rp.route_id = deleted.route_id AND rp.position > deleted.position;`
+
+	_, err = tx.ExecContext(ctx, shiftRoutePositionsQuery, placeID)
+	if err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return customerr.ErrNormalizer(
+				customerr.ErrorPair{Message: customerr.ExecErr, Err: err},
+				customerr.ErrorPair{Message: customerr.RollbackErr, Err: rbErr},
+			)
+		}
+
+		return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ExecErr, Err: err})
+	}
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM translations WHERE entity_type = 'place' AND entity_id = $1;`, placeID)
+	if err != nil {
+		return rollbackWithErr(tx, customerr.ExecErr, err)
+	}
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM entity_managers WHERE entity_type = 'place' AND entity_id = $1;`, placeID)
+	if err != nil {
+		return rollbackWithErr(tx, customerr.ExecErr, err)
+	}
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM media_attachments WHERE entity_type = $1 AND entity_id = $2;`,
+		models.MediaEntityPlace, placeID)
+	if err != nil {
+		return rollbackWithErr(tx, customerr.ExecErr, err)
+	}
+
+	deletePlaceQuery := `DELETE FROM places WHERE id = $1;`
+
+	res, err := tx.ExecContext(ctx, deletePlaceQuery, placeID)
+	if err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return customerr.ErrNormalizer(
+				customerr.ErrorPair{Message: customerr.ExecErr, Err: err},
+				customerr.ErrorPair{Message: customerr.RollbackErr, Err: rbErr},
+			)
+		}
+
+		return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ExecErr, Err: err})
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return customerr.ErrNormalizer(
+				customerr.ErrorPair{Message: customerr.RowsErr, Err: err},
+				customerr.ErrorPair{Message: customerr.RollbackErr, Err: rbErr},
+			)
+		}
+
+		return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.RowsErr, Err: err})
+	}
+	if count == 0 {
+		return rollbackKeepErr(tx, customerr.PlaceNotFound)
+	}
+	if count != 1 {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return customerr.ErrNormalizer(
+				customerr.ErrorPair{Message: customerr.RowsErr, Err: fmt.Errorf(customerr.CountErr, count)},
+				customerr.ErrorPair{Message: customerr.RollbackErr, Err: rbErr},
+			)
+		}
+
+		return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.RowsErr, Err: fmt.Errorf(customerr.CountErr, count)})
+	}
+
+	if err = tx.Commit(); err != nil {
+		return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.CommitErr, Err: err})
+	}
+
+	return nil
+}
+
+func (p placeRepo) SetArchived(ctx context.Context, placeID int, archived bool) error {
+	tx, err := p.db.Beginx()
+	if err != nil {
+		return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.TransactionErr, Err: err})
+	}
+
+	query := `UPDATE places SET archived = $2 WHERE id = $1;`
+
+	res, err := tx.ExecContext(ctx, query, placeID, archived)
+	if err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return customerr.ErrNormalizer(
+				customerr.ErrorPair{Message: customerr.ExecErr, Err: err},
+				customerr.ErrorPair{Message: customerr.RollbackErr, Err: rbErr},
+			)
+		}
+
+		return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ExecErr, Err: err})
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return customerr.ErrNormalizer(
+				customerr.ErrorPair{Message: customerr.RowsErr, Err: err},
+				customerr.ErrorPair{Message: customerr.RollbackErr, Err: rbErr},
+			)
+		}
+
+		return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.RowsErr, Err: err})
+	}
+	if count == 0 {
+		return rollbackKeepErr(tx, customerr.PlaceNotFound)
+	}
+	if count != 1 {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return customerr.ErrNormalizer(
+				customerr.ErrorPair{Message: customerr.RowsErr, Err: fmt.Errorf(customerr.CountErr, count)},
+				customerr.ErrorPair{Message: customerr.RollbackErr, Err: rbErr},
+			)
+		}
+
+		return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.RowsErr, Err: fmt.Errorf(customerr.CountErr, count)})
+	}
+
+	if err = tx.Commit(); err != nil {
+		return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.CommitErr, Err: err})
+	}
+
+	return nil
+}
@@ -1,5 +1,13 @@
 package repository
 
+import (
+	"context"
+	"errors"
+	"mth/internal/models"
+	"mth/pkg/customerr"
+	"testing"
+)
+
 //
 //import (
 //	"context"
@@ -249,3 +257,63 @@ package repository
 //
 //	testCases(placeRepo)
 //}
+
+func TestPlaceRepo_MissingPlace(t *testing.T) {
+	db := testDB(t)
+	repo := InitPlaceRepo(db)
+
+	update := models.PlaceUpdate{ID: -1, PlaceBase: models.PlaceBase{Name: "нет такого места", Variety: "cafe"}}
+	if err := repo.Update(context.TODO(), update); !errors.Is(err, customerr.PlaceNotFound) {
+		t.Errorf("update: expected PlaceNotFound, got %v", err)
+	}
+	if err := repo.SetArchived(context.TODO(), -1, true); !errors.Is(err, customerr.PlaceNotFound) {
+		t.Errorf("archive: expected PlaceNotFound, got %v", err)
+	}
+	if err := repo.Delete(context.TODO(), -1); !errors.Is(err, customerr.PlaceNotFound) {
+		t.Errorf("delete: expected PlaceNotFound, got %v", err)
+	}
+}
+
+func TestPlaceRepo_UpdateArchiveDelete(t *testing.T) {
+	db := testDB(t)
+	repo := InitPlaceRepo(db)
+	placeID, userIDs := createRatedPlace(t, db, 1)
+
+	place, err := repo.GetByID(context.TODO(), placeID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	name := place.Name + " обновлено"
+	update := models.PlaceUpdate{ID: placeID, PlaceBase: models.PlaceBase{CityID: place.CityID, Name: name, Variety: place.Variety}}
+	if err = repo.Update(context.TODO(), update); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = repo.SetArchived(context.TODO(), placeID, true); err != nil {
+		t.Fatal(err)
+	}
+	if place, err = repo.GetByID(context.TODO(), placeID); err != nil || place.Name != name || !place.Archived {
+		t.Errorf("expected renamed archived place, got %+v, %v", place, err)
+	}
+
+	page, err := repo.GetAllWithFilter(context.TODO(), models.PlaceFilters{Name: name, Limit: 10})
+	if err != nil || len(page.Items) != 0 {
+		t.Errorf("archived place must be hidden from search, got %v, %v", page.Items, err)
+	}
+
+	if _, err = db.Exec(`INSERT INTO users_favourite_places (user_id, place_id, timestamp) VALUES ($1, $2, now());`,
+		userIDs[0], placeID); err != nil {
+		t.Fatal(err)
+	}
+	if err = repo.Delete(context.TODO(), placeID); !errors.Is(err, customerr.PlaceHasHistory) {
+		t.Errorf("place with favourites: expected PlaceHasHistory, got %v", err)
+	}
+
+	if _, err = db.Exec(`DELETE FROM users_favourite_places WHERE place_id = $1;`, placeID); err != nil {
+		t.Fatal(err)
+	}
+	if err = repo.Delete(context.TODO(), placeID); err != nil {
+		t.Errorf("place without history must be deleted, got %v", err)
+	}
+}
@@ -0,0 +1,147 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"github.com/jmoiron/sqlx"
+	"mth/internal/models"
+	"mth/pkg/customerr"
+)
+
+type popularityRepo struct {
+	db *sqlx.DB
+}
+
+func InitPopularityRepo(db *sqlx.DB) Popularity {
+	return popularityRepo{
+		db: db,
+	}
+}
+
+// decayExpr вклад события с весом weight и временем ts, $1 период полураспада в секундах.
+// Показатель ограничен снизу, иначе exp уходит в underflow на старых событиях
+const decayExpr = `weight * exp(GREATEST(-ln(2) * EXTRACT(EPOCH FROM (current_timestamp - ts)) / $1::DOUBLE PRECISION, -700))`
+
+// roundedScore округление убирает бесконечно малые изменения затухающих оценок, иначе каждая строка перезаписывалась бы при каждом пересчёте
+const roundedScore = `ROUND(COALESCE(scores.score, 0)::NUMERIC, 4)::DOUBLE PRECISION`
+
+// Пересчёт обновляет только строки, у которых изменилась оценка или ещё не совпадают текущая и прежняя.
+// popularity_prev хранит значения предыдущего поколения, по ним продолжается выдача с курсором этого поколения
+const placePopularityQuery = `WITH events AS (
+		SELECT place_id, $2::DOUBLE PRECISION AS weight, timestamp AS ts FROM users_place_checkin
+		UNION ALL
+		SELECT place_id, $3::DOUBLE PRECISION, timestamp FROM users_favourite_places
+		UNION ALL
+		SELECT place_id, $4::DOUBLE PRECISION, timestamp FROM places_reviews WHERE status = 'published'
+	), scores AS (
+		SELECT place_id, SUM(` + decayExpr + `) AS score FROM events
+		WHERE ts IS NOT NULL AND ts <= current_timestamp
+		GROUP BY place_id
+	)
+	UPDATE places SET popularity_prev = places.popularity, popularity = ` + roundedScore + `
+	FROM places p LEFT JOIN scores ON scores.place_id = p.id
+	WHERE places.id = p.id
+		AND (places.popularity IS DISTINCT FROM ` + roundedScore + ` OR places.popularity_prev IS DISTINCT FROM places.popularity);`
+
+const routePopularityQuery = `WITH events AS (
+		SELECT route_id, $2::DOUBLE PRECISION AS weight, timestamp AS ts FROM users_favourite_routes
+		UNION ALL
+		SELECT route_id, $3::DOUBLE PRECISION, timestamp FROM route_reviews WHERE status = 'published'
+		UNION ALL
+		SELECT route_id, $4::DOUBLE PRECISION, start_time FROM users_route_logs
+		UNION ALL
+		SELECT route_id, $5::DOUBLE PRECISION, end_time FROM users_route_logs
+	), scores AS (
+		SELECT route_id, SUM(` + decayExpr + `) AS score FROM events
+		WHERE ts IS NOT NULL AND ts <= current_timestamp
+		GROUP BY route_id
+	)
+	UPDATE routes SET popularity_prev = routes.popularity, popularity = ` + roundedScore + `
+	FROM routes r LEFT JOIN scores ON scores.route_id = r.id
+	WHERE routes.id = r.id
+		AND (routes.popularity IS DISTINCT FROM ` + roundedScore + ` OR routes.popularity_prev IS DISTINCT FROM routes.popularity);`
+
+// Recompute пересчитывает популярность всех мест и маршрутов одной транзакцией.
+// Поколение увеличивается, только если изменилась хотя бы одна строка
+func (p popularityRepo) Recompute(ctx context.Context, weights models.PopularityWeights) error {
+	halfLifeSeconds := weights.HalfLifeHours * 3600
+
+	tx, err := p.db.Beginx()
+	if err != nil {
+		return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.TransactionErr, Err: err})
+	}
+
+	placesRes, err := tx.ExecContext(ctx, placePopularityQuery, halfLifeSeconds, weights.CheckIn, weights.Favourite, weights.Review)
+	if err != nil {
+		return rollbackWithErr(tx, customerr.ExecErr, err)
+	}
+
+	routesRes, err := tx.ExecContext(ctx, routePopularityQuery, halfLifeSeconds, weights.Favourite, weights.Review,
+		weights.RouteStart, weights.RouteComplete)
+	if err != nil {
+		return rollbackWithErr(tx, customerr.ExecErr, err)
+	}
+
+	var changed int64
+	for _, res := range []sql.Result{placesRes, routesRes} {
+		count, err := res.RowsAffected()
+		if err != nil {
+			return rollbackWithErr(tx, customerr.RowsErr, err)
+		}
+
+		changed += count
+	}
+
+	if changed > 0 {
+		if _, err = tx.ExecContext(ctx, `UPDATE popularity_state SET generation = generation + 1;`); err != nil {
+			return rollbackWithErr(tx, customerr.ExecErr, err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.CommitErr, Err: err})
+	}
+
+	return nil
+}
+
+// popularityGenerationExpr номер последнего пересчёта, выбирается вместе со страницей, чтобы видеть тот же снимок
+const popularityGenerationExpr = `(SELECT generation FROM popularity_state)`
+
+func popularityGeneration(ctx context.Context, db *sqlx.DB) (int64, error) {
+	var generation int64
+	if err := db.QueryRowContext(ctx, `SELECT generation FROM popularity_state;`).Scan(&generation); err != nil {
+		return 0, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ScanErr, Err: err})
+	}
+
+	return generation, nil
+}
+
+// popularityColumn колонка, по которой продолжается выдача с курсором поколения cursorGeneration
+// при текущем поколении generation. Курсоры старше предыдущего пересчёта недействительны
+func popularityColumn(generation, cursorGeneration int64) (string, error) {
+	switch cursorGeneration {
+	case generation:
+		return "popularity", nil
+	case generation - 1:
+		return "popularity_prev", nil
+	default:
+		return "", customerr.InvalidCursor
+	}
+}
+
+// popularitySnapshot колонка сортировки для продолжения выдачи с курсором поколения cursorGeneration
+// и текущее поколение, с которым должна совпасть выбранная страница
+func popularitySnapshot(ctx context.Context, db *sqlx.DB, cursorGeneration int64) (string, int64, error) {
+	generation, err := popularityGeneration(ctx, db)
+	if err != nil {
+		return "", 0, err
+	}
+
+	column, err := popularityColumn(generation, cursorGeneration)
+	if err != nil {
+		return "", 0, err
+	}
+
+	return column, generation, nil
+}
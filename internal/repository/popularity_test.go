@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"errors"
+	"mth/pkg/customerr"
+	"mth/pkg/pagination"
+	"testing"
+)
+
+func TestPopularityColumn(t *testing.T) {
+	cases := []struct {
+		generation, cursor int64
+		column             string
+	}{
+		{generation: 5, cursor: 5, column: "popularity"},
+		{generation: 5, cursor: 4, column: "popularity_prev"},
+		{generation: 0, cursor: 0, column: "popularity"},
+	}
+	for _, c := range cases {
+		if column, err := popularityColumn(c.generation, c.cursor); err != nil || column != c.column {
+			t.Errorf("generation %v, cursor %v: got %v, %v", c.generation, c.cursor, column, err)
+		}
+	}
+
+	for _, cursor := range []int64{3, 6} {
+		if _, err := popularityColumn(5, cursor); !errors.Is(err, customerr.InvalidCursor) {
+			t.Errorf("cursor %v at generation 5: expected InvalidCursor, got %v", cursor, err)
+		}
+	}
+}
+
+func TestPlaceCursorKeepsGeneration(t *testing.T) {
+	encoded, err := pagination.EncodeCursor(placeCursor{ID: 3, Popularity: 1.5, Generation: 7, Sort: "popularity"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded placeCursor
+	if err = pagination.DecodeCursor(encoded, &decoded); err != nil || decoded.Generation != 7 || decoded.Popularity != 1.5 {
+		t.Errorf("unexpected cursor %+v, %v", decoded, err)
+	}
+}
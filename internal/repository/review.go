@@ -4,19 +4,28 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/guregu/null/v5"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"mth/internal/models"
 	"mth/pkg/customerr"
+	"mth/pkg/pagination"
+	"mth/pkg/wilson"
 	"time"
 )
 
 type reviewRepo struct {
-	db *sqlx.DB
+	db    *sqlx.DB
+	scale models.MarkScale
 }
 
-func InitReviewRepo(db *sqlx.DB) Review {
+// InitReviewRepo по целым значениям scale строятся гистограммы рейтинга, по её краям понижаются отзывы новых аккаунтов
+func InitReviewRepo(db *sqlx.DB, scale models.MarkScale) Review {
 	return reviewRepo{
-		db: db,
+		db:    db,
+		scale: scale,
 	}
 }
 
@@ -25,28 +34,185 @@ type reviewCreate struct {
 	EntityID   int
 	Properties interface{}
 	Mark       float32
+	SubMarks   map[string]float32
 	TimeStamp  time.Time
+	Status     string
 	_          struct{}
 }
 
 type reviewGet struct {
 	ID int
 	reviewCreate
+	ModerationReason string
+	Verified         bool
+	Votes            models.ReviewVotes
+	Downranked       bool
+	Reply            *models.ReviewReply
 }
 
-func (r reviewRepo) create(ctx context.Context, query string, review reviewCreate) (int, error) {
-	tx, err := r.db.Beginx()
+// ratingQuery пересчитывает денормализованные агрегаты оценок сущности по опубликованным отзывам.
+// $1 - id сущности, $2 и $3 - целые границы шкалы для гистограммы, аргументы собирает ratingArgs.
+// Оценка округляется через NUMERIC: у real половины округляются к чётному, и 4.5 попадала бы в 4
+func ratingQuery(entityTable, reviewTable, entityColumn string) string {
+	return `UPDATE ` + entityTable + ` SET
+				reviews_count = agg.cnt,
+				reviews_average = agg.average,
+				reviews_histogram = ARRAY(
+					SELECT COUNT(r.id)::INTEGER FROM generate_series($2::INTEGER, $3::INTEGER) b
+					LEFT JOIN ` + reviewTable + ` r ON r.` + entityColumn + ` = $1 AND r.status = 'published'
+						AND LEAST(GREATEST(ROUND(r.mark::NUMERIC), $2::INTEGER), $3::INTEGER) = b
+					GROUP BY b ORDER BY b
+				),
+				reviews_criteria = COALESCE((
+					SELECT jsonb_object_agg(c.criterion, jsonb_build_object('average', c.average, 'count', c.cnt))
+					FROM (
+						SELECT s.key AS criterion, AVG(s.value::REAL) AS average, COUNT(*) AS cnt
+						FROM ` + reviewTable + ` r, jsonb_each_text(r.sub_marks) s
+						WHERE r.` + entityColumn + ` = $1 AND r.status = 'published'
+						GROUP BY s.key
+					) c
+				), '{}')
+			FROM (
+				SELECT COUNT(*)::INTEGER AS cnt, COALESCE(AVG(mark), 0) AS average
+				FROM ` + reviewTable + ` WHERE ` + entityColumn + ` = $1 AND status = 'published'
+			) agg
+			WHERE ` + entityTable + `.id = $1;`
+}
+
+var (
+	placeRatingQuery = ratingQuery("places", "places_reviews", "place_id")
+	routeRatingQuery = ratingQuery("routes", "route_reviews", "route_id")
+)
+
+// marshalSubMarks отсутствующие оценки по критериям хранятся пустым объектом
+func marshalSubMarks(subMarks map[string]float32) ([]byte, error) {
+	if subMarks == nil {
+		subMarks = map[string]float32{}
+	}
+
+	jsonSubMarks, err := json.Marshal(subMarks)
 	if err != nil {
-		return 0, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.TransactionErr, Err: err})
+		return nil, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.BindErr, Err: err})
+	}
+
+	return jsonSubMarks, nil
+}
+
+// ratingArgs при изменении шкалы гистограммы пересчитываются с новыми отзывами
+func ratingArgs(scale models.MarkScale, entityID int) []any {
+	low, high := scale.Buckets()
+	return []any{entityID, low, high}
+}
+
+// parseCriteriaRating агрегаты по критериям из reviews_criteria мест и маршрутов
+func parseCriteriaRating(raw []byte) (map[string]models.CriterionRating, error) {
+	criteria := map[string]models.CriterionRating{}
+	if len(raw) == 0 {
+		return criteria, nil
+	}
+
+	if err := json.Unmarshal(raw, &criteria); err != nil {
+		return nil, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.BindErr, Err: err})
+	}
+
+	return criteria, nil
+}
+
+// verified expressions проверяют визит автора отзыва r: отметку в месте или завершённое прохождение маршрута
+const (
+	placeVerifiedExpr = `EXISTS(SELECT 1 FROM users_place_checkin c WHERE c.user_id = r.author_id AND c.place_id = r.place_id)`
+	routeVerifiedExpr = `EXISTS(SELECT 1 FROM users_route_logs l
+							WHERE l.user_id = r.author_id AND l.route_id = r.route_id AND l.end_time IS NOT NULL)`
+)
+
+// reviewTable таблица отзывов одного типа и всё, что с ней связано, refColumn - ссылка на отзыв в жалобах и голосах
+type reviewTable struct {
+	table        string
+	entityTable  string
+	entityColumn string
+	refColumn    string
+	mediaEntity  string
+	ratingQuery  string
+	verifiedExpr string
+}
+
+var reviewTables = map[string]reviewTable{
+	models.ReviewTypePlace: {
+		table:        "places_reviews",
+		entityTable:  "places",
+		entityColumn: "place_id",
+		refColumn:    "place_review_id",
+		mediaEntity:  models.MediaEntityPlaceReview,
+		ratingQuery:  placeRatingQuery,
+		verifiedExpr: placeVerifiedExpr,
+	},
+	models.ReviewTypeRoute: {
+		table:        "route_reviews",
+		entityTable:  "routes",
+		entityColumn: "route_id",
+		refColumn:    "route_review_id",
+		mediaEntity:  models.MediaEntityRouteReview,
+		ratingQuery:  routeRatingQuery,
+		verifiedExpr: routeVerifiedExpr,
+	},
+}
+
+// lockRatedEntity блокирует место или маршрут до конца транзакции. Пересчёт агрегатов идёт отдельным запросом
+// уже после блокировки, поэтому под READ COMMITTED он видит отзывы всех параллельных транзакций, успевших закоммитить
+func lockRatedEntity(ctx context.Context, tx *sqlx.Tx, table reviewTable, entityID int) error {
+	_, err := tx.ExecContext(ctx, `SELECT id FROM `+table.entityTable+` WHERE id = $1 FOR UPDATE;`, entityID)
+	if err != nil {
+		return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ExecErr, Err: err})
+	}
+
+	return nil
+}
+
+// refreshRating пересчитывает агрегаты сущности под блокировкой её строки
+func refreshRating(ctx context.Context, tx *sqlx.Tx, table reviewTable, scale models.MarkScale, entityID int) error {
+	if err := lockRatedEntity(ctx, tx, table, entityID); err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, table.ratingQuery, ratingArgs(scale, entityID)...); err != nil {
+		return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ExecErr, Err: err})
 	}
 
+	return nil
+}
+
+func getReviewTable(reviewType string) (reviewTable, error) {
+	table, ok := reviewTables[reviewType]
+	if !ok {
+		return reviewTable{}, fmt.Errorf("%w: unknown review type %v", customerr.BadInput, reviewType)
+	}
+
+	return table, nil
+}
+
+func (r reviewRepo) create(ctx context.Context, query string, table reviewTable, review reviewCreate) (int, error) {
 	jsonProperties, err := json.Marshal(review.Properties)
 	if err != nil {
 		return 0, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.BindErr, Err: err})
 	}
 
+	jsonSubMarks, err := marshalSubMarks(review.SubMarks)
+	if err != nil {
+		return 0, err
+	}
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.TransactionErr, Err: err})
+	}
+
+	if err = lockRatedEntity(ctx, tx, table, review.EntityID); err != nil {
+		return 0, rollbackKeepErr(tx, err)
+	}
+
 	var createdID int
-	err = tx.QueryRowxContext(ctx, query, review.EntityID, review.AuthorID, jsonProperties, review.Mark).Scan(&createdID)
+	err = tx.QueryRowxContext(ctx, query, review.EntityID, review.AuthorID, jsonProperties, review.Mark, review.Status,
+		jsonSubMarks).Scan(&createdID)
 	if err != nil {
 		if rbErr := tx.Rollback(); rbErr != nil {
 			return 0, customerr.ErrNormalizer(
@@ -58,6 +224,18 @@ func (r reviewRepo) create(ctx context.Context, query string, review reviewCreat
 		return 0, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ScanErr, Err: err})
 	}
 
+	_, err = tx.ExecContext(ctx, table.ratingQuery, ratingArgs(r.scale, review.EntityID)...)
+	if err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return 0, customerr.ErrNormalizer(
+				customerr.ErrorPair{Message: customerr.ExecErr, Err: err},
+				customerr.ErrorPair{Message: customerr.RollbackErr, Err: rbErr},
+			)
+		}
+
+		return 0, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ExecErr, Err: err})
+	}
+
 	if err = tx.Commit(); err != nil {
 		return 0, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.CommitErr, Err: err})
 	}
@@ -65,70 +243,148 @@ func (r reviewRepo) create(ctx context.Context, query string, review reviewCreat
 	return createdID, nil
 }
 
-func (r reviewRepo) get(ctx context.Context, query string, authorID int, entityID int) ([]reviewGet, error) {
-	var rows *sql.Rows
-	var err error
+// reviewColumns поля отзыва r в порядке scanReview, downranked - выражение признака понижения в выдаче
+func reviewColumns(table reviewTable, downranked string) string {
+	return `r.id, r.` + table.entityColumn + ` AS entity_id, r.author_id, r.properties, r.mark, r.sub_marks, r.timestamp, r.status,
+			r.moderation_reason, ` + table.verifiedExpr + ` AS verified, r.helpful_count, r.unhelpful_count, r.helpfulness,
+			` + downranked + ` AS downranked`
+}
 
-	switch authorID {
-	case 0:
-		rows, err = r.db.QueryContext(ctx, query, entityID)
-	default:
-		rows, err = r.db.QueryContext(ctx, query, authorID)
+// downrankedExpr крайняя оценка шкалы от аккаунта, которому на момент at меньше newAccountDays дней, и у которого
+// все оценки одинаковые. at - параметр запроса, чтобы признак не менялся между страницами. Пользователи без даты
+// регистрации считаются старыми
+func downrankedExpr(scale models.MarkScale, newAccountDays int, at string) string {
+	return fmt.Sprintf(`COALESCE((r.mark <= %[2]v OR r.mark >= %[3]v)
+			AND (SELECT u.created_at FROM users u WHERE u.id = r.author_id) > %[4]s::TIMESTAMPTZ - make_interval(days => %[1]d)
+			AND NOT EXISTS(SELECT 1 FROM places_reviews o WHERE o.author_id = r.author_id AND o.mark <> r.mark)
+			AND NOT EXISTS(SELECT 1 FROM route_reviews o WHERE o.author_id = r.author_id AND o.mark <> r.mark), false)`,
+		newAccountDays, scale.Min, scale.Max, at)
+}
+
+func scanReview(rows *sql.Rows, extra ...any) (reviewGet, error) {
+	var review reviewGet
+	var propertiesRaw, subMarksRaw []byte
+	var timestamp null.Time
+	var moderationReason null.String
+
+	dest := append([]any{&review.ID, &review.EntityID, &review.AuthorID, &propertiesRaw, &review.Mark, &subMarksRaw, &timestamp,
+		&review.Status, &moderationReason, &review.Verified, &review.Votes.Helpful, &review.Votes.Unhelpful,
+		&review.Votes.Score, &review.Downranked}, extra...)
+	if err := rows.Scan(dest...); err != nil {
+		return reviewGet{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ScanErr, Err: err})
+	}
+
+	if len(propertiesRaw) > 0 {
+		if err := json.Unmarshal(propertiesRaw, &review.Properties); err != nil {
+			return reviewGet{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.BindErr, Err: err})
+		}
+	}
+
+	if len(subMarksRaw) > 0 {
+		if err := json.Unmarshal(subMarksRaw, &review.SubMarks); err != nil {
+			return reviewGet{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.BindErr, Err: err})
+		}
 	}
 
+	review.TimeStamp = timestamp.Time
+	review.ModerationReason = moderationReason.String
+
+	return review, nil
+}
+
+func (r reviewRepo) get(ctx context.Context, query string, args ...any) ([]reviewGet, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return []reviewGet{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ExecErr, Err: err})
 	}
+	defer rows.Close()
 
-	var reviews []reviewGet
-
+	reviews := []reviewGet{}
 	for rows.Next() {
-		var propertiesRaw []byte
-		var review reviewGet
-		err := rows.Scan(&review.ID, &review.EntityID, &review.AuthorID, &propertiesRaw, &review.Mark, &review.TimeStamp)
+		review, err := scanReview(rows)
 		if err != nil {
-			return []reviewGet{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ScanErr, Err: nil})
-		}
-
-		err = json.Unmarshal(propertiesRaw, &review.Properties)
-		if err != nil {
-			return []reviewGet{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.BindErr, Err: err})
+			return []reviewGet{}, err
 		}
 
 		reviews = append(reviews, review)
 	}
 
-	err = rows.Err()
-	if err != nil {
-		return []reviewGet{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.RowsErr, Err: nil})
+	if err = rows.Err(); err != nil {
+		return []reviewGet{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.RowsErr, Err: err})
 	}
 
 	return reviews, nil
 }
 
-func (r reviewRepo) update(ctx context.Context, query string, reviewUpd models.ReviewUpdate) error {
-	tx, err := r.db.BeginTx(ctx, nil)
-	if err != nil {
-		return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.TransactionErr, Err: err})
+func (g reviewGet) base() models.ReviewBase {
+	return models.ReviewBase{
+		AuthorID:   g.AuthorID,
+		Properties: g.Properties,
+		Mark:       g.Mark,
+		SubMarks:   g.SubMarks,
+		TimeStamp:  g.TimeStamp,
 	}
+}
+
+func (g reviewGet) state() models.ReviewModerationState {
+	return models.ReviewModerationState{
+		Status:           g.Status,
+		ModerationReason: g.ModerationReason,
+	}
+}
 
+func (g reviewGet) placeReview() models.PlaceReview {
+	return models.PlaceReview{
+		ID:                    g.ID,
+		PlaceReviewCreate:     models.PlaceReviewCreate{PlaceID: g.EntityID, ReviewBase: g.base()},
+		ReviewModerationState: g.state(),
+		Verified:              g.Verified,
+		Votes:                 g.Votes,
+		Downranked:            g.Downranked,
+		Reply:                 g.Reply,
+	}
+}
+
+func (g reviewGet) routeReview() models.RouteReview {
+	return models.RouteReview{
+		ID:                    g.ID,
+		RouteReviewCreate:     models.RouteReviewCreate{RouteID: g.EntityID, ReviewBase: g.base()},
+		ReviewModerationState: g.state(),
+		Verified:              g.Verified,
+		Votes:                 g.Votes,
+		Downranked:            g.Downranked,
+		Reply:                 g.Reply,
+	}
+}
+
+func convertReviewPage[T any](page models.Page[reviewGet], convert func(reviewGet) T) models.Page[T] {
+	items := make([]T, 0, len(page.Items))
+	for _, review := range page.Items {
+		items = append(items, convert(review))
+	}
+
+	return models.Page[T]{Items: items, NextCursor: page.NextCursor, HasMore: page.HasMore, Total: page.Total}
+}
+
+// update query должен возвращать id сущности, на которую оставлен отзыв, $4 - отправить отзыв на модерацию, $5 - оценки по критериям
+func (r reviewRepo) update(ctx context.Context, query string, table reviewTable, reviewUpd models.ReviewUpdate, premoderation bool) error {
 	jsonProperties, err := json.Marshal(reviewUpd.Properties)
 	if err != nil {
 		return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.BindErr, Err: err})
 	}
 
-	res, err := tx.ExecContext(ctx, query, reviewUpd.ID, jsonProperties, reviewUpd.Mark)
+	jsonSubMarks, err := marshalSubMarks(reviewUpd.SubMarks)
 	if err != nil {
-		if rbErr := tx.Rollback(); rbErr != nil {
-			return customerr.ErrNormalizer(
-				customerr.ErrorPair{Message: customerr.ScanErr, Err: err},
-				customerr.ErrorPair{Message: customerr.RollbackErr, Err: rbErr},
-			)
-		}
+		return err
+	}
 
-		return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ScanErr, Err: err})
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.TransactionErr, Err: err})
 	}
-	count, err := res.RowsAffected()
+
+	var entityID int
+	err = tx.QueryRowxContext(ctx, query, reviewUpd.ID, jsonProperties, reviewUpd.Mark, premoderation, jsonSubMarks).Scan(&entityID)
 	if err != nil {
 		if rbErr := tx.Rollback(); rbErr != nil {
 			return customerr.ErrNormalizer(
@@ -139,14 +395,9 @@ func (r reviewRepo) update(ctx context.Context, query string, reviewUpd models.R
 
 		return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ScanErr, Err: err})
 	}
-	if count != 1 {
-		if rbErr := tx.Rollback(); rbErr != nil {
-			return customerr.ErrNormalizer(
-				customerr.ErrorPair{Message: customerr.CountErr, Err: err},
-				customerr.ErrorPair{Message: customerr.RollbackErr, Err: rbErr},
-			)
-		}
-		return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.CountErr, Err: err})
+
+	if err = refreshRating(ctx, tx, table, r.scale, entityID); err != nil {
+		return rollbackKeepErr(tx, err)
 	}
 
 	err = tx.Commit()
@@ -157,134 +408,653 @@ func (r reviewRepo) update(ctx context.Context, query string, reviewUpd models.R
 	return nil
 }
 
-func (r reviewRepo) CreateOnRoute(ctx context.Context, routeReview models.RouteReviewCreate) (int, error) {
-	createRouteReviewQuery := `INSERT INTO route_reviews (route_id, author_id, properties, mark, timestamp) VALUES ($1, $2, $3, $4, current_timestamp) RETURNING id`
+func (r reviewRepo) CreateOnRoute(ctx context.Context, routeReview models.RouteReviewCreate, status string) (int, error) {
+	createRouteReviewQuery := `INSERT INTO route_reviews (route_id, author_id, properties, mark, status, sub_marks, timestamp)
+								VALUES ($1, $2, $3, $4, $5, $6, current_timestamp) RETURNING id;`
 	review := reviewCreate{
 		AuthorID:   routeReview.AuthorID,
 		EntityID:   routeReview.RouteID,
 		Properties: routeReview.Properties,
 		Mark:       routeReview.Mark,
+		SubMarks:   routeReview.SubMarks,
+		Status:     status,
 	}
-	return r.create(ctx, createRouteReviewQuery, review)
+	return r.create(ctx, createRouteReviewQuery, reviewTables[models.ReviewTypeRoute], review)
 }
 
-func (r reviewRepo) CreateOnPlace(ctx context.Context, placeReview models.PlaceReviewCreate) (int, error) {
-	createRouteReviewQuery := `INSERT INTO places_reviews (place_id, author_id, properties, mark, timestamp) VALUES ($1, $2, $3, $4, current_timestamp) RETURNING id;`
+func (r reviewRepo) CreateOnPlace(ctx context.Context, placeReview models.PlaceReviewCreate, status string) (int, error) {
+	createRouteReviewQuery := `INSERT INTO places_reviews (place_id, author_id, properties, mark, status, sub_marks, timestamp)
+								VALUES ($1, $2, $3, $4, $5, $6, current_timestamp) RETURNING id;`
 	review := reviewCreate{
 		AuthorID:   placeReview.AuthorID,
 		EntityID:   placeReview.PlaceID,
 		Properties: placeReview.Properties,
 		Mark:       placeReview.Mark,
+		SubMarks:   placeReview.SubMarks,
+		Status:     status,
 	}
-	return r.create(ctx, createRouteReviewQuery, review)
+	return r.create(ctx, createRouteReviewQuery, reviewTables[models.ReviewTypePlace], review)
 }
 
 func (r reviewRepo) GetByAuthor(ctx context.Context, authorID int) ([]models.PlaceReview, []models.RouteReview, error) {
-	getRouteReviewByUser := `SELECT id, route_id, author_id, properties, mark, timestamp FROM route_reviews WHERE author_id = $1;`
-	reviews, err := r.get(ctx, getRouteReviewByUser, authorID, 0)
+	routeTable := reviewTables[models.ReviewTypeRoute]
+	getRouteReviewByUser := `SELECT ` + reviewColumns(routeTable, "false") + ` FROM route_reviews r WHERE r.author_id = $1;`
+	reviews, err := r.get(ctx, getRouteReviewByUser, authorID)
 	if err != nil {
 		return []models.PlaceReview{}, []models.RouteReview{}, err
 	}
+	if err = r.attachReplies(ctx, routeTable, reviews); err != nil {
+		return []models.PlaceReview{}, []models.RouteReview{}, err
+	}
 
 	routeReviews := make([]models.RouteReview, len(reviews))
 	for i := range reviews {
-		routeReviews[i] = models.RouteReview{
-			ID: reviews[i].ID,
-			RouteReviewCreate: models.RouteReviewCreate{
-				RouteID: reviews[i].EntityID,
-				ReviewBase: models.ReviewBase{
-					AuthorID:   reviews[i].AuthorID,
-					Properties: reviews[i].Properties,
-					Mark:       reviews[i].Mark,
-					TimeStamp:  reviews[i].TimeStamp,
-				},
-			},
-		}
+		routeReviews[i] = reviews[i].routeReview()
 	}
 
-	getPlaceReviewsByAuthorQuery := `SELECT id, place_id, author_id, properties, mark, timestamp FROM places_reviews WHERE author_id = $1;`
-	reviews, err = r.get(ctx, getPlaceReviewsByAuthorQuery, authorID, 0)
+	placeTable := reviewTables[models.ReviewTypePlace]
+	getPlaceReviewsByAuthorQuery := `SELECT ` + reviewColumns(placeTable, "false") + ` FROM places_reviews r WHERE r.author_id = $1;`
+	reviews, err = r.get(ctx, getPlaceReviewsByAuthorQuery, authorID)
 	if err != nil {
 		return []models.PlaceReview{}, []models.RouteReview{}, err
 	}
+	if err = r.attachReplies(ctx, placeTable, reviews); err != nil {
+		return []models.PlaceReview{}, []models.RouteReview{}, err
+	}
 
 	placeReviews := make([]models.PlaceReview, len(reviews))
 	for i := range reviews {
-		placeReviews[i] = models.PlaceReview{
-			ID: reviews[i].ID,
-			PlaceReviewCreate: models.PlaceReviewCreate{
-				PlaceID: reviews[i].EntityID,
-				ReviewBase: models.ReviewBase{
-					AuthorID:   reviews[i].AuthorID,
-					Properties: reviews[i].Properties,
-					Mark:       reviews[i].Mark,
-					TimeStamp:  reviews[i].TimeStamp,
-				},
-			},
-		}
+		placeReviews[i] = reviews[i].placeReview()
 	}
 
 	return placeReviews, routeReviews, nil
 }
 
-func (r reviewRepo) GetByRoute(ctx context.Context, routeID int) ([]models.RouteReview, error) {
-	getByRouteQuery := `SELECT id, route_id, author_id, properties, mark, timestamp FROM route_reviews WHERE route_id = $1;`
-	reviews, err := r.get(ctx, getByRouteQuery, 0, routeID)
+// reviewSortKeys ключ сортировки по убыванию для каждого порядка, при равных ключах новые отзывы выше.
+// При сортировке по полезности пониженные отзывы идут после всех остальных
+var reviewSortKeys = map[string]string{
+	models.ReviewSortHelpful:  `q.helpfulness - CASE WHEN q.downranked THEN 2 ELSE 0 END`,
+	models.ReviewSortRecent:   `EXTRACT(EPOCH FROM date_trunc('second', COALESCE(q.timestamp, 'epoch')))::DOUBLE PRECISION`,
+	models.ReviewSortMarkHigh: `q.mark::DOUBLE PRECISION`,
+	models.ReviewSortMarkLow:  `-q.mark::DOUBLE PRECISION`,
+}
+
+// reviewListCursor At - момент, на который считается понижение отзывов, один для всех страниц выдачи
+type reviewListCursor struct {
+	ID   int       `json:"id"`
+	Key  float64   `json:"k"`
+	Sort string    `json:"s"`
+	At   time.Time `json:"t"`
+}
+
+// list опубликованные отзывы сущности постранично, request.SortBy и request.PageSize проверены сервисом
+func (r reviewRepo) list(ctx context.Context, table reviewTable, request models.ReviewListRequest,
+	newAccountDays int) (models.Page[reviewGet], error) {
+	sortKey, ok := reviewSortKeys[request.SortBy]
+	if !ok {
+		return models.Page[reviewGet]{}, fmt.Errorf("%w: unknown sort %v", customerr.BadInput, request.SortBy)
+	}
+
+	var cursor *reviewListCursor
+	if request.Cursor != "" {
+		cursor = &reviewListCursor{}
+		if err := pagination.DecodeCursor(request.Cursor, cursor); err != nil {
+			return models.Page[reviewGet]{}, err
+		}
+		if cursor.Sort != request.SortBy || cursor.At.IsZero() {
+			return models.Page[reviewGet]{}, customerr.InvalidCursor
+		}
+	}
+
+	evaluatedAt := time.Now()
+	if cursor != nil {
+		evaluatedAt = cursor.At
+	}
+
+	condition := `r.` + table.entityColumn + ` = $1 AND r.status = 'published'`
+	if request.VerifiedOnly {
+		condition += ` AND ` + table.verifiedExpr
+	}
+
+	query := `SELECT * FROM (
+				SELECT q.*, ` + sortKey + ` AS sort_key FROM (
+					SELECT ` + reviewColumns(table, downrankedExpr(r.scale, newAccountDays, "$3")) + ` FROM ` + table.table + ` r
+					WHERE ` + condition + `
+				) q
+			) s`
+	args := []any{request.ID, request.PageSize + 1, evaluatedAt}
+	if cursor != nil {
+		query += ` WHERE (sort_key, id) < ($4, $5)`
+		args = append(args, cursor.Key, cursor.ID)
+	}
+	query += ` ORDER BY sort_key DESC, id DESC LIMIT $2;`
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
-		return []models.RouteReview{}, err
+		return models.Page[reviewGet]{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ExecErr, Err: err})
 	}
+	defer rows.Close()
 
-	routeReviews := make([]models.RouteReview, len(reviews))
-	for i := range reviews {
-		routeReviews[i] = models.RouteReview{
-			ID: reviews[i].ID,
-			RouteReviewCreate: models.RouteReviewCreate{
-				RouteID: reviews[i].EntityID,
-				ReviewBase: models.ReviewBase{
-					AuthorID:   reviews[i].AuthorID,
-					Properties: reviews[i].Properties,
-					Mark:       reviews[i].Mark,
-					TimeStamp:  reviews[i].TimeStamp,
-				},
-			},
+	result := models.Page[reviewGet]{Items: []reviewGet{}}
+	var keys []float64
+	for rows.Next() {
+		var key float64
+		review, err := scanReview(rows, &key)
+		if err != nil {
+			return models.Page[reviewGet]{}, err
+		}
+
+		result.Items = append(result.Items, review)
+		keys = append(keys, key)
+	}
+
+	if err = rows.Err(); err != nil {
+		return models.Page[reviewGet]{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.RowsErr, Err: err})
+	}
+
+	if len(result.Items) > request.PageSize {
+		result.Items = result.Items[:request.PageSize]
+		result.HasMore = true
+
+		result.NextCursor, err = pagination.EncodeCursor(reviewListCursor{
+			ID:   result.Items[request.PageSize-1].ID,
+			Key:  keys[request.PageSize-1],
+			Sort: request.SortBy,
+			At:   evaluatedAt,
+		})
+		if err != nil {
+			return models.Page[reviewGet]{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.BindErr, Err: err})
 		}
 	}
 
-	return routeReviews, nil
+	if request.WithTotal {
+		var total int
+		countQuery := `SELECT COUNT(*) FROM ` + table.table + ` r WHERE ` + condition + `;`
+		if err = r.db.QueryRowContext(ctx, countQuery, request.ID).Scan(&total); err != nil {
+			return models.Page[reviewGet]{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ScanErr, Err: err})
+		}
+
+		result.Total = &total
+	}
+
+	if err = r.attachReplies(ctx, table, result.Items); err != nil {
+		return models.Page[reviewGet]{}, err
+	}
+
+	return result, nil
 }
 
-func (r reviewRepo) GetByPlace(ctx context.Context, placeID int) ([]models.PlaceReview, error) {
-	getByPlaceQuery := `SELECT id, place_id, author_id, properties, mark, timestamp FROM places_reviews WHERE place_id = $1;`
-	reviews, err := r.get(ctx, getByPlaceQuery, 0, placeID)
+func (r reviewRepo) GetByRoute(ctx context.Context, request models.ReviewListRequest,
+	newAccountDays int) (models.Page[models.RouteReview], error) {
+	page, err := r.list(ctx, reviewTables[models.ReviewTypeRoute], request, newAccountDays)
 	if err != nil {
-		return []models.PlaceReview{}, err
+		return models.Page[models.RouteReview]{}, err
 	}
 
-	placeReviews := make([]models.PlaceReview, len(reviews))
-	for i := range reviews {
-		placeReviews[i] = models.PlaceReview{
-			ID: reviews[i].ID,
-			PlaceReviewCreate: models.PlaceReviewCreate{
-				PlaceID: reviews[i].EntityID,
-				ReviewBase: models.ReviewBase{
-					AuthorID:   reviews[i].AuthorID,
-					Properties: reviews[i].Properties,
-					Mark:       reviews[i].Mark,
-					TimeStamp:  reviews[i].TimeStamp,
-				},
-			},
+	return convertReviewPage(page, reviewGet.routeReview), nil
+}
+
+func (r reviewRepo) GetByPlace(ctx context.Context, request models.ReviewListRequest,
+	newAccountDays int) (models.Page[models.PlaceReview], error) {
+	page, err := r.list(ctx, reviewTables[models.ReviewTypePlace], request, newAccountDays)
+	if err != nil {
+		return models.Page[models.PlaceReview]{}, err
+	}
+
+	return convertReviewPage(page, reviewGet.placeReview), nil
+}
+
+// reviewUpdateStatus отклонённый или скрытый отзыв после правки снова проверяется модератором
+const reviewUpdateStatus = `status = CASE WHEN $4 OR status IN ('rejected', 'hidden') THEN 'pending' ELSE status END`
+
+func (r reviewRepo) UpdateOnPlace(ctx context.Context, reviewUpd models.ReviewUpdate, premoderation bool) error {
+	updatePlaceReviewQuery := `UPDATE places_reviews SET properties = $2, mark = $3, sub_marks = $5, ` + reviewUpdateStatus + `
+								WHERE id = $1 RETURNING place_id;`
+	return r.update(ctx, updatePlaceReviewQuery, reviewTables[models.ReviewTypePlace], reviewUpd, premoderation)
+}
+
+func (r reviewRepo) UpdateOnRoute(ctx context.Context, reviewUpd models.ReviewUpdate, premoderation bool) error {
+	updateRouteReviewQuery := `UPDATE route_reviews SET properties = $2, mark = $3, sub_marks = $5, ` + reviewUpdateStatus + `
+								WHERE id = $1 RETURNING route_id;`
+	return r.update(ctx, updateRouteReviewQuery, reviewTables[models.ReviewTypeRoute], reviewUpd, premoderation)
+}
+
+// PlaceReviewPolicy требования вида места к отзывам, вид, которого нет в таблице, ничего не требует
+func (r reviewRepo) PlaceReviewPolicy(ctx context.Context, placeID int) (models.PlaceReviewPolicy, error) {
+	query := `SELECT COALESCE(v.require_verified_review, false), COALESCE(v.review_criteria, '{}') FROM places p
+				LEFT JOIN varieties v ON v.name = p.variety
+				WHERE p.id = $1;`
+
+	var policy models.PlaceReviewPolicy
+	err := r.db.QueryRowContext(ctx, query, placeID).Scan(&policy.RequireVerified, pq.Array(&policy.Criteria))
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.PlaceReviewPolicy{}, customerr.PlaceNotFound
+	}
+	if err != nil {
+		return models.PlaceReviewPolicy{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ScanErr, Err: err})
+	}
+
+	return policy, nil
+}
+
+// PlaceReviewCriteria критерии вида места, на которое оставлен отзыв
+func (r reviewRepo) PlaceReviewCriteria(ctx context.Context, reviewID int) ([]string, error) {
+	query := `SELECT COALESCE(v.review_criteria, '{}') FROM places_reviews r
+				JOIN places p ON p.id = r.place_id
+				LEFT JOIN varieties v ON v.name = p.variety
+				WHERE r.id = $1;`
+
+	var criteria []string
+	err := r.db.QueryRowContext(ctx, query, reviewID).Scan(pq.Array(&criteria))
+	if errors.Is(err, sql.ErrNoRows) {
+		return []string{}, customerr.ReviewNotFound
+	}
+	if err != nil {
+		return []string{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ScanErr, Err: err})
+	}
+
+	return criteria, nil
+}
+
+// GetAuthor автор отзыва и место или маршрут, к которому он оставлен
+func (r reviewRepo) GetAuthor(ctx context.Context, reviewType string, reviewID int) (int, int, error) {
+	table, err := getReviewTable(reviewType)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var authorID, entityID int
+	err = r.db.QueryRowContext(ctx, `SELECT author_id, `+table.entityColumn+` FROM `+table.table+` WHERE id = $1;`,
+		reviewID).Scan(&authorID, &entityID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, 0, customerr.ReviewNotFound
+	}
+	if err != nil {
+		return 0, 0, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ScanErr, Err: err})
+	}
+
+	return authorID, entityID, nil
+}
+
+// IsVerified был ли автор в месте или прошёл маршрут, на который пишет отзыв
+func (r reviewRepo) IsVerified(ctx context.Context, reviewType string, authorID int, entityID int) (bool, error) {
+	table, err := getReviewTable(reviewType)
+	if err != nil {
+		return false, err
+	}
+
+	query := `SELECT ` + table.verifiedExpr + ` FROM (SELECT $1::INTEGER AS author_id, $2::INTEGER AS ` +
+		table.entityColumn + `) r;`
+
+	var verified bool
+	if err = r.db.QueryRowContext(ctx, query, authorID, entityID).Scan(&verified); err != nil {
+		return false, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ScanErr, Err: err})
+	}
+
+	return verified, nil
+}
+
+// Delete удаляет отзыв автора вместе с прикреплёнными медиа и жалобами и пересчитывает рейтинг
+func (r reviewRepo) Delete(ctx context.Context, reviewDelete models.ReviewDelete) error {
+	table, err := getReviewTable(reviewDelete.ReviewType)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.TransactionErr, Err: err})
+	}
+
+	var authorID, entityID int
+	err = tx.QueryRowxContext(ctx, `SELECT author_id, `+table.entityColumn+` FROM `+table.table+` WHERE id = $1 FOR UPDATE;`,
+		reviewDelete.ReviewID).Scan(&authorID, &entityID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return rollbackKeepErr(tx, customerr.ReviewNotFound)
+	}
+	if err != nil {
+		return rollbackWithErr(tx, customerr.ScanErr, err)
+	}
+	if authorID != reviewDelete.UserID {
+		return rollbackKeepErr(tx, customerr.UserNotEntityOwner)
+	}
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM media_attachments WHERE entity_type = $1 AND entity_id = $2;`,
+		table.mediaEntity, reviewDelete.ReviewID)
+	if err != nil {
+		return rollbackWithErr(tx, customerr.ExecErr, err)
+	}
+
+	if _, err = tx.ExecContext(ctx, `DELETE FROM `+table.table+` WHERE id = $1;`, reviewDelete.ReviewID); err != nil {
+		return rollbackWithErr(tx, customerr.ExecErr, err)
+	}
+
+	if err = refreshRating(ctx, tx, table, r.scale, entityID); err != nil {
+		return rollbackKeepErr(tx, err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.CommitErr, Err: err})
+	}
+
+	return nil
+}
+
+// Flag добавляет жалобу пользователя. Опубликованный отзыв, набравший hideThreshold открытых жалоб,
+// скрывается до решения модератора, hideThreshold 0 отключает скрытие
+func (r reviewRepo) Flag(ctx context.Context, flag models.ReviewFlagCreate, hideThreshold int) (models.ReviewFlagResult, error) {
+	table, err := getReviewTable(flag.ReviewType)
+	if err != nil {
+		return models.ReviewFlagResult{}, err
+	}
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return models.ReviewFlagResult{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.TransactionErr, Err: err})
+	}
+
+	var authorID, entityID int
+	var status string
+	err = tx.QueryRowxContext(ctx, `SELECT author_id, `+table.entityColumn+`, status FROM `+table.table+` WHERE id = $1 FOR UPDATE;`,
+		flag.ReviewID).Scan(&authorID, &entityID, &status)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.ReviewFlagResult{}, rollbackKeepErr(tx, customerr.ReviewNotFound)
+	}
+	if err != nil {
+		return models.ReviewFlagResult{}, rollbackWithErr(tx, customerr.ScanErr, err)
+	}
+	if authorID == flag.UserID {
+		return models.ReviewFlagResult{}, rollbackKeepErr(tx, fmt.Errorf("%w: own review can not be flagged", customerr.BadInput))
+	}
+
+	res, err := tx.ExecContext(ctx, `INSERT INTO review_flags (`+table.refColumn+`, user_id, reason, comment)
+										VALUES ($1, $2, $3, $4) ON CONFLICT DO NOTHING;`,
+		flag.ReviewID, flag.UserID, flag.Reason, null.NewString(flag.Comment, flag.Comment != ""))
+	if err != nil {
+		return models.ReviewFlagResult{}, rollbackWithErr(tx, customerr.ExecErr, err)
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return models.ReviewFlagResult{}, rollbackWithErr(tx, customerr.RowsErr, err)
+	}
+	if count == 0 {
+		return models.ReviewFlagResult{}, rollbackKeepErr(tx, customerr.ReviewAlreadyFlagged)
+	}
+
+	var result models.ReviewFlagResult
+	err = tx.QueryRowxContext(ctx, `SELECT COUNT(*) FROM review_flags WHERE `+table.refColumn+` = $1 AND resolved_at IS NULL;`,
+		flag.ReviewID).Scan(&result.OpenFlags)
+	if err != nil {
+		return models.ReviewFlagResult{}, rollbackWithErr(tx, customerr.ScanErr, err)
+	}
+
+	if hideThreshold > 0 && result.OpenFlags >= hideThreshold && status == models.ReviewStatusPublished {
+		_, err = tx.ExecContext(ctx, `UPDATE `+table.table+` SET status = 'hidden', moderation_reason = 'flagged by users',
+										moderated_by = NULL, moderated_at = current_timestamp WHERE id = $1;`, flag.ReviewID)
+		if err != nil {
+			return models.ReviewFlagResult{}, rollbackWithErr(tx, customerr.ExecErr, err)
+		}
+
+		if err = refreshRating(ctx, tx, table, r.scale, entityID); err != nil {
+			return models.ReviewFlagResult{}, rollbackKeepErr(tx, err)
 		}
+
+		result.Hidden = true
 	}
 
-	return placeReviews, nil
+	if err = tx.Commit(); err != nil {
+		return models.ReviewFlagResult{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.CommitErr, Err: err})
+	}
+
+	return result, nil
 }
 
-func (r reviewRepo) UpdateOnPlace(ctx context.Context, reviewUpd models.ReviewUpdate) error {
-	updatePlaceReviewQuery := `UPDATE places_reviews SET properties = $2, mark = $3 WHERE id = $1;`
-	return r.update(ctx, updatePlaceReviewQuery, reviewUpd)
+// Moderate переводит отзыв в status, закрывает открытые жалобы и пересчитывает рейтинг.
+// Действие и причина проверены сервисом
+func (r reviewRepo) Moderate(ctx context.Context, moderation models.ReviewModeration, status string) error {
+	table, err := getReviewTable(moderation.ReviewType)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.TransactionErr, Err: err})
+	}
+
+	var entityID int
+	err = tx.QueryRowxContext(ctx, `UPDATE `+table.table+` SET status = $2, moderation_reason = $3, moderated_by = $4,
+										moderated_at = current_timestamp
+									WHERE id = $1 RETURNING `+table.entityColumn+`;`,
+		moderation.ReviewID, status, null.NewString(moderation.Reason, moderation.Reason != ""), moderation.ModeratorID).
+		Scan(&entityID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return rollbackKeepErr(tx, customerr.ReviewNotFound)
+	}
+	if err != nil {
+		return rollbackWithErr(tx, customerr.ScanErr, err)
+	}
+
+	_, err = tx.ExecContext(ctx, `UPDATE review_flags SET resolved_at = current_timestamp
+									WHERE `+table.refColumn+` = $1 AND resolved_at IS NULL;`, moderation.ReviewID)
+	if err != nil {
+		return rollbackWithErr(tx, customerr.ExecErr, err)
+	}
+
+	if err = refreshRating(ctx, tx, table, r.scale, entityID); err != nil {
+		return rollbackKeepErr(tx, err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.CommitErr, Err: err})
+	}
+
+	return nil
+}
+
+// reviewQueueCursor очередь упорядочена по типу отзыва и id
+type reviewQueueCursor struct {
+	Type string `json:"type"`
+	ID   int    `json:"id"`
+}
+
+// reviewQueueSource отзывы одного типа вместе с открытыми жалобами
+func reviewQueueSource(reviewType string) string {
+	table := reviewTables[reviewType]
+
+	return `SELECT '` + reviewType + `' AS review_type, r.id, r.` + table.entityColumn + ` AS entity_id, r.author_id,
+				r.properties, r.mark, r.timestamp, r.status, r.moderation_reason,
+				ARRAY(SELECT f.reason || COALESCE(': ' || f.comment, '') FROM review_flags f
+					WHERE f.` + table.refColumn + ` = r.id AND f.resolved_at IS NULL ORDER BY f.created_at, f.id) AS flags
+			FROM ` + table.table + ` r`
+}
+
+// GetModerationQueue новые отзывы и отзывы с открытыми жалобами по типу и id. request.PageSize уже ограничен сервисом
+func (r reviewRepo) GetModerationQueue(ctx context.Context, request models.ReviewQueueRequest) (models.Page[models.ReviewQueueItem], error) {
+	var cursor reviewQueueCursor
+	if err := pagination.DecodeCursor(request.Cursor, &cursor); err != nil {
+		return models.Page[models.ReviewQueueItem]{}, err
+	}
+
+	var condition string
+	switch request.Queue {
+	case models.ReviewQueuePending:
+		condition = `status = 'pending'`
+	case models.ReviewQueueFlagged:
+		condition = `status IN ('published', 'hidden') AND cardinality(flags) > 0`
+	default:
+		condition = `(status = 'pending' OR (status IN ('published', 'hidden') AND cardinality(flags) > 0))`
+	}
+
+	queueQuery := `SELECT review_type, id, entity_id, author_id, properties, mark, timestamp, status, moderation_reason, flags
+					FROM (
+						` + reviewQueueSource(models.ReviewTypePlace) + `
+						UNION ALL
+						` + reviewQueueSource(models.ReviewTypeRoute) + `
+					) q
+					WHERE ` + condition
+
+	query := queueQuery + ` AND (review_type, id) > ($1, $2) ORDER BY review_type, id LIMIT $3;`
+
+	rows, err := r.db.QueryContext(ctx, query, cursor.Type, cursor.ID, request.PageSize+1)
+	if err != nil {
+		return models.Page[models.ReviewQueueItem]{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ExecErr, Err: err})
+	}
+	defer rows.Close()
+
+	result := models.Page[models.ReviewQueueItem]{Items: []models.ReviewQueueItem{}}
+	for rows.Next() {
+		var item models.ReviewQueueItem
+		var propertiesRaw []byte
+		var timestamp null.Time
+		var moderationReason null.String
+		var flags pq.StringArray
+
+		err = rows.Scan(&item.ReviewType, &item.ID, &item.EntityID, &item.AuthorID, &propertiesRaw, &item.Mark,
+			&timestamp, &item.Status, &moderationReason, &flags)
+		if err != nil {
+			return models.Page[models.ReviewQueueItem]{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ScanErr, Err: err})
+		}
+
+		if len(propertiesRaw) > 0 {
+			if err = json.Unmarshal(propertiesRaw, &item.Properties); err != nil {
+				return models.Page[models.ReviewQueueItem]{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.BindErr, Err: err})
+			}
+		}
+
+		item.TimeStamp = timestamp.Time
+		item.ModerationReason = moderationReason.String
+		item.Flags = flags
+		result.Items = append(result.Items, item)
+	}
+
+	if err = rows.Err(); err != nil {
+		return models.Page[models.ReviewQueueItem]{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.RowsErr, Err: err})
+	}
+
+	if len(result.Items) > request.PageSize {
+		result.Items = result.Items[:request.PageSize]
+		result.HasMore = true
+
+		last := result.Items[request.PageSize-1]
+		result.NextCursor, err = pagination.EncodeCursor(reviewQueueCursor{Type: last.ReviewType, ID: last.ID})
+		if err != nil {
+			return models.Page[models.ReviewQueueItem]{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.BindErr, Err: err})
+		}
+	}
+
+	if request.WithTotal {
+		var total int
+		if err = r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM (`+queueQuery+`) c;`).Scan(&total); err != nil {
+			return models.Page[models.ReviewQueueItem]{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ScanErr, Err: err})
+		}
+
+		result.Total = &total
+	}
+
+	return result, nil
+}
+
+// recountVotes пересчитывает денормализованные счётчики голосов и оценку полезности отзыва
+func recountVotes(ctx context.Context, tx *sqlx.Tx, table reviewTable, reviewID int) (models.ReviewVotes, error) {
+	var votes models.ReviewVotes
+	err := tx.QueryRowxContext(ctx, `SELECT COUNT(*) FILTER (WHERE helpful), COUNT(*) FILTER (WHERE NOT helpful)
+										FROM review_votes WHERE `+table.refColumn+` = $1;`, reviewID).
+		Scan(&votes.Helpful, &votes.Unhelpful)
+	if err != nil {
+		return models.ReviewVotes{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ScanErr, Err: err})
+	}
+
+	votes.Score = wilson.LowerBound(votes.Helpful, votes.Helpful+votes.Unhelpful, wilson.Z95)
+
+	_, err = tx.ExecContext(ctx, `UPDATE `+table.table+` SET helpful_count = $2, unhelpful_count = $3, helpfulness = $4
+									WHERE id = $1;`, reviewID, votes.Helpful, votes.Unhelpful, votes.Score)
+	if err != nil {
+		return models.ReviewVotes{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ExecErr, Err: err})
+	}
+
+	return votes, nil
 }
 
-func (r reviewRepo) UpdateOnRoute(ctx context.Context, reviewUpd models.ReviewUpdate) error {
-	updateRouteReviewQuery := `UPDATE route_reviews SET properties = $2, mark = $3 WHERE id = $1;`
-	return r.update(ctx, updateRouteReviewQuery, reviewUpd)
+// Vote голосовать можно только за опубликованный чужой отзыв, повторный голос заменяет прежний
+func (r reviewRepo) Vote(ctx context.Context, vote models.ReviewVote) (models.ReviewVotes, error) {
+	table, err := getReviewTable(vote.ReviewType)
+	if err != nil {
+		return models.ReviewVotes{}, err
+	}
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return models.ReviewVotes{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.TransactionErr, Err: err})
+	}
+
+	var authorID int
+	err = tx.QueryRowxContext(ctx, `SELECT author_id FROM `+table.table+` WHERE id = $1 AND status = 'published' FOR UPDATE;`,
+		vote.ReviewID).Scan(&authorID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.ReviewVotes{}, rollbackKeepErr(tx, customerr.ReviewNotFound)
+	}
+	if err != nil {
+		return models.ReviewVotes{}, rollbackWithErr(tx, customerr.ScanErr, err)
+	}
+	if authorID == vote.UserID {
+		return models.ReviewVotes{}, rollbackKeepErr(tx, fmt.Errorf("%w: own review can not be voted", customerr.BadInput))
+	}
+
+	_, err = tx.ExecContext(ctx, `INSERT INTO review_votes (`+table.refColumn+`, user_id, helpful) VALUES ($1, $2, $3)
+									ON CONFLICT (`+table.refColumn+`, user_id)
+									DO UPDATE SET helpful = EXCLUDED.helpful, updated_at = current_timestamp;`,
+		vote.ReviewID, vote.UserID, vote.Helpful)
+	if err != nil {
+		return models.ReviewVotes{}, rollbackWithErr(tx, customerr.ExecErr, err)
+	}
+
+	votes, err := recountVotes(ctx, tx, table, vote.ReviewID)
+	if err != nil {
+		return models.ReviewVotes{}, rollbackKeepErr(tx, err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return models.ReviewVotes{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.CommitErr, Err: err})
+	}
+
+	return votes, nil
+}
+
+func (r reviewRepo) DeleteVote(ctx context.Context, vote models.ReviewVoteDelete) (models.ReviewVotes, error) {
+	table, err := getReviewTable(vote.ReviewType)
+	if err != nil {
+		return models.ReviewVotes{}, err
+	}
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return models.ReviewVotes{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.TransactionErr, Err: err})
+	}
+
+	res, err := tx.ExecContext(ctx, `DELETE FROM review_votes WHERE `+table.refColumn+` = $1 AND user_id = $2;`,
+		vote.ReviewID, vote.UserID)
+	if err != nil {
+		return models.ReviewVotes{}, rollbackWithErr(tx, customerr.ExecErr, err)
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return models.ReviewVotes{}, rollbackWithErr(tx, customerr.RowsErr, err)
+	}
+	if count == 0 {
+		return models.ReviewVotes{}, rollbackKeepErr(tx, customerr.ReviewVoteNotFound)
+	}
+
+	votes, err := recountVotes(ctx, tx, table, vote.ReviewID)
+	if err != nil {
+		return models.ReviewVotes{}, rollbackKeepErr(tx, err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return models.ReviewVotes{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.CommitErr, Err: err})
+	}
+
+	return votes, nil
 }
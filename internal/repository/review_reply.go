@@ -0,0 +1,245 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/guregu/null/v5"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"mth/internal/models"
+	"mth/pkg/customerr"
+)
+
+// lockReviewForManager блокирует отзыв и проверяет, что userID - владелец или редактор сущности отзыва.
+// Возвращает автора отзыва
+func lockReviewForManager(ctx context.Context, tx *sqlx.Tx, reviewType string, table reviewTable, reviewID int,
+	userID int) (int, error) {
+	var authorID, entityID int
+	err := tx.QueryRowxContext(ctx, `SELECT author_id, `+table.entityColumn+` FROM `+table.table+` WHERE id = $1 FOR UPDATE;`,
+		reviewID).Scan(&authorID, &entityID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, customerr.ReviewNotFound
+	}
+	if err != nil {
+		return 0, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ScanErr, Err: err})
+	}
+
+	manager, err := isManager(ctx, tx, reviewType, entityID, userID)
+	if err != nil {
+		return 0, err
+	}
+	if !manager {
+		return 0, customerr.NotEntityManager
+	}
+
+	return authorID, nil
+}
+
+// UpsertReply у отзыва один ответ: первый ответ создаётся и автор отзыва получает уведомление,
+// следующие заменяют текст, прежняя версия уходит в историю
+func (r reviewRepo) UpsertReply(ctx context.Context, reply models.ReviewReplyUpsert) (models.ReviewReply, error) {
+	table, err := getReviewTable(reply.ReviewType)
+	if err != nil {
+		return models.ReviewReply{}, err
+	}
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return models.ReviewReply{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.TransactionErr, Err: err})
+	}
+
+	reviewAuthorID, err := lockReviewForManager(ctx, tx, reply.ReviewType, table, reply.ReviewID, reply.UserID)
+	if err != nil {
+		return models.ReviewReply{}, rollbackKeepErr(tx, err)
+	}
+
+	var current models.ReviewReply
+	var currentAuthorID null.Int
+	err = tx.QueryRowxContext(ctx, `SELECT id, author_id, text, created_at, updated_at FROM review_replies
+										WHERE `+table.refColumn+` = $1;`, reply.ReviewID).
+		Scan(&current.ID, &currentAuthorID, &current.Text, &current.CreatedAt, &current.UpdatedAt)
+
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		err = tx.QueryRowxContext(ctx, `INSERT INTO review_replies (`+table.refColumn+`, author_id, text) VALUES ($1, $2, $3)
+											RETURNING id, created_at, updated_at;`, reply.ReviewID, reply.UserID, reply.Text).
+			Scan(&current.ID, &current.CreatedAt, &current.UpdatedAt)
+		if err != nil {
+			return models.ReviewReply{}, rollbackWithErr(tx, customerr.ScanErr, err)
+		}
+
+		if reviewAuthorID != reply.UserID {
+			err = notify(ctx, tx, reviewAuthorID, models.NotificationReviewReply, map[string]interface{}{
+				"review_type": reply.ReviewType,
+				"review_id":   reply.ReviewID,
+				"reply_id":    current.ID,
+			})
+			if err != nil {
+				return models.ReviewReply{}, rollbackKeepErr(tx, err)
+			}
+		}
+	case err != nil:
+		return models.ReviewReply{}, rollbackWithErr(tx, customerr.ScanErr, err)
+	case current.Text != reply.Text:
+		_, err = tx.ExecContext(ctx, `INSERT INTO review_reply_history (reply_id, author_id, text, written_at) VALUES ($1, $2, $3, $4);`,
+			current.ID, currentAuthorID, current.Text, current.UpdatedAt)
+		if err != nil {
+			return models.ReviewReply{}, rollbackWithErr(tx, customerr.ExecErr, err)
+		}
+
+		err = tx.QueryRowxContext(ctx, `UPDATE review_replies SET text = $2, author_id = $3, updated_at = current_timestamp
+											WHERE id = $1 RETURNING updated_at;`, current.ID, reply.Text, reply.UserID).
+			Scan(&current.UpdatedAt)
+		if err != nil {
+			return models.ReviewReply{}, rollbackWithErr(tx, customerr.ScanErr, err)
+		}
+
+		current.Edited = true
+	default:
+		var edited bool
+		err = tx.QueryRowxContext(ctx, `SELECT EXISTS(SELECT 1 FROM review_reply_history WHERE reply_id = $1);`, current.ID).
+			Scan(&edited)
+		if err != nil {
+			return models.ReviewReply{}, rollbackWithErr(tx, customerr.ScanErr, err)
+		}
+
+		current.Edited = edited
+		reply.UserID = int(currentAuthorID.Int64)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return models.ReviewReply{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.CommitErr, Err: err})
+	}
+
+	current.AuthorID = reply.UserID
+	current.Text = reply.Text
+
+	return current, nil
+}
+
+// DeleteReply удаляет ответ вместе с историей правок
+func (r reviewRepo) DeleteReply(ctx context.Context, key models.ReviewReplyKey) error {
+	table, err := getReviewTable(key.ReviewType)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.TransactionErr, Err: err})
+	}
+
+	if _, err = lockReviewForManager(ctx, tx, key.ReviewType, table, key.ReviewID, key.UserID); err != nil {
+		return rollbackKeepErr(tx, err)
+	}
+
+	res, err := tx.ExecContext(ctx, `DELETE FROM review_replies WHERE `+table.refColumn+` = $1;`, key.ReviewID)
+	if err != nil {
+		return rollbackWithErr(tx, customerr.ExecErr, err)
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return rollbackWithErr(tx, customerr.RowsErr, err)
+	}
+	if count == 0 {
+		return rollbackKeepErr(tx, customerr.ReviewReplyNotFound)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.CommitErr, Err: err})
+	}
+
+	return nil
+}
+
+// GetReplyHistory прежние версии ответа от старых к новым, текущая версия в самом отзыве
+func (r reviewRepo) GetReplyHistory(ctx context.Context, reviewType string, reviewID int) ([]models.ReviewReplyRevision, error) {
+	table, err := getReviewTable(reviewType)
+	if err != nil {
+		return []models.ReviewReplyRevision{}, err
+	}
+
+	var replyID int
+	err = r.db.QueryRowContext(ctx, `SELECT id FROM review_replies WHERE `+table.refColumn+` = $1;`, reviewID).Scan(&replyID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return []models.ReviewReplyRevision{}, customerr.ReviewReplyNotFound
+	}
+	if err != nil {
+		return []models.ReviewReplyRevision{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ScanErr, Err: err})
+	}
+
+	rows, err := r.db.QueryContext(ctx, `SELECT author_id, text, written_at FROM review_reply_history
+											WHERE reply_id = $1 ORDER BY written_at, id;`, replyID)
+	if err != nil {
+		return []models.ReviewReplyRevision{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ExecErr, Err: err})
+	}
+	defer rows.Close()
+
+	revisions := []models.ReviewReplyRevision{}
+	for rows.Next() {
+		var revision models.ReviewReplyRevision
+		var authorID null.Int
+
+		if err = rows.Scan(&authorID, &revision.Text, &revision.WrittenAt); err != nil {
+			return []models.ReviewReplyRevision{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ScanErr, Err: err})
+		}
+
+		revision.AuthorID = int(authorID.Int64)
+		revisions = append(revisions, revision)
+	}
+
+	if err = rows.Err(); err != nil {
+		return []models.ReviewReplyRevision{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.RowsErr, Err: err})
+	}
+
+	return revisions, nil
+}
+
+// attachReplies подставляет официальные ответы в прочитанные отзывы одного типа
+func (r reviewRepo) attachReplies(ctx context.Context, table reviewTable, reviews []reviewGet) error {
+	if len(reviews) == 0 {
+		return nil
+	}
+
+	ids := make([]int, 0, len(reviews))
+	for _, review := range reviews {
+		ids = append(ids, review.ID)
+	}
+
+	query := `SELECT rr.` + table.refColumn + `, rr.id, rr.author_id, rr.text, rr.created_at, rr.updated_at,
+				EXISTS(SELECT 1 FROM review_reply_history h WHERE h.reply_id = rr.id)
+			FROM review_replies rr WHERE rr.` + table.refColumn + ` = ANY($1);`
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(ids))
+	if err != nil {
+		return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ExecErr, Err: err})
+	}
+	defer rows.Close()
+
+	replies := make(map[int]*models.ReviewReply)
+	for rows.Next() {
+		var reviewID int
+		var authorID null.Int
+		var reply models.ReviewReply
+
+		err = rows.Scan(&reviewID, &reply.ID, &authorID, &reply.Text, &reply.CreatedAt, &reply.UpdatedAt, &reply.Edited)
+		if err != nil {
+			return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ScanErr, Err: err})
+		}
+
+		reply.AuthorID = int(authorID.Int64)
+		replies[reviewID] = &reply
+	}
+
+	if err = rows.Err(); err != nil {
+		return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.RowsErr, Err: err})
+	}
+
+	for i := range reviews {
+		reviews[i].Reply = replies[reviews[i].ID]
+	}
+
+	return nil
+}
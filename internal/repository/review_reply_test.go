@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"mth/internal/models"
+	"mth/pkg/customerr"
+	"testing"
+)
+
+func countNotifications(t *testing.T, repo Notification, userID int) int {
+	page, err := repo.GetByUser(context.TODO(), models.NotificationsRequest{
+		UserID:      userID,
+		PageRequest: models.PageRequest{PageSize: 100},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return len(page.Items)
+}
+
+func TestReviewRepo_ReplyUpsertAndHistory(t *testing.T) {
+	db := testDB(t)
+	repo := InitReviewRepo(db, testMarkScale)
+	notifications := InitNotificationRepo(db)
+	placeID, userIDs := createRatedPlace(t, db, 2)
+	authorID, managerID := userIDs[0], userIDs[1]
+
+	reviewID, err := repo.CreateOnPlace(context.TODO(), models.PlaceReviewCreate{
+		PlaceID:    placeID,
+		ReviewBase: models.ReviewBase{AuthorID: authorID, Mark: 3},
+	}, models.ReviewStatusPublished)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = repo.GetReplyHistory(context.TODO(), models.ReviewTypePlace, reviewID); !errors.Is(err, customerr.ReviewReplyNotFound) {
+		t.Errorf("review without reply: expected ReviewReplyNotFound, got %v", err)
+	}
+
+	upsert := models.ReviewReplyUpsert{ReviewType: models.ReviewTypePlace, ReviewID: reviewID, UserID: managerID, Text: "Спасибо"}
+	if _, err = repo.UpsertReply(context.TODO(), upsert); !errors.Is(err, customerr.NotEntityManager) {
+		t.Errorf("reply of a stranger: expected NotEntityManager, got %v", err)
+	}
+
+	err = repo.SetManager(context.TODO(), models.EntityManager{
+		EntityType: models.ReviewTypePlace, EntityID: placeID, UserID: managerID, Role: models.ManagerRoleOwner,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reply, err := repo.UpsertReply(context.TODO(), upsert)
+	if err != nil || reply.Text != upsert.Text || reply.Edited {
+		t.Fatalf("expected new reply, got %+v, %v", reply, err)
+	}
+	if count := countNotifications(t, notifications, authorID); count != 1 {
+		t.Errorf("author must be notified about the first reply once, got %v notifications", count)
+	}
+
+	if reply, err = repo.UpsertReply(context.TODO(), upsert); err != nil || reply.Edited {
+		t.Errorf("same text must not create a revision, got %+v, %v", reply, err)
+	}
+
+	upsert.Text = "Спасибо, ждём снова"
+	if reply, err = repo.UpsertReply(context.TODO(), upsert); err != nil || !reply.Edited || reply.Text != upsert.Text {
+		t.Errorf("expected edited reply, got %+v, %v", reply, err)
+	}
+	if count := countNotifications(t, notifications, authorID); count != 1 {
+		t.Errorf("edits must not notify the author again, got %v notifications", count)
+	}
+
+	history, err := repo.GetReplyHistory(context.TODO(), models.ReviewTypePlace, reviewID)
+	if err != nil || len(history) != 1 || history[0].Text != "Спасибо" || history[0].AuthorID != managerID {
+		t.Errorf("expected previous version in history, got %+v, %v", history, err)
+	}
+}
+
+func TestNotificationRepo_MarkRead(t *testing.T) {
+	db := testDB(t)
+	repo := InitNotificationRepo(db)
+	_, userIDs := createRatedPlace(t, db, 2)
+	userID, otherID := userIDs[0], userIDs[1]
+
+	ids := make([]int, 3)
+	for i := range ids {
+		err := db.QueryRow(`INSERT INTO notifications (user_id, kind) VALUES ($1, $2) RETURNING id;`,
+			userID, models.NotificationReviewReply).Scan(&ids[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if count, err := repo.MarkRead(context.TODO(), models.NotificationsRead{UserID: otherID, IDs: ids}); err != nil || count != 0 {
+		t.Errorf("notifications of another user must stay unread, got %v, %v", count, err)
+	}
+	if count, err := repo.MarkRead(context.TODO(), models.NotificationsRead{UserID: userID, IDs: ids[:1]}); err != nil || count != 1 {
+		t.Errorf("expected one notification marked, got %v, %v", count, err)
+	}
+	if count, err := repo.MarkRead(context.TODO(), models.NotificationsRead{UserID: userID}); err != nil || count != 2 {
+		t.Errorf("empty ids must mark all remaining, got %v, %v", count, err)
+	}
+
+	unread, err := repo.GetByUser(context.TODO(), models.NotificationsRequest{
+		UserID:      userID,
+		UnreadOnly:  true,
+		PageRequest: models.PageRequest{PageSize: 10},
+	})
+	if err != nil || len(unread.Items) != 0 {
+		t.Errorf("expected no unread notifications, got %v, %v", unread.Items, err)
+	}
+}
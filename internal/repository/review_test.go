@@ -0,0 +1,271 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"math"
+	"mth/internal/models"
+	"mth/pkg/config"
+	"mth/pkg/customerr"
+	"mth/pkg/database"
+	"mth/pkg/pagination"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testMarkScale шкала по умолчанию из настроек REVIEW_MARK_*
+var testMarkScale = models.MarkScale{Min: 1, Max: 5, Step: 0.5}
+
+func TestRatingArgs(t *testing.T) {
+	args := ratingArgs(models.MarkScale{Min: 0.5, Max: 10}, 7)
+	if len(args) != 3 || args[0] != 7 || args[1] != 1 || args[2] != 10 {
+		t.Errorf("unexpected rating args %v", args)
+	}
+}
+
+// testDB база для тестов запросов, без неё тест пропускается
+func testDB(t *testing.T) *sqlx.DB {
+	config.InitConfig()
+	db := database.GetDB()
+	if err := db.Ping(); err != nil {
+		t.Skipf("database is not available: %v", err)
+	}
+
+	return db
+}
+
+func createRatedPlace(t *testing.T, db *sqlx.DB, authors int) (int, []int) {
+	var cityID, placeID int
+	if err := db.QueryRow(`INSERT INTO city (name) VALUES ('rating test') RETURNING id;`).Scan(&cityID); err != nil {
+		t.Fatalf("unable to create city, err: %v", err)
+	}
+
+	err := db.QueryRow(`INSERT INTO places (city_id, name, variety) VALUES ($1, $2, 'cafe') RETURNING id;`,
+		cityID, fmt.Sprintf("rating test %d", cityID)).Scan(&placeID)
+	if err != nil {
+		t.Fatalf("unable to create place, err: %v", err)
+	}
+
+	userIDs := make([]int, authors)
+	for i := range userIDs {
+		if err = db.QueryRow(`INSERT INTO users (properties) VALUES (null) RETURNING id;`).Scan(&userIDs[i]); err != nil {
+			t.Fatalf("unable to create user, err: %v", err)
+		}
+	}
+
+	t.Cleanup(func() {
+		_, _ = db.Exec(`DELETE FROM places_reviews WHERE place_id = $1;`, placeID)
+		_, _ = db.Exec(`DELETE FROM places WHERE id = $1;`, placeID)
+		_, _ = db.Exec(`DELETE FROM users WHERE id = ANY($1);`, pq.Array(userIDs))
+		_, _ = db.Exec(`DELETE FROM city WHERE id = $1;`, cityID)
+	})
+
+	return placeID, userIDs
+}
+
+func placeRating(t *testing.T, db *sqlx.DB, placeID int) models.Rating {
+	var average float32
+	var count int
+	var histogram []int64
+	err := db.QueryRow(`SELECT reviews_average, reviews_count, reviews_histogram FROM places WHERE id = $1;`, placeID).
+		Scan(&average, &count, pq.Array(&histogram))
+	if err != nil {
+		t.Fatalf("unable to get rating, err: %v", err)
+	}
+
+	return models.RatingFromRaw(average, count, histogram)
+}
+
+func TestReviewRepo_RatingAggregates(t *testing.T) {
+	db := testDB(t)
+	repo := InitReviewRepo(db, testMarkScale)
+
+	marks := []float32{5, 4.5, 1, 2}
+	placeID, userIDs := createRatedPlace(t, db, len(marks)+1)
+
+	for i, mark := range marks {
+		review := models.PlaceReviewCreate{PlaceID: placeID, ReviewBase: models.ReviewBase{AuthorID: userIDs[i], Mark: mark}}
+		if _, err := repo.CreateOnPlace(context.TODO(), review, models.ReviewStatusPublished); err != nil {
+			t.Fatalf("unable to create review, err: %v", err)
+		}
+	}
+
+	pending := models.PlaceReviewCreate{PlaceID: placeID, ReviewBase: models.ReviewBase{AuthorID: userIDs[len(marks)], Mark: 1}}
+	if _, err := repo.CreateOnPlace(context.TODO(), pending, models.ReviewStatusPending); err != nil {
+		t.Fatalf("unable to create review, err: %v", err)
+	}
+
+	rating := placeRating(t, db, placeID)
+	if rating.Count != 4 || math.Abs(float64(rating.Average)-3.125) > 1e-4 {
+		t.Errorf("unpublished reviews must not be counted, got %v", rating)
+	}
+
+	// 4.5 округляется до 5, гистограмма по целым значениям шкалы 1..5
+	expected := []int{1, 1, 0, 0, 2}
+	if fmt.Sprint(rating.Histogram) != fmt.Sprint(expected) {
+		t.Errorf("expected histogram %v, got %v", expected, rating.Histogram)
+	}
+}
+
+func TestReviewRepo_RatingConcurrentCreate(t *testing.T) {
+	db := testDB(t)
+	repo := InitReviewRepo(db, testMarkScale)
+
+	const authors = 8
+	placeID, userIDs := createRatedPlace(t, db, authors)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, authors)
+	for _, userID := range userIDs {
+		wg.Add(1)
+		go func(userID int) {
+			defer wg.Done()
+			review := models.PlaceReviewCreate{PlaceID: placeID, ReviewBase: models.ReviewBase{AuthorID: userID, Mark: 4}}
+			_, err := repo.CreateOnPlace(context.TODO(), review, models.ReviewStatusPublished)
+			errs <- err
+		}(userID)
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("unable to create review, err: %v", err)
+		}
+	}
+
+	if rating := placeRating(t, db, placeID); rating.Count != authors || rating.Histogram[3] != authors {
+		t.Errorf("concurrent reviews lost in aggregates, got %v", rating)
+	}
+}
+
+func TestReviewRepo_Verified(t *testing.T) {
+	db := testDB(t)
+	repo := InitReviewRepo(db, testMarkScale)
+	placeID, userIDs := createRatedPlace(t, db, 2)
+	visitor, stranger := userIDs[0], userIDs[1]
+
+	variety := fmt.Sprintf("verified test %d", placeID)
+	if _, err := db.Exec(`INSERT INTO varieties (name, require_verified_review) VALUES ($1, true);`, variety); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_, _ = db.Exec(`DELETE FROM users_place_checkin WHERE place_id = $1;`, placeID)
+		_, _ = db.Exec(`UPDATE places SET variety = 'cafe' WHERE id = $1;`, placeID)
+		_, _ = db.Exec(`DELETE FROM varieties WHERE name = $1;`, variety)
+	})
+
+	policy, err := repo.PlaceReviewPolicy(context.TODO(), placeID)
+	if err != nil || policy.RequireVerified {
+		t.Errorf("variety without settings must not require a visit, got %v, %v", policy, err)
+	}
+
+	if _, err = db.Exec(`UPDATE places SET variety = $2 WHERE id = $1;`, placeID, variety); err != nil {
+		t.Fatal(err)
+	}
+	if policy, err = repo.PlaceReviewPolicy(context.TODO(), placeID); err != nil || !policy.RequireVerified {
+		t.Errorf("expected visit to be required, got %v, %v", policy, err)
+	}
+
+	if _, err = db.Exec(`INSERT INTO users_place_checkin (user_id, place_id, timestamp) VALUES ($1, $2, now());`,
+		visitor, placeID); err != nil {
+		t.Fatal(err)
+	}
+
+	for userID, expected := range map[int]bool{visitor: true, stranger: false} {
+		verified, err := repo.IsVerified(context.TODO(), models.ReviewTypePlace, userID, placeID)
+		if err != nil || verified != expected {
+			t.Errorf("user %v: expected verified %v, got %v, %v", userID, expected, verified, err)
+		}
+	}
+
+	for _, userID := range userIDs {
+		review := models.PlaceReviewCreate{PlaceID: placeID, ReviewBase: models.ReviewBase{AuthorID: userID, Mark: 4}}
+		if _, err = repo.CreateOnPlace(context.TODO(), review, models.ReviewStatusPublished); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	request := models.ReviewListRequest{ID: placeID, SortBy: models.ReviewSortRecent, PageRequest: models.PageRequest{PageSize: 10}}
+	page, err := repo.GetByPlace(context.TODO(), request, 0)
+	if err != nil || len(page.Items) != 2 {
+		t.Fatalf("expected both reviews, got %v, %v", page.Items, err)
+	}
+
+	request.VerifiedOnly = true
+	page, err = repo.GetByPlace(context.TODO(), request, 0)
+	if err != nil || len(page.Items) != 1 || page.Items[0].AuthorID != visitor || !page.Items[0].Verified {
+		t.Errorf("expected only the verified review of the visitor, got %v, %v", page.Items, err)
+	}
+}
+
+// Аккаунт, постаревший между страницами, не должен менять порядок: иначе его отзыв выпадает из выдачи
+func TestReviewRepo_HelpfulCursorKeepsDownranking(t *testing.T) {
+	db := testDB(t)
+	repo := InitReviewRepo(db, testMarkScale)
+	placeID, userIDs := createRatedPlace(t, db, 2)
+	oldAuthor, newAuthor := userIDs[0], userIDs[1]
+
+	if _, err := db.Exec(`UPDATE users SET created_at = NULL WHERE id = $1;`, oldAuthor); err != nil {
+		t.Fatal(err)
+	}
+
+	// отзыв нового аккаунта создаётся позже: при равных ключах он шёл бы раньше курсора и терялся
+	for i, mark := range []float32{4, 5} {
+		review := models.PlaceReviewCreate{PlaceID: placeID, ReviewBase: models.ReviewBase{AuthorID: userIDs[i], Mark: mark}}
+		if _, err := repo.CreateOnPlace(context.TODO(), review, models.ReviewStatusPublished); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	request := models.ReviewListRequest{ID: placeID, SortBy: models.ReviewSortHelpful, PageRequest: models.PageRequest{PageSize: 1}}
+	first, err := repo.GetByPlace(context.TODO(), request, 7)
+	if err != nil || len(first.Items) != 1 || first.Items[0].AuthorID != oldAuthor || first.NextCursor == "" {
+		t.Fatalf("expected review of the old account first, got %+v, %v", first, err)
+	}
+
+	if _, err = db.Exec(`UPDATE users SET created_at = current_timestamp - INTERVAL '30 days' WHERE id = $1;`, newAuthor); err != nil {
+		t.Fatal(err)
+	}
+
+	request.Cursor = first.NextCursor
+	second, err := repo.GetByPlace(context.TODO(), request, 7)
+	if err != nil || len(second.Items) != 1 || second.Items[0].AuthorID != newAuthor || !second.Items[0].Downranked {
+		t.Errorf("expected downranked review on the second page, got %+v, %v", second, err)
+	}
+}
+
+func TestReviewListCursorRequiresEvaluationTime(t *testing.T) {
+	cursor, err := pagination.EncodeCursor(reviewListCursor{ID: 1, Sort: models.ReviewSortHelpful})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	request := models.ReviewListRequest{ID: 1, SortBy: models.ReviewSortHelpful, PageRequest: models.PageRequest{PageSize: 1, Cursor: cursor}}
+	if _, err = (reviewRepo{}).GetByPlace(context.TODO(), request, 7); !errors.Is(err, customerr.InvalidCursor) {
+		t.Errorf("cursor without evaluation time: expected InvalidCursor, got %v", err)
+	}
+}
+
+// Отзыв с несериализуемыми свойствами не должен оставлять открытую транзакцию с блокировкой места
+func TestReviewRepo_CreateBadPropertiesKeepsPlaceUnlocked(t *testing.T) {
+	db := testDB(t)
+	repo := InitReviewRepo(db, testMarkScale)
+	placeID, userIDs := createRatedPlace(t, db, 2)
+
+	bad := models.PlaceReviewCreate{PlaceID: placeID, ReviewBase: models.ReviewBase{AuthorID: userIDs[0], Mark: 4, Properties: make(chan int)}}
+	if _, err := repo.CreateOnPlace(context.TODO(), bad, models.ReviewStatusPublished); err == nil {
+		t.Fatal("expected marshal error")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	good := models.PlaceReviewCreate{PlaceID: placeID, ReviewBase: models.ReviewBase{AuthorID: userIDs[1], Mark: 4}}
+	if _, err := repo.CreateOnPlace(ctx, good, models.ReviewStatusPublished); err != nil {
+		t.Errorf("review after a failed one: unexpected error %v", err)
+	}
+}
@@ -3,12 +3,13 @@ package repository
 import (
 	"context"
 	"encoding/json"
+	"github.com/Masterminds/squirrel"
 	"github.com/guregu/null/v5"
 	"github.com/jmoiron/sqlx"
-	"github.com/spf13/viper"
+	"github.com/lib/pq"
 	"mth/internal/models"
-	"mth/pkg/config"
 	"mth/pkg/customerr"
+	"mth/pkg/pagination"
 )
 
 type routeRepo struct {
@@ -96,7 +97,8 @@ func contains[T models.Tag | models.PlaceIDWithPosition](slice []T, elem T) bool
 }
 
 func (r routeRepo) GetByID(ctx context.Context, routeID int) (models.RouteRaw, error) {
-	query := `SELECT r.id, r.city_id, r.price, r.name, r.properties, t.id, t.name, rp.place_id, rp.position  FROM routes r
+	query := `SELECT r.id, r.city_id, r.price, r.name, r.properties, r.reviews_average, r.reviews_count, r.reviews_histogram,
+       			r.reviews_criteria, r.popularity, t.id, t.name, rp.place_id, rp.position  FROM routes r
 				LEFT JOIN routes_places rp on r.id = rp.route_id
     			LEFT JOIN routes_tags rt on r.id = rt.route_id
 				LEFT JOIN tags t on rt.tag_id = t.id
@@ -113,12 +115,22 @@ func (r routeRepo) GetByID(ctx context.Context, routeID int) (models.RouteRaw, e
 	var tagName null.String
 	var placeID null.Int
 	var position null.Int
+	var reviewsAverage float32
+	var reviewsCount int
+	var reviewsHistogram []int64
+	var reviewsCriteria []byte
 	for rows.Next() {
-		err = rows.Scan(&route.ID, &route.CityID, &route.Price, &route.Name, &propertiesRow, &tagID, &tagName, &placeID, &position)
+		err = rows.Scan(&route.ID, &route.CityID, &route.Price, &route.Name, &propertiesRow, &reviewsAverage, &reviewsCount,
+			pq.Array(&reviewsHistogram), &reviewsCriteria, &route.Popularity, &tagID, &tagName, &placeID, &position)
 		if err != nil {
 			return models.RouteRaw{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ScanErr, Err: err})
 		}
 
+		route.Rating = models.RatingFromRaw(reviewsAverage, reviewsCount, reviewsHistogram)
+		if route.Rating.Criteria, err = parseCriteriaRating(reviewsCriteria); err != nil {
+			return models.RouteRaw{}, err
+		}
+
 		err = json.Unmarshal(propertiesRow, &route.Properties)
 		if err != nil {
 			return models.RouteRaw{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.BindErr, Err: err})
@@ -151,41 +163,144 @@ func (r routeRepo) GetByID(ctx context.Context, routeID int) (models.RouteRaw, e
 	return route, nil
 }
 
-func (r routeRepo) GetAll(ctx context.Context, page int) ([]models.RouteRaw, error) {
-	query := `SELECT r.id FROM routes r
-					ORDER BY r.id
-					LIMIT $1 OFFSET $2`
+// routeCursor Sort должен совпадать с сортировкой запроса
+type routeCursor struct {
+	ID         int     `json:"id"`
+	Popularity float64 `json:"p,omitempty"`
+	Generation int64   `json:"g,omitempty"`
+	Sort       string  `json:"s,omitempty"`
+}
+
+// GetAll page.PageSize уже ограничен сервисом, sortBy пустой или models.RouteSortByPopularity
+// GetAll tagGroups маршрут должен иметь хотя бы один тег из каждой группы
+func (r routeRepo) GetAll(ctx context.Context, filters models.RouteFilters, tagGroups [][]int) (models.Page[models.RouteRaw], error) {
+	page, sortBy := filters.PageRequest, filters.SortBy
+
+	var cursor *routeCursor
+	if page.Cursor != "" {
+		cursor = &routeCursor{}
+		if err := pagination.DecodeCursor(page.Cursor, cursor); err != nil {
+			return models.Page[models.RouteRaw]{}, err
+		}
+		if cursor.Sort != sortBy {
+			return models.Page[models.RouteRaw]{}, customerr.InvalidCursor
+		}
+	}
+
+	// продолжение выдачи по популярности идёт по значениям поколения, в котором она началась
+	popularity, generation := "popularity", int64(0)
+	continued := sortBy == models.RouteSortByPopularity && cursor != nil
+	if continued {
+		var err error
+		if popularity, generation, err = popularitySnapshot(ctx, r.db, cursor.Generation); err != nil {
+			return models.Page[models.RouteRaw]{}, err
+		}
+	}
+
+	psql := squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)
+	queryBuilder := psql.Select("r.id", "r."+popularity, popularityGenerationExpr).From("routes r")
+	countBuilder := psql.Select("COUNT(*)").From("routes r")
 
-	rows, err := r.db.QueryContext(ctx, query, viper.GetInt(config.PlacesOnPage), viper.GetInt(config.PlacesOnPage)*page)
+	for _, group := range tagGroups {
+		tagCondition := squirrel.Expr(
+			"EXISTS (SELECT 1 FROM routes_tags rt WHERE rt.route_id = r.id AND rt.tag_id = ANY(?))", pq.Array(group))
+		queryBuilder = queryBuilder.Where(tagCondition)
+		countBuilder = countBuilder.Where(tagCondition)
+	}
+
+	if sortBy == models.RouteSortByPopularity {
+		if cursor != nil {
+			queryBuilder = queryBuilder.Where(squirrel.Or{
+				squirrel.Lt{"r." + popularity: cursor.Popularity},
+				squirrel.And{squirrel.Eq{"r." + popularity: cursor.Popularity}, squirrel.Gt{"r.id": cursor.ID}},
+			})
+		}
+		queryBuilder = queryBuilder.OrderBy("r."+popularity+" DESC", "r.id")
+	} else {
+		if cursor != nil {
+			queryBuilder = queryBuilder.Where(squirrel.Gt{"r.id": cursor.ID})
+		}
+		queryBuilder = queryBuilder.OrderBy("r.id")
+	}
+
+	query, args, err := queryBuilder.Limit(uint64(page.PageSize + 1)).ToSql()
 	if err != nil {
-		return []models.RouteRaw{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ExecErr, Err: err})
+		return models.Page[models.RouteRaw]{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.QueryBuild, Err: err})
 	}
 
-	var routeIDs []int
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return models.Page[models.RouteRaw]{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ExecErr, Err: err})
+	}
+	defer rows.Close()
+
+	var keys []routeCursor
+	var rowGeneration int64
 	for rows.Next() {
-		var routeID null.Int
+		key := routeCursor{Sort: sortBy}
 
-		err = rows.Scan(&routeID)
+		err = rows.Scan(&key.ID, &key.Popularity, &rowGeneration)
 		if err != nil {
-			return []models.RouteRaw{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ScanErr, Err: err})
+			return models.Page[models.RouteRaw]{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ScanErr, Err: err})
 		}
 
-		if routeID.Valid {
-			routeIDs = append(routeIDs, int(routeID.Int64))
+		if sortBy == models.RouteSortByPopularity {
+			key.Generation = rowGeneration
+			if continued {
+				key.Generation = cursor.Generation
+			}
 		}
+
+		keys = append(keys, key)
 	}
 
-	var routes []models.RouteRaw
-	if len(routeIDs) > 0 {
-		for _, routeID := range routeIDs {
-			route, err := r.GetByID(ctx, routeID)
-			if err != nil {
-				return []models.RouteRaw{}, err
-			}
+	err = rows.Err()
+	if err != nil {
+		return models.Page[models.RouteRaw]{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.RowsErr, Err: err})
+	}
+
+	// пересчёт успел закоммитить между выбором колонки и запросом
+	if continued && len(keys) > 0 && rowGeneration != generation {
+		return models.Page[models.RouteRaw]{}, customerr.InvalidCursor
+	}
+
+	result := models.Page[models.RouteRaw]{}
+
+	if len(keys) > page.PageSize {
+		keys = keys[:page.PageSize]
+		result.HasMore = true
+
+		result.NextCursor, err = pagination.EncodeCursor(keys[page.PageSize-1])
+		if err != nil {
+			return models.Page[models.RouteRaw]{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.BindErr, Err: err})
+		}
+	}
 
-			routes = append(routes, route)
+	result.Items = make([]models.RouteRaw, 0, len(keys))
+	for _, key := range keys {
+		routeID := key.ID
+		route, err := r.GetByID(ctx, routeID)
+		if err != nil {
+			return models.Page[models.RouteRaw]{}, err
 		}
+
+		result.Items = append(result.Items, route)
+	}
+
+	if page.WithTotal {
+		countQuery, countArgs, err := countBuilder.ToSql()
+		if err != nil {
+			return models.Page[models.RouteRaw]{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.QueryBuild, Err: err})
+		}
+
+		var total int
+		err = r.db.QueryRowContext(ctx, countQuery, countArgs...).Scan(&total)
+		if err != nil {
+			return models.Page[models.RouteRaw]{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ScanErr, Err: err})
+		}
+
+		result.Total = &total
 	}
 
-	return routes, nil
+	return result, nil
 }
@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"github.com/jmoiron/sqlx"
+	"mth/internal/models"
+	"mth/pkg/customerr"
+	"time"
+)
+
+type statsRepo struct {
+	db *sqlx.DB
+}
+
+func InitStatsRepo(db *sqlx.DB) Stats {
+	return statsRepo{
+		db: db,
+	}
+}
+
+// areaCondition $1 - город, $2 - район или 0 для всего города
+const areaCondition = `p.city_id = $1 AND ($2 = 0 OR p.district_id = $2)`
+
+// GetArea в Timeline попадают только интервалы, где были отметки, пустые дополняет сервис
+func (s statsRepo) GetArea(ctx context.Context, cityID, districtID int, window models.StatsWindow) (models.AreaStats, error) {
+	stats := models.AreaStats{
+		CityID:          cityID,
+		DistrictID:      districtID,
+		PlacesByVariety: map[string]int{},
+		Timeline:        []models.StatsBucket{},
+	}
+
+	varietyQuery := `SELECT COALESCE(p.variety, ''), COUNT(*) FROM places p
+						WHERE ` + areaCondition + ` AND NOT p.archived
+						GROUP BY 1;`
+
+	rows, err := s.db.QueryContext(ctx, varietyQuery, cityID, districtID)
+	if err != nil {
+		return models.AreaStats{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ExecErr, Err: err})
+	}
+
+	for rows.Next() {
+		var variety string
+		var count int
+		if err = rows.Scan(&variety, &count); err != nil {
+			rows.Close()
+			return models.AreaStats{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ScanErr, Err: err})
+		}
+
+		stats.PlacesByVariety[variety] = count
+	}
+	rows.Close()
+
+	if err = rows.Err(); err != nil {
+		return models.AreaStats{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.RowsErr, Err: err})
+	}
+
+	routesQuery := `SELECT COUNT(DISTINCT rp.route_id) FROM routes_places rp
+						JOIN places p ON p.id = rp.place_id
+						WHERE ` + areaCondition + `;`
+
+	err = s.db.QueryRowContext(ctx, routesQuery, cityID, districtID).Scan(&stats.RoutesPassing)
+	if err != nil {
+		return models.AreaStats{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ScanErr, Err: err})
+	}
+
+	checkInsQuery := `SELECT COUNT(*), COUNT(DISTINCT c.user_id) FROM users_place_checkin c
+						JOIN places p ON p.id = c.place_id
+						WHERE ` + areaCondition + ` AND c.timestamp >= $3 AND c.timestamp < $4;`
+
+	err = s.db.QueryRowContext(ctx, checkInsQuery, cityID, districtID, window.From, window.To).
+		Scan(&stats.CheckIns, &stats.UniqueVisitors)
+	if err != nil {
+		return models.AreaStats{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ScanErr, Err: err})
+	}
+
+	timelineQuery := `SELECT date_trunc($5, c.timestamp) AS bucket, COUNT(*), COUNT(DISTINCT c.user_id)
+						FROM users_place_checkin c
+						JOIN places p ON p.id = c.place_id
+						WHERE ` + areaCondition + ` AND c.timestamp >= $3 AND c.timestamp < $4
+						GROUP BY bucket
+						ORDER BY bucket;`
+
+	rows, err = s.db.QueryContext(ctx, timelineQuery, cityID, districtID, window.From, window.To, window.Granularity)
+	if err != nil {
+		return models.AreaStats{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ExecErr, Err: err})
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var bucket models.StatsBucket
+		var start time.Time
+		if err = rows.Scan(&start, &bucket.CheckIns, &bucket.Visitors); err != nil {
+			return models.AreaStats{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ScanErr, Err: err})
+		}
+
+		bucket.Start = start.UTC()
+		stats.Timeline = append(stats.Timeline, bucket)
+	}
+
+	if err = rows.Err(); err != nil {
+		return models.AreaStats{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.RowsErr, Err: err})
+	}
+
+	ratingQuery := `SELECT COUNT(*), COALESCE(AVG(r.mark), 0) FROM places_reviews r
+						JOIN places p ON p.id = r.place_id
+						WHERE ` + areaCondition + ` AND r.status = 'published' AND r.timestamp >= $3 AND r.timestamp < $4;`
+
+	err = s.db.QueryRowContext(ctx, ratingQuery, cityID, districtID, window.From, window.To).
+		Scan(&stats.ReviewsCount, &stats.AverageRating)
+	if err != nil {
+		return models.AreaStats{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ScanErr, Err: err})
+	}
+
+	return stats, nil
+}
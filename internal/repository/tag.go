@@ -2,7 +2,11 @@ package repository
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"github.com/guregu/null/v5"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"mth/internal/models"
 	"mth/pkg/customerr"
 )
@@ -17,25 +21,129 @@ func InitTagRepo(db *sqlx.DB) Tag {
 	}
 }
 
+// rollbackKeepErr откатывает транзакцию и возвращает err без изменений, чтобы sentinel-ошибки проверялись через errors.Is
+func rollbackKeepErr(tx *sqlx.Tx, err error) error {
+	if rbErr := tx.Rollback(); rbErr != nil {
+		return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.RollbackErr, Err: rbErr})
+	}
+
+	return err
+}
+
+// lockTagHierarchy сериализует изменения иерархии тегов до конца транзакции. Блокировки отдельных тегов мало:
+// перевешивания X под Y и U под V не пересекаются по строкам, но вместе замыкают цикл через уже существующие связи
+func lockTagHierarchy(ctx context.Context, tx *sqlx.Tx) error {
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtext('tags_hierarchy'));`); err != nil {
+		return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ExecErr, Err: err})
+	}
+
+	return nil
+}
+
+// checkTagName название не должно совпадать без учёта регистра ни с тегом, ни с синонимом.
+// Тег excludeTagID не учитывается, чтобы при переименовании можно было сменить регистр
+func (t tagRepo) checkTagName(ctx context.Context, tx *sqlx.Tx, name string, excludeTagID int) error {
+	takenQuery := `SELECT EXISTS(SELECT 1 FROM tags WHERE lower(name) = lower($1) AND id <> $2)
+					OR EXISTS(SELECT 1 FROM tag_synonyms WHERE lower(name) = lower($1));`
+
+	var taken bool
+	if err := tx.QueryRowxContext(ctx, takenQuery, name, excludeTagID).Scan(&taken); err != nil {
+		return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ScanErr, Err: err})
+	}
+	if taken {
+		return customerr.TagNameTaken
+	}
+
+	return nil
+}
+
+// checkParent родитель должен существовать и не быть потомком тега tagID, tagID 0 для нового тега
+func (t tagRepo) checkParent(ctx context.Context, tx *sqlx.Tx, tagID, parentID int) error {
+	if parentID == 0 {
+		return nil
+	}
+
+	ancestorsQuery := `WITH RECURSIVE ancestors AS (
+							SELECT id, parent_id FROM tags WHERE id = $1
+							UNION
+							SELECT t.id, t.parent_id FROM tags t JOIN ancestors a ON t.id = a.parent_id
+						)
+						SELECT id FROM ancestors;`
+
+	rows, err := tx.QueryContext(ctx, ancestorsQuery, parentID)
+	if err != nil {
+		return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ExecErr, Err: err})
+	}
+	defer rows.Close()
+
+	var found bool
+	var cycle bool
+	for rows.Next() {
+		var id int
+		if err = rows.Scan(&id); err != nil {
+			return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ScanErr, Err: err})
+		}
+
+		found = true
+		if id == tagID {
+			cycle = true
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.RowsErr, Err: err})
+	}
+
+	if !found {
+		return customerr.TagNotFound
+	}
+	if cycle {
+		return customerr.TagCycle
+	}
+
+	return nil
+}
+
+func (t tagRepo) checkCategory(ctx context.Context, tx *sqlx.Tx, categoryID int) error {
+	if categoryID == 0 {
+		return nil
+	}
+
+	var exists bool
+	err := tx.QueryRowxContext(ctx, `SELECT EXISTS(SELECT 1 FROM tag_categories WHERE id = $1);`, categoryID).Scan(&exists)
+	if err != nil {
+		return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ScanErr, Err: err})
+	}
+	if !exists {
+		return customerr.TagCategoryNotFound
+	}
+
+	return nil
+}
+
 func (t tagRepo) Create(ctx context.Context, tag models.TagCreate) (int, error) {
 	tx, err := t.db.Beginx()
 	if err != nil {
 		return 0, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.TransactionErr, Err: err})
 	}
 
-	createTagQuery := `INSERT INTO tags (name) VALUES ($1) RETURNING id;`
+	if err = t.checkTagName(ctx, tx, tag.Name, 0); err != nil {
+		return 0, rollbackKeepErr(tx, err)
+	}
+	if err = t.checkParent(ctx, tx, 0, tag.ParentID); err != nil {
+		return 0, rollbackKeepErr(tx, err)
+	}
+	if err = t.checkCategory(ctx, tx, tag.CategoryID); err != nil {
+		return 0, rollbackKeepErr(tx, err)
+	}
+
+	createTagQuery := `INSERT INTO tags (name, parent_id, category_id) VALUES ($1, $2, $3) RETURNING id;`
 
 	var createdTagID int
-	err = tx.QueryRowxContext(ctx, createTagQuery, tag.Name).Scan(&createdTagID)
+	err = tx.QueryRowxContext(ctx, createTagQuery, tag.Name, null.NewInt(int64(tag.ParentID), tag.ParentID != 0),
+		null.NewInt(int64(tag.CategoryID), tag.CategoryID != 0)).Scan(&createdTagID)
 	if err != nil {
-		if rbErr := tx.Rollback(); rbErr != nil {
-			return 0, customerr.ErrNormalizer(
-				customerr.ErrorPair{Message: customerr.ScanErr, Err: err},
-				customerr.ErrorPair{Message: customerr.RollbackErr, Err: rbErr},
-			)
-		}
-
-		return 0, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ScanErr, Err: err})
+		return 0, rollbackWithErr(tx, customerr.ScanErr, err)
 	}
 
 	if err = tx.Commit(); err != nil {
@@ -45,29 +153,429 @@ func (t tagRepo) Create(ctx context.Context, tag models.TagCreate) (int, error)
 	return createdTagID, nil
 }
 
-func (t tagRepo) GetAll(ctx context.Context) ([]models.Tag, error) {
-	getAllTagsQuery := `SELECT id, name FROM tags;`
+func (t tagRepo) GetAll(ctx context.Context) ([]models.TagDetails, error) {
+	getAllTagsQuery := `SELECT t.id, t.name, COALESCE(t.parent_id, 0), COALESCE(t.category_id, 0),
+							ARRAY(SELECT s.name FROM tag_synonyms s WHERE s.tag_id = t.id ORDER BY s.name)
+						FROM tags t ORDER BY t.id;`
 
 	rows, err := t.db.QueryContext(ctx, getAllTagsQuery)
 	if err != nil {
-		return []models.Tag{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ExecErr, Err: err})
+		return []models.TagDetails{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ExecErr, Err: err})
 	}
+	defer rows.Close()
 
-	var tags []models.Tag
-
+	tags := []models.TagDetails{}
 	for rows.Next() {
-		var tag models.Tag
-		err := rows.Scan(&tag.ID, &tag.Name)
+		var tag models.TagDetails
+		var synonyms pq.StringArray
+
+		err = rows.Scan(&tag.ID, &tag.Name, &tag.ParentID, &tag.CategoryID, &synonyms)
 		if err != nil {
-			return []models.Tag{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ScanErr, Err: nil})
+			return []models.TagDetails{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ScanErr, Err: err})
 		}
+
+		tag.Synonyms = synonyms
 		tags = append(tags, tag)
 	}
 
 	err = rows.Err()
 	if err != nil {
-		return []models.Tag{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.RowsErr, Err: nil})
+		return []models.TagDetails{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.RowsErr, Err: err})
 	}
-	
+
 	return tags, nil
 }
+
+// SetHierarchy перевешивает тег, родитель не может быть самим тегом или его потомком
+func (t tagRepo) SetHierarchy(ctx context.Context, hierarchy models.TagHierarchy) error {
+	tx, err := t.db.Beginx()
+	if err != nil {
+		return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.TransactionErr, Err: err})
+	}
+
+	// предки проверяются уже под блокировкой иерархии и видят все закоммиченные до неё перевешивания
+	if err = lockTagHierarchy(ctx, tx); err != nil {
+		return rollbackKeepErr(tx, err)
+	}
+
+	var tagID int
+	err = tx.QueryRowxContext(ctx, `SELECT id FROM tags WHERE id = $1 FOR UPDATE;`, hierarchy.ID).Scan(&tagID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return rollbackKeepErr(tx, customerr.TagNotFound)
+		}
+
+		return rollbackWithErr(tx, customerr.ScanErr, err)
+	}
+
+	if err = t.checkParent(ctx, tx, hierarchy.ID, hierarchy.ParentID); err != nil {
+		return rollbackKeepErr(tx, err)
+	}
+	if err = t.checkCategory(ctx, tx, hierarchy.CategoryID); err != nil {
+		return rollbackKeepErr(tx, err)
+	}
+
+	_, err = tx.ExecContext(ctx, `UPDATE tags SET parent_id = $2, category_id = $3 WHERE id = $1;`, hierarchy.ID,
+		null.NewInt(int64(hierarchy.ParentID), hierarchy.ParentID != 0),
+		null.NewInt(int64(hierarchy.CategoryID), hierarchy.CategoryID != 0))
+	if err != nil {
+		return rollbackWithErr(tx, customerr.ExecErr, err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.CommitErr, Err: err})
+	}
+
+	return nil
+}
+
+func (t tagRepo) AddSynonym(ctx context.Context, synonym models.TagSynonym) error {
+	tx, err := t.db.Beginx()
+	if err != nil {
+		return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.TransactionErr, Err: err})
+	}
+
+	var exists bool
+	err = tx.QueryRowxContext(ctx, `SELECT EXISTS(SELECT 1 FROM tags WHERE id = $1);`, synonym.TagID).Scan(&exists)
+	if err != nil {
+		return rollbackWithErr(tx, customerr.ScanErr, err)
+	}
+	if !exists {
+		return rollbackKeepErr(tx, customerr.TagNotFound)
+	}
+
+	if err = t.checkTagName(ctx, tx, synonym.Name, 0); err != nil {
+		return rollbackKeepErr(tx, err)
+	}
+
+	_, err = tx.ExecContext(ctx, `INSERT INTO tag_synonyms (tag_id, name) VALUES ($1, $2);`, synonym.TagID, synonym.Name)
+	if err != nil {
+		return rollbackWithErr(tx, customerr.ExecErr, err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.CommitErr, Err: err})
+	}
+
+	return nil
+}
+
+func (t tagRepo) DeleteSynonym(ctx context.Context, name string) error {
+	res, err := t.db.ExecContext(ctx, `DELETE FROM tag_synonyms WHERE lower(name) = lower($1);`, name)
+	if err != nil {
+		return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ExecErr, Err: err})
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.RowsErr, Err: err})
+	}
+	if count != 1 {
+		return customerr.TagNotFound
+	}
+
+	return nil
+}
+
+func (t tagRepo) CreateCategory(ctx context.Context, category models.TagCategoryCreate) (int, error) {
+	var id int
+	err := t.db.QueryRowxContext(ctx, `INSERT INTO tag_categories (name) VALUES ($1) RETURNING id;`, category.Name).Scan(&id)
+	if err != nil {
+		return 0, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ScanErr, Err: err})
+	}
+
+	return id, nil
+}
+
+func (t tagRepo) GetCategories(ctx context.Context) ([]models.TagCategory, error) {
+	rows, err := t.db.QueryContext(ctx, `SELECT id, name FROM tag_categories ORDER BY name;`)
+	if err != nil {
+		return []models.TagCategory{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ExecErr, Err: err})
+	}
+	defer rows.Close()
+
+	categories := []models.TagCategory{}
+	for rows.Next() {
+		var category models.TagCategory
+		if err = rows.Scan(&category.ID, &category.Name); err != nil {
+			return []models.TagCategory{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ScanErr, Err: err})
+		}
+
+		categories = append(categories, category)
+	}
+
+	if err = rows.Err(); err != nil {
+		return []models.TagCategory{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.RowsErr, Err: err})
+	}
+
+	return categories, nil
+}
+
+// DeleteCategory теги категории остаются без категории
+func (t tagRepo) DeleteCategory(ctx context.Context, categoryID int) error {
+	res, err := t.db.ExecContext(ctx, `DELETE FROM tag_categories WHERE id = $1;`, categoryID)
+	if err != nil {
+		return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ExecErr, Err: err})
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.RowsErr, Err: err})
+	}
+	if count != 1 {
+		return customerr.TagCategoryNotFound
+	}
+
+	return nil
+}
+
+// resolveTagQuery тег по id ($1) или по названию/синониму ($2), с $3 вместе со всеми потомками
+const resolveTagQuery = `WITH RECURSIVE roots AS (
+							SELECT id FROM tags WHERE id = $1 OR lower(name) = lower($2)
+							UNION
+							SELECT tag_id FROM tag_synonyms WHERE lower(name) = lower($2)
+						), tree AS (
+							SELECT id FROM roots
+							UNION
+							SELECT t.id FROM tags t JOIN tree ON t.parent_id = tree.id WHERE $3
+						)
+						SELECT id FROM tree ORDER BY id;`
+
+func (t tagRepo) resolveTag(ctx context.Context, tagID int, name string, withDescendants bool) ([]int, error) {
+	rows, err := t.db.QueryContext(ctx, resolveTagQuery, tagID, name, withDescendants)
+	if err != nil {
+		return nil, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ExecErr, Err: err})
+	}
+	defer rows.Close()
+
+	ids := []int{}
+	for rows.Next() {
+		var id int
+		if err = rows.Scan(&id); err != nil {
+			return nil, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ScanErr, Err: err})
+		}
+
+		ids = append(ids, id)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.RowsErr, Err: err})
+	}
+
+	return ids, nil
+}
+
+// ResolveFilter группа id на каждый запрошенный тег. Неизвестный тег даёт пустую группу,
+// с которой ничего не совпадает, а не пропадает из фильтра
+func (t tagRepo) ResolveFilter(ctx context.Context, filter models.TagFilter) ([][]int, error) {
+	groups := make([][]int, 0, len(filter.TagIDs)+len(filter.Names))
+
+	for _, tagID := range filter.TagIDs {
+		ids, err := t.resolveTag(ctx, tagID, "", filter.IncludeDescendants)
+		if err != nil {
+			return nil, err
+		}
+
+		groups = append(groups, ids)
+	}
+
+	for _, name := range filter.Names {
+		ids, err := t.resolveTag(ctx, 0, name, filter.IncludeDescendants)
+		if err != nil {
+			return nil, err
+		}
+
+		groups = append(groups, ids)
+	}
+
+	return groups, nil
+}
+
+// Rename новое название не должно совпадать с другим тегом или синонимом, смена регистра разрешена
+func (t tagRepo) Rename(ctx context.Context, rename models.TagRename) error {
+	tx, err := t.db.Beginx()
+	if err != nil {
+		return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.TransactionErr, Err: err})
+	}
+
+	if err = t.checkTagName(ctx, tx, rename.Name, rename.ID); err != nil {
+		return rollbackKeepErr(tx, err)
+	}
+
+	res, err := tx.ExecContext(ctx, `UPDATE tags SET name = $2 WHERE id = $1;`, rename.ID, rename.Name)
+	if err != nil {
+		return rollbackWithErr(tx, customerr.ExecErr, err)
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return rollbackWithErr(tx, customerr.RowsErr, err)
+	}
+	if count != 1 {
+		return rollbackKeepErr(tx, customerr.TagNotFound)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.CommitErr, Err: err})
+	}
+
+	return nil
+}
+
+// Delete без cascade возвращает customerr.TagInUse, если тег висит на местах, маршрутах или у него есть дочерние теги.
+// С cascade связи удаляются, а дочерние теги переходят к родителю удаляемого
+func (t tagRepo) Delete(ctx context.Context, tagID int, cascade bool) (models.TagDeleteResult, error) {
+	tx, err := t.db.Beginx()
+	if err != nil {
+		return models.TagDeleteResult{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.TransactionErr, Err: err})
+	}
+
+	var parentID null.Int
+	err = tx.QueryRowxContext(ctx, `SELECT parent_id FROM tags WHERE id = $1 FOR UPDATE;`, tagID).Scan(&parentID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.TagDeleteResult{}, rollbackKeepErr(tx, customerr.TagNotFound)
+		}
+
+		return models.TagDeleteResult{}, rollbackWithErr(tx, customerr.ScanErr, err)
+	}
+
+	usageQuery := `SELECT (SELECT COUNT(*) FROM places_tags WHERE tag_id = $1),
+						(SELECT COUNT(*) FROM routes_tags WHERE tag_id = $1),
+						(SELECT COUNT(*) FROM tags WHERE parent_id = $1);`
+
+	var result models.TagDeleteResult
+	var children int
+	err = tx.QueryRowxContext(ctx, usageQuery, tagID).Scan(&result.Places, &result.Routes, &children)
+	if err != nil {
+		return models.TagDeleteResult{}, rollbackWithErr(tx, customerr.ScanErr, err)
+	}
+
+	if !cascade && result.Places+result.Routes+children > 0 {
+		return models.TagDeleteResult{}, rollbackKeepErr(tx, customerr.TagInUse)
+	}
+
+	_, err = tx.ExecContext(ctx, `UPDATE tags SET parent_id = $2 WHERE parent_id = $1;`, tagID, parentID)
+	if err != nil {
+		return models.TagDeleteResult{}, rollbackWithErr(tx, customerr.ExecErr, err)
+	}
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM translations WHERE entity_type = 'tag' AND entity_id = $1;`, tagID)
+	if err != nil {
+		return models.TagDeleteResult{}, rollbackWithErr(tx, customerr.ExecErr, err)
+	}
+
+	// связи с местами и маршрутами и синонимы удаляются каскадно
+	if _, err = tx.ExecContext(ctx, `DELETE FROM tags WHERE id = $1;`, tagID); err != nil {
+		return models.TagDeleteResult{}, rollbackWithErr(tx, customerr.ExecErr, err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return models.TagDeleteResult{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.CommitErr, Err: err})
+	}
+
+	return result, nil
+}
+
+// tagMergeSteps $1 целевой тег, $2 вливаемый. Связь, которая нарушила бы UNIQUE (tag_id, place_id/route_id),
+// не переносится, а удаляется: у места или маршрута целевой тег уже есть. Переводы целевого тега приоритетнее
+func tagMergeSteps(result *models.TagMergeResult) []mergeStep {
+	var ignored int
+
+	return []mergeStep{
+		{`UPDATE places_tags s SET tag_id = $1 WHERE s.tag_id = $2
+			AND NOT EXISTS (SELECT 1 FROM places_tags t WHERE t.tag_id = $1 AND t.place_id = s.place_id);`, &result.PlacesMoved},
+		{`DELETE FROM places_tags WHERE tag_id = $2;`, &result.PlacesDropped},
+
+		{`UPDATE routes_tags s SET tag_id = $1 WHERE s.tag_id = $2
+			AND NOT EXISTS (SELECT 1 FROM routes_tags t WHERE t.tag_id = $1 AND t.route_id = s.route_id);`, &result.RoutesMoved},
+		{`DELETE FROM routes_tags WHERE tag_id = $2;`, &result.RoutesDropped},
+
+		{`UPDATE tags SET parent_id = $1 WHERE parent_id = $2;`, &result.Children},
+		{`UPDATE tag_synonyms SET tag_id = $1 WHERE tag_id = $2;`, &result.Synonyms},
+
+		{`INSERT INTO translations (entity_type, entity_id, lang, field, value)
+			SELECT entity_type, $1, lang, field, value FROM translations WHERE entity_type = 'tag' AND entity_id = $2
+			ON CONFLICT (entity_type, entity_id, lang, field) DO NOTHING;`, &ignored},
+		{`DELETE FROM translations WHERE entity_type = 'tag' AND entity_id = $2;`, &ignored},
+	}
+}
+
+// Merge вливает тег в другой одной транзакцией: связи, дочерние теги и синонимы переходят к целевому тегу,
+// название вливаемого тега становится его синонимом, чтобы старые фильтры продолжали работать.
+// Целевой тег не может быть потомком вливаемого
+func (t tagRepo) Merge(ctx context.Context, request models.TagMergeRequest) (models.TagMergeResult, error) {
+	tx, err := t.db.Beginx()
+	if err != nil {
+		return models.TagMergeResult{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.TransactionErr, Err: err})
+	}
+
+	if err = lockTagHierarchy(ctx, tx); err != nil {
+		return models.TagMergeResult{}, rollbackKeepErr(tx, err)
+	}
+
+	rows, err := tx.QueryContext(ctx, `SELECT id, name FROM tags WHERE id IN ($1, $2) ORDER BY id FOR UPDATE;`,
+		request.SourceID, request.TargetID)
+	if err != nil {
+		return models.TagMergeResult{}, rollbackWithErr(tx, customerr.ExecErr, err)
+	}
+
+	var found int
+	var sourceName string
+	for rows.Next() {
+		var id int
+		var name string
+		if err = rows.Scan(&id, &name); err != nil {
+			rows.Close()
+			return models.TagMergeResult{}, rollbackWithErr(tx, customerr.ScanErr, err)
+		}
+
+		found++
+		if id == request.SourceID {
+			sourceName = name
+		}
+	}
+	rows.Close()
+
+	if err = rows.Err(); err != nil {
+		return models.TagMergeResult{}, rollbackWithErr(tx, customerr.RowsErr, err)
+	}
+	if found != 2 {
+		return models.TagMergeResult{}, rollbackKeepErr(tx, customerr.TagNotFound)
+	}
+
+	// вливаемый тег не должен оказаться среди предков целевого, иначе перевешивание детей замкнёт цикл
+	if err = t.checkParent(ctx, tx, request.SourceID, request.TargetID); err != nil {
+		return models.TagMergeResult{}, rollbackKeepErr(tx, err)
+	}
+
+	var result models.TagMergeResult
+	for _, step := range tagMergeSteps(&result) {
+		res, err := tx.ExecContext(ctx, step.query, request.TargetID, request.SourceID)
+		if err != nil {
+			return models.TagMergeResult{}, rollbackWithErr(tx, customerr.ExecErr, err)
+		}
+
+		count, err := res.RowsAffected()
+		if err != nil {
+			return models.TagMergeResult{}, rollbackWithErr(tx, customerr.RowsErr, err)
+		}
+
+		*step.count += int(count)
+	}
+
+	if _, err = tx.ExecContext(ctx, `DELETE FROM tags WHERE id = $1;`, request.SourceID); err != nil {
+		return models.TagMergeResult{}, rollbackWithErr(tx, customerr.ExecErr, err)
+	}
+
+	_, err = tx.ExecContext(ctx, `INSERT INTO tag_synonyms (tag_id, name) VALUES ($1, $2);`, request.TargetID, sourceName)
+	if err != nil {
+		return models.TagMergeResult{}, rollbackWithErr(tx, customerr.ExecErr, err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return models.TagMergeResult{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.CommitErr, Err: err})
+	}
+
+	return result, nil
+}
@@ -0,0 +1,254 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"mth/internal/models"
+	"mth/pkg/customerr"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// createTestTags теги с уникальными для запуска названиями, удаляются после теста
+func createTestTags(t *testing.T, db *sqlx.DB, repo Tag, names ...string) []int {
+	suffix := time.Now().UnixNano()
+
+	ids := make([]int, 0, len(names))
+	t.Cleanup(func() {
+		_, _ = db.Exec(`UPDATE tags SET parent_id = NULL WHERE id = ANY($1);`, pq.Array(ids))
+		_, _ = db.Exec(`DELETE FROM tags WHERE id = ANY($1);`, pq.Array(ids))
+	})
+
+	for _, name := range names {
+		id, err := repo.Create(context.TODO(), models.TagCreate{Name: fmt.Sprintf("%v %d", name, suffix)})
+		if err != nil {
+			t.Fatalf("unable to create tag %v, err: %v", name, err)
+		}
+
+		ids = append(ids, id)
+	}
+
+	return ids
+}
+
+func setParent(repo Tag, tagID, parentID int) error {
+	return repo.SetHierarchy(context.TODO(), models.TagHierarchy{ID: tagID, ParentID: parentID})
+}
+
+func tagName(t *testing.T, db *sqlx.DB, tagID int) string {
+	var name string
+	if err := db.QueryRow(`SELECT name FROM tags WHERE id = $1;`, tagID).Scan(&name); err != nil {
+		t.Fatal(err)
+	}
+
+	return name
+}
+
+func TestTagRepo_SetHierarchyRejectsCycle(t *testing.T) {
+	db := testDB(t)
+	repo := InitTagRepo(db)
+	ids := createTestTags(t, db, repo, "a", "b", "c")
+	a, b, c := ids[0], ids[1], ids[2]
+
+	if err := setParent(repo, b, a); err != nil {
+		t.Fatal(err)
+	}
+	if err := setParent(repo, c, b); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := setParent(repo, a, c); !errors.Is(err, customerr.TagCycle) {
+		t.Errorf("a under its grandchild: expected TagCycle, got %v", err)
+	}
+	if err := setParent(repo, a, a); !errors.Is(err, customerr.TagCycle) {
+		t.Errorf("a under itself: expected TagCycle, got %v", err)
+	}
+	if err := setParent(repo, a, -1); !errors.Is(err, customerr.TagNotFound) {
+		t.Errorf("unknown parent: expected TagNotFound, got %v", err)
+	}
+}
+
+// Перевешивания x под y и u под v не пересекаются по строкам, но при y под u и v под x замыкают цикл
+func TestTagRepo_SetHierarchyConcurrentCycle(t *testing.T) {
+	db := testDB(t)
+	repo := InitTagRepo(db)
+
+	for i := 0; i < 10; i++ {
+		ids := createTestTags(t, db, repo, "x", "y", "u", "v")
+		x, y, u, v := ids[0], ids[1], ids[2], ids[3]
+
+		if err := setParent(repo, y, u); err != nil {
+			t.Fatal(err)
+		}
+		if err := setParent(repo, v, x); err != nil {
+			t.Fatal(err)
+		}
+
+		var wg sync.WaitGroup
+		errs := make([]error, 2)
+		for j, pair := range [][2]int{{x, y}, {u, v}} {
+			wg.Add(1)
+			go func(j int, tagID, parentID int) {
+				defer wg.Done()
+				errs[j] = setParent(repo, tagID, parentID)
+			}(j, pair[0], pair[1])
+		}
+		wg.Wait()
+
+		if errs[0] == nil && errs[1] == nil {
+			t.Fatalf("both moves committed, hierarchy has a cycle")
+		}
+		for _, err := range errs {
+			if err != nil && !errors.Is(err, customerr.TagCycle) {
+				t.Fatalf("expected TagCycle, got %v", err)
+			}
+		}
+	}
+}
+
+func TestTagRepo_ResolveFilter(t *testing.T) {
+	db := testDB(t)
+	repo := InitTagRepo(db)
+	ids := createTestTags(t, db, repo, "Еда", "Кофе", "Эспрессо")
+	food, coffee, espresso := ids[0], ids[1], ids[2]
+
+	if err := setParent(repo, coffee, food); err != nil {
+		t.Fatal(err)
+	}
+	if err := setParent(repo, espresso, coffee); err != nil {
+		t.Fatal(err)
+	}
+
+	coffeeName := tagName(t, db, coffee)
+
+	synonym := fmt.Sprintf("кава %d", time.Now().UnixNano())
+	if err := repo.AddSynonym(context.TODO(), models.TagSynonym{TagID: coffee, Name: synonym}); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = repo.DeleteSynonym(context.TODO(), synonym) })
+
+	groups, err := repo.ResolveFilter(context.TODO(), models.TagFilter{
+		TagIDs:             []int{food},
+		Names:              []string{strings.ToUpper(coffeeName), synonym, "нет такого тега"},
+		IncludeDescendants: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := fmt.Sprint([][]int{{food, coffee, espresso}, {coffee, espresso}, {coffee, espresso}, {}})
+	if fmt.Sprint(groups) != expected {
+		t.Errorf("expected groups %v, got %v", expected, groups)
+	}
+
+	groups, err = repo.ResolveFilter(context.TODO(), models.TagFilter{TagIDs: []int{food}})
+	if err != nil || fmt.Sprint(groups) != fmt.Sprint([][]int{{food}}) {
+		t.Errorf("without descendants only the tag itself is expected, got %v, %v", groups, err)
+	}
+}
+
+func TestTagRepo_RenameIntoTakenName(t *testing.T) {
+	db := testDB(t)
+	repo := InitTagRepo(db)
+	ids := createTestTags(t, db, repo, "парк", "сквер")
+	park, square := ids[0], ids[1]
+
+	synonym := fmt.Sprintf("сад %d", time.Now().UnixNano())
+	if err := repo.AddSynonym(context.TODO(), models.TagSynonym{TagID: park, Name: synonym}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{strings.ToUpper(tagName(t, db, park)), strings.ToUpper(synonym)} {
+		err := repo.Rename(context.TODO(), models.TagRename{ID: square, Name: name})
+		if !errors.Is(err, customerr.TagNameTaken) {
+			t.Errorf("rename into %q: expected TagNameTaken, got %v", name, err)
+		}
+	}
+
+	upper := strings.ToUpper(tagName(t, db, square))
+	if err := repo.Rename(context.TODO(), models.TagRename{ID: square, Name: upper}); err != nil {
+		t.Errorf("changing case of own name must be allowed, got %v", err)
+	}
+
+	if err := repo.Rename(context.TODO(), models.TagRename{ID: -1, Name: "нет такого тега"}); !errors.Is(err, customerr.TagNotFound) {
+		t.Errorf("expected TagNotFound, got %v", err)
+	}
+}
+
+func TestTagRepo_MergeKeepsSynonyms(t *testing.T) {
+	db := testDB(t)
+	repo := InitTagRepo(db)
+	ids := createTestTags(t, db, repo, "кафе", "кофейня", "веранда")
+	target, source, child := ids[0], ids[1], ids[2]
+
+	if err := setParent(repo, child, source); err != nil {
+		t.Fatal(err)
+	}
+
+	sourceName := tagName(t, db, source)
+	synonym := fmt.Sprintf("кофешоп %d", time.Now().UnixNano())
+	if err := repo.AddSynonym(context.TODO(), models.TagSynonym{TagID: source, Name: synonym}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := repo.Merge(context.TODO(), models.TagMergeRequest{SourceID: child, TargetID: source}); !errors.Is(err, customerr.TagCycle) {
+		t.Errorf("merge into own child: expected TagCycle, got %v", err)
+	}
+
+	result, err := repo.Merge(context.TODO(), models.TagMergeRequest{SourceID: source, TargetID: target})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Synonyms != 1 || result.Children != 1 {
+		t.Errorf("expected one synonym and one child moved, got %+v", result)
+	}
+
+	groups, err := repo.ResolveFilter(context.TODO(), models.TagFilter{Names: []string{sourceName, synonym}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(groups) != fmt.Sprint([][]int{{target}, {target}}) {
+		t.Errorf("old name and synonym of merged tag must resolve to target, got %v", groups)
+	}
+
+	var parentID int
+	if err = db.QueryRow(`SELECT parent_id FROM tags WHERE id = $1;`, child).Scan(&parentID); err != nil || parentID != target {
+		t.Errorf("child must move to target, got %v, %v", parentID, err)
+	}
+}
+
+func TestTagRepo_DeleteWithChildren(t *testing.T) {
+	db := testDB(t)
+	repo := InitTagRepo(db)
+	ids := createTestTags(t, db, repo, "природа", "лес", "ельник")
+	root, middle, leaf := ids[0], ids[1], ids[2]
+
+	if err := setParent(repo, middle, root); err != nil {
+		t.Fatal(err)
+	}
+	if err := setParent(repo, leaf, middle); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := repo.Delete(context.TODO(), middle, false); !errors.Is(err, customerr.TagInUse) {
+		t.Errorf("tag with children without cascade: expected TagInUse, got %v", err)
+	}
+
+	if _, err := repo.Delete(context.TODO(), middle, true); err != nil {
+		t.Fatal(err)
+	}
+
+	var parentID int
+	if err := db.QueryRow(`SELECT parent_id FROM tags WHERE id = $1;`, leaf).Scan(&parentID); err != nil || parentID != root {
+		t.Errorf("children of deleted tag must move to its parent, got %v, %v", parentID, err)
+	}
+
+	if _, err := repo.Delete(context.TODO(), middle, true); !errors.Is(err, customerr.TagNotFound) {
+		t.Errorf("expected TagNotFound for deleted tag, got %v", err)
+	}
+}
@@ -0,0 +1,215 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"github.com/guregu/null/v5"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"mth/internal/models"
+	"mth/pkg/customerr"
+	"mth/pkg/pagination"
+)
+
+type translationRepo struct {
+	db *sqlx.DB
+}
+
+func InitTranslationRepo(db *sqlx.DB) Translation {
+	return translationRepo{
+		db: db,
+	}
+}
+
+// translationSources таблица сущности и условие на строки, которые нужно переводить
+var translationSources = map[string]struct {
+	table     string
+	condition string
+}{
+	models.TranslationEntityTag:   {table: "tags", condition: "TRUE"},
+	models.TranslationEntityPlace: {table: "places", condition: "NOT e.archived"},
+	models.TranslationEntityRoute: {table: "routes", condition: "TRUE"},
+}
+
+// Upsert заменяет перевод поля, если он уже есть. Тип сущности и поле проверены сервисом
+func (t translationRepo) Upsert(ctx context.Context, translation models.TranslationUpsert) error {
+	source, ok := translationSources[translation.EntityType]
+	if !ok {
+		return fmt.Errorf("%w: unknown entity type %v", customerr.BadInput, translation.EntityType)
+	}
+
+	tx, err := t.db.Beginx()
+	if err != nil {
+		return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.TransactionErr, Err: err})
+	}
+
+	var exists bool
+	err = tx.QueryRowxContext(ctx, `SELECT EXISTS(SELECT 1 FROM `+source.table+` WHERE id = $1);`, translation.EntityID).
+		Scan(&exists)
+	if err != nil {
+		return rollbackWithErr(tx, customerr.ScanErr, err)
+	}
+	if !exists {
+		return rollbackKeepErr(tx, customerr.TranslationEntityNotFound)
+	}
+
+	upsertQuery := `INSERT INTO translations (entity_type, entity_id, lang, field, value) VALUES ($1, $2, $3, $4, $5)
+					ON CONFLICT (entity_type, entity_id, lang, field)
+					DO UPDATE SET value = EXCLUDED.value, updated_at = current_timestamp;`
+
+	_, err = tx.ExecContext(ctx, upsertQuery, translation.EntityType, translation.EntityID, translation.Lang,
+		translation.Field, translation.Value)
+	if err != nil {
+		return rollbackWithErr(tx, customerr.ExecErr, err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.CommitErr, Err: err})
+	}
+
+	return nil
+}
+
+func (t translationRepo) Delete(ctx context.Context, key models.TranslationKey) error {
+	deleteQuery := `DELETE FROM translations WHERE entity_type = $1 AND entity_id = $2 AND lang = $3 AND field = $4;`
+
+	res, err := t.db.ExecContext(ctx, deleteQuery, key.EntityType, key.EntityID, key.Lang, key.Field)
+	if err != nil {
+		return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ExecErr, Err: err})
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.RowsErr, Err: err})
+	}
+	if count != 1 {
+		return customerr.TranslationNotFound
+	}
+
+	return nil
+}
+
+func (t translationRepo) queryTranslations(ctx context.Context, query string, args ...any) ([]models.Translation, error) {
+	rows, err := t.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return []models.Translation{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ExecErr, Err: err})
+	}
+	defer rows.Close()
+
+	translations := []models.Translation{}
+	for rows.Next() {
+		var translation models.Translation
+
+		err = rows.Scan(&translation.EntityType, &translation.EntityID, &translation.Lang, &translation.Field,
+			&translation.Value, &translation.UpdatedAt)
+		if err != nil {
+			return []models.Translation{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ScanErr, Err: err})
+		}
+
+		translations = append(translations, translation)
+	}
+
+	if err = rows.Err(); err != nil {
+		return []models.Translation{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.RowsErr, Err: err})
+	}
+
+	return translations, nil
+}
+
+func (t translationRepo) GetByEntity(ctx context.Context, entityType string, entityID int) ([]models.Translation, error) {
+	query := `SELECT entity_type, entity_id, lang, field, value, updated_at FROM translations
+				WHERE entity_type = $1 AND entity_id = $2
+				ORDER BY lang, field;`
+
+	return t.queryTranslations(ctx, query, entityType, entityID)
+}
+
+// GetForEntities переводы сущностей entityIDs только на языки langs
+func (t translationRepo) GetForEntities(ctx context.Context, entityType string, entityIDs []int, langs []string) ([]models.Translation, error) {
+	if len(entityIDs) == 0 || len(langs) == 0 {
+		return []models.Translation{}, nil
+	}
+
+	query := `SELECT entity_type, entity_id, lang, field, value, updated_at FROM translations
+				WHERE entity_type = $1 AND entity_id = ANY($2) AND lang = ANY($3);`
+
+	return t.queryTranslations(ctx, query, entityType, pq.Array(entityIDs), pq.Array(langs))
+}
+
+// GetMissing сущности без перевода хотя бы одного из fields на request.Lang, порядок по id.
+// request.PageSize уже ограничен сервисом
+func (t translationRepo) GetMissing(ctx context.Context, request models.MissingTranslationsRequest,
+	fields []string) (models.Page[models.MissingTranslation], error) {
+	source, ok := translationSources[request.EntityType]
+	if !ok {
+		return models.Page[models.MissingTranslation]{}, fmt.Errorf("%w: unknown entity type %v", customerr.BadInput, request.EntityType)
+	}
+
+	var cursor idCursor
+	if err := pagination.DecodeCursor(request.Cursor, &cursor); err != nil {
+		return models.Page[models.MissingTranslation]{}, err
+	}
+
+	missingQuery := `SELECT id, name, missing FROM (
+						SELECT e.id, e.name, ARRAY(
+							SELECT f FROM unnest($3::VARCHAR[]) f
+							WHERE NOT EXISTS(SELECT 1 FROM translations tr
+								WHERE tr.entity_type = $1 AND tr.entity_id = e.id AND tr.lang = $2 AND tr.field = f)
+						) AS missing
+						FROM ` + source.table + ` e
+						WHERE ` + source.condition + `
+					) m
+					WHERE cardinality(missing) > 0`
+
+	query := missingQuery + ` AND id > $4 ORDER BY id LIMIT $5;`
+
+	rows, err := t.db.QueryContext(ctx, query, request.EntityType, request.Lang, pq.Array(fields), cursor.ID,
+		request.PageSize+1)
+	if err != nil {
+		return models.Page[models.MissingTranslation]{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ExecErr, Err: err})
+	}
+	defer rows.Close()
+
+	result := models.Page[models.MissingTranslation]{Items: []models.MissingTranslation{}}
+	for rows.Next() {
+		var missing models.MissingTranslation
+		var name null.String
+		var missingFields pq.StringArray
+
+		if err = rows.Scan(&missing.EntityID, &name, &missingFields); err != nil {
+			return models.Page[models.MissingTranslation]{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ScanErr, Err: err})
+		}
+
+		missing.Name = name.String
+		missing.Missing = missingFields
+		result.Items = append(result.Items, missing)
+	}
+
+	if err = rows.Err(); err != nil {
+		return models.Page[models.MissingTranslation]{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.RowsErr, Err: err})
+	}
+
+	if len(result.Items) > request.PageSize {
+		result.Items = result.Items[:request.PageSize]
+		result.HasMore = true
+
+		result.NextCursor, err = pagination.EncodeCursor(idCursor{ID: result.Items[request.PageSize-1].EntityID})
+		if err != nil {
+			return models.Page[models.MissingTranslation]{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.BindErr, Err: err})
+		}
+	}
+
+	if request.WithTotal {
+		var total int
+		countQuery := `SELECT COUNT(*) FROM (` + missingQuery + `) c;`
+
+		err = t.db.QueryRowContext(ctx, countQuery, request.EntityType, request.Lang, pq.Array(fields)).Scan(&total)
+		if err != nil {
+			return models.Page[models.MissingTranslation]{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ScanErr, Err: err})
+		}
+
+		result.Total = &total
+	}
+
+	return result, nil
+}
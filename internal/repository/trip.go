@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
 	"fmt"
 	"github.com/jmoiron/sqlx"
@@ -487,3 +488,51 @@ func (t tripRepo) DeletePlace(ctx context.Context, tripID, placeID int) error {
 
 	return nil
 }
+
+// GetStops места и маршруты поездки по дням и позициям, отметка в месте учитывается только в даты поездки
+func (t tripRepo) GetStops(ctx context.Context, tripID int) ([]models.TripStop, error) {
+	query := `SELECT tp.day, tp.position, 'place', p.id, p.name, c.timestamp
+				FROM trip_places tp
+				JOIN trips t ON t.id = tp.trip_id
+				JOIN places p ON p.id = tp.place_id
+				LEFT JOIN users_place_checkin c ON c.place_id = p.id AND c.user_id = t.user_id
+					AND c.timestamp >= t.date_start AND c.timestamp < t.date_end + 1
+				WHERE tp.trip_id = $1
+			UNION ALL
+			SELECT tr.day, tr.position, 'route', r.id, r.name, NULL
+				FROM trip_routes tr
+				JOIN routes r ON r.id = tr.route_id
+				WHERE tr.trip_id = $1
+			ORDER BY 1, 2, 3;`
+
+	rows, err := t.db.QueryContext(ctx, query, tripID)
+	if err != nil {
+		return []models.TripStop{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ExecErr, Err: err})
+	}
+	defer rows.Close()
+
+	stops := []models.TripStop{}
+	for rows.Next() {
+		var stop models.TripStop
+		var name sql.NullString
+		var checkedInAt sql.NullTime
+
+		err = rows.Scan(&stop.Day, &stop.Position, &stop.EntityType, &stop.EntityID, &name, &checkedInAt)
+		if err != nil {
+			return []models.TripStop{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ScanErr, Err: err})
+		}
+
+		stop.Name = name.String
+		if checkedInAt.Valid {
+			stop.CheckedInAt = &checkedInAt.Time
+		}
+
+		stops = append(stops, stop)
+	}
+
+	if err = rows.Err(); err != nil {
+		return []models.TripStop{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.RowsErr, Err: err})
+	}
+
+	return stops, nil
+}
@@ -0,0 +1,225 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"mth/internal/models"
+	"mth/pkg/customerr"
+)
+
+type varietyRepo struct {
+	db *sqlx.DB
+}
+
+func InitVarietyRepo(db *sqlx.DB) Variety {
+	return varietyRepo{
+		db: db,
+	}
+}
+
+func (v varietyRepo) Create(ctx context.Context, variety models.VarietyBase) (int, error) {
+	jsonDisplayNames, err := json.Marshal(variety.DisplayNames)
+	if err != nil {
+		return 0, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.BindErr, Err: err})
+	}
+
+	tx, err := v.db.Beginx()
+	if err != nil {
+		return 0, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.TransactionErr, Err: err})
+	}
+
+	createVarietyQuery := `INSERT INTO varieties (name, display_names, icon_key, checkinable, geofence_radius, require_verified_review,
+								review_criteria)
+							VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id;`
+
+	var createdID int
+	err = tx.QueryRowxContext(ctx, createVarietyQuery, variety.Name, jsonDisplayNames, variety.IconKey,
+		variety.Checkinable, variety.GeofenceRadius, variety.RequireVerifiedReview, pq.Array(variety.ReviewCriteria)).Scan(&createdID)
+	if err != nil {
+		return 0, rollbackWithErr(tx, customerr.ScanErr, err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return 0, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.CommitErr, Err: err})
+	}
+
+	return createdID, nil
+}
+
+func scanVariety(row interface{ Scan(dest ...any) error }) (models.Variety, error) {
+	var variety models.Variety
+	var displayNamesRaw []byte
+
+	err := row.Scan(&variety.ID, &variety.Name, &displayNamesRaw, &variety.IconKey, &variety.Checkinable, &variety.GeofenceRadius,
+		&variety.RequireVerifiedReview, pq.Array(&variety.ReviewCriteria))
+	if err != nil {
+		return models.Variety{}, err
+	}
+
+	err = json.Unmarshal(displayNamesRaw, &variety.DisplayNames)
+	if err != nil {
+		return models.Variety{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.BindErr, Err: err})
+	}
+
+	return variety, nil
+}
+
+// GetCheckinable флаг checkinable только для перечисленных видов, отсутствующих в таблице видов нет и в ответе
+func (v varietyRepo) GetCheckinable(ctx context.Context, names []string) (map[string]bool, error) {
+	checkinable := make(map[string]bool, len(names))
+	if len(names) == 0 {
+		return checkinable, nil
+	}
+
+	rows, err := v.db.QueryContext(ctx, `SELECT name, checkinable FROM varieties WHERE name = ANY($1);`, pq.Array(names))
+	if err != nil {
+		return nil, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ExecErr, Err: err})
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var name string
+		var value bool
+		if err = rows.Scan(&name, &value); err != nil {
+			return nil, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ScanErr, Err: err})
+		}
+
+		checkinable[name] = value
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.RowsErr, Err: err})
+	}
+
+	return checkinable, nil
+}
+
+func (v varietyRepo) GetAll(ctx context.Context) ([]models.Variety, error) {
+	query := `SELECT id, name, display_names, icon_key, checkinable, geofence_radius, require_verified_review, review_criteria FROM varieties ORDER BY name;`
+
+	rows, err := v.db.QueryContext(ctx, query)
+	if err != nil {
+		return []models.Variety{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ExecErr, Err: err})
+	}
+	defer rows.Close()
+
+	varieties := []models.Variety{}
+	for rows.Next() {
+		variety, err := scanVariety(rows)
+		if err != nil {
+			return []models.Variety{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ScanErr, Err: err})
+		}
+
+		varieties = append(varieties, variety)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return []models.Variety{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.RowsErr, Err: err})
+	}
+
+	return varieties, nil
+}
+
+// GetByName возвращает customerr.UnknownVariety, если такого вида нет
+func (v varietyRepo) GetByName(ctx context.Context, name string) (models.Variety, error) {
+	query := `SELECT id, name, display_names, icon_key, checkinable, geofence_radius, require_verified_review, review_criteria FROM varieties WHERE name = $1;`
+
+	variety, err := scanVariety(v.db.QueryRowContext(ctx, query, name))
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.Variety{}, customerr.UnknownVariety
+	}
+	if err != nil {
+		return models.Variety{}, customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.ScanErr, Err: err})
+	}
+
+	return variety, nil
+}
+
+// Update при переименовании вида места переносятся на новое имя в той же транзакции
+func (v varietyRepo) Update(ctx context.Context, variety models.Variety) error {
+	jsonDisplayNames, err := json.Marshal(variety.DisplayNames)
+	if err != nil {
+		return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.BindErr, Err: err})
+	}
+
+	tx, err := v.db.Beginx()
+	if err != nil {
+		return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.TransactionErr, Err: err})
+	}
+
+	var oldName string
+	err = tx.QueryRowxContext(ctx, `SELECT name FROM varieties WHERE id = $1 FOR UPDATE;`, variety.ID).Scan(&oldName)
+	if err != nil {
+		return rollbackWithErr(tx, customerr.ScanErr, err)
+	}
+
+	updateVarietyQuery := `UPDATE varieties SET name = $2, display_names = $3, icon_key = $4, checkinable = $5, geofence_radius = $6,
+							require_verified_review = $7, review_criteria = $8 WHERE id = $1;`
+
+	_, err = tx.ExecContext(ctx, updateVarietyQuery, variety.ID, variety.Name, jsonDisplayNames, variety.IconKey,
+		variety.Checkinable, variety.GeofenceRadius, variety.RequireVerifiedReview, pq.Array(variety.ReviewCriteria))
+	if err != nil {
+		return rollbackWithErr(tx, customerr.ExecErr, err)
+	}
+
+	if oldName != variety.Name {
+		_, err = tx.ExecContext(ctx, `UPDATE places SET variety = $2 WHERE variety = $1;`, oldName, variety.Name)
+		if err != nil {
+			return rollbackWithErr(tx, customerr.ExecErr, err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.CommitErr, Err: err})
+	}
+
+	return nil
+}
+
+// Delete возвращает customerr.VarietyInUse, если есть места этого вида
+func (v varietyRepo) Delete(ctx context.Context, varietyID int) error {
+	tx, err := v.db.Beginx()
+	if err != nil {
+		return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.TransactionErr, Err: err})
+	}
+
+	usageQuery := `SELECT COUNT(*) FROM places p JOIN varieties v ON p.variety = v.name WHERE v.id = $1;`
+
+	var usage int
+	if err = tx.QueryRowxContext(ctx, usageQuery, varietyID).Scan(&usage); err != nil {
+		return rollbackWithErr(tx, customerr.ScanErr, err)
+	}
+
+	if usage > 0 {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.RollbackErr, Err: rbErr})
+		}
+
+		return customerr.VarietyInUse
+	}
+
+	res, err := tx.ExecContext(ctx, `DELETE FROM varieties WHERE id = $1;`, varietyID)
+	if err != nil {
+		return rollbackWithErr(tx, customerr.ExecErr, err)
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return rollbackWithErr(tx, customerr.RowsErr, err)
+	}
+	if count != 1 {
+		return rollbackWithErr(tx, customerr.RowsErr, fmt.Errorf(customerr.CountErr, count))
+	}
+
+	if err = tx.Commit(); err != nil {
+		return customerr.ErrNormalizer(customerr.ErrorPair{Message: customerr.CommitErr, Err: err})
+	}
+
+	return nil
+}
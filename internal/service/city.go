@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"mth/internal/models"
+	"mth/internal/repository"
+	"mth/pkg/customerr"
+	"mth/pkg/log"
+	"regexp"
+	"strings"
+	"time"
+
+	_ "time/tzdata"
+)
+
+type cityService struct {
+	cityRepo repository.City
+	logger   *log.Logs
+}
+
+func InitCityService(cityRepo repository.City, logger *log.Logs) City {
+	return cityService{
+		cityRepo: cityRepo,
+		logger:   logger,
+	}
+}
+
+var currencyCode = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// validateCity часовой пояс проверяется по базе IANA, встроенной в бинарник
+func validateCity(city models.CityBase) error {
+	if strings.TrimSpace(city.Name) == "" {
+		return fmt.Errorf("%w: city name is required", customerr.BadInput)
+	}
+
+	if city.TimeZone == "" {
+		return fmt.Errorf("%w: time zone is required", customerr.BadInput)
+	}
+	if _, err := time.LoadLocation(city.TimeZone); err != nil {
+		return fmt.Errorf("%w: unknown time zone %q", customerr.BadInput, city.TimeZone)
+	}
+
+	if !currencyCode.MatchString(city.Currency) {
+		return fmt.Errorf("%w: currency must be ISO 4217 code, got %q", customerr.BadInput, city.Currency)
+	}
+
+	if city.Center != nil && !city.Center.Valid() {
+		return fmt.Errorf("%w: center coordinates out of range", customerr.BadInput)
+	}
+
+	return nil
+}
+
+func (c cityService) Create(ctx context.Context, city models.CityBase) (int, error) {
+	if err := validateCity(city); err != nil {
+		return 0, err
+	}
+
+	id, err := c.cityRepo.Create(ctx, city)
+	if err != nil {
+		c.logger.Error(err.Error())
+		return 0, err
+	}
+
+	return id, nil
+}
+
+func (c cityService) GetAll(ctx context.Context) ([]models.City, error) {
+	cities, err := c.cityRepo.GetAll(ctx)
+	if err != nil {
+		c.logger.Error(err.Error())
+		return []models.City{}, err
+	}
+
+	return cities, nil
+}
+
+func (c cityService) GetByID(ctx context.Context, cityID int) (models.City, error) {
+	city, err := c.cityRepo.GetByID(ctx, cityID)
+	if err != nil {
+		c.logger.Error(err.Error())
+		return models.City{}, err
+	}
+
+	return city, nil
+}
+
+func (c cityService) Update(ctx context.Context, city models.City) error {
+	if err := validateCity(city.CityBase); err != nil {
+		return err
+	}
+
+	err := c.cityRepo.Update(ctx, city)
+	if err != nil {
+		c.logger.Error(err.Error())
+		return err
+	}
+
+	return nil
+}
+
+func (c cityService) Delete(ctx context.Context, cityID int) error {
+	err := c.cityRepo.Delete(ctx, cityID)
+	if err != nil {
+		c.logger.Error(err.Error())
+		return err
+	}
+
+	return nil
+}
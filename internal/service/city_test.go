@@ -0,0 +1,31 @@
+package service
+
+import (
+	"errors"
+	"mth/internal/models"
+	"mth/pkg/customerr"
+	"testing"
+)
+
+func TestValidateCity(t *testing.T) {
+	valid := models.CityBase{Name: "Москва", Country: "RU", TimeZone: "Europe/Moscow", Currency: "RUB",
+		Center: &models.Point{Lat: 55.75, Lon: 37.62}}
+	if err := validateCity(valid); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	invalid := map[string]func(city *models.CityBase){
+		"empty name":    func(city *models.CityBase) { city.Name = " " },
+		"bad time zone": func(city *models.CityBase) { city.TimeZone = "Moscow" },
+		"bad currency":  func(city *models.CityBase) { city.Currency = "rub" },
+		"bad center":    func(city *models.CityBase) { city.Center = &models.Point{Lat: 95} },
+	}
+
+	for name, change := range invalid {
+		city := valid
+		change(&city)
+		if err := validateCity(city); !errors.Is(err, customerr.BadInput) {
+			t.Errorf("%v: expected bad input, got %v", name, err)
+		}
+	}
+}
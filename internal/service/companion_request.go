@@ -0,0 +1,158 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/spf13/viper"
+	"mth/internal/models"
+	"mth/internal/repository"
+	"mth/pkg/config"
+	"mth/pkg/customerr"
+	"mth/pkg/log"
+	"mth/pkg/pagination"
+	"strings"
+	"unicode/utf8"
+)
+
+// companionMessageMaxLength ограничение сопроводительного сообщения в символах
+const companionMessageMaxLength = 1000
+
+type companionRequestService struct {
+	requestRepo repository.CompanionRequest
+	localizer   localizer
+	logger      *log.Logs
+}
+
+func InitCompanionRequestService(requestRepo repository.CompanionRequest, translationRepo repository.Translation,
+	logger *log.Logs) CompanionRequest {
+	return companionRequestService{
+		requestRepo: requestRepo,
+		localizer:   localizer{translationRepo: translationRepo},
+		logger:      logger,
+	}
+}
+
+func validateCompanionRequest(request models.CompanionRequestCreate) (models.CompanionRequestCreate, error) {
+	if request.ListingType != models.CompanionListingPlace && request.ListingType != models.CompanionListingRoute {
+		return request, fmt.Errorf("%w: unknown listing type %v", customerr.BadInput, request.ListingType)
+	}
+
+	request.Message = strings.TrimSpace(request.Message)
+	if utf8.RuneCountInString(request.Message) > companionMessageMaxLength {
+		return request, fmt.Errorf("%w: message is longer than %d characters", customerr.BadInput, companionMessageMaxLength)
+	}
+
+	return request, nil
+}
+
+func validateCompanionRequestStatus(status string) error {
+	switch status {
+	case "", models.CompanionRequestPending, models.CompanionRequestAccepted, models.CompanionRequestDeclined,
+		models.CompanionRequestCancelled:
+		return nil
+	default:
+		return fmt.Errorf("%w: unknown companion request status %v", customerr.BadInput, status)
+	}
+}
+
+// revealContacts контакты второй стороны выдаются только по принятому запросу
+func revealContacts(request models.CompanionRequest, keys []string) models.CompanionRequest {
+	public, contacts := splitContacts(request.UserProperties, keys)
+
+	request.UserProperties = public
+	request.Contacts = nil
+	if request.Status == models.CompanionRequestAccepted {
+		request.Contacts = contacts
+	}
+
+	return request
+}
+
+// companionRequestExpectedErrors ошибки проверок и прав доступа ожидаемы и не логируются
+var companionRequestExpectedErrors = []error{
+	customerr.BadInput, customerr.CompanionListingNotFound, customerr.CompanionRequestNotFound,
+	customerr.CompanionRequestExists, customerr.CompanionRequestStatus, customerr.UserNotEntityOwner,
+	customerr.InvalidCursor,
+}
+
+func (c companionRequestService) logUnexpected(err error) {
+	logUnexpected(c.logger, err, companionRequestExpectedErrors)
+}
+
+func (c companionRequestService) Create(ctx context.Context, request models.CompanionRequestCreate) (int, error) {
+	request, err := validateCompanionRequest(request)
+	if err != nil {
+		return 0, err
+	}
+
+	id, err := c.requestRepo.Create(ctx, request)
+	if err != nil {
+		c.logUnexpected(err)
+		return 0, err
+	}
+
+	return id, nil
+}
+
+func (c companionRequestService) setStatus(ctx context.Context, action models.CompanionRequestAction, status string) error {
+	err := c.requestRepo.SetStatus(ctx, action, status)
+	if err != nil {
+		c.logUnexpected(err)
+		return err
+	}
+
+	return nil
+}
+
+func (c companionRequestService) Accept(ctx context.Context, action models.CompanionRequestAction) error {
+	return c.setStatus(ctx, action, models.CompanionRequestAccepted)
+}
+
+func (c companionRequestService) Decline(ctx context.Context, action models.CompanionRequestAction) error {
+	return c.setStatus(ctx, action, models.CompanionRequestDeclined)
+}
+
+func (c companionRequestService) Cancel(ctx context.Context, action models.CompanionRequestAction) error {
+	return c.setStatus(ctx, action, models.CompanionRequestCancelled)
+}
+
+func (c companionRequestService) list(ctx context.Context, request models.CompanionRequestsRequest,
+	get func(context.Context, models.CompanionRequestsRequest) (models.Page[models.CompanionRequest], error)) (models.Page[models.CompanionRequest], error) {
+	if err := validateCompanionRequestStatus(request.Status); err != nil {
+		return models.Page[models.CompanionRequest]{}, err
+	}
+
+	request.PageSize = pagination.PageSize(request.PageSize, viper.GetInt(config.CompanionsOnPage), viper.GetInt(config.MaxPageSize))
+
+	page, err := get(ctx, request)
+	if err != nil {
+		c.logUnexpected(err)
+		return models.Page[models.CompanionRequest]{}, err
+	}
+
+	keys := companionContactKeys()
+	for i := range page.Items {
+		page.Items[i] = revealContacts(page.Items[i], keys)
+	}
+
+	entities := make(map[string][]localizedEntity)
+	for i := range page.Items {
+		item := &page.Items[i]
+		entities[item.ListingType] = append(entities[item.ListingType], localizedEntity{id: item.EntityID, name: &item.ListingName})
+	}
+
+	if err = c.localizer.names(ctx, entities); err != nil {
+		c.logger.Error(err.Error())
+		return models.Page[models.CompanionRequest]{}, err
+	}
+
+	return page, nil
+}
+
+func (c companionRequestService) GetIncoming(ctx context.Context, request models.CompanionRequestsRequest) (models.Page[models.CompanionRequest], error) {
+	return c.list(ctx, request, c.requestRepo.GetIncoming)
+}
+
+func (c companionRequestService) GetOutgoing(ctx context.Context, request models.CompanionRequestsRequest) (models.Page[models.CompanionRequest], error) {
+	return c.list(ctx, request, c.requestRepo.GetOutgoing)
+}
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"errors"
+	"mth/internal/models"
+	"mth/pkg/config"
+	"mth/pkg/customerr"
+	"strings"
+	"testing"
+)
+
+func TestRevealContacts(t *testing.T) {
+	keys := []string{"phone", "telegram"}
+	profile := map[string]interface{}{"name": "Аня", "phone": "+79990000000", "telegram": "@anya"}
+
+	for _, status := range []string{models.CompanionRequestPending, models.CompanionRequestDeclined,
+		models.CompanionRequestCancelled, models.CompanionRequestAccepted} {
+		request := revealContacts(models.CompanionRequest{Status: status, UserProperties: profile}, keys)
+
+		public := request.UserProperties.(map[string]interface{})
+		if _, ok := public["phone"]; ok || public["name"] != "Аня" {
+			t.Errorf("%v: contacts must be removed from profile, got %v", status, public)
+		}
+
+		accepted := status == models.CompanionRequestAccepted
+		if accepted && (request.Contacts["phone"] != "+79990000000" || request.Contacts["telegram"] != "@anya") {
+			t.Errorf("accepted request must reveal contacts, got %v", request.Contacts)
+		}
+		if !accepted && request.Contacts != nil {
+			t.Errorf("%v: contacts must stay hidden, got %v", status, request.Contacts)
+		}
+	}
+
+	if _, ok := profile["phone"]; !ok {
+		t.Error("source profile must not be modified")
+	}
+
+	request := revealContacts(models.CompanionRequest{Status: models.CompanionRequestAccepted, UserProperties: "профиль"}, keys)
+	if request.UserProperties != "профиль" || request.Contacts != nil {
+		t.Errorf("non-object profile must be left as is, got %+v", request)
+	}
+}
+
+func TestValidateCompanionRequest(t *testing.T) {
+	request, err := validateCompanionRequest(models.CompanionRequestCreate{ListingType: models.CompanionListingRoute, Message: "  привет  "})
+	if err != nil || request.Message != "привет" {
+		t.Errorf("expected trimmed message, got %q, %v", request.Message, err)
+	}
+
+	invalid := []models.CompanionRequestCreate{
+		{ListingType: "trip"},
+		{ListingType: models.CompanionListingPlace, Message: strings.Repeat("я", companionMessageMaxLength+1)},
+	}
+	for _, request := range invalid {
+		if _, err := validateCompanionRequest(request); !errors.Is(err, customerr.BadInput) {
+			t.Errorf("%v: expected bad input, got %v", request.ListingType, err)
+		}
+	}
+
+	if err := validateCompanionRequestStatus("approved"); !errors.Is(err, customerr.BadInput) {
+		t.Errorf("expected bad input for unknown status, got %v", err)
+	}
+}
+
+func TestHideContacts(t *testing.T) {
+	config.InitConfig()
+
+	profile := map[string]interface{}{"name": "Аня", "phone": "+79990000000"}
+	places := []models.CompanionsPlace{{CompanionBase: models.CompanionBase{UserProperties: profile}}}
+	routes := []models.CompanionsRoute{{CompanionBase: models.CompanionBase{UserProperties: profile}}}
+
+	hideContacts(places, routes)
+
+	for _, properties := range []interface{}{places[0].UserProperties, routes[0].UserProperties} {
+		public := properties.(map[string]interface{})
+		if _, ok := public["phone"]; ok || public["name"] != "Аня" {
+			t.Errorf("contacts must be removed from listing profile, got %v", public)
+		}
+	}
+}
@@ -2,23 +2,103 @@ package service
 
 import (
 	"context"
+	"errors"
+	"github.com/spf13/viper"
 	"mth/internal/models"
 	"mth/internal/repository"
+	"mth/pkg/config"
+	"mth/pkg/customerr"
 	"mth/pkg/log"
+	"mth/pkg/pagination"
+	"strings"
 )
 
 type companionsService struct {
 	companionRepo repository.Companions
+	localizer     localizer
 	logger        *log.Logs
 }
 
-func InitCompanionsService(companionsRepo repository.Companions, logger *log.Logs) Companions {
+func InitCompanionsService(companionsRepo repository.Companions, translationRepo repository.Translation, logger *log.Logs) Companions {
 	return companionsService{
 		companionRepo: companionsRepo,
+		localizer:     localizer{translationRepo: translationRepo},
 		logger:        logger,
 	}
 }
 
+// companionContactKeys поля профиля, которые видны попутчику только после принятия запроса
+func companionContactKeys() []string {
+	var keys []string
+	for _, key := range strings.Split(viper.GetString(config.CompanionContactKeys), ",") {
+		if key = strings.TrimSpace(key); key != "" {
+			keys = append(keys, key)
+		}
+	}
+
+	return keys
+}
+
+// splitContacts делит профиль на открытую часть и контакты, профиль не-объект не делится
+func splitContacts(properties interface{}, keys []string) (interface{}, map[string]interface{}) {
+	fields, ok := properties.(map[string]interface{})
+	if !ok {
+		return properties, nil
+	}
+
+	public := make(map[string]interface{}, len(fields))
+	for key, value := range fields {
+		public[key] = value
+	}
+
+	var contacts map[string]interface{}
+	for _, key := range keys {
+		value, ok := public[key]
+		if !ok {
+			continue
+		}
+
+		if contacts == nil {
+			contacts = make(map[string]interface{})
+		}
+		contacts[key] = value
+		delete(public, key)
+	}
+
+	return public, contacts
+}
+
+// hideContacts контакты авторов объявлений выдаются только по принятому запросу попутчика
+func hideContacts(places []models.CompanionsPlace, routes []models.CompanionsRoute) {
+	keys := companionContactKeys()
+	for i := range places {
+		places[i].UserProperties, _ = splitContacts(places[i].UserProperties, keys)
+	}
+	for i := range routes {
+		routes[i].UserProperties, _ = splitContacts(routes[i].UserProperties, keys)
+	}
+}
+
+// localizeNames переводит названия мест и маршрутов объявлений
+func (c companionsService) localizeNames(ctx context.Context, places []models.CompanionsPlace, routes []models.CompanionsRoute) error {
+	entities := make(map[string][]localizedEntity)
+	for i := range places {
+		entities[models.TranslationEntityPlace] = append(entities[models.TranslationEntityPlace],
+			localizedEntity{id: places[i].PlaceID, name: &places[i].PlaceName})
+	}
+	for i := range routes {
+		entities[models.TranslationEntityRoute] = append(entities[models.TranslationEntityRoute],
+			localizedEntity{id: routes[i].RouteID, name: &routes[i].RouteName})
+	}
+
+	if err := c.localizer.names(ctx, entities); err != nil {
+		c.logger.Error(err.Error())
+		return err
+	}
+
+	return nil
+}
+
 func (c companionsService) CreatePlaceCompanions(ctx context.Context, companion models.CompanionsPlaceCreate) error {
 	err := c.companionRepo.CreatePlaceCompanions(ctx, companion)
 	if err != nil {
@@ -46,24 +126,50 @@ func (c companionsService) GetByUser(ctx context.Context, userID int) ([]models.
 		return places, routes, err
 	}
 
+	hideContacts(places, routes)
+
+	if err = c.localizeNames(ctx, places, routes); err != nil {
+		return []models.CompanionsPlace{}, []models.CompanionsRoute{}, err
+	}
+
 	return places, routes, nil
 }
 
-func (c companionsService) GetCompanionsPlace(ctx context.Context, filters models.CompanionsFilters) ([]models.CompanionsPlace, error) {
+func (c companionsService) GetCompanionsPlace(ctx context.Context, filters models.CompanionsFilters) (models.Page[models.CompanionsPlace], error) {
+	filters.PageSize = pagination.PageSize(filters.PageSize, viper.GetInt(config.CompanionsOnPage), viper.GetInt(config.MaxPageSize))
+
 	places, err := c.companionRepo.GetCompanionsPlace(ctx, filters)
 	if err != nil {
-		c.logger.Error(err.Error())
-		return places, err
+		if !errors.Is(err, customerr.InvalidCursor) {
+			c.logger.Error(err.Error())
+		}
+		return models.Page[models.CompanionsPlace]{}, err
+	}
+
+	hideContacts(places.Items, nil)
+
+	if err = c.localizeNames(ctx, places.Items, nil); err != nil {
+		return models.Page[models.CompanionsPlace]{}, err
 	}
 
 	return places, nil
 }
 
-func (c companionsService) GetCompanionsRoute(ctx context.Context, filters models.CompanionsFilters) ([]models.CompanionsRoute, error) {
+func (c companionsService) GetCompanionsRoute(ctx context.Context, filters models.CompanionsFilters) (models.Page[models.CompanionsRoute], error) {
+	filters.PageSize = pagination.PageSize(filters.PageSize, viper.GetInt(config.CompanionsOnPage), viper.GetInt(config.MaxPageSize))
+
 	routes, err := c.companionRepo.GetCompanionsRoute(ctx, filters)
 	if err != nil {
-		c.logger.Error(err.Error())
-		return routes, err
+		if !errors.Is(err, customerr.InvalidCursor) {
+			c.logger.Error(err.Error())
+		}
+		return models.Page[models.CompanionsRoute]{}, err
+	}
+
+	hideContacts(nil, routes.Items)
+
+	if err = c.localizeNames(ctx, nil, routes.Items); err != nil {
+		return models.Page[models.CompanionsRoute]{}, err
 	}
 
 	return routes, nil
@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/spf13/viper"
+	"mth/internal/models"
+	"mth/internal/repository"
+	"mth/pkg/config"
+	"mth/pkg/contentfilter"
+	"mth/pkg/customerr"
+	"mth/pkg/log"
+	"mth/pkg/pagination"
+	"strings"
+	"sync"
+)
+
+var (
+	contentFilterOnce sync.Once
+	contentFilter     *contentfilter.Filter
+)
+
+// sharedContentFilter фильтр собирается из конфига один раз на все сервисы
+func sharedContentFilter(logger *log.Logs) *contentfilter.Filter {
+	contentFilterOnce.Do(func() {
+		contentFilter = newContentFilter(logger)
+	})
+
+	return contentFilter
+}
+
+// newContentFilter правило с неизвестным действием в конфиге выключается
+func newContentFilter(logger *log.Logs) *contentfilter.Filter {
+	action := func(key string) contentfilter.Action {
+		action, err := contentfilter.ParseAction(strings.TrimSpace(viper.GetString(key)))
+		if err != nil {
+			logger.Error(fmt.Sprintf("%v: %v", key, err))
+		}
+		return action
+	}
+
+	words := append([]string{}, contentfilter.DefaultWordlist...)
+	for _, word := range strings.Split(viper.GetString(config.ContentFilterWordlist), ",") {
+		if word = strings.TrimSpace(word); word != "" {
+			words = append(words, word)
+		}
+	}
+
+	return contentfilter.New().
+		Add(contentfilter.NewProfanity(words), action(config.ContentFilterProfanity)).
+		Add(contentfilter.NewSpam(), action(config.ContentFilterSpam)).
+		Add(contentfilter.NewRepeat(), action(config.ContentFilterRepeat))
+}
+
+// contentSubject сохранённые тексты автора за окно повторов загружаются из базы, поэтому отклонённый фильтром
+// текст нигде не запоминается. При выключенном правиле повторов и без автора они не нужны
+func contentSubject(ctx context.Context, contentReportRepo repository.ContentReport, authorID int, target string) (contentfilter.Subject, error) {
+	subject := contentfilter.Subject{AuthorID: authorID, Target: target}
+
+	action, err := contentfilter.ParseAction(strings.TrimSpace(viper.GetString(config.ContentFilterRepeat)))
+	if err != nil || action == contentfilter.ActionOff || authorID == 0 {
+		return subject, nil
+	}
+
+	recent, err := contentReportRepo.RecentProperties(ctx, authorID, viper.GetInt(config.ContentFilterRepeatWindow), target)
+	if err != nil {
+		return subject, err
+	}
+
+	for _, properties := range recent {
+		subject.Recent = append(subject.Recent, contentfilter.Strings(properties)...)
+	}
+
+	return subject, nil
+}
+
+type contentReportService struct {
+	contentReportRepo repository.ContentReport
+	logger            *log.Logs
+}
+
+func InitContentReportService(contentReportRepo repository.ContentReport, logger *log.Logs) ContentReport {
+	return contentReportService{
+		contentReportRepo: contentReportRepo,
+		logger:            logger,
+	}
+}
+
+func (c contentReportService) GetOpen(ctx context.Context, request models.ContentReportsRequest) (models.Page[models.ContentReport], error) {
+	if err := checkModerator(request.ModeratorID); err != nil {
+		return models.Page[models.ContentReport]{}, err
+	}
+
+	request.PageSize = pagination.PageSize(request.PageSize, viper.GetInt(config.ReviewsOnPage), viper.GetInt(config.MaxPageSize))
+
+	reports, err := c.contentReportRepo.GetOpen(ctx, request)
+	if err != nil {
+		if !errors.Is(err, customerr.InvalidCursor) {
+			c.logger.Error(err.Error())
+		}
+		return models.Page[models.ContentReport]{}, err
+	}
+
+	return reports, nil
+}
+
+func (c contentReportService) Resolve(ctx context.Context, resolve models.ContentReportResolve) error {
+	if err := checkModerator(resolve.ModeratorID); err != nil {
+		return err
+	}
+
+	if err := c.contentReportRepo.Resolve(ctx, resolve); err != nil {
+		if !errors.Is(err, customerr.ContentReportNotFound) {
+			c.logger.Error(err.Error())
+		}
+		return err
+	}
+
+	return nil
+}
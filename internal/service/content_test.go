@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/spf13/viper"
+	"mth/internal/repository"
+	"mth/pkg/config"
+	"mth/pkg/contentfilter"
+	"testing"
+)
+
+// fakeRecentRepo сохранённые тексты одного автора по целям
+type fakeRecentRepo struct {
+	repository.ContentReport
+	authorID int
+	texts    map[string]interface{}
+	calls    *int
+}
+
+func (f fakeRecentRepo) RecentProperties(_ context.Context, authorID int, _ int, excludeTarget string) ([]interface{}, error) {
+	*f.calls++
+
+	var result []interface{}
+	for target, properties := range f.texts {
+		if authorID == f.authorID && target != excludeTarget {
+			result = append(result, properties)
+		}
+	}
+
+	return result, nil
+}
+
+func TestContentSubject(t *testing.T) {
+	config.InitConfig()
+	repeat := viper.Get(config.ContentFilterRepeat)
+	defer viper.Set(config.ContentFilterRepeat, repeat)
+
+	text := "Лучшее место в городе, всем советую!"
+	calls := 0
+	repo := fakeRecentRepo{authorID: 1, calls: &calls, texts: map[string]interface{}{
+		"place:1":        map[string]interface{}{"comment": text},
+		"note:route:2:0": map[string]interface{}{"tags": []interface{}{"вид", 5.0}},
+	}}
+	filter := contentfilter.New().Add(contentfilter.NewRepeat(), contentfilter.ActionReject)
+
+	viper.Set(config.ContentFilterRepeat, "reject")
+
+	subject, err := contentSubject(context.TODO(), repo, 1, "place:1")
+	if err != nil || fmt.Sprint(subject.Recent) != "[вид]" {
+		t.Fatalf("texts under the same target must be excluded, got %v, %v", subject.Recent, err)
+	}
+	if _, _, err = filter.Text(subject, text); err != nil {
+		t.Errorf("editing own text must not be a repeat, got %v", err)
+	}
+
+	subject, err = contentSubject(context.TODO(), repo, 1, "place:3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err = filter.Text(subject, text); err == nil {
+		t.Error("stored text under other target must be a repeat")
+	}
+
+	calls = 0
+	if subject, err = contentSubject(context.TODO(), repo, 0, "place:3"); err != nil || subject.Recent != nil {
+		t.Errorf("anonymous subject must not load texts, got %v, %v", subject.Recent, err)
+	}
+
+	viper.Set(config.ContentFilterRepeat, "off")
+	if subject, err = contentSubject(context.TODO(), repo, 1, "place:3"); err != nil || subject.Recent != nil {
+		t.Errorf("texts must not be loaded with repeat rule off, got %v, %v", subject.Recent, err)
+	}
+	if calls != 0 {
+		t.Errorf("expected no queries, got %v", calls)
+	}
+}
@@ -0,0 +1,387 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/spf13/viper"
+	"io"
+	"mth/internal/models"
+	"mth/internal/repository"
+	"mth/pkg/config"
+	"mth/pkg/customerr"
+	"mth/pkg/diary"
+	"mth/pkg/log"
+	"mth/pkg/storage"
+	"sort"
+	"strings"
+	"time"
+)
+
+type diaryService struct {
+	tripRepo   repository.Trip
+	noteRepo   repository.Note
+	reviewRepo repository.Review
+	mediaRepo  repository.Media
+	storage    storage.Storage
+	localizer  localizer
+	logger     *log.Logs
+}
+
+func InitDiaryService(tripRepo repository.Trip, noteRepo repository.Note, reviewRepo repository.Review,
+	mediaRepo repository.Media, translationRepo repository.Translation, storage storage.Storage, logger *log.Logs) Diary {
+	return diaryService{
+		tripRepo:   tripRepo,
+		noteRepo:   noteRepo,
+		reviewRepo: reviewRepo,
+		mediaRepo:  mediaRepo,
+		storage:    storage,
+		localizer:  localizer{translationRepo: translationRepo},
+		logger:     logger,
+	}
+}
+
+// diaryEntity ключ места, маршрута, заметки или отзыва в дневнике
+type diaryEntity struct {
+	Type string
+	ID   int
+}
+
+// propertiesText текст из произвольных properties: строка, поле text или пары ключ-значение по алфавиту
+func propertiesText(properties interface{}) string {
+	switch value := properties.(type) {
+	case nil:
+		return ""
+	case string:
+		return value
+	case map[string]interface{}:
+		if text, ok := value["text"].(string); ok {
+			return text
+		}
+
+		keys := make([]string, 0, len(value))
+		for key := range value {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		lines := make([]string, 0, len(keys))
+		for _, key := range keys {
+			if text := propertiesText(value[key]); text != "" {
+				lines = append(lines, key+": "+text)
+			}
+		}
+
+		return strings.Join(lines, "\n")
+	case []interface{}:
+		lines := make([]string, 0, len(value))
+		for _, item := range value {
+			if text := propertiesText(item); text != "" {
+				lines = append(lines, text)
+			}
+		}
+
+		return strings.Join(lines, ", ")
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+// tripTitle название поездки из properties, иначе номер
+func tripTitle(trip models.Trip) string {
+	if properties, ok := trip.Properties.(map[string]interface{}); ok {
+		for _, key := range []string{"name", "title"} {
+			if title, ok := properties[key].(string); ok && strings.TrimSpace(title) != "" {
+				return title
+			}
+		}
+	}
+
+	return fmt.Sprintf("Поездка %d", trip.ID)
+}
+
+// duringTrip заметки и отзывы к местам и маршрутам попадают в дневник, только если написаны в даты поездки,
+// иначе туда попадал бы отзыв о месте, оставленный в другой приезд. Без дат поездки проверки нет
+func duringTrip(trip models.Trip, at time.Time) bool {
+	if trip.DateStart.IsZero() || trip.DateEnd.IsZero() {
+		return true
+	}
+
+	return !at.Before(trip.DateStart) && at.Before(trip.DateEnd.AddDate(0, 0, 1))
+}
+
+// diaryNoteInTrip заметка к самой поездке, её дню или к месту и маршруту из поездки, написанная в её даты
+func diaryNoteInTrip(note models.Note, trip models.Trip, stops map[diaryEntity]bool) bool {
+	switch note.TargetType {
+	case models.NoteTargetTrip, models.NoteTargetTripDay:
+		return note.TargetID == trip.ID
+	default:
+		return stops[diaryEntity{Type: note.TargetType, ID: note.TargetID}] && duringTrip(trip, note.CreatedAt)
+	}
+}
+
+func diaryStops(stops []models.TripStop) map[diaryEntity]bool {
+	entities := make(map[diaryEntity]bool, len(stops))
+	for _, stop := range stops {
+		entities[diaryEntity{Type: stop.EntityType, ID: stop.EntityID}] = true
+	}
+
+	return entities
+}
+
+// buildDiary раскладывает заметки и отзывы по дням. Место, встречающееся в поездке несколько раз,
+// получает заметки и отзыв только при первом посещении. photos - фотографии заметок и отзывов
+func buildDiary(trip models.Trip, stops []models.TripStop, notes []models.Note, placeReviews []models.PlaceReview,
+	routeReviews []models.RouteReview, photos map[diaryEntity][]diary.Photo) diary.Diary {
+	d := diary.Diary{
+		ID:        fmt.Sprintf("trip-%d", trip.ID),
+		Title:     tripTitle(trip),
+		DateStart: trip.DateStart,
+		DateEnd:   trip.DateEnd,
+	}
+
+	diaryNote := func(note models.Note) diary.Note {
+		return diary.Note{
+			Text:      propertiesText(note.Properties),
+			CreatedAt: note.CreatedAt,
+			Photos:    photos[diaryEntity{Type: models.MediaEntityNote, ID: note.ID}],
+		}
+	}
+
+	entityNotes := make(map[diaryEntity][]diary.Note)
+	dayNotes := make(map[int][]diary.Note)
+	days := make(map[int]bool)
+	for _, note := range notes {
+		switch note.TargetType {
+		case models.NoteTargetTrip:
+			d.Notes = append(d.Notes, diaryNote(note))
+		case models.NoteTargetTripDay:
+			dayNotes[note.Day] = append(dayNotes[note.Day], diaryNote(note))
+			days[note.Day] = true
+		default:
+			entity := diaryEntity{Type: note.TargetType, ID: note.TargetID}
+			entityNotes[entity] = append(entityNotes[entity], diaryNote(note))
+		}
+	}
+
+	reviews := make(map[diaryEntity]*diary.Review)
+	for _, review := range placeReviews {
+		reviews[diaryEntity{Type: diary.KindPlace, ID: review.PlaceID}] = &diary.Review{
+			Mark:   review.Mark,
+			Text:   propertiesText(review.Properties),
+			Photos: photos[diaryEntity{Type: models.MediaEntityPlaceReview, ID: review.ID}],
+		}
+	}
+	for _, review := range routeReviews {
+		reviews[diaryEntity{Type: diary.KindRoute, ID: review.RouteID}] = &diary.Review{
+			Mark:   review.Mark,
+			Text:   propertiesText(review.Properties),
+			Photos: photos[diaryEntity{Type: models.MediaEntityRouteReview, ID: review.ID}],
+		}
+	}
+
+	dayStops := make(map[int][]diary.Stop)
+	visited := make(map[diaryEntity]bool)
+	for _, stop := range stops {
+		entity := diaryEntity{Type: stop.EntityType, ID: stop.EntityID}
+		diaryStop := diary.Stop{Kind: stop.EntityType, Name: stop.Name, CheckedInAt: stop.CheckedInAt}
+		if !visited[entity] {
+			visited[entity] = true
+			diaryStop.Notes = entityNotes[entity]
+			diaryStop.Review = reviews[entity]
+		}
+
+		dayStops[stop.Day] = append(dayStops[stop.Day], diaryStop)
+		days[stop.Day] = true
+	}
+
+	numbers := make([]int, 0, len(days))
+	for number := range days {
+		numbers = append(numbers, number)
+	}
+	sort.Ints(numbers)
+
+	for _, number := range numbers {
+		d.Days = append(d.Days, diary.Day{Number: number, Notes: dayNotes[number], Stops: dayStops[number]})
+	}
+
+	return d
+}
+
+// photos фотографии заметок и отзывов одним запросом, содержимое файлов читается только для встраивающих форматов
+func (d diaryService) photos(ctx context.Context, entities []diaryEntity, embed bool) (map[diaryEntity][]diary.Photo, error) {
+	keys := make([]models.MediaEntityKey, 0, len(entities))
+	for _, entity := range entities {
+		keys = append(keys, models.MediaEntityKey{EntityType: entity.Type, EntityID: entity.ID})
+	}
+
+	attached, err := d.mediaRepo.GetByEntities(ctx, keys)
+	if err != nil {
+		return nil, err
+	}
+
+	embedder := photoEmbedder{diaryService: d, budget: viper.GetInt64(config.DiaryEmbedMaxSize), embedded: make(map[int]diary.Photo)}
+
+	photos := make(map[diaryEntity][]diary.Photo)
+	for i, entity := range entities {
+		for _, media := range attached[keys[i]] {
+			extension, ok := mediaExtensions[media.ContentType]
+			if !ok {
+				continue
+			}
+
+			photo := diary.Photo{
+				Name:        fmt.Sprintf("photo-%d.%v", media.ID, extension),
+				ContentType: media.ContentType,
+				URL:         d.storage.URL(media.StorageKey),
+			}
+
+			if embed {
+				if photo, err = embedder.embed(ctx, photo, media); err != nil {
+					return nil, err
+				}
+			}
+
+			photos[entity] = append(photos[entity], photo)
+		}
+	}
+
+	return photos, nil
+}
+
+// photoEmbedder встраивает фотографии, пока их суммарный размер укладывается в DIARY_EMBED_MAX_SIZE,
+// дальше встраиваются миниатюры, а когда не помещаются и они, фото остаётся ссылкой
+type photoEmbedder struct {
+	diaryService
+	budget   int64
+	embedded map[int]diary.Photo
+}
+
+func (e *photoEmbedder) embed(ctx context.Context, photo diary.Photo, media models.AttachedMedia) (diary.Photo, error) {
+	if embedded, ok := e.embedded[media.ID]; ok {
+		return embedded, nil
+	}
+
+	switch {
+	case media.Size <= e.budget:
+		data, err := e.readFile(ctx, media.StorageKey)
+		if err != nil {
+			return photo, err
+		}
+
+		photo.Data = data
+	case media.ThumbnailKey != "":
+		data, err := e.readFile(ctx, media.ThumbnailKey)
+		if err != nil {
+			return photo, err
+		}
+
+		if int64(len(data)) <= e.budget {
+			photo.Name = fmt.Sprintf("photo-%d-thumbnail.jpg", media.ID)
+			photo.ContentType = "image/jpeg"
+			photo.Data = data
+		}
+	}
+
+	e.budget -= int64(len(photo.Data))
+	e.embedded[media.ID] = photo
+
+	return photo, nil
+}
+
+func (d diaryService) readFile(ctx context.Context, key string) ([]byte, error) {
+	file, err := d.storage.Open(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return io.ReadAll(file)
+}
+
+func (d diaryService) Export(ctx context.Context, request models.TripDiaryRequest) (diary.File, error) {
+	if request.Format != diary.FormatMarkdown && request.Format != diary.FormatHTML && request.Format != diary.FormatEPUB {
+		return diary.File{}, fmt.Errorf("%w: unknown diary format %v", customerr.BadInput, request.Format)
+	}
+
+	trip, err := d.tripRepo.GetTripByID(ctx, request.TripID)
+	if err != nil {
+		d.logger.Error(err.Error())
+		return diary.File{}, err
+	}
+	if trip.ID == 0 {
+		return diary.File{}, customerr.TripNotFound
+	}
+	if trip.UserID != request.UserID {
+		return diary.File{}, customerr.UserNotEntityOwner
+	}
+
+	stops, err := d.tripRepo.GetStops(ctx, trip.ID)
+	if err != nil {
+		d.logger.Error(err.Error())
+		return diary.File{}, err
+	}
+
+	stopNames := make(map[string][]localizedEntity)
+	for i := range stops {
+		stopNames[stops[i].EntityType] = append(stopNames[stops[i].EntityType],
+			localizedEntity{id: stops[i].EntityID, name: &stops[i].Name})
+	}
+	if err = d.localizer.names(ctx, stopNames); err != nil {
+		d.logger.Error(err.Error())
+		return diary.File{}, err
+	}
+	stopSet := diaryStops(stops)
+
+	allNotes, err := d.noteRepo.GetByUser(ctx, trip.UserID, trip.UserID)
+	if err != nil {
+		d.logger.Error(err.Error())
+		return diary.File{}, err
+	}
+
+	allPlaceReviews, allRouteReviews, err := d.reviewRepo.GetByAuthor(ctx, trip.UserID)
+	if err != nil {
+		d.logger.Error(err.Error())
+		return diary.File{}, err
+	}
+
+	var notes []models.Note
+	var placeReviews []models.PlaceReview
+	var routeReviews []models.RouteReview
+	var entities []diaryEntity
+
+	for _, note := range allNotes {
+		if diaryNoteInTrip(note, trip, stopSet) {
+			notes = append(notes, note)
+			entities = append(entities, diaryEntity{Type: models.MediaEntityNote, ID: note.ID})
+		}
+	}
+	for _, review := range allPlaceReviews {
+		if stopSet[diaryEntity{Type: diary.KindPlace, ID: review.PlaceID}] && duringTrip(trip, review.TimeStamp) {
+			placeReviews = append(placeReviews, review)
+			entities = append(entities, diaryEntity{Type: models.MediaEntityPlaceReview, ID: review.ID})
+		}
+	}
+	for _, review := range allRouteReviews {
+		if stopSet[diaryEntity{Type: diary.KindRoute, ID: review.RouteID}] && duringTrip(trip, review.TimeStamp) {
+			routeReviews = append(routeReviews, review)
+			entities = append(entities, diaryEntity{Type: models.MediaEntityRouteReview, ID: review.ID})
+		}
+	}
+
+	photos, err := d.photos(ctx, entities, request.Format != diary.FormatMarkdown)
+	if err != nil {
+		d.logger.Error(err.Error())
+		return diary.File{}, err
+	}
+
+	tripDiary := buildDiary(trip, stops, notes, placeReviews, routeReviews, photos)
+	tripDiary.GeneratedAt = time.Now()
+
+	file, err := diary.Render(tripDiary, request.Format)
+	if err != nil {
+		d.logger.Error(err.Error())
+		return diary.File{}, err
+	}
+
+	return file, nil
+}
@@ -0,0 +1,201 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"github.com/spf13/viper"
+	"io"
+	"mth/internal/models"
+	"mth/internal/repository"
+	"mth/pkg/config"
+	"mth/pkg/diary"
+	"mth/pkg/storage"
+	"testing"
+	"time"
+)
+
+func TestPropertiesText(t *testing.T) {
+	cases := []struct {
+		properties interface{}
+		want       string
+	}{
+		{nil, ""},
+		{"просто текст", "просто текст"},
+		{map[string]interface{}{"text": "главное", "mood": "хорошо"}, "главное"},
+		{map[string]interface{}{"weather": "дождь", "mood": "хорошо", "empty": ""}, "mood: хорошо\nweather: дождь"},
+		{map[string]interface{}{"tags": []interface{}{"еда", "вид"}}, "tags: еда, вид"},
+	}
+
+	for _, c := range cases {
+		if got := propertiesText(c.properties); got != c.want {
+			t.Errorf("%v: expected %q, got %q", c.properties, c.want, got)
+		}
+	}
+}
+
+func TestBuildDiary(t *testing.T) {
+	trip := models.Trip{ID: 5}
+	trip.Properties = map[string]interface{}{"name": "Казань"}
+
+	checkIn := time.Date(2024, 5, 2, 10, 0, 0, 0, time.UTC)
+	stops := []models.TripStop{
+		{Day: 1, Position: 0, EntityType: models.NoteTargetPlace, EntityID: 10, Name: "Кремль", CheckedInAt: &checkIn},
+		{Day: 1, Position: 1, EntityType: models.NoteTargetRoute, EntityID: 20, Name: "Набережная"},
+		{Day: 3, Position: 0, EntityType: models.NoteTargetPlace, EntityID: 10, Name: "Кремль"},
+	}
+
+	note := func(id int, targetType string, targetID, day int) models.Note {
+		n := models.Note{ID: id, NoteTarget: models.NoteTarget{TargetType: targetType, TargetID: targetID, Day: day}}
+		n.Properties = map[string]interface{}{"text": targetType}
+		return n
+	}
+	notes := []models.Note{
+		note(1, models.NoteTargetTrip, 5, 0),
+		note(2, models.NoteTargetTripDay, 5, 2),
+		note(3, models.NoteTargetPlace, 10, 0),
+	}
+
+	placeReview := models.PlaceReview{ID: 7}
+	placeReview.PlaceID = 10
+	placeReview.Mark = 5
+
+	photos := map[diaryEntity][]diary.Photo{
+		{Type: models.MediaEntityNote, ID: 3}:        {{Name: "photo-1.jpg"}},
+		{Type: models.MediaEntityPlaceReview, ID: 7}: {{Name: "photo-2.jpg"}},
+	}
+
+	d := buildDiary(trip, stops, notes, []models.PlaceReview{placeReview}, nil, photos)
+
+	if d.Title != "Казань" || len(d.Notes) != 1 || d.Notes[0].Text != models.NoteTargetTrip {
+		t.Fatalf("unexpected diary header: %+v", d)
+	}
+	if len(d.Days) != 3 || d.Days[0].Number != 1 || d.Days[1].Number != 2 || d.Days[2].Number != 3 {
+		t.Fatalf("expected days 1, 2, 3, got %+v", d.Days)
+	}
+
+	first := d.Days[0].Stops[0]
+	if first.Name != "Кремль" || first.CheckedInAt == nil || len(first.Notes) != 1 || len(first.Notes[0].Photos) != 1 ||
+		first.Review == nil || len(first.Review.Photos) != 1 {
+		t.Errorf("unexpected first visit: %+v", first)
+	}
+	if d.Days[0].Stops[1].Kind != diary.KindRoute {
+		t.Errorf("expected route second, got %+v", d.Days[0].Stops[1])
+	}
+	if len(d.Days[1].Notes) != 1 || len(d.Days[1].Stops) != 0 {
+		t.Errorf("expected only a day note on day 2, got %+v", d.Days[1])
+	}
+	if again := d.Days[2].Stops[0]; len(again.Notes) != 0 || again.Review != nil {
+		t.Errorf("notes and review must be shown only on the first visit, got %+v", again)
+	}
+}
+
+func TestDiaryNoteInTrip(t *testing.T) {
+	trip := models.Trip{ID: 5}
+	trip.DateStart = time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	trip.DateEnd = time.Date(2024, 5, 3, 0, 0, 0, 0, time.UTC)
+	stops := map[diaryEntity]bool{{Type: models.NoteTargetPlace, ID: 10}: true}
+
+	note := func(targetType string, targetID int, createdAt time.Time) models.Note {
+		n := models.Note{NoteTarget: models.NoteTarget{TargetType: targetType, TargetID: targetID}}
+		n.CreatedAt = createdAt
+		return n
+	}
+	earlier := time.Date(2023, 8, 1, 12, 0, 0, 0, time.UTC)
+	lastEvening := time.Date(2024, 5, 3, 23, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		name     string
+		note     models.Note
+		expected bool
+	}{
+		{name: "place during the trip", note: note(models.NoteTargetPlace, 10, lastEvening), expected: true},
+		{name: "place in an earlier visit", note: note(models.NoteTargetPlace, 10, earlier)},
+		{name: "place not in the trip", note: note(models.NoteTargetPlace, 11, lastEvening)},
+		{name: "trip note written before it", note: note(models.NoteTargetTrip, 5, earlier), expected: true},
+	}
+	for _, c := range cases {
+		if got := diaryNoteInTrip(c.note, trip, stops); got != c.expected {
+			t.Errorf("%v: expected %v, got %v", c.name, c.expected, got)
+		}
+	}
+
+	if !diaryNoteInTrip(note(models.NoteTargetPlace, 10, earlier), models.Trip{ID: 5}, stops) {
+		t.Error("trip without dates must not filter notes by time")
+	}
+}
+
+// fakeDiaryMedia медиа в памяти, считает запросы к базе
+type fakeDiaryMedia struct {
+	repository.Media
+	attached map[models.MediaEntityKey][]models.AttachedMedia
+	queries  *int
+}
+
+func (f fakeDiaryMedia) GetByEntities(_ context.Context, _ []models.MediaEntityKey) (map[models.MediaEntityKey][]models.AttachedMedia, error) {
+	*f.queries++
+	return f.attached, nil
+}
+
+type fakeDiaryStorage struct {
+	storage.Storage
+	files map[string][]byte
+}
+
+func (f fakeDiaryStorage) Open(_ context.Context, key string) (io.ReadCloser, error) {
+	return io.NopCloser(bytes.NewReader(f.files[key])), nil
+}
+
+func (f fakeDiaryStorage) URL(key string) string {
+	return "/media/file/" + key
+}
+
+func TestDiaryPhotosEmbedBudget(t *testing.T) {
+	config.InitConfig()
+	embedMaxSize := viper.Get(config.DiaryEmbedMaxSize)
+	defer viper.Set(config.DiaryEmbedMaxSize, embedMaxSize)
+	viper.Set(config.DiaryEmbedMaxSize, 10)
+
+	media := func(id int, key, thumbnailKey string, size int64) models.AttachedMedia {
+		var m models.AttachedMedia
+		m.ID, m.StorageKey, m.ThumbnailKey, m.ContentType, m.Size = id, key, thumbnailKey, "image/png", size
+		return m
+	}
+
+	queries := 0
+	fake := fakeDiaryMedia{
+		queries: &queries,
+		attached: map[models.MediaEntityKey][]models.AttachedMedia{
+			{EntityType: models.MediaEntityNote, EntityID: 1}:        {media(1, "a.png", "", 6), media(2, "b.png", "b.jpg", 6)},
+			{EntityType: models.MediaEntityPlaceReview, EntityID: 2}: {media(3, "c.png", "c.jpg", 6), media(1, "a.png", "", 6)},
+		},
+	}
+	files := fakeDiaryStorage{files: map[string][]byte{"a.png": []byte("aaaaaa"), "b.png": []byte("bbbbbb"),
+		"b.jpg": []byte("bb"), "c.png": []byte("cccccc"), "c.jpg": []byte("cccc")}}
+	d := diaryService{mediaRepo: fake, storage: files}
+
+	photos, err := d.photos(context.TODO(), []diaryEntity{
+		{Type: models.MediaEntityNote, ID: 1}, {Type: models.MediaEntityPlaceReview, ID: 2}}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if queries != 1 {
+		t.Errorf("expected one media query, got %v", queries)
+	}
+
+	note, review := photos[diaryEntity{Type: models.MediaEntityNote, ID: 1}], photos[diaryEntity{Type: models.MediaEntityPlaceReview, ID: 2}]
+	if len(note) != 2 || len(review) != 2 {
+		t.Fatalf("unexpected photos %+v", photos)
+	}
+	if string(note[0].Data) != "aaaaaa" {
+		t.Errorf("first photo fits and must be embedded, got %+v", note[0])
+	}
+	if string(note[1].Data) != "bb" || note[1].Name != "photo-2-thumbnail.jpg" || note[1].ContentType != "image/jpeg" {
+		t.Errorf("second photo must fall back to thumbnail, got %+v", note[1])
+	}
+	if review[0].Data != nil || review[0].URL != "/media/file/c.png" {
+		t.Errorf("photo over the budget must stay a link, got %+v", review[0])
+	}
+	if string(review[1].Data) != "aaaaaa" {
+		t.Errorf("photo attached twice must be embedded once and reused, got %+v", review[1])
+	}
+}
@@ -2,9 +2,14 @@ package service
 
 import (
 	"context"
+	"fmt"
 	"mth/internal/models"
 	"mth/internal/repository"
+	"mth/pkg/customerr"
+	"mth/pkg/geo"
+	"mth/pkg/geojson"
 	"mth/pkg/log"
+	"strings"
 )
 
 type districtService struct {
@@ -19,7 +24,95 @@ func InitDistrictService(districtRepo repository.District, logger *log.Logs) Dis
 	}
 }
 
-func (d districtService) GetByID(ctx context.Context, cityID int) ([]models.District, error) {
+// districtShape разобранная граница района для проверки попадания точки
+type districtShape struct {
+	ID     int
+	CityID int
+	Shape  geo.MultiPolygon
+}
+
+func validateBoundary(boundary *geojson.Geometry) error {
+	if boundary == nil {
+		return nil
+	}
+
+	shape, err := boundary.MultiPolygon()
+	if err != nil {
+		return fmt.Errorf("%w: %v", customerr.BadInput, err)
+	}
+	if !shape.Valid() {
+		return fmt.Errorf("%w: boundary rings must be closed, have at least 4 points and valid coordinates", customerr.BadInput)
+	}
+
+	return nil
+}
+
+// loadDistrictShapes границы, которые не удалось разобрать, пропускаются - они проверяются при сохранении
+func loadDistrictShapes(ctx context.Context, districtRepo repository.District, cityID int) ([]districtShape, error) {
+	districts, err := districtRepo.GetWithBoundaries(ctx, cityID)
+	if err != nil {
+		return nil, err
+	}
+
+	shapes := make([]districtShape, 0, len(districts))
+	for _, district := range districts {
+		shape, err := district.Boundary.MultiPolygon()
+		if err != nil {
+			continue
+		}
+
+		shapes = append(shapes, districtShape{ID: district.ID, CityID: district.CityID, Shape: shape})
+	}
+
+	return shapes, nil
+}
+
+// locateDistrict при пересечении границ выигрывает район с меньшим id, shapes упорядочены по id
+func locateDistrict(shapes []districtShape, point models.Point) (districtShape, bool) {
+	for _, shape := range shapes {
+		if shape.Shape.Contains(point.Lon, point.Lat) {
+			return shape, true
+		}
+	}
+
+	return districtShape{}, false
+}
+
+// reconcileDistricts сравнивает сохранённый район с вычисленным по координатам.
+// Города без границ районов не проверяются
+func reconcileDistricts(places []models.PlaceLocation, shapes []districtShape) models.DistrictReconciliation {
+	citiesWithShapes := make(map[int]bool)
+	for _, shape := range shapes {
+		citiesWithShapes[shape.CityID] = true
+	}
+
+	report := models.DistrictReconciliation{Mismatches: []models.DistrictMismatch{}}
+	for _, place := range places {
+		if place.Coordinates == nil || !citiesWithShapes[place.CityID] {
+			continue
+		}
+		report.Checked++
+
+		var computedID int
+		if shape, ok := locateDistrict(shapes, *place.Coordinates); ok && shape.CityID == place.CityID {
+			computedID = shape.ID
+		}
+
+		if computedID != place.DistrictID {
+			report.Mismatches = append(report.Mismatches, models.DistrictMismatch{
+				PlaceID:            place.ID,
+				PlaceName:          place.Name,
+				CityID:             place.CityID,
+				StoredDistrictID:   place.DistrictID,
+				ComputedDistrictID: computedID,
+			})
+		}
+	}
+
+	return report
+}
+
+func (d districtService) GetByCityID(ctx context.Context, cityID int) ([]models.District, error) {
 	districts, err := d.districtRepo.GetByCityID(ctx, cityID)
 	if err != nil {
 		d.logger.Error(err.Error())
@@ -28,3 +121,110 @@ func (d districtService) GetByID(ctx context.Context, cityID int) ([]models.Dist
 
 	return districts, nil
 }
+
+func (d districtService) Create(ctx context.Context, district models.DistrictBase) (int, error) {
+	if strings.TrimSpace(district.Name) == "" {
+		return 0, fmt.Errorf("%w: district name is required", customerr.BadInput)
+	}
+	if err := validateBoundary(district.Boundary); err != nil {
+		return 0, err
+	}
+
+	id, err := d.districtRepo.Create(ctx, district)
+	if err != nil {
+		d.logger.Error(err.Error())
+		return 0, err
+	}
+
+	return id, nil
+}
+
+func (d districtService) GetByID(ctx context.Context, districtID int) (models.District, error) {
+	district, err := d.districtRepo.GetByID(ctx, districtID)
+	if err != nil {
+		d.logger.Error(err.Error())
+		return models.District{}, err
+	}
+
+	return district, nil
+}
+
+func (d districtService) Update(ctx context.Context, district models.DistrictUpdate) error {
+	if strings.TrimSpace(district.Name) == "" {
+		return fmt.Errorf("%w: district name is required", customerr.BadInput)
+	}
+	if err := validateBoundary(district.Boundary); err != nil {
+		return err
+	}
+
+	err := d.districtRepo.Update(ctx, district)
+	if err != nil {
+		d.logger.Error(err.Error())
+		return err
+	}
+
+	return nil
+}
+
+func (d districtService) Delete(ctx context.Context, districtID int) error {
+	err := d.districtRepo.Delete(ctx, districtID)
+	if err != nil {
+		d.logger.Error(err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// GetPolygons границы районов для подложки карты, при cityID = 0 по всем городам
+func (d districtService) GetPolygons(ctx context.Context, cityID int) (geojson.FeatureCollection, error) {
+	districts, err := d.districtRepo.GetWithBoundaries(ctx, cityID)
+	if err != nil {
+		d.logger.Error(err.Error())
+		return geojson.FeatureCollection{}, err
+	}
+
+	features := make([]geojson.Feature, 0, len(districts))
+	for _, district := range districts {
+		features = append(features, geojson.NewFeature(district.ID, district.Boundary, map[string]interface{}{
+			"name":    district.Name,
+			"city_id": district.CityID,
+		}))
+	}
+
+	return geojson.NewFeatureCollection(features), nil
+}
+
+// Reconcile при apply места переносятся в вычисленный район, у мест вне всех границ города район снимается, как при сохранении места
+func (d districtService) Reconcile(ctx context.Context, cityID int, apply bool) (models.DistrictReconciliation, error) {
+	shapes, err := loadDistrictShapes(ctx, d.districtRepo, cityID)
+	if err != nil {
+		d.logger.Error(err.Error())
+		return models.DistrictReconciliation{}, err
+	}
+
+	places, err := d.districtRepo.GetPlaceLocations(ctx, cityID)
+	if err != nil {
+		d.logger.Error(err.Error())
+		return models.DistrictReconciliation{}, err
+	}
+
+	report := reconcileDistricts(places, shapes)
+	if !apply {
+		return report, nil
+	}
+
+	assignments := make(map[int]int)
+	for _, mismatch := range report.Mismatches {
+		assignments[mismatch.PlaceID] = mismatch.ComputedDistrictID
+	}
+
+	report.Updated, err = d.districtRepo.AssignPlaces(ctx, assignments)
+	if err != nil {
+		d.logger.Error(err.Error())
+		return models.DistrictReconciliation{}, err
+	}
+	report.Applied = true
+
+	return report, nil
+}
@@ -0,0 +1,149 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"mth/internal/models"
+	"mth/internal/repository"
+	"mth/pkg/customerr"
+	"mth/pkg/geo"
+	"mth/pkg/geojson"
+	"testing"
+)
+
+func square(lon, lat, size float64) geo.MultiPolygon {
+	return geo.MultiPolygon{geo.Polygon{geo.Ring{
+		{lon, lat}, {lon + size, lat}, {lon + size, lat + size}, {lon, lat + size}, {lon, lat},
+	}}}
+}
+
+func TestReconcileDistricts(t *testing.T) {
+	shapes := []districtShape{
+		{ID: 1, CityID: 1, Shape: square(37, 55, 1)},
+		{ID: 2, CityID: 1, Shape: square(38, 55, 1)},
+	}
+
+	places := []models.PlaceLocation{
+		{ID: 10, CityID: 1, DistrictID: 1, Coordinates: &models.Point{Lat: 55.5, Lon: 37.5}},
+		{ID: 11, CityID: 1, DistrictID: 1, Coordinates: &models.Point{Lat: 55.5, Lon: 38.5}},
+		{ID: 12, CityID: 1, DistrictID: 2, Coordinates: &models.Point{Lat: 50, Lon: 30}},
+		{ID: 13, CityID: 2, DistrictID: 5, Coordinates: &models.Point{Lat: 59.9, Lon: 30.3}},
+		{ID: 14, CityID: 1, DistrictID: 1},
+	}
+
+	report := reconcileDistricts(places, shapes)
+
+	if report.Checked != 3 {
+		t.Errorf("expected 3 checked places, got %d", report.Checked)
+	}
+	if len(report.Mismatches) != 2 {
+		t.Fatalf("expected 2 mismatches, got %+v", report.Mismatches)
+	}
+	if m := report.Mismatches[0]; m.PlaceID != 11 || m.StoredDistrictID != 1 || m.ComputedDistrictID != 2 {
+		t.Errorf("unexpected mismatch %+v", m)
+	}
+	if m := report.Mismatches[1]; m.PlaceID != 12 || m.ComputedDistrictID != 0 {
+		t.Errorf("unexpected mismatch %+v", m)
+	}
+}
+
+func TestValidateBoundary(t *testing.T) {
+	if err := validateBoundary(nil); err != nil {
+		t.Errorf("expected empty boundary to be valid, got %v", err)
+	}
+
+	polygon := &geojson.Geometry{Type: geojson.TypePolygon, Coordinates: []byte(`[[[37,55],[38,55],[38,56],[37,55]]]`)}
+	if err := validateBoundary(polygon); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	open := &geojson.Geometry{Type: geojson.TypePolygon, Coordinates: []byte(`[[[37,55],[38,55],[38,56],[37,56]]]`)}
+	if err := validateBoundary(open); !errors.Is(err, customerr.BadInput) {
+		t.Errorf("expected bad input for open ring, got %v", err)
+	}
+
+	point := &geojson.Geometry{Type: geojson.TypePoint, Coordinates: []byte(`[37,55]`)}
+	if err := validateBoundary(point); !errors.Is(err, customerr.BadInput) {
+		t.Errorf("expected bad input for point, got %v", err)
+	}
+}
+
+func TestApplyDistrict(t *testing.T) {
+	shapes := []districtShape{{ID: 1, CityID: 1, Shape: square(37, 55, 1)}}
+
+	inside := models.PlaceBase{CityID: 3, DistrictID: 7, Coordinates: &models.Point{Lat: 55.5, Lon: 37.5}}
+	applyDistrict(shapes, &inside)
+	if inside.DistrictID != 1 || inside.CityID != 1 {
+		t.Errorf("point inside boundary must get its district and city, got %+v", inside)
+	}
+
+	outside := models.PlaceBase{CityID: 1, DistrictID: 7, Coordinates: &models.Point{Lat: 50, Lon: 30}}
+	applyDistrict(shapes, &outside)
+	if outside.DistrictID != 0 || outside.CityID != 1 {
+		t.Errorf("point outside every boundary must lose client district, got %+v", outside)
+	}
+
+	unmapped := models.PlaceBase{CityID: 2, DistrictID: 7, Coordinates: &models.Point{Lat: 50, Lon: 30}}
+	applyDistrict(nil, &unmapped)
+	if unmapped.DistrictID != 7 {
+		t.Errorf("city without boundaries keeps client district, got %+v", unmapped)
+	}
+}
+
+func TestDistrictAssigner(t *testing.T) {
+	assign := districtAssigner([]districtShape{{ID: 1, CityID: 1, Shape: square(37, 55, 1)}})
+
+	inside := models.PlaceBase{CityID: 1, DistrictID: 7, Coordinates: &models.Point{Lat: 55.5, Lon: 37.5}}
+	assign(&inside)
+	if inside.DistrictID != 1 {
+		t.Errorf("imported point inside boundary must get its district, got %+v", inside)
+	}
+
+	outside := models.PlaceBase{CityID: 1, DistrictID: 7, Coordinates: &models.Point{Lat: 50, Lon: 30}}
+	assign(&outside)
+	if outside.DistrictID != 0 {
+		t.Errorf("imported point outside every boundary must lose its district, got %+v", outside)
+	}
+
+	unmapped := models.PlaceBase{CityID: 2, DistrictID: 7, Coordinates: &models.Point{Lat: 55.5, Lon: 37.5}}
+	assign(&unmapped)
+	if unmapped.DistrictID != 7 || unmapped.CityID != 2 {
+		t.Errorf("city without boundaries keeps district from the file, got %+v", unmapped)
+	}
+}
+
+// fakeReconcileRepo отдаёт одну границу и запоминает переназначения
+type fakeReconcileRepo struct {
+	repository.District
+	places      []models.PlaceLocation
+	assignments map[int]int
+}
+
+func (f *fakeReconcileRepo) GetWithBoundaries(_ context.Context, _ int) ([]models.District, error) {
+	boundary := &geojson.Geometry{Type: geojson.TypePolygon, Coordinates: []byte(`[[[37,55],[38,55],[38,56],[37,56],[37,55]]]`)}
+	return []models.District{{ID: 1, DistrictBase: models.DistrictBase{CityID: 1, Boundary: boundary}}}, nil
+}
+
+func (f *fakeReconcileRepo) GetPlaceLocations(_ context.Context, _ int) ([]models.PlaceLocation, error) {
+	return f.places, nil
+}
+
+func (f *fakeReconcileRepo) AssignPlaces(_ context.Context, assignments map[int]int) (int, error) {
+	f.assignments = assignments
+	return len(assignments), nil
+}
+
+func TestReconcileClearsPlacesOutsideBoundaries(t *testing.T) {
+	repo := &fakeReconcileRepo{places: []models.PlaceLocation{
+		{ID: 10, CityID: 1, DistrictID: 2, Coordinates: &models.Point{Lat: 55.5, Lon: 37.5}},
+		{ID: 11, CityID: 1, DistrictID: 1, Coordinates: &models.Point{Lat: 50, Lon: 30}},
+	}}
+
+	report, err := districtService{districtRepo: repo}.Reconcile(context.TODO(), 1, true)
+	if err != nil || !report.Applied || report.Updated != 2 {
+		t.Fatalf("expected both places to be reassigned, got %+v, %v", report, err)
+	}
+	if repo.assignments[10] != 1 || repo.assignments[11] != 0 {
+		t.Errorf("expected place inside to move and place outside to lose its district, got %v", repo.assignments)
+	}
+}
@@ -0,0 +1,210 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/spf13/viper"
+	"mth/internal/models"
+	"mth/internal/repository"
+	"mth/pkg/config"
+	"mth/pkg/customerr"
+	"mth/pkg/geo"
+	"mth/pkg/log"
+	"mth/pkg/pagination"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+type placeDuplicateService struct {
+	duplicateRepo repository.PlaceDuplicate
+	logger        *log.Logs
+}
+
+func InitPlaceDuplicateService(duplicateRepo repository.PlaceDuplicate, logger *log.Logs) PlaceDuplicate {
+	return placeDuplicateService{
+		duplicateRepo: duplicateRepo,
+		logger:        logger,
+	}
+}
+
+// duplicateThresholds Radius в метрах. Места без координат сравниваются только по названию с порогом NameOnlySimilarity
+type duplicateThresholds struct {
+	Similarity         float64
+	NameOnlySimilarity float64
+	Radius             float64
+}
+
+// nameTrigrams триграммы слов названия как в pg_trgm: регистр и ё не учитываются, каждое слово дополняется пробелами
+func nameTrigrams(name string) map[string]struct{} {
+	name = strings.ReplaceAll(strings.ToLower(name), "ё", "е")
+	words := strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	trigrams := make(map[string]struct{})
+	for _, word := range words {
+		padded := []rune("  " + word + " ")
+		for i := 0; i+3 <= len(padded); i++ {
+			trigrams[string(padded[i:i+3])] = struct{}{}
+		}
+	}
+
+	return trigrams
+}
+
+// nameSimilarity доля общих триграмм от 0 до 1, порядок слов не важен
+func nameSimilarity(a, b string) float64 {
+	trigramsA := nameTrigrams(a)
+	trigramsB := nameTrigrams(b)
+	if len(trigramsA) == 0 || len(trigramsB) == 0 {
+		return 0
+	}
+
+	var common int
+	for trigram := range trigramsA {
+		if _, ok := trigramsB[trigram]; ok {
+			common++
+		}
+	}
+
+	return float64(common) / float64(len(trigramsA)+len(trigramsB)-common)
+}
+
+// findDuplicates сравнивает места попарно внутри города, в паре меньший id идёт первым
+func findDuplicates(places []models.PlaceLocation, thresholds duplicateThresholds) []models.PlaceDuplicate {
+	byCity := make(map[int][]models.PlaceLocation)
+	for _, place := range places {
+		byCity[place.CityID] = append(byCity[place.CityID], place)
+	}
+
+	var duplicates []models.PlaceDuplicate
+	for _, cityPlaces := range byCity {
+		sort.Slice(cityPlaces, func(i, j int) bool {
+			return cityPlaces[i].ID < cityPlaces[j].ID
+		})
+
+		for i := range cityPlaces {
+			for j := i + 1; j < len(cityPlaces); j++ {
+				first, second := cityPlaces[i], cityPlaces[j]
+
+				var distance *float64
+				threshold := thresholds.NameOnlySimilarity
+				if first.Coordinates != nil && second.Coordinates != nil {
+					meters := geo.Distance(first.Coordinates.Lat, first.Coordinates.Lon, second.Coordinates.Lat, second.Coordinates.Lon)
+					if meters > thresholds.Radius {
+						continue
+					}
+					distance = &meters
+					threshold = thresholds.Similarity
+				}
+
+				similarity := nameSimilarity(first.Name, second.Name)
+				if similarity < threshold {
+					continue
+				}
+
+				duplicates = append(duplicates, models.PlaceDuplicate{
+					PlaceID:       first.ID,
+					PlaceName:     first.Name,
+					DuplicateID:   second.ID,
+					DuplicateName: second.Name,
+					Similarity:    similarity,
+					Distance:      distance,
+					Status:        models.DuplicateStatusPending,
+				})
+			}
+		}
+	}
+
+	sort.Slice(duplicates, func(i, j int) bool {
+		if duplicates[i].PlaceID != duplicates[j].PlaceID {
+			return duplicates[i].PlaceID < duplicates[j].PlaceID
+		}
+		return duplicates[i].DuplicateID < duplicates[j].DuplicateID
+	})
+
+	return duplicates
+}
+
+// Detect ищет дубли среди неархивных мест и сохраняет новые пары, отклонённые пары остаются отклонёнными
+func (d placeDuplicateService) Detect(ctx context.Context) (int, error) {
+	places, err := d.duplicateRepo.GetLocations(ctx)
+	if err != nil {
+		d.logger.Error(err.Error())
+		return 0, err
+	}
+
+	duplicates := findDuplicates(places, duplicateThresholds{
+		Similarity:         viper.GetFloat64(config.PlaceDuplicatesSimilarity),
+		NameOnlySimilarity: viper.GetFloat64(config.PlaceDuplicatesNameOnlySimilarity),
+		Radius:             viper.GetFloat64(config.PlaceDuplicatesRadius),
+	})
+
+	if err = d.duplicateRepo.Save(ctx, duplicates); err != nil {
+		d.logger.Error(err.Error())
+		return 0, err
+	}
+
+	return len(duplicates), nil
+}
+
+func (d placeDuplicateService) StartDetection(ctx context.Context) {
+	interval := time.Duration(viper.GetInt(config.PlaceDuplicatesInterval)) * time.Minute
+
+	runPeriodically(ctx, interval, d.logger, "place duplicates detection", func(ctx context.Context) {
+		if found, err := d.Detect(ctx); err == nil {
+			d.logger.Info(fmt.Sprintf("place duplicates detection found %v pairs", found))
+		}
+	})
+}
+
+func (d placeDuplicateService) GetPending(ctx context.Context, page models.PageRequest) (models.Page[models.PlaceDuplicate], error) {
+	page.PageSize = pagination.PageSize(page.PageSize, viper.GetInt(config.PlacesOnPage), viper.GetInt(config.MaxPageSize))
+
+	duplicates, err := d.duplicateRepo.GetPending(ctx, page)
+	if err != nil {
+		if !errors.Is(err, customerr.InvalidCursor) {
+			d.logger.Error(err.Error())
+		}
+		return models.Page[models.PlaceDuplicate]{}, err
+	}
+
+	return duplicates, nil
+}
+
+func (d placeDuplicateService) Dismiss(ctx context.Context, duplicateID int) error {
+	err := d.duplicateRepo.Dismiss(ctx, duplicateID)
+	if err != nil {
+		d.logger.Error(err.Error())
+		return err
+	}
+
+	return nil
+}
+
+func (d placeDuplicateService) Merge(ctx context.Context, request models.PlaceMergeRequest) (models.PlaceMerge, error) {
+	if request.SurvivorID == request.DuplicateID {
+		return models.PlaceMerge{}, customerr.MergeSamePlace
+	}
+
+	merge, err := d.duplicateRepo.Merge(ctx, request)
+	if err != nil {
+		d.logger.Error(err.Error())
+		return models.PlaceMerge{}, err
+	}
+
+	return merge, nil
+}
+
+func (d placeDuplicateService) GetMerges(ctx context.Context, survivorID int) ([]models.PlaceMerge, error) {
+	merges, err := d.duplicateRepo.GetMerges(ctx, survivorID)
+	if err != nil {
+		d.logger.Error(err.Error())
+		return []models.PlaceMerge{}, err
+	}
+
+	return merges, nil
+}
@@ -0,0 +1,52 @@
+package service
+
+import (
+	"mth/internal/models"
+	"testing"
+)
+
+func TestNameSimilarity(t *testing.T) {
+	if similarity := nameSimilarity("Парк Коптево", "Коптевский парк"); similarity < 0.5 {
+		t.Errorf("expected similar names, got %v", similarity)
+	}
+	if similarity := nameSimilarity("Парк Горького", "парк горького!"); similarity != 1 {
+		t.Errorf("expected case and punctuation to be ignored, got %v", similarity)
+	}
+	if similarity := nameSimilarity("Ёлки", "Елки"); similarity != 1 {
+		t.Errorf("expected ё and е to match, got %v", similarity)
+	}
+	if similarity := nameSimilarity("Парк Коптево", "Музей космонавтики"); similarity > 0.2 {
+		t.Errorf("expected different names, got %v", similarity)
+	}
+	if similarity := nameSimilarity("", "Парк"); similarity != 0 {
+		t.Errorf("expected zero for empty name, got %v", similarity)
+	}
+}
+
+func TestFindDuplicates(t *testing.T) {
+	thresholds := duplicateThresholds{Similarity: 0.4, NameOnlySimilarity: 0.8, Radius: 200}
+
+	places := []models.PlaceLocation{
+		{ID: 3, Name: "Коптевский парк", CityID: 1, Coordinates: &models.Point{Lat: 55.8301, Lon: 37.5201}},
+		{ID: 1, Name: "Парк Коптево", CityID: 1, Coordinates: &models.Point{Lat: 55.83, Lon: 37.52}},
+		{ID: 2, Name: "Парк Коптево", CityID: 2, Coordinates: &models.Point{Lat: 55.83, Lon: 37.52}},
+		{ID: 4, Name: "Парк Коптево у пруда", CityID: 1, Coordinates: &models.Point{Lat: 55.9, Lon: 37.52}},
+		{ID: 5, Name: "парк коптево", CityID: 1},
+	}
+
+	duplicates := findDuplicates(places, thresholds)
+
+	if len(duplicates) != 2 {
+		t.Fatalf("expected 2 pairs, got %+v", duplicates)
+	}
+
+	near := duplicates[0]
+	if near.PlaceID != 1 || near.DuplicateID != 3 || near.Distance == nil || *near.Distance > 20 {
+		t.Errorf("unexpected nearby pair %+v", near)
+	}
+
+	nameOnly := duplicates[1]
+	if nameOnly.PlaceID != 1 || nameOnly.DuplicateID != 5 || nameOnly.Distance != nil {
+		t.Errorf("unexpected name only pair %+v", nameOnly)
+	}
+}
@@ -11,14 +11,17 @@ type favouriteService struct {
 	favouriteRepo repository.Favourite
 	placeRepo     repository.Place
 	routeRepo     repository.Route
+	localizer     localizer
 	logger        *log.Logs
 }
 
-func InitFavouriteService(favouriteRepo repository.Favourite, placeRepo repository.Place, routeRepo repository.Route, logger *log.Logs) Favourite {
+func InitFavouriteService(favouriteRepo repository.Favourite, placeRepo repository.Place, routeRepo repository.Route,
+	translationRepo repository.Translation, logger *log.Logs) Favourite {
 	return favouriteService{
 		favouriteRepo: favouriteRepo,
 		placeRepo:     placeRepo,
 		routeRepo:     routeRepo,
+		localizer:     localizer{translationRepo: translationRepo},
 		logger:        logger,
 	}
 }
@@ -74,6 +77,24 @@ func (f favouriteService) GetLikedByUser(ctx context.Context, userID int) ([]mod
 		routesRaw = append(routesRaw, routeRaw)
 	}
 
+	placePointers := make([]*models.Place, 0, len(places))
+	for i := range places {
+		placePointers = append(placePointers, &places[i])
+	}
+	routePointers := make([]*models.RouteRaw, 0, len(routesRaw))
+	for i := range routesRaw {
+		routePointers = append(routePointers, &routesRaw[i])
+	}
+
+	if err = f.localizer.places(ctx, placePointers); err != nil {
+		f.logger.Error(err.Error())
+		return []models.Place{}, []models.RouteRaw{}, err
+	}
+	if err = f.localizer.routesRaw(ctx, routePointers); err != nil {
+		f.logger.Error(err.Error())
+		return []models.Place{}, []models.RouteRaw{}, err
+	}
+
 	return places, routesRaw, nil
 }
 
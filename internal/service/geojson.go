@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/spf13/viper"
+	"mth/internal/models"
+	"mth/internal/models/swagger"
+	"mth/pkg/config"
+	"mth/pkg/customerr"
+	"mth/pkg/geojson"
+	"sort"
+)
+
+const (
+	featureKindPlace = "place"
+	featureKindRoute = "route"
+	featureKindStop  = "stop"
+)
+
+func tagNames(tags []models.Tag) []string {
+	names := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		names = append(names, tag.Name)
+	}
+
+	return names
+}
+
+func ratingProperties(rating models.Rating) map[string]interface{} {
+	return map[string]interface{}{
+		"average": rating.Average,
+		"count":   rating.Count,
+	}
+}
+
+func placeFeature(place models.Place, kind string) geojson.Feature {
+	return geojson.NewFeature(place.ID, geojson.NewPoint(place.Coordinates.Lon, place.Coordinates.Lat), map[string]interface{}{
+		"kind":        kind,
+		"name":        place.Name,
+		"variety":     place.Variety,
+		"tags":        tagNames(place.Tags),
+		"rating":      ratingProperties(place.Rating),
+		"city_id":     place.CityID,
+		"district_id": place.DistrictID,
+	})
+}
+
+// GetGeoJSON те же фильтры, что у GetAllWithFilter, но страница размером GEOJSON_MAX_FEATURES и только места с координатами.
+// Если мест больше, коллекция помечается обрезанной и получает курсор следующей страницы
+func (p placeService) GetGeoJSON(ctx context.Context, filters swagger.Filters) (geojson.FeatureCollection, error) {
+	placeFilters, err := p.placeFilters(ctx, filters)
+	if err != nil {
+		return geojson.FeatureCollection{}, err
+	}
+	placeFilters.Limit = viper.GetInt(config.GeoJSONMaxFeatures)
+	placeFilters.WithCoordinates = true
+	placeFilters.WithTotal = false
+
+	places, err := p.placeRepo.GetAllWithFilter(ctx, placeFilters)
+	if err != nil {
+		if !errors.Is(err, customerr.InvalidCursor) {
+			p.logger.Error(err.Error())
+		}
+		return geojson.FeatureCollection{}, err
+	}
+
+	if err = p.localizePage(ctx, places.Items); err != nil {
+		return geojson.FeatureCollection{}, err
+	}
+
+	features := make([]geojson.Feature, 0, len(places.Items))
+	for _, place := range places.Items {
+		features = append(features, placeFeature(place, featureKindPlace))
+	}
+
+	collection := geojson.NewFeatureCollection(features)
+	collection.NextCursor = places.NextCursor
+	collection.Truncated = places.HasMore
+
+	return collection, nil
+}
+
+// GetGeoJSON линия через остановки маршрута по порядку и точка на каждую остановку.
+// Остановки без координат пропускаются, линия не строится, если точек меньше двух
+func (r routeService) GetGeoJSON(ctx context.Context, routeID int) (geojson.FeatureCollection, error) {
+	route, err := r.GetByID(ctx, routeID)
+	if err != nil {
+		return geojson.FeatureCollection{}, err
+	}
+
+	sort.SliceStable(route.Places, func(i, j int) bool {
+		return route.Places[i].Position < route.Places[j].Position
+	})
+
+	var line [][2]float64
+	var stops []geojson.Feature
+
+	for _, stop := range route.Places {
+		if stop.Place.Coordinates == nil {
+			continue
+		}
+
+		line = append(line, [2]float64{stop.Place.Coordinates.Lon, stop.Place.Coordinates.Lat})
+
+		feature := placeFeature(stop.Place, featureKindStop)
+		feature.Properties["position"] = stop.Position
+		feature.Properties["route_id"] = route.ID
+		stops = append(stops, feature)
+	}
+
+	features := make([]geojson.Feature, 0, len(stops)+1)
+	if len(line) >= 2 {
+		features = append(features, geojson.NewFeature(route.ID, geojson.NewLineString(line), map[string]interface{}{
+			"kind":    featureKindRoute,
+			"name":    route.Name,
+			"price":   route.Price,
+			"city_id": route.CityID,
+			"tags":    tagNames(route.Tags),
+			"rating":  ratingProperties(route.Rating),
+		}))
+	}
+	features = append(features, stops...)
+
+	return geojson.NewFeatureCollection(features), nil
+}
@@ -0,0 +1,305 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/spf13/viper"
+	"io"
+	"mth/internal/models"
+	"mth/internal/repository"
+	"mth/pkg/config"
+	"mth/pkg/customerr"
+	"mth/pkg/geojson"
+	"mth/pkg/log"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	importColumnName      = "name"
+	importColumnVariety   = "variety"
+	importColumnCity      = "city"
+	importColumnDistrict  = "district"
+	importColumnTags      = "tags"
+	importColumnLatitude  = "lat"
+	importColumnLongitude = "lon"
+
+	// importTagsSeparator разделитель тегов внутри одной ячейки CSV
+	importTagsSeparator = ";"
+)
+
+type importService struct {
+	importRepo   repository.Import
+	districtRepo repository.District
+	logger       *log.Logs
+}
+
+func InitImportService(importRepo repository.Import, districtRepo repository.District, logger *log.Logs) Import {
+	return importService{
+		importRepo:   importRepo,
+		districtRepo: districtRepo,
+		logger:       logger,
+	}
+}
+
+func badImportFile(err error) error {
+	return fmt.Errorf("%w: %v", customerr.ImportBadFile, err)
+}
+
+func splitImportTags(raw string) []string {
+	var tags []string
+	for _, tag := range strings.Split(raw, importTagsSeparator) {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+
+	return tags
+}
+
+// parseImportCSV первая строка заголовок, колонки кроме известных попадают в properties
+func parseImportCSV(file io.Reader) ([]models.PlaceImport, []models.ImportRowError, error) {
+	reader := csv.NewReader(file)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, nil, badImportFile(err)
+	}
+	for i := range header {
+		header[i] = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(header[i], "\ufeff")))
+	}
+
+	var places []models.PlaceImport
+	var rowErrors []models.ImportRowError
+
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) && errors.Is(parseErr.Err, csv.ErrFieldCount) {
+				rowErrors = append(rowErrors, models.ImportRowError{Row: parseErr.StartLine, Error: err.Error()})
+				continue
+			}
+
+			return nil, nil, badImportFile(err)
+		}
+
+		// номер строки начала записи, значения в кавычках могут быть многострочными
+		row, _ := reader.FieldPos(0)
+
+		place := models.PlaceImport{Row: row, Properties: map[string]interface{}{}}
+		var latitude, longitude string
+
+		for i, value := range record {
+			value = strings.TrimSpace(value)
+
+			switch header[i] {
+			case importColumnName:
+				place.Name = value
+			case importColumnVariety:
+				place.Variety = value
+			case importColumnCity:
+				place.City = value
+			case importColumnDistrict:
+				place.District = value
+			case importColumnTags:
+				place.Tags = splitImportTags(value)
+			case importColumnLatitude:
+				latitude = value
+			case importColumnLongitude:
+				longitude = value
+			default:
+				if value != "" {
+					place.Properties[header[i]] = value
+				}
+			}
+		}
+
+		if latitude != "" || longitude != "" {
+			lat, latErr := strconv.ParseFloat(latitude, 64)
+			lon, lonErr := strconv.ParseFloat(longitude, 64)
+			if latErr != nil || lonErr != nil {
+				rowErrors = append(rowErrors, models.ImportRowError{Row: row, Name: place.Name, Error: "invalid coordinates"})
+				continue
+			}
+
+			place.Coordinates = &models.Point{Lat: lat, Lon: lon}
+		}
+
+		places = append(places, place)
+	}
+
+	return places, rowErrors, nil
+}
+
+// parseImportGeoJSON ожидает FeatureCollection с геометрией Point, известные поля берутся из properties
+func parseImportGeoJSON(file io.Reader) ([]models.PlaceImport, []models.ImportRowError, error) {
+	var collection geojson.FeatureCollection
+	if err := json.NewDecoder(file).Decode(&collection); err != nil {
+		return nil, nil, badImportFile(err)
+	}
+	if collection.Type != geojson.TypeFeatureCollection {
+		return nil, nil, badImportFile(fmt.Errorf("expected %s, got %q", geojson.TypeFeatureCollection, collection.Type))
+	}
+
+	var places []models.PlaceImport
+	var rowErrors []models.ImportRowError
+
+	for i, feature := range collection.Features {
+		place := models.PlaceImport{Row: i + 1, Properties: map[string]interface{}{}}
+		var rowErr string
+
+		for key, value := range feature.Properties {
+			stringValue, isString := value.(string)
+			stringValue = strings.TrimSpace(stringValue)
+
+			switch strings.ToLower(key) {
+			case importColumnName:
+				place.Name = stringValue
+			case importColumnVariety:
+				place.Variety = stringValue
+			case importColumnCity:
+				place.City = stringValue
+			case importColumnDistrict:
+				place.District = stringValue
+			case importColumnTags:
+				switch tags := value.(type) {
+				case string:
+					place.Tags = splitImportTags(tags)
+				case []interface{}:
+					for _, tag := range tags {
+						tagName, ok := tag.(string)
+						if !ok {
+							rowErr = "tags must be strings"
+							break
+						}
+						if tagName = strings.TrimSpace(tagName); tagName != "" {
+							place.Tags = append(place.Tags, tagName)
+						}
+					}
+				case nil:
+				default:
+					rowErr = "tags must be a string or an array of strings"
+				}
+				continue
+			default:
+				place.Properties[key] = value
+				continue
+			}
+
+			if !isString && value != nil {
+				rowErr = fmt.Sprintf("property %q must be a string", key)
+			}
+		}
+
+		if rowErr == "" && feature.Geometry != nil {
+			lon, lat, err := feature.Geometry.Point()
+			if err != nil {
+				rowErr = err.Error()
+			} else {
+				place.Coordinates = &models.Point{Lat: lat, Lon: lon}
+			}
+		}
+
+		if rowErr != "" {
+			rowErrors = append(rowErrors, models.ImportRowError{Row: place.Row, Name: place.Name, Error: rowErr})
+			continue
+		}
+
+		places = append(places, place)
+	}
+
+	return places, rowErrors, nil
+}
+
+// validateImportPlaces проверки, не требующие базы: имя, координаты, повторы имени в файле
+func validateImportPlaces(places []models.PlaceImport) ([]models.PlaceImport, []models.ImportRowError) {
+	valid := make([]models.PlaceImport, 0, len(places))
+	var rowErrors []models.ImportRowError
+	seen := make(map[string]int)
+
+	for _, place := range places {
+		rowErr := ""
+
+		switch {
+		case place.Name == "":
+			rowErr = "name is required"
+		case place.Coordinates != nil && !place.Coordinates.Valid():
+			rowErr = "coordinates are out of range"
+		case seen[place.Name] != 0:
+			rowErr = fmt.Sprintf("duplicate name, first seen in row %d", seen[place.Name])
+		}
+
+		if rowErr != "" {
+			rowErrors = append(rowErrors, models.ImportRowError{Row: place.Row, Name: place.Name, Error: rowErr})
+			continue
+		}
+
+		seen[place.Name] = place.Row
+		valid = append(valid, place)
+	}
+
+	return valid, rowErrors
+}
+
+func (i importService) ImportPlaces(ctx context.Context, format string, file io.Reader, dryRun bool) (models.ImportReport, error) {
+	maxSize := viper.GetInt64(config.ImportMaxSize)
+
+	data, err := io.ReadAll(io.LimitReader(file, maxSize+1))
+	if err != nil {
+		i.logger.Error(err.Error())
+		return models.ImportReport{}, err
+	}
+	if int64(len(data)) > maxSize {
+		return models.ImportReport{}, customerr.ImportTooLarge
+	}
+
+	var places []models.PlaceImport
+	var parseErrors []models.ImportRowError
+
+	switch format {
+	case models.ImportFormatCSV:
+		places, parseErrors, err = parseImportCSV(bytes.NewReader(data))
+	case models.ImportFormatGeoJSON:
+		places, parseErrors, err = parseImportGeoJSON(bytes.NewReader(data))
+	default:
+		err = fmt.Errorf("%w: unknown import format %q", customerr.BadInput, format)
+	}
+	if err != nil {
+		return models.ImportReport{}, err
+	}
+
+	places, validationErrors := validateImportPlaces(places)
+	rowErrors := append(parseErrors, validationErrors...)
+
+	shapes, err := loadDistrictShapes(ctx, i.districtRepo, 0)
+	if err != nil {
+		i.logger.Error(err.Error())
+		return models.ImportReport{}, err
+	}
+
+	// при ошибках разбора база всё равно проверяет остальные строки, но ничего не применяет
+	report, err := i.importRepo.ImportPlaces(ctx, places, dryRun || len(rowErrors) > 0, districtAssigner(shapes))
+	if err != nil {
+		i.logger.Error(err.Error())
+		return models.ImportReport{}, err
+	}
+
+	report.DryRun = dryRun
+	report.Total += len(rowErrors)
+	report.Errors = append(report.Errors, rowErrors...)
+	sort.SliceStable(report.Errors, func(a, b int) bool {
+		return report.Errors[a].Row < report.Errors[b].Row
+	})
+
+	return report, nil
+}
@@ -0,0 +1,79 @@
+package service
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseImportCSV(t *testing.T) {
+	file := "name,variety,district,tags,lat,lon,site\n" +
+		"Парк Коптево,Природа,Коптево,Парк; Прогулки,55.83,37.52,https://example.com\n" +
+		"Без координат,Кафе,Коптево,,,,\n" +
+		"Кривые координаты,Кафе,Коптево,,abc,37.5,\n" +
+		"Лишняя колонка,Кафе,Коптево,,,,,extra\n"
+
+	places, rowErrors, err := parseImportCSV(strings.NewReader(file))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(places) != 2 {
+		t.Fatalf("expected 2 places, got %d", len(places))
+	}
+	if len(rowErrors) != 2 || rowErrors[0].Row != 4 || rowErrors[1].Row != 5 {
+		t.Fatalf("expected errors in rows 4 and 5, got %+v", rowErrors)
+	}
+
+	park := places[0]
+	if park.Row != 2 || park.District != "Коптево" || park.Coordinates == nil || park.Coordinates.Lat != 55.83 {
+		t.Errorf("unexpected first place %+v", park)
+	}
+	if len(park.Tags) != 2 || park.Tags[1] != "Прогулки" {
+		t.Errorf("unexpected tags %v", park.Tags)
+	}
+	if park.Properties["site"] != "https://example.com" {
+		t.Errorf("extra column is not in properties: %v", park.Properties)
+	}
+	if places[1].Coordinates != nil {
+		t.Errorf("expected no coordinates, got %+v", places[1].Coordinates)
+	}
+}
+
+func TestParseImportGeoJSON(t *testing.T) {
+	file := `{"type": "FeatureCollection", "features": [
+		{"type": "Feature", "geometry": {"type": "Point", "coordinates": [37.52, 55.83]},
+			"properties": {"name": "Парк Коптево", "tags": ["Парк", "Прогулки"], "site": "https://example.com"}},
+		{"type": "Feature", "geometry": {"type": "LineString", "coordinates": [[37.52, 55.83], [37.53, 55.84]]},
+			"properties": {"name": "Линия"}},
+		{"type": "Feature", "geometry": null, "properties": {"name": 5}}
+	]}`
+
+	places, rowErrors, err := parseImportGeoJSON(strings.NewReader(file))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(places) != 1 {
+		t.Fatalf("expected 1 place, got %d", len(places))
+	}
+	if len(rowErrors) != 2 || rowErrors[0].Row != 2 || rowErrors[1].Row != 3 {
+		t.Fatalf("expected errors in features 2 and 3, got %+v", rowErrors)
+	}
+
+	park := places[0]
+	if park.Coordinates == nil || park.Coordinates.Lat != 55.83 || park.Coordinates.Lon != 37.52 {
+		t.Errorf("unexpected coordinates %+v", park.Coordinates)
+	}
+	if len(park.Tags) != 2 || park.Properties["site"] != "https://example.com" {
+		t.Errorf("unexpected place %+v", park)
+	}
+}
+
+func TestValidateImportPlaces(t *testing.T) {
+	places, _, _ := parseImportCSV(strings.NewReader("name,lat,lon\nПарк,55,37\n,55,37\nПарк,55,37\nДалеко,95,37\n"))
+
+	valid, rowErrors := validateImportPlaces(places)
+	if len(valid) != 1 || len(rowErrors) != 3 {
+		t.Fatalf("expected 1 valid place and 3 errors, got %d and %+v", len(valid), rowErrors)
+	}
+}
@@ -0,0 +1,31 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"mth/pkg/log"
+	"time"
+)
+
+// runPeriodically выполняет job в отдельной горутине каждые interval, пока не отменён ctx.
+// Ошибки логирует сам job: те же методы сервиса вызываются и из обработчиков
+func runPeriodically(ctx context.Context, interval time.Duration, logger *log.Logs, name string, job func(ctx context.Context)) {
+	if interval <= 0 {
+		logger.Info(fmt.Sprintf("job %v disabled, interval: %v", name, interval))
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				job(ctx)
+			}
+		}
+	}()
+}
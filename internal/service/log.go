@@ -0,0 +1,17 @@
+package service
+
+import (
+	"errors"
+	"mth/pkg/log"
+)
+
+// logUnexpected логирует ошибку, если она не входит в список ожидаемых ошибок сервиса
+func logUnexpected(logger *log.Logs, err error, expected []error) {
+	for _, target := range expected {
+		if errors.Is(err, target) {
+			return
+		}
+	}
+
+	logger.Error(err.Error())
+}
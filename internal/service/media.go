@@ -0,0 +1,314 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"github.com/spf13/viper"
+	"image"
+	"image/jpeg"
+	"io"
+	"mth/internal/models"
+	"mth/internal/repository"
+	"mth/pkg/config"
+	"mth/pkg/customerr"
+	"mth/pkg/log"
+	"mth/pkg/storage"
+	"mth/pkg/thumbnail"
+	"net/http"
+	"time"
+
+	_ "image/gif"
+	_ "image/png"
+)
+
+type mediaService struct {
+	mediaRepo repository.Media
+	storage   storage.Storage
+	logger    *log.Logs
+}
+
+func InitMediaService(mediaRepo repository.Media, storage storage.Storage, logger *log.Logs) Media {
+	return mediaService{
+		mediaRepo: mediaRepo,
+		storage:   storage,
+		logger:    logger,
+	}
+}
+
+// mediaExpectedErrors отказ в правах и неверные данные прикрепления ожидаемы и не логируются
+var mediaExpectedErrors = []error{customerr.UserNotEntityOwner, customerr.BadInput}
+
+func (m mediaService) logUnexpected(err error) {
+	logUnexpected(m.logger, err, mediaExpectedErrors)
+}
+
+var mediaExtensions = map[string]string{
+	"image/jpeg": "jpg",
+	"image/png":  "png",
+	"image/gif":  "gif",
+}
+
+func newMediaKey(now time.Time, extension string) (string, error) {
+	randomBytes := make([]byte, 16)
+	if _, err := rand.Read(randomBytes); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%v/%v.%v", now.Format("2006/01"), hex.EncodeToString(randomBytes), extension), nil
+}
+
+func (m mediaService) fillURLs(media *models.Media) {
+	media.URL = m.storage.URL(media.StorageKey)
+	if media.ThumbnailKey != "" {
+		media.ThumbnailURL = m.storage.URL(media.ThumbnailKey)
+	}
+}
+
+func (m mediaService) Upload(ctx context.Context, ownerID int, file io.Reader) (models.Media, error) {
+	maxSize := viper.GetInt64(config.MediaMaxSize)
+
+	data, err := io.ReadAll(io.LimitReader(file, maxSize+1))
+	if err != nil {
+		m.logger.Error(err.Error())
+		return models.Media{}, err
+	}
+
+	if int64(len(data)) > maxSize {
+		return models.Media{}, customerr.MediaTooLarge
+	}
+
+	contentType := http.DetectContentType(data)
+	extension, ok := mediaExtensions[contentType]
+	if !ok {
+		return models.Media{}, customerr.MediaUnsupportedType
+	}
+
+	// размеры читаются из заголовка до декодирования: маленький файл может объявить огромное изображение
+	imageConfig, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return models.Media{}, customerr.MediaUnsupportedType
+	}
+
+	if int64(imageConfig.Width)*int64(imageConfig.Height) > viper.GetInt64(config.MediaMaxPixels) {
+		return models.Media{}, customerr.MediaTooLarge
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		m.logger.Error(err.Error())
+		return models.Media{}, customerr.MediaUnsupportedType
+	}
+
+	now := time.Now()
+	storageKey, err := newMediaKey(now, extension)
+	if err != nil {
+		m.logger.Error(err.Error())
+		return models.Media{}, err
+	}
+
+	var thumbnailBuffer bytes.Buffer
+	err = jpeg.Encode(&thumbnailBuffer, thumbnail.Fit(img, viper.GetInt(config.MediaThumbnailSize)), &jpeg.Options{Quality: 80})
+	if err != nil {
+		m.logger.Error(err.Error())
+		return models.Media{}, err
+	}
+
+	if err = m.storage.Save(ctx, storageKey, bytes.NewReader(data)); err != nil {
+		m.logger.Error(err.Error())
+		return models.Media{}, err
+	}
+
+	thumbnailKey := "thumbs/" + storageKey + ".jpg"
+	if err = m.storage.Save(ctx, thumbnailKey, &thumbnailBuffer); err != nil {
+		m.logger.Error(err.Error())
+		_ = m.storage.Delete(ctx, storageKey)
+		return models.Media{}, err
+	}
+
+	mediaCreate := models.MediaCreate{
+		OwnerID:      ownerID,
+		StorageKey:   storageKey,
+		ThumbnailKey: thumbnailKey,
+		ContentType:  contentType,
+		Size:         int64(len(data)),
+		Width:        img.Bounds().Dx(),
+		Height:       img.Bounds().Dy(),
+	}
+
+	id, err := m.mediaRepo.Create(ctx, mediaCreate)
+	if err != nil {
+		m.logger.Error(err.Error())
+		_ = m.storage.Delete(ctx, storageKey)
+		_ = m.storage.Delete(ctx, thumbnailKey)
+		return models.Media{}, err
+	}
+
+	media := models.Media{
+		ID:          id,
+		CreatedAt:   now,
+		MediaCreate: mediaCreate,
+	}
+	m.fillURLs(&media)
+
+	return media, nil
+}
+
+func (m mediaService) GetByID(ctx context.Context, mediaID int) (models.Media, error) {
+	media, err := m.mediaRepo.GetByID(ctx, mediaID)
+	if err != nil {
+		m.logger.Error(err.Error())
+		return models.Media{}, err
+	}
+
+	m.fillURLs(&media)
+
+	return media, nil
+}
+
+func (m mediaService) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	file, err := m.storage.Open(ctx, key)
+	if err != nil {
+		m.logger.Error(err.Error())
+		return nil, err
+	}
+
+	return file, nil
+}
+
+// checkAttachmentRights прикреплять можно только свои медиа: к своим отзывам и заметкам,
+// к местам и маршрутам - их владельцам и редакторам или модератору
+func (m mediaService) checkAttachmentRights(ctx context.Context, attachment models.MediaAttachment) error {
+	media, err := m.mediaRepo.GetByID(ctx, attachment.MediaID)
+	if err != nil {
+		return err
+	}
+
+	if media.OwnerID != attachment.UserID {
+		return customerr.UserNotEntityOwner
+	}
+
+	if attachment.EntityType == models.MediaEntityPlace || attachment.EntityType == models.MediaEntityRoute {
+		manager, err := m.mediaRepo.IsEntityManager(ctx, attachment.EntityType, attachment.EntityID, attachment.UserID)
+		if err != nil {
+			return err
+		}
+
+		if !manager && checkModerator(attachment.UserID) != nil {
+			return customerr.UserNotEntityOwner
+		}
+
+		return nil
+	}
+
+	entityOwnerID, err := m.mediaRepo.GetEntityOwner(ctx, attachment.EntityType, attachment.EntityID)
+	if err != nil {
+		return err
+	}
+
+	if entityOwnerID != attachment.UserID {
+		return customerr.UserNotEntityOwner
+	}
+
+	return nil
+}
+
+func (m mediaService) Attach(ctx context.Context, attachment models.MediaAttachment) error {
+	if err := m.checkAttachmentRights(ctx, attachment); err != nil {
+		m.logUnexpected(err)
+		return err
+	}
+
+	if err := m.mediaRepo.Attach(ctx, attachment); err != nil {
+		m.logUnexpected(err)
+		return err
+	}
+
+	return nil
+}
+
+func (m mediaService) Detach(ctx context.Context, attachment models.MediaAttachment) error {
+	if err := m.checkAttachmentRights(ctx, attachment); err != nil {
+		m.logUnexpected(err)
+		return err
+	}
+
+	if err := m.mediaRepo.Detach(ctx, attachment); err != nil {
+		m.logUnexpected(err)
+		return err
+	}
+
+	return nil
+}
+
+func (m mediaService) ChangePosition(ctx context.Context, attachment models.MediaAttachment) error {
+	if err := m.checkAttachmentRights(ctx, attachment); err != nil {
+		m.logUnexpected(err)
+		return err
+	}
+
+	if err := m.mediaRepo.ChangePosition(ctx, attachment); err != nil {
+		m.logUnexpected(err)
+		return err
+	}
+
+	return nil
+}
+
+func (m mediaService) GetByEntity(ctx context.Context, entityType string, entityID int) ([]models.AttachedMedia, error) {
+	attached, err := m.mediaRepo.GetByEntity(ctx, entityType, entityID)
+	if err != nil {
+		m.logger.Error(err.Error())
+		return []models.AttachedMedia{}, err
+	}
+
+	for i := range attached {
+		m.fillURLs(&attached[i].Media)
+	}
+
+	return attached, nil
+}
+
+func (m mediaService) CollectOrphans(ctx context.Context) (int, error) {
+	ttl := time.Duration(viper.GetInt(config.MediaOrphanTTL)) * time.Minute
+
+	orphaned, err := m.mediaRepo.GetOrphaned(ctx, time.Now().Add(-ttl))
+	if err != nil {
+		m.logger.Error(err.Error())
+		return 0, err
+	}
+
+	var collected int
+	for _, media := range orphaned {
+		// запись удаляется раньше файлов, чтобы не отдать битую ссылку, если медиа прикрепили между выборкой и удалением
+		if err = m.mediaRepo.Delete(ctx, media.ID); err != nil {
+			m.logger.Error(fmt.Sprintf("orphan media %v not deleted, err: %v", media.ID, err))
+			continue
+		}
+
+		if err = m.storage.Delete(ctx, media.StorageKey); err != nil {
+			m.logger.Error(err.Error())
+		}
+		if media.ThumbnailKey != "" {
+			if err = m.storage.Delete(ctx, media.ThumbnailKey); err != nil {
+				m.logger.Error(err.Error())
+			}
+		}
+
+		collected++
+	}
+
+	return collected, nil
+}
+
+func (m mediaService) StartOrphanCollector(ctx context.Context) {
+	interval := time.Duration(viper.GetInt(config.MediaGCInterval)) * time.Minute
+
+	runPeriodically(ctx, interval, m.logger, "media orphan collector", func(ctx context.Context) {
+		if collected, err := m.CollectOrphans(ctx); err == nil {
+			m.logger.Info(fmt.Sprintf("media orphan collector removed %v files", collected))
+		}
+	})
+}
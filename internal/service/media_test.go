@@ -0,0 +1,32 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/color"
+	"image/gif"
+	"mth/pkg/config"
+	"mth/pkg/customerr"
+	"testing"
+)
+
+func TestUploadRejectsHugeDimensions(t *testing.T) {
+	config.InitConfig()
+
+	var buffer bytes.Buffer
+	img := image.NewPaletted(image.Rect(0, 0, 1, 1), color.Palette{color.Black})
+	if err := gif.Encode(&buffer, img, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// логический размер экрана GIF 65535x65535 при файле в несколько десятков байт
+	data := buffer.Bytes()
+	copy(data[6:10], []byte{0xff, 0xff, 0xff, 0xff})
+
+	_, err := mediaService{}.Upload(context.TODO(), 1, bytes.NewReader(data))
+	if !errors.Is(err, customerr.MediaTooLarge) {
+		t.Errorf("expected MediaTooLarge, got %v", err)
+	}
+}
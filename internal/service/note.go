@@ -2,35 +2,153 @@ package service
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"github.com/spf13/viper"
 	"mth/internal/models"
 	"mth/internal/repository"
+	"mth/pkg/config"
+	"mth/pkg/contentfilter"
+	"mth/pkg/customerr"
 	"mth/pkg/log"
+	"mth/pkg/pagination"
 )
 
 type noteService struct {
-	noteRepo repository.Note
-	logger   *log.Logs
+	noteRepo          repository.Note
+	contentReportRepo repository.ContentReport
+	filter            *contentfilter.Filter
+	logger            *log.Logs
 }
 
-func InitNoteService(noteRepo repository.Note, logger *log.Logs) Note {
+func InitNoteService(noteRepo repository.Note, contentReportRepo repository.ContentReport, logger *log.Logs) Note {
 	return noteService{
-		noteRepo: noteRepo,
-		logger:   logger,
+		noteRepo:          noteRepo,
+		contentReportRepo: contentReportRepo,
+		filter:            sharedContentFilter(logger),
+		logger:            logger,
+	}
+}
+
+// validateNoteTarget день указывается только у заметок к дню поездки и считается с 1, верхняя граница
+// по датам поездки проверяется при сохранении
+func validateNoteTarget(target models.NoteTarget) error {
+	switch target.TargetType {
+	case models.NoteTargetPlace, models.NoteTargetRoute, models.NoteTargetTrip:
+		if target.Day != 0 {
+			return fmt.Errorf("%w: day is only allowed for %v notes", customerr.BadInput, models.NoteTargetTripDay)
+		}
+	case models.NoteTargetTripDay:
+		if target.Day < 1 {
+			return fmt.Errorf("%w: trip days start from 1", customerr.BadInput)
+		}
+	default:
+		return fmt.Errorf("%w: unknown note target %v", customerr.BadInput, target.TargetType)
+	}
+
+	if target.TargetID <= 0 {
+		return fmt.Errorf("%w: note target id is required", customerr.BadInput)
+	}
+
+	return nil
+}
+
+// validateNoteVisibility пустая видимость допустима только при правке, где она не меняется
+func validateNoteVisibility(visibility string, allowEmpty bool) error {
+	switch visibility {
+	case models.NoteVisibilityPrivate, models.NoteVisibilityCompanions, models.NoteVisibilityLink, models.NoteVisibilityPublic:
+		return nil
+	case "":
+		if allowEmpty {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: unknown note visibility %q", customerr.BadInput, visibility)
+}
+
+func newShareToken() (string, error) {
+	randomBytes := make([]byte, 24)
+	if _, err := rand.Read(randomBytes); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(randomBytes), nil
+}
+
+// noteExpectedErrors ошибки проверок и прав доступа ожидаемы и не логируются
+var noteExpectedErrors = []error{
+	customerr.BadInput, customerr.NoteNotFound, customerr.NoteTargetNotFound, customerr.UserNotEntityOwner,
+	customerr.ContentRejected, customerr.NoteNotShareable, customerr.NoteShareTokenNotFound,
+	customerr.InvalidCursor,
+}
+
+func (n noteService) logUnexpected(err error) {
+	logUnexpected(n.logger, err, noteExpectedErrors)
+}
+
+func (n noteService) filterProperties(ctx context.Context, userID int, target models.NoteTarget,
+	properties interface{}) (interface{}, contentfilter.Verdict, error) {
+	subject, err := contentSubject(ctx, n.contentReportRepo, userID,
+		fmt.Sprintf("note:%v:%v:%v", target.TargetType, target.TargetID, target.Day))
+	if err != nil {
+		n.logger.Error(err.Error())
+		return nil, contentfilter.Verdict{}, err
+	}
+
+	return n.filter.Properties(subject, properties)
+}
+
+func (n noteService) filterNote(ctx context.Context, note models.NoteCreate) (models.NoteCreate, contentfilter.Verdict, error) {
+	properties, verdict, err := n.filterProperties(ctx, note.UserID,
+		models.NoteTarget{TargetType: models.NoteTargetPlace, TargetID: note.PlaceID}, note.Properties)
+	if err != nil {
+		return note, verdict, err
+	}
+	note.Properties = properties
+
+	return note, verdict, nil
+}
+
+// report у заметок нет статуса модерации, поэтому они сохраняются, а модератор получает жалобу.
+// Заметка к этому моменту уже сохранена, поэтому ошибка жалобы только логируется и запрос не проваливается
+func (n noteService) report(ctx context.Context, noteID int, userID int, verdict contentfilter.Verdict) {
+	if !verdict.Moderate {
+		return
+	}
+
+	err := n.contentReportRepo.Create(ctx, models.ContentReport{
+		EntityType: models.ContentReportNote,
+		EntityID:   noteID,
+		UserID:     userID,
+		Rules:      verdict.Rules,
+	})
+	if err != nil {
+		n.logger.Error(fmt.Sprintf("unable to report note %v, rules %v: %v", noteID, verdict.Rules, err))
 	}
 }
 
 func (n noteService) Create(ctx context.Context, noteCreate models.NoteCreate) (int, error) {
+	noteCreate, verdict, err := n.filterNote(ctx, noteCreate)
+	if err != nil {
+		return 0, err
+	}
+
 	id, err := n.noteRepo.Create(ctx, noteCreate)
 	if err != nil {
 		n.logger.Error(err.Error())
 		return 0, err
 	}
 
+	n.report(ctx, id, noteCreate.UserID, verdict)
+
 	return id, nil
 }
 
-func (n noteService) GetByIDs(ctx context.Context, userID int, placeID int) (models.Note, error) {
-	note, err := n.noteRepo.GetByIDs(ctx, userID, placeID)
+// GetByIDs без viewerID смотрящий анонимен и видит только публичные заметки
+func (n noteService) GetByIDs(ctx context.Context, viewerID int, userID int, placeID int) (models.Note, error) {
+	note, err := n.noteRepo.GetByIDs(ctx, viewerID, userID, placeID)
 	if err != nil {
 		n.logger.Error(err.Error())
 		return models.Note{}, err
@@ -39,18 +157,24 @@ func (n noteService) GetByIDs(ctx context.Context, userID int, placeID int) (mod
 	return note, nil
 }
 
-func (n noteService) GetByID(ctx context.Context, noteID int) (models.Note, error) {
-	note, err := n.noteRepo.GetByID(ctx, noteID)
+// GetByID по id заметки владелец неизвестен, поэтому смотрящий обязателен
+func (n noteService) GetByID(ctx context.Context, viewerID int, noteID int) (models.Note, error) {
+	if viewerID <= 0 {
+		return models.Note{}, fmt.Errorf("%w: viewer_id is required", customerr.BadInput)
+	}
+
+	note, err := n.noteRepo.GetByID(ctx, viewerID, noteID)
 	if err != nil {
-		n.logger.Error(err.Error())
+		n.logUnexpected(err)
 		return models.Note{}, err
 	}
 
 	return note, nil
 }
 
-func (n noteService) GetByUser(ctx context.Context, userID int) ([]models.Note, error) {
-	notes, err := n.noteRepo.GetByUser(ctx, userID)
+// GetByUser без viewerID смотрящий анонимен и видит только публичные заметки
+func (n noteService) GetByUser(ctx context.Context, viewerID int, userID int) ([]models.Note, error) {
+	notes, err := n.noteRepo.GetByUser(ctx, viewerID, userID)
 	if err != nil {
 		n.logger.Error(err.Error())
 		return []models.Note{}, err
@@ -60,11 +184,168 @@ func (n noteService) GetByUser(ctx context.Context, userID int) ([]models.Note,
 }
 
 func (n noteService) Update(ctx context.Context, noteUpd models.NoteCreate) error {
-	err := n.noteRepo.Update(ctx, noteUpd)
+	noteUpd, verdict, err := n.filterNote(ctx, noteUpd)
+	if err != nil {
+		return err
+	}
+
+	err = n.noteRepo.Update(ctx, noteUpd)
+	if err != nil {
+		n.logger.Error(err.Error())
+		return err
+	}
+
+	if !verdict.Moderate {
+		return nil
+	}
+
+	note, err := n.noteRepo.GetByIDs(ctx, noteUpd.UserID, noteUpd.UserID, noteUpd.PlaceID)
+	if err != nil {
+		n.logger.Error(fmt.Sprintf("unable to report note of user %v on place %v: %v", noteUpd.UserID, noteUpd.PlaceID, err))
+		return nil
+	}
+	n.report(ctx, note.ID, noteUpd.UserID, verdict)
+
+	return nil
+}
+
+func (n noteService) CreateForTarget(ctx context.Context, noteCreate models.NoteTargetCreate) (int, error) {
+	if err := validateNoteTarget(noteCreate.NoteTarget); err != nil {
+		return 0, err
+	}
+
+	if noteCreate.Visibility == "" {
+		noteCreate.Visibility = models.NoteVisibilityPrivate
+	}
+	if err := validateNoteVisibility(noteCreate.Visibility, false); err != nil {
+		return 0, err
+	}
+
+	properties, verdict, err := n.filterProperties(ctx, noteCreate.UserID, noteCreate.NoteTarget, noteCreate.Properties)
+	if err != nil {
+		return 0, err
+	}
+	noteCreate.Properties = properties
+
+	id, err := n.noteRepo.CreateForTarget(ctx, noteCreate)
+	if err != nil {
+		n.logUnexpected(err)
+		return 0, err
+	}
+
+	n.report(ctx, id, noteCreate.UserID, verdict)
+
+	return id, nil
+}
+
+func (n noteService) GetByTarget(ctx context.Context, notesByTarget models.NotesByTarget) ([]models.Note, error) {
+	if err := validateNoteTarget(notesByTarget.NoteTarget); err != nil {
+		return []models.Note{}, err
+	}
+
+	notes, err := n.noteRepo.GetByTarget(ctx, notesByTarget.ViewerID, notesByTarget.UserID, notesByTarget.NoteTarget)
+	if err != nil {
+		n.logUnexpected(err)
+		return []models.Note{}, err
+	}
+
+	return notes, nil
+}
+
+func (n noteService) UpdateByID(ctx context.Context, noteUpd models.NoteUpdate) error {
+	if err := validateNoteVisibility(noteUpd.Visibility, true); err != nil {
+		return err
+	}
+
+	note, err := n.noteRepo.GetByID(ctx, noteUpd.UserID, noteUpd.ID)
+	if err != nil {
+		n.logUnexpected(err)
+		return err
+	}
+
+	properties, verdict, err := n.filterProperties(ctx, noteUpd.UserID, note.NoteTarget, noteUpd.Properties)
+	if err != nil {
+		return err
+	}
+	noteUpd.Properties = properties
+
+	if err = n.noteRepo.UpdateByID(ctx, noteUpd); err != nil {
+		n.logUnexpected(err)
+		return err
+	}
+
+	n.report(ctx, noteUpd.ID, noteUpd.UserID, verdict)
+
+	return nil
+}
+
+func (n noteService) Delete(ctx context.Context, noteDelete models.NoteDelete) error {
+	err := n.noteRepo.Delete(ctx, noteDelete)
+	if err != nil {
+		n.logUnexpected(err)
+		return err
+	}
+
+	return nil
+}
+
+func (n noteService) GetFeed(ctx context.Context, feed models.NotesFeed) (models.Page[models.Note], error) {
+	if feed.TargetType != models.NoteTargetPlace && feed.TargetType != models.NoteTargetRoute {
+		return models.Page[models.Note]{}, fmt.Errorf("%w: no public feed for %v notes", customerr.BadInput, feed.TargetType)
+	}
+
+	feed.PageSize = pagination.PageSize(feed.PageSize, viper.GetInt(config.NotesOnPage), viper.GetInt(config.MaxPageSize))
+
+	page, err := n.noteRepo.GetFeed(ctx, feed)
+	if err != nil {
+		n.logUnexpected(err)
+		return models.Page[models.Note]{}, err
+	}
+
+	return page, nil
+}
+
+func (n noteService) CreateShareToken(ctx context.Context, share models.NoteShare) (string, error) {
+	token, err := newShareToken()
 	if err != nil {
 		n.logger.Error(err.Error())
+		return "", err
+	}
+
+	if err = n.noteRepo.CreateShareToken(ctx, share, token); err != nil {
+		n.logUnexpected(err)
+		return "", err
+	}
+
+	return token, nil
+}
+
+func (n noteService) GetShareTokens(ctx context.Context, request models.NoteSharesRequest) ([]models.NoteShareToken, error) {
+	tokens, err := n.noteRepo.GetShareTokens(ctx, request)
+	if err != nil {
+		n.logUnexpected(err)
+		return []models.NoteShareToken{}, err
+	}
+
+	return tokens, nil
+}
+
+func (n noteService) RevokeShareToken(ctx context.Context, revoke models.NoteShareRevoke) error {
+	err := n.noteRepo.RevokeShareToken(ctx, revoke)
+	if err != nil {
+		n.logUnexpected(err)
 		return err
 	}
 
 	return nil
 }
+
+func (n noteService) GetByShareToken(ctx context.Context, token string) (models.Note, error) {
+	note, err := n.noteRepo.GetByShareToken(ctx, token)
+	if err != nil {
+		n.logUnexpected(err)
+		return models.Note{}, err
+	}
+
+	return note, nil
+}
@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"mth/internal/models"
+	"mth/internal/repository"
+	"mth/pkg/customerr"
+	"testing"
+)
+
+func TestValidateNoteTarget(t *testing.T) {
+	valid := []models.NoteTarget{
+		{TargetType: models.NoteTargetPlace, TargetID: 1},
+		{TargetType: models.NoteTargetRoute, TargetID: 2},
+		{TargetType: models.NoteTargetTrip, TargetID: 3},
+		{TargetType: models.NoteTargetTripDay, TargetID: 3, Day: 1},
+		{TargetType: models.NoteTargetTripDay, TargetID: 3, Day: 4},
+	}
+	for _, target := range valid {
+		if err := validateNoteTarget(target); err != nil {
+			t.Errorf("%+v: unexpected error %v", target, err)
+		}
+	}
+
+	invalid := []models.NoteTarget{
+		{TargetType: "city", TargetID: 1},
+		{TargetType: models.NoteTargetPlace},
+		{TargetType: models.NoteTargetPlace, TargetID: 1, Day: 1},
+		{TargetType: models.NoteTargetTrip, TargetID: 3, Day: 2},
+		{TargetType: models.NoteTargetTripDay, TargetID: 3, Day: -1},
+		{TargetType: models.NoteTargetTripDay, TargetID: 3},
+	}
+	for _, target := range invalid {
+		if err := validateNoteTarget(target); !errors.Is(err, customerr.BadInput) {
+			t.Errorf("%+v: expected bad input, got %v", target, err)
+		}
+	}
+}
+
+func TestValidateNoteVisibility(t *testing.T) {
+	for _, visibility := range []string{models.NoteVisibilityPrivate, models.NoteVisibilityCompanions,
+		models.NoteVisibilityLink, models.NoteVisibilityPublic} {
+		if err := validateNoteVisibility(visibility, false); err != nil {
+			t.Errorf("%v: unexpected error %v", visibility, err)
+		}
+	}
+
+	if err := validateNoteVisibility("", true); err != nil {
+		t.Errorf("empty visibility on update: unexpected error %v", err)
+	}
+	if err := validateNoteVisibility("", false); !errors.Is(err, customerr.BadInput) {
+		t.Errorf("empty visibility on create: expected bad input, got %v", err)
+	}
+	if err := validateNoteVisibility("friends", true); !errors.Is(err, customerr.BadInput) {
+		t.Errorf("unknown visibility: expected bad input, got %v", err)
+	}
+}
+
+// fakeViewerRepo запоминает, от чьего имени запрошены заметки
+type fakeViewerRepo struct {
+	repository.Note
+	viewerID *int
+}
+
+func (f fakeViewerRepo) GetByIDs(_ context.Context, viewerID int, _ int, _ int) (models.Note, error) {
+	*f.viewerID = viewerID
+	return models.Note{}, nil
+}
+
+func (f fakeViewerRepo) GetByUser(_ context.Context, viewerID int, _ int) ([]models.Note, error) {
+	*f.viewerID = viewerID
+	return []models.Note{}, nil
+}
+
+func TestAnonymousNoteViewer(t *testing.T) {
+	viewerID := -1
+	n := noteService{noteRepo: fakeViewerRepo{viewerID: &viewerID}}
+
+	if _, err := n.GetByIDs(context.TODO(), 0, 7, 1); err != nil || viewerID != 0 {
+		t.Errorf("by user and place without viewer: expected anonymous viewer, got %v, %v", viewerID, err)
+	}
+	viewerID = -1
+	if _, err := n.GetByUser(context.TODO(), 0, 7); err != nil || viewerID != 0 {
+		t.Errorf("by user without viewer: expected anonymous viewer, got %v, %v", viewerID, err)
+	}
+	if _, err := n.GetByUser(context.TODO(), 3, 7); err != nil || viewerID != 3 {
+		t.Errorf("explicit viewer must be kept, got %v, %v", viewerID, err)
+	}
+
+	if _, err := n.GetByID(context.TODO(), 0, 1); !errors.Is(err, customerr.BadInput) {
+		t.Errorf("by id without viewer: expected BadInput, got %v", err)
+	}
+}
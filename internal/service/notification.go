@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/spf13/viper"
+	"mth/internal/models"
+	"mth/internal/repository"
+	"mth/pkg/config"
+	"mth/pkg/customerr"
+	"mth/pkg/log"
+	"mth/pkg/pagination"
+)
+
+type notificationService struct {
+	notificationRepo repository.Notification
+	logger           *log.Logs
+}
+
+func InitNotificationService(notificationRepo repository.Notification, logger *log.Logs) Notification {
+	return notificationService{
+		notificationRepo: notificationRepo,
+		logger:           logger,
+	}
+}
+
+func (n notificationService) GetByUser(ctx context.Context, request models.NotificationsRequest) (models.Page[models.Notification], error) {
+	request.PageSize = pagination.PageSize(request.PageSize, viper.GetInt(config.NotificationsOnPage), viper.GetInt(config.MaxPageSize))
+
+	notifications, err := n.notificationRepo.GetByUser(ctx, request)
+	if err != nil {
+		if !errors.Is(err, customerr.InvalidCursor) {
+			n.logger.Error(err.Error())
+		}
+		return models.Page[models.Notification]{}, err
+	}
+
+	return notifications, nil
+}
+
+// validateNotificationsRead пользователь обязателен, иначе нечего ограничивать, id уведомлений положительные
+func validateNotificationsRead(read models.NotificationsRead) error {
+	if read.UserID <= 0 {
+		return fmt.Errorf("%w: user_id is required", customerr.BadInput)
+	}
+
+	for _, id := range read.IDs {
+		if id <= 0 {
+			return fmt.Errorf("%w: invalid notification id %v", customerr.BadInput, id)
+		}
+	}
+
+	return nil
+}
+
+func (n notificationService) MarkRead(ctx context.Context, read models.NotificationsRead) (int, error) {
+	if err := validateNotificationsRead(read); err != nil {
+		return 0, err
+	}
+
+	count, err := n.notificationRepo.MarkRead(ctx, read)
+	if err != nil {
+		n.logger.Error(err.Error())
+		return 0, err
+	}
+
+	return count, nil
+}
@@ -0,0 +1,27 @@
+package service
+
+import (
+	"errors"
+	"mth/internal/models"
+	"mth/pkg/customerr"
+	"testing"
+)
+
+func TestValidateNotificationsRead(t *testing.T) {
+	tests := []struct {
+		read     models.NotificationsRead
+		expected error
+	}{
+		{read: models.NotificationsRead{UserID: 1}},
+		{read: models.NotificationsRead{UserID: 1, IDs: []int{3, 5}}},
+		{read: models.NotificationsRead{IDs: []int{3}}, expected: customerr.BadInput},
+		{read: models.NotificationsRead{UserID: -1}, expected: customerr.BadInput},
+		{read: models.NotificationsRead{UserID: 1, IDs: []int{3, 0}}, expected: customerr.BadInput},
+	}
+
+	for _, test := range tests {
+		if err := validateNotificationsRead(test.read); !errors.Is(err, test.expected) {
+			t.Errorf("%+v: expected %v, got %v", test.read, test.expected, err)
+		}
+	}
+}
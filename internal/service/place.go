@@ -2,25 +2,121 @@ package service
 
 import (
 	"context"
+	"errors"
+	"github.com/spf13/viper"
 	"mth/internal/models"
 	"mth/internal/models/swagger"
 	"mth/internal/repository"
+	"mth/pkg/config"
+	"mth/pkg/customerr"
 	"mth/pkg/log"
+	"mth/pkg/pagination"
 )
 
 type placeService struct {
-	placeRepo repository.Place
-	logger    *log.Logs
+	placeRepo    repository.Place
+	varietyRepo  repository.Variety
+	districtRepo repository.District
+	tagRepo      repository.Tag
+	localizer    localizer
+	logger       *log.Logs
 }
 
-func InitPlaceService(placeRepo repository.Place, logger *log.Logs) Place {
+func InitPlaceService(placeRepo repository.Place, varietyRepo repository.Variety, districtRepo repository.District,
+	tagRepo repository.Tag, translationRepo repository.Translation, logger *log.Logs) Place {
 	return placeService{
-		placeRepo: placeRepo,
-		logger:    logger,
+		placeRepo:    placeRepo,
+		varietyRepo:  varietyRepo,
+		districtRepo: districtRepo,
+		tagRepo:      tagRepo,
+		localizer:    localizer{translationRepo: translationRepo},
+		logger:       logger,
+	}
+}
+
+// placeExpectedErrors отсутствие места, его история и неверные вид или район ожидаемы и не логируются
+var placeExpectedErrors = []error{
+	customerr.PlaceNotFound, customerr.PlaceHasHistory, customerr.DistrictNotInCity, customerr.UnknownVariety,
+}
+
+func (p placeService) logUnexpected(err error) {
+	logUnexpected(p.logger, err, placeExpectedErrors)
+}
+
+// localizePage переводит места страницы на язык запроса
+func (p placeService) localizePage(ctx context.Context, places []models.Place) error {
+	pointers := make([]*models.Place, 0, len(places))
+	for i := range places {
+		pointers = append(pointers, &places[i])
+	}
+
+	if err := p.localizer.places(ctx, pointers); err != nil {
+		p.logger.Error(err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// checkVariety вид места должен быть заведён в таблице varieties
+func (p placeService) checkVariety(ctx context.Context, variety string) error {
+	_, err := p.varietyRepo.GetByName(ctx, variety)
+	if err != nil && !errors.Is(err, customerr.UnknownVariety) {
+		p.logger.Error(err.Error())
+	}
+
+	return err
+}
+
+// assignDistrict место с координатами попадает в район, чья граница его содержит, город берётся из района.
+// Если у города есть границы районов, но точка вне всех, район снимается. Без границ переданный district_id остаётся
+func (p placeService) assignDistrict(ctx context.Context, place *models.PlaceBase) error {
+	if place.Coordinates == nil {
+		return nil
+	}
+
+	shapes, err := loadDistrictShapes(ctx, p.districtRepo, place.CityID)
+	if err != nil {
+		p.logger.Error(err.Error())
+		return err
+	}
+
+	applyDistrict(shapes, place)
+
+	return nil
+}
+
+// districtAssigner назначает район по границам города места так же, как при создании и изменении места
+func districtAssigner(shapes []districtShape) func(place *models.PlaceBase) {
+	byCity := make(map[int][]districtShape)
+	for _, shape := range shapes {
+		byCity[shape.CityID] = append(byCity[shape.CityID], shape)
+	}
+
+	return func(place *models.PlaceBase) {
+		if place.Coordinates != nil {
+			applyDistrict(byCity[place.CityID], place)
+		}
+	}
+}
+
+func applyDistrict(shapes []districtShape, place *models.PlaceBase) {
+	if shape, ok := locateDistrict(shapes, *place.Coordinates); ok {
+		place.DistrictID = shape.ID
+		place.CityID = shape.CityID
+	} else if len(shapes) > 0 {
+		place.DistrictID = 0
 	}
 }
 
 func (p placeService) Create(ctx context.Context, placeCreate models.PlaceCreate) (int, error) {
+	if err := p.checkVariety(ctx, placeCreate.Variety); err != nil {
+		return 0, err
+	}
+	if err := p.assignDistrict(ctx, &placeCreate.PlaceBase); err != nil {
+		return 0, err
+	}
+
 	id, err := p.placeRepo.Create(ctx, placeCreate)
 	if err != nil {
 		p.logger.Error(err.Error())
@@ -30,12 +126,54 @@ func (p placeService) Create(ctx context.Context, placeCreate models.PlaceCreate
 	return id, nil
 }
 
-func (p placeService) GetAllWithFilter(ctx context.Context, filters swagger.Filters) ([]models.Place, error) {
-	places, err := p.placeRepo.GetAllWithFilter(ctx, filters.DistrictID, filters.CityID, filters.TagIDs,
-		filters.PaginationPage, filters.Name, filters.Variety)
+func placeFiltersFromSwagger(filters swagger.Filters) models.PlaceFilters {
+	return models.PlaceFilters{
+		DistrictID: filters.DistrictID,
+		CityID:     filters.CityID,
+		Cursor:     filters.Cursor,
+		Limit:      pagination.PageSize(filters.PageSize, viper.GetInt(config.PlacesOnPage), viper.GetInt(config.MaxPageSize)),
+		WithTotal:  filters.WithTotal,
+		Name:       filters.Name,
+		Variety:    filters.Variety,
+		MinRating:  filters.MinRating,
+		SortBy:     filters.SortBy,
+	}
+}
+
+// placeFilters фильтры репозитория с тегами, развёрнутыми в группы id
+func (p placeService) placeFilters(ctx context.Context, filters swagger.Filters) (models.PlaceFilters, error) {
+	placeFilters := placeFiltersFromSwagger(filters)
+
+	tagGroups, err := resolveTagGroups(ctx, p.tagRepo, models.TagFilter{
+		TagIDs:             filters.TagIDs,
+		Names:              filters.Tags,
+		IncludeDescendants: filters.IncludeDescendants,
+	})
 	if err != nil {
 		p.logger.Error(err.Error())
-		return []models.Place{}, err
+		return models.PlaceFilters{}, err
+	}
+
+	placeFilters.TagGroups = tagGroups
+	return placeFilters, nil
+}
+
+func (p placeService) GetAllWithFilter(ctx context.Context, filters swagger.Filters) (models.Page[models.Place], error) {
+	placeFilters, err := p.placeFilters(ctx, filters)
+	if err != nil {
+		return models.Page[models.Place]{}, err
+	}
+
+	places, err := p.placeRepo.GetAllWithFilter(ctx, placeFilters)
+	if err != nil {
+		if !errors.Is(err, customerr.InvalidCursor) {
+			p.logger.Error(err.Error())
+		}
+		return models.Page[models.Place]{}, err
+	}
+
+	if err = p.localizePage(ctx, places.Items); err != nil {
+		return models.Page[models.Place]{}, err
 	}
 
 	return places, nil
@@ -48,5 +186,69 @@ func (p placeService) GetByID(ctx context.Context, placeID int) (models.Place, e
 		return models.Place{}, err
 	}
 
+	if err = p.localizer.places(ctx, []*models.Place{&place}); err != nil {
+		p.logger.Error(err.Error())
+		return models.Place{}, err
+	}
+
 	return place, nil
 }
+
+func (p placeService) Update(ctx context.Context, placeUpd models.PlaceUpdate) error {
+	if err := p.checkVariety(ctx, placeUpd.Variety); err != nil {
+		return err
+	}
+	if err := p.assignDistrict(ctx, &placeUpd.PlaceBase); err != nil {
+		return err
+	}
+
+	err := p.placeRepo.Update(ctx, placeUpd)
+	if err != nil {
+		p.logUnexpected(err)
+		return err
+	}
+
+	return nil
+}
+
+func (p placeService) Delete(ctx context.Context, placeID int) error {
+	err := p.placeRepo.Delete(ctx, placeID)
+	if err != nil {
+		p.logUnexpected(err)
+		return err
+	}
+
+	return nil
+}
+
+func (p placeService) SetArchived(ctx context.Context, placeID int, archived bool) error {
+	err := p.placeRepo.SetArchived(ctx, placeID, archived)
+	if err != nil {
+		p.logUnexpected(err)
+		return err
+	}
+
+	return nil
+}
+
+// GetTrending самые популярные места города или района, limit ограничивается MAX_PAGE_SIZE
+func (p placeService) GetTrending(ctx context.Context, cityID int, districtID int, limit int) ([]models.Place, error) {
+	filters := models.PlaceFilters{
+		CityID:     cityID,
+		DistrictID: districtID,
+		Limit:      pagination.PageSize(limit, viper.GetInt(config.TrendingLimit), viper.GetInt(config.MaxPageSize)),
+		SortBy:     models.PlaceSortByPopularity,
+	}
+
+	places, err := p.placeRepo.GetAllWithFilter(ctx, filters)
+	if err != nil {
+		p.logger.Error(err.Error())
+		return []models.Place{}, err
+	}
+
+	if err = p.localizePage(ctx, places.Items); err != nil {
+		return []models.Place{}, err
+	}
+
+	return places.Items, nil
+}
@@ -0,0 +1,74 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/spf13/viper"
+	"mth/internal/models"
+	"mth/internal/repository"
+	"mth/pkg/config"
+	"mth/pkg/customerr"
+	"mth/pkg/log"
+	"time"
+)
+
+type popularityService struct {
+	popularityRepo repository.Popularity
+	logger         *log.Logs
+}
+
+func InitPopularityService(popularityRepo repository.Popularity, logger *log.Logs) Popularity {
+	return popularityService{
+		popularityRepo: popularityRepo,
+		logger:         logger,
+	}
+}
+
+func popularityWeights() models.PopularityWeights {
+	return models.PopularityWeights{
+		CheckIn:       viper.GetFloat64(config.PopularityCheckInWeight),
+		Favourite:     viper.GetFloat64(config.PopularityFavouriteWeight),
+		Review:        viper.GetFloat64(config.PopularityReviewWeight),
+		RouteStart:    viper.GetFloat64(config.PopularityRouteStartWeight),
+		RouteComplete: viper.GetFloat64(config.PopularityRouteCompleteWeight),
+		HalfLifeHours: viper.GetFloat64(config.PopularityHalfLife),
+	}
+}
+
+// Recompute веса читаются из конфига при каждом запуске
+func (p popularityService) Recompute(ctx context.Context) error {
+	weights := popularityWeights()
+	if weights.HalfLifeHours <= 0 {
+		return customerr.BadInput
+	}
+
+	err := p.popularityRepo.Recompute(ctx, weights)
+	if err != nil {
+		p.logger.Error(err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// StartScoring пересчитывает популярность сразу и затем каждые POPULARITY_INTERVAL минут.
+// При неположительном периоде полураспада пересчёт не запускается
+func (p popularityService) StartScoring(ctx context.Context) {
+	interval := time.Duration(viper.GetInt(config.PopularityInterval)) * time.Minute
+	if interval <= 0 {
+		return
+	}
+
+	if popularityWeights().HalfLifeHours <= 0 {
+		p.logger.Error(fmt.Sprintf("popularity scoring disabled, %v must be positive", config.PopularityHalfLife))
+		return
+	}
+
+	recompute := func(ctx context.Context) {
+		_ = p.Recompute(ctx)
+	}
+
+	go recompute(ctx)
+
+	runPeriodically(ctx, interval, p.logger, "popularity scoring", recompute)
+}
@@ -0,0 +1,28 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/spf13/viper"
+	"mth/pkg/config"
+	"mth/pkg/customerr"
+	"testing"
+)
+
+func TestPopularityRecomputeRejectsHalfLife(t *testing.T) {
+	config.InitConfig()
+	halfLife, reviewWeight := viper.Get(config.PopularityHalfLife), viper.Get(config.PopularityReviewWeight)
+	defer viper.Set(config.PopularityHalfLife, halfLife)
+	defer viper.Set(config.PopularityReviewWeight, reviewWeight)
+
+	viper.Set(config.PopularityHalfLife, 0)
+
+	if err := (popularityService{}).Recompute(context.TODO()); !errors.Is(err, customerr.BadInput) {
+		t.Errorf("expected BadInput for zero half-life, got %v", err)
+	}
+
+	viper.Set(config.PopularityReviewWeight, 1.5)
+	if weights := popularityWeights(); weights.Review != 1.5 || weights.HalfLifeHours != 0 {
+		t.Errorf("weights must be read from config on every run, got %+v", weights)
+	}
+}
@@ -2,25 +2,194 @@ package service
 
 import (
 	"context"
+	"fmt"
+	"github.com/spf13/viper"
 	"mth/internal/models"
 	"mth/internal/repository"
+	"mth/pkg/config"
+	"mth/pkg/contentfilter"
+	"mth/pkg/customerr"
 	"mth/pkg/log"
+	"mth/pkg/pagination"
+	"strconv"
+	"strings"
 )
 
 type reviewService struct {
-	reviewRepo repository.Review
-	logger     *log.Logs
+	reviewRepo        repository.Review
+	contentReportRepo repository.ContentReport
+	filter            *contentfilter.Filter
+	logger            *log.Logs
 }
 
-func InitReviewService(reviewRepo repository.Review, logger *log.Logs) Review {
+func InitReviewService(reviewRepo repository.Review, contentReportRepo repository.ContentReport, logger *log.Logs) Review {
 	return reviewService{
-		reviewRepo: reviewRepo,
-		logger:     logger,
+		reviewRepo:        reviewRepo,
+		contentReportRepo: contentReportRepo,
+		filter:            sharedContentFilter(logger),
+		logger:            logger,
 	}
 }
 
+// newReviewStatus при премодерации или по решению фильтра новый отзыв ждёт решения модератора
+func newReviewStatus(moderate bool) string {
+	if moderate || viper.GetBool(config.ReviewPremoderation) {
+		return models.ReviewStatusPending
+	}
+
+	return models.ReviewStatusPublished
+}
+
+// parseModerators id модераторов из строки через запятую, некорректные значения пропускаются
+func parseModerators(raw string) map[int]bool {
+	moderators := make(map[int]bool)
+	for _, part := range strings.Split(raw, ",") {
+		id, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil || id <= 0 {
+			continue
+		}
+
+		moderators[id] = true
+	}
+
+	return moderators
+}
+
+func checkModerator(userID int) error {
+	if !parseModerators(viper.GetString(config.ReviewModerators))[userID] {
+		return customerr.NotModerator
+	}
+
+	return nil
+}
+
+// moderationStatus статус, в который действие модератора переводит отзыв
+func moderationStatus(moderation models.ReviewModeration) (string, error) {
+	var status string
+	switch moderation.Action {
+	case models.ReviewActionApprove:
+		return models.ReviewStatusPublished, nil
+	case models.ReviewActionReject:
+		status = models.ReviewStatusRejected
+	case models.ReviewActionHide:
+		status = models.ReviewStatusHidden
+	default:
+		return "", fmt.Errorf("%w: unknown moderation action %v", customerr.BadInput, moderation.Action)
+	}
+
+	if strings.TrimSpace(moderation.Reason) == "" {
+		return "", fmt.Errorf("%w: reason is required to %v a review", customerr.BadInput, moderation.Action)
+	}
+
+	return status, nil
+}
+
+func validateFlag(flag models.ReviewFlagCreate) (models.ReviewFlagCreate, error) {
+	flag.Comment = strings.TrimSpace(flag.Comment)
+
+	for _, reason := range models.ReviewFlagReasons {
+		if reason == flag.Reason {
+			return flag, nil
+		}
+	}
+
+	return flag, fmt.Errorf("%w: unknown flag reason %v", customerr.BadInput, flag.Reason)
+}
+
+// reviewExpectedErrors ошибки проверок и прав доступа ожидаемы и не логируются
+var reviewExpectedErrors = []error{
+	customerr.BadInput, customerr.ReviewNotFound, customerr.ReviewAlreadyFlagged, customerr.UserNotEntityOwner,
+	customerr.NotModerator, customerr.InvalidCursor, customerr.ReviewNotVerified, customerr.PlaceNotFound,
+	customerr.ReviewVoteNotFound, customerr.NotEntityManager, customerr.ReviewReplyNotFound,
+	customerr.ManagedEntityNotFound, customerr.EntityManagerNotFound, customerr.ContentRejected,
+}
+
+func (r reviewService) logUnexpected(err error) {
+	logUnexpected(r.logger, err, reviewExpectedErrors)
+}
+
+// checkVerified возвращает customerr.ReviewNotVerified, если визит обязателен, а автор не был на месте
+func (r reviewService) checkVerified(ctx context.Context, required bool, reviewType string, authorID int, entityID int) error {
+	if !required {
+		return nil
+	}
+
+	verified, err := r.reviewRepo.IsVerified(ctx, reviewType, authorID, entityID)
+	if err != nil {
+		return err
+	}
+	if !verified {
+		return customerr.ReviewNotVerified
+	}
+
+	return nil
+}
+
+// routeCriteria критерии оценки маршрутов из REVIEW_ROUTE_CRITERIA
+func routeCriteria() []string {
+	return normalizeCriteria(strings.Split(viper.GetString(config.ReviewRouteCriteria), ","))
+}
+
+// MarkScale шкала оценок из настроек REVIEW_MARK_*: по ней сервис проверяет оценки,
+// а репозитории отзывов строят гистограммы рейтинга
+func MarkScale() models.MarkScale {
+	return models.MarkScale{
+		Min:  viper.GetFloat64(config.ReviewMarkMin),
+		Max:  viper.GetFloat64(config.ReviewMarkMax),
+		Step: viper.GetFloat64(config.ReviewMarkStep),
+	}
+}
+
+// validateMarks оценка и оценки по критериям в шкале, критерии только из списка вида места или маршрута.
+// Возвращает оценки по критериям с приведёнными к нижнему регистру ключами
+func validateMarks(scale models.MarkScale, criteria []string, mark float32, subMarks map[string]float32) (map[string]float32, error) {
+	if !scale.Valid(mark) {
+		return nil, fmt.Errorf("%w: mark %v is out of scale %v..%v", customerr.BadInput, mark, scale.Min, scale.Max)
+	}
+
+	allowed := make(map[string]bool, len(criteria))
+	for _, criterion := range criteria {
+		allowed[criterion] = true
+	}
+
+	result := make(map[string]float32, len(subMarks))
+	for criterion, subMark := range subMarks {
+		key := strings.ToLower(strings.TrimSpace(criterion))
+		if !allowed[key] {
+			return nil, fmt.Errorf("%w: unknown criterion %v", customerr.BadInput, criterion)
+		}
+		if !scale.Valid(subMark) {
+			return nil, fmt.Errorf("%w: mark %v for %v is out of scale %v..%v", customerr.BadInput, subMark, criterion,
+				scale.Min, scale.Max)
+		}
+
+		result[key] = subMark
+	}
+
+	return result, nil
+}
+
 func (r reviewService) CreateOnRoute(ctx context.Context, routeReview models.RouteReviewCreate) (int, error) {
-	id, err := r.reviewRepo.CreateOnRoute(ctx, routeReview)
+	subMarks, err := validateMarks(MarkScale(), routeCriteria(), routeReview.Mark, routeReview.SubMarks)
+	if err != nil {
+		return 0, err
+	}
+	routeReview.SubMarks = subMarks
+
+	err = r.checkVerified(ctx, viper.GetBool(config.ReviewRouteRequireVerified), models.ReviewTypeRoute,
+		routeReview.AuthorID, routeReview.RouteID)
+	if err != nil {
+		r.logUnexpected(err)
+		return 0, err
+	}
+
+	properties, verdict, err := r.filterProperties(ctx, models.ReviewTypeRoute, routeReview.AuthorID, routeReview.RouteID, routeReview.Properties)
+	if err != nil {
+		return 0, err
+	}
+	routeReview.Properties = properties
+
+	id, err := r.reviewRepo.CreateOnRoute(ctx, routeReview, newReviewStatus(verdict.Moderate))
 	if err != nil {
 		r.logger.Error(err.Error())
 		return 0, err
@@ -30,7 +199,31 @@ func (r reviewService) CreateOnRoute(ctx context.Context, routeReview models.Rou
 }
 
 func (r reviewService) CreateOnPlace(ctx context.Context, placeReview models.PlaceReviewCreate) (int, error) {
-	id, err := r.reviewRepo.CreateOnPlace(ctx, placeReview)
+	policy, err := r.reviewRepo.PlaceReviewPolicy(ctx, placeReview.PlaceID)
+	if err != nil {
+		r.logUnexpected(err)
+		return 0, err
+	}
+
+	subMarks, err := validateMarks(MarkScale(), policy.Criteria, placeReview.Mark, placeReview.SubMarks)
+	if err != nil {
+		return 0, err
+	}
+	placeReview.SubMarks = subMarks
+
+	err = r.checkVerified(ctx, policy.RequireVerified, models.ReviewTypePlace, placeReview.AuthorID, placeReview.PlaceID)
+	if err != nil {
+		r.logUnexpected(err)
+		return 0, err
+	}
+
+	properties, verdict, err := r.filterProperties(ctx, models.ReviewTypePlace, placeReview.AuthorID, placeReview.PlaceID, placeReview.Properties)
+	if err != nil {
+		return 0, err
+	}
+	placeReview.Properties = properties
+
+	id, err := r.reviewRepo.CreateOnPlace(ctx, placeReview, newReviewStatus(verdict.Moderate))
 	if err != nil {
 		r.logger.Error(err.Error())
 		return 0, err
@@ -49,28 +242,96 @@ func (r reviewService) GetByAuthor(ctx context.Context, authorID int) ([]models.
 	return placeReviews, routeReviews, nil
 }
 
-func (r reviewService) GetByRoute(ctx context.Context, routeID int) ([]models.RouteReview, error) {
-	routeReviews, err := r.reviewRepo.GetByRoute(ctx, routeID)
+// prepareListRequest порядок по умолчанию - по полезности, размер страницы ограничен MAX_PAGE_SIZE
+func prepareListRequest(request models.ReviewListRequest) (models.ReviewListRequest, error) {
+	switch request.SortBy {
+	case "":
+		request.SortBy = models.ReviewSortHelpful
+	case models.ReviewSortHelpful, models.ReviewSortRecent, models.ReviewSortMarkHigh, models.ReviewSortMarkLow:
+	default:
+		return request, fmt.Errorf("%w: unknown sort %v", customerr.BadInput, request.SortBy)
+	}
+
+	request.PageSize = pagination.PageSize(request.PageSize, viper.GetInt(config.ReviewsOnPage), viper.GetInt(config.MaxPageSize))
+
+	return request, nil
+}
+
+func (r reviewService) GetByRoute(ctx context.Context, request models.ReviewListRequest) (models.Page[models.RouteReview], error) {
+	request, err := prepareListRequest(request)
 	if err != nil {
-		r.logger.Error(err.Error())
-		return []models.RouteReview{}, err
+		return models.Page[models.RouteReview]{}, err
+	}
+
+	routeReviews, err := r.reviewRepo.GetByRoute(ctx, request, viper.GetInt(config.ReviewNewAccountDays))
+	if err != nil {
+		r.logUnexpected(err)
+		return models.Page[models.RouteReview]{}, err
 	}
 
 	return routeReviews, nil
 }
 
-func (r reviewService) GetByPlace(ctx context.Context, placeID int) ([]models.PlaceReview, error) {
-	placeReviews, err := r.reviewRepo.GetByPlace(ctx, placeID)
+func (r reviewService) GetByPlace(ctx context.Context, request models.ReviewListRequest) (models.Page[models.PlaceReview], error) {
+	request, err := prepareListRequest(request)
 	if err != nil {
-		r.logger.Error(err.Error())
-		return []models.PlaceReview{}, err
+		return models.Page[models.PlaceReview]{}, err
+	}
+
+	placeReviews, err := r.reviewRepo.GetByPlace(ctx, request, viper.GetInt(config.ReviewNewAccountDays))
+	if err != nil {
+		r.logUnexpected(err)
+		return models.Page[models.PlaceReview]{}, err
 	}
 
 	return placeReviews, nil
 }
 
+func (r reviewService) filterProperties(ctx context.Context, reviewType string, authorID int, entityID int,
+	properties interface{}) (interface{}, contentfilter.Verdict, error) {
+	subject, err := contentSubject(ctx, r.contentReportRepo, authorID, fmt.Sprintf("%v:%v", reviewType, entityID))
+	if err != nil {
+		r.logger.Error(err.Error())
+		return nil, contentfilter.Verdict{}, err
+	}
+
+	return r.filter.Properties(subject, properties)
+}
+
+// filterUpdate автор и сущность берутся из сохранённого отзыва, чтобы правка проверялась на повтор так же, как создание
+func (r reviewService) filterUpdate(ctx context.Context, reviewType string, reviewUpd models.ReviewUpdate) (models.ReviewUpdate, bool, error) {
+	authorID, entityID, err := r.reviewRepo.GetAuthor(ctx, reviewType, reviewUpd.ID)
+	if err != nil {
+		r.logUnexpected(err)
+		return reviewUpd, false, err
+	}
+
+	properties, verdict, err := r.filterProperties(ctx, reviewType, authorID, entityID, reviewUpd.Properties)
+	if err != nil {
+		return reviewUpd, false, err
+	}
+	reviewUpd.Properties = properties
+
+	return reviewUpd, verdict.Moderate || viper.GetBool(config.ReviewPremoderation), nil
+}
+
 func (r reviewService) UpdateOnPlace(ctx context.Context, reviewUpd models.ReviewUpdate) error {
-	err := r.reviewRepo.UpdateOnPlace(ctx, reviewUpd)
+	criteria, err := r.reviewRepo.PlaceReviewCriteria(ctx, reviewUpd.ID)
+	if err != nil {
+		r.logUnexpected(err)
+		return err
+	}
+
+	if reviewUpd.SubMarks, err = validateMarks(MarkScale(), criteria, reviewUpd.Mark, reviewUpd.SubMarks); err != nil {
+		return err
+	}
+
+	reviewUpd, premoderation, err := r.filterUpdate(ctx, models.ReviewTypePlace, reviewUpd)
+	if err != nil {
+		return err
+	}
+
+	err = r.reviewRepo.UpdateOnPlace(ctx, reviewUpd, premoderation)
 	if err != nil {
 		r.logger.Error(err.Error())
 		return err
@@ -80,7 +341,18 @@ func (r reviewService) UpdateOnPlace(ctx context.Context, reviewUpd models.Revie
 }
 
 func (r reviewService) UpdateOnRoute(ctx context.Context, reviewUpd models.ReviewUpdate) error {
-	err := r.reviewRepo.UpdateOnRoute(ctx, reviewUpd)
+	subMarks, err := validateMarks(MarkScale(), routeCriteria(), reviewUpd.Mark, reviewUpd.SubMarks)
+	if err != nil {
+		return err
+	}
+	reviewUpd.SubMarks = subMarks
+
+	reviewUpd, premoderation, err := r.filterUpdate(ctx, models.ReviewTypeRoute, reviewUpd)
+	if err != nil {
+		return err
+	}
+
+	err = r.reviewRepo.UpdateOnRoute(ctx, reviewUpd, premoderation)
 	if err != nil {
 		r.logger.Error(err.Error())
 		return err
@@ -88,3 +360,173 @@ func (r reviewService) UpdateOnRoute(ctx context.Context, reviewUpd models.Revie
 
 	return nil
 }
+
+func (r reviewService) Delete(ctx context.Context, reviewDelete models.ReviewDelete) error {
+	if err := r.reviewRepo.Delete(ctx, reviewDelete); err != nil {
+		r.logUnexpected(err)
+		return err
+	}
+
+	return nil
+}
+
+func (r reviewService) Flag(ctx context.Context, flag models.ReviewFlagCreate) (models.ReviewFlagResult, error) {
+	flag, err := validateFlag(flag)
+	if err != nil {
+		return models.ReviewFlagResult{}, err
+	}
+
+	result, err := r.reviewRepo.Flag(ctx, flag, viper.GetInt(config.ReviewFlagsToHide))
+	if err != nil {
+		r.logUnexpected(err)
+		return models.ReviewFlagResult{}, err
+	}
+
+	return result, nil
+}
+
+func (r reviewService) Moderate(ctx context.Context, moderation models.ReviewModeration) error {
+	if err := checkModerator(moderation.ModeratorID); err != nil {
+		return err
+	}
+
+	status, err := moderationStatus(moderation)
+	if err != nil {
+		return err
+	}
+	moderation.Reason = strings.TrimSpace(moderation.Reason)
+
+	if err = r.reviewRepo.Moderate(ctx, moderation, status); err != nil {
+		r.logUnexpected(err)
+		return err
+	}
+
+	return nil
+}
+
+func (r reviewService) GetModerationQueue(ctx context.Context, request models.ReviewQueueRequest) (models.Page[models.ReviewQueueItem], error) {
+	if err := checkModerator(request.ModeratorID); err != nil {
+		return models.Page[models.ReviewQueueItem]{}, err
+	}
+
+	if request.Queue != "" && request.Queue != models.ReviewQueuePending && request.Queue != models.ReviewQueueFlagged {
+		return models.Page[models.ReviewQueueItem]{}, fmt.Errorf("%w: unknown queue %v", customerr.BadInput, request.Queue)
+	}
+
+	request.PageSize = pagination.PageSize(request.PageSize, viper.GetInt(config.PlacesOnPage), viper.GetInt(config.MaxPageSize))
+
+	queue, err := r.reviewRepo.GetModerationQueue(ctx, request)
+	if err != nil {
+		r.logUnexpected(err)
+		return models.Page[models.ReviewQueueItem]{}, err
+	}
+
+	return queue, nil
+}
+
+func (r reviewService) Vote(ctx context.Context, vote models.ReviewVote) (models.ReviewVotes, error) {
+	votes, err := r.reviewRepo.Vote(ctx, vote)
+	if err != nil {
+		r.logUnexpected(err)
+		return models.ReviewVotes{}, err
+	}
+
+	return votes, nil
+}
+
+func (r reviewService) DeleteVote(ctx context.Context, vote models.ReviewVoteDelete) (models.ReviewVotes, error) {
+	votes, err := r.reviewRepo.DeleteVote(ctx, vote)
+	if err != nil {
+		r.logUnexpected(err)
+		return models.ReviewVotes{}, err
+	}
+
+	return votes, nil
+}
+
+// validateManager проверяет тип сущности и роль привязки
+func validateManager(manager models.EntityManager) error {
+	if manager.EntityType != models.ReviewTypePlace && manager.EntityType != models.ReviewTypeRoute {
+		return fmt.Errorf("%w: unknown entity type %v", customerr.BadInput, manager.EntityType)
+	}
+
+	if manager.Role != models.ManagerRoleOwner && manager.Role != models.ManagerRoleEditor {
+		return fmt.Errorf("%w: unknown manager role %v", customerr.BadInput, manager.Role)
+	}
+
+	return nil
+}
+
+func (r reviewService) SetManager(ctx context.Context, manager models.EntityManagerUpdate) error {
+	if err := checkModerator(manager.ModeratorID); err != nil {
+		return err
+	}
+
+	if err := validateManager(manager.EntityManager); err != nil {
+		return err
+	}
+
+	if err := r.reviewRepo.SetManager(ctx, manager.EntityManager); err != nil {
+		r.logUnexpected(err)
+		return err
+	}
+
+	return nil
+}
+
+func (r reviewService) DeleteManager(ctx context.Context, manager models.EntityManagerDelete) error {
+	if err := checkModerator(manager.ModeratorID); err != nil {
+		return err
+	}
+
+	if err := r.reviewRepo.DeleteManager(ctx, manager); err != nil {
+		r.logUnexpected(err)
+		return err
+	}
+
+	return nil
+}
+
+func (r reviewService) GetManagers(ctx context.Context, entityType string, entityID int) ([]models.EntityManager, error) {
+	managers, err := r.reviewRepo.GetManagers(ctx, entityType, entityID)
+	if err != nil {
+		r.logUnexpected(err)
+		return []models.EntityManager{}, err
+	}
+
+	return managers, nil
+}
+
+func (r reviewService) UpsertReply(ctx context.Context, reply models.ReviewReplyUpsert) (models.ReviewReply, error) {
+	reply.Text = strings.TrimSpace(reply.Text)
+	if reply.Text == "" {
+		return models.ReviewReply{}, fmt.Errorf("%w: reply text is empty", customerr.BadInput)
+	}
+
+	result, err := r.reviewRepo.UpsertReply(ctx, reply)
+	if err != nil {
+		r.logUnexpected(err)
+		return models.ReviewReply{}, err
+	}
+
+	return result, nil
+}
+
+func (r reviewService) DeleteReply(ctx context.Context, key models.ReviewReplyKey) error {
+	if err := r.reviewRepo.DeleteReply(ctx, key); err != nil {
+		r.logUnexpected(err)
+		return err
+	}
+
+	return nil
+}
+
+func (r reviewService) GetReplyHistory(ctx context.Context, reviewType string, reviewID int) ([]models.ReviewReplyRevision, error) {
+	revisions, err := r.reviewRepo.GetReplyHistory(ctx, reviewType, reviewID)
+	if err != nil {
+		r.logUnexpected(err)
+		return []models.ReviewReplyRevision{}, err
+	}
+
+	return revisions, nil
+}
@@ -0,0 +1,189 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"mth/internal/models"
+	"mth/internal/repository"
+	"mth/pkg/config"
+	"mth/pkg/customerr"
+	"testing"
+)
+
+func TestParseModerators(t *testing.T) {
+	moderators := parseModerators(" 1, 7,abc,,-3, 12 ")
+	if len(moderators) != 3 || !moderators[1] || !moderators[7] || !moderators[12] {
+		t.Errorf("unexpected moderators %v", moderators)
+	}
+
+	if len(parseModerators("")) != 0 {
+		t.Error("empty config must give no moderators")
+	}
+}
+
+func TestModerationStatus(t *testing.T) {
+	status, err := moderationStatus(models.ReviewModeration{Action: models.ReviewActionApprove})
+	if err != nil || status != models.ReviewStatusPublished {
+		t.Errorf("approve: got %v, %v", status, err)
+	}
+
+	status, err = moderationStatus(models.ReviewModeration{Action: models.ReviewActionReject, Reason: "spam"})
+	if err != nil || status != models.ReviewStatusRejected {
+		t.Errorf("reject: got %v, %v", status, err)
+	}
+
+	status, err = moderationStatus(models.ReviewModeration{Action: models.ReviewActionHide, Reason: "offensive"})
+	if err != nil || status != models.ReviewStatusHidden {
+		t.Errorf("hide: got %v, %v", status, err)
+	}
+
+	bad := []models.ReviewModeration{
+		{Action: models.ReviewActionReject},
+		{Action: models.ReviewActionHide, Reason: "  "},
+		{Action: "delete", Reason: "spam"},
+	}
+	for _, moderation := range bad {
+		if _, err = moderationStatus(moderation); !errors.Is(err, customerr.BadInput) {
+			t.Errorf("%+v: expected bad input, got %v", moderation, err)
+		}
+	}
+}
+
+func TestValidateFlag(t *testing.T) {
+	flag, err := validateFlag(models.ReviewFlagCreate{Reason: "spam", Comment: "  casino link "})
+	if err != nil || flag.Comment != "casino link" {
+		t.Errorf("unexpected result %+v, %v", flag, err)
+	}
+
+	if _, err = validateFlag(models.ReviewFlagCreate{Reason: "boring"}); !errors.Is(err, customerr.BadInput) {
+		t.Errorf("expected bad input, got %v", err)
+	}
+}
+
+func TestPrepareListRequest(t *testing.T) {
+	request, err := prepareListRequest(models.ReviewListRequest{ID: 1})
+	if err != nil || request.SortBy != models.ReviewSortHelpful || request.PageSize <= 0 {
+		t.Errorf("unexpected default request %+v, %v", request, err)
+	}
+
+	request, err = prepareListRequest(models.ReviewListRequest{ID: 1, SortBy: models.ReviewSortMarkLow})
+	if err != nil || request.SortBy != models.ReviewSortMarkLow {
+		t.Errorf("unexpected request %+v, %v", request, err)
+	}
+
+	if _, err = prepareListRequest(models.ReviewListRequest{ID: 1, SortBy: "random"}); !errors.Is(err, customerr.BadInput) {
+		t.Errorf("expected bad input, got %v", err)
+	}
+}
+
+func TestValidateManager(t *testing.T) {
+	valid := []models.EntityManager{
+		{EntityType: models.ReviewTypePlace, Role: models.ManagerRoleOwner},
+		{EntityType: models.ReviewTypeRoute, Role: models.ManagerRoleEditor},
+	}
+	for _, manager := range valid {
+		if err := validateManager(manager); err != nil {
+			t.Errorf("%+v: unexpected error %v", manager, err)
+		}
+	}
+
+	invalid := []models.EntityManager{
+		{EntityType: "trip", Role: models.ManagerRoleOwner},
+		{EntityType: models.ReviewTypePlace, Role: "admin"},
+		{EntityType: models.ReviewTypePlace},
+	}
+	for _, manager := range invalid {
+		if err := validateManager(manager); !errors.Is(err, customerr.BadInput) {
+			t.Errorf("%+v: expected BadInput, got %v", manager, err)
+		}
+	}
+}
+
+func TestValidateMarks(t *testing.T) {
+	scale := models.MarkScale{Min: 1, Max: 5, Step: 0.5}
+	criteria := []string{"price", "cleanliness"}
+
+	subMarks, err := validateMarks(scale, criteria, 4.5, map[string]float32{" Price ": 3, "cleanliness": 5})
+	if err != nil || len(subMarks) != 2 || subMarks["price"] != 3 {
+		t.Errorf("valid marks: got %v, %v", subMarks, err)
+	}
+
+	if subMarks, err = validateMarks(scale, nil, 1, nil); err != nil || len(subMarks) != 0 {
+		t.Errorf("without sub-marks: got %v, %v", subMarks, err)
+	}
+
+	bad := []struct {
+		mark     float32
+		subMarks map[string]float32
+	}{
+		{mark: 1000},
+		{mark: -5},
+		{mark: 0.5},
+		{mark: 4.2},
+		{mark: 4, subMarks: map[string]float32{"atmosphere": 4}},
+		{mark: 4, subMarks: map[string]float32{"price": 6}},
+	}
+	for _, c := range bad {
+		if _, err = validateMarks(scale, criteria, c.mark, c.subMarks); !errors.Is(err, customerr.BadInput) {
+			t.Errorf("%v %v: expected BadInput, got %v", c.mark, c.subMarks, err)
+		}
+	}
+
+	if _, err = validateMarks(models.MarkScale{Min: 0, Max: 10}, nil, 7.3, nil); err != nil {
+		t.Errorf("zero step must accept any mark in range, got %v", err)
+	}
+
+	if low, high := (models.MarkScale{Min: 0.5, Max: 10}).Buckets(); low != 1 || high != 10 {
+		t.Errorf("unexpected buckets %v..%v", low, high)
+	}
+}
+
+// Оценки с половиной балла уже хранятся в базе, шкала по умолчанию должна их принимать
+func TestDefaultMarkScale(t *testing.T) {
+	config.InitConfig()
+
+	if scale := MarkScale(); !scale.Valid(4.5) || scale.Valid(4.2) {
+		t.Errorf("default scale must have step 0.5, got %+v", scale)
+	}
+}
+
+// fakeVisitRepo визиты авторов: тип отзыва -> автор -> сущность
+type fakeVisitRepo struct {
+	repository.Review
+	visits map[string]map[int]int
+}
+
+func (f fakeVisitRepo) IsVerified(_ context.Context, reviewType string, authorID int, entityID int) (bool, error) {
+	visited, ok := f.visits[reviewType][authorID]
+	return ok && visited == entityID, nil
+}
+
+func TestCheckVerified(t *testing.T) {
+	r := reviewService{reviewRepo: fakeVisitRepo{visits: map[string]map[int]int{
+		models.ReviewTypePlace: {1: 10},
+		models.ReviewTypeRoute: {1: 20},
+	}}}
+
+	tests := []struct {
+		name       string
+		required   bool
+		reviewType string
+		authorID   int
+		entityID   int
+		expected   error
+	}{
+		{name: "not required", required: false, reviewType: models.ReviewTypePlace, authorID: 2, entityID: 10},
+		{name: "checked in", required: true, reviewType: models.ReviewTypePlace, authorID: 1, entityID: 10},
+		{name: "no check-in", required: true, reviewType: models.ReviewTypePlace, authorID: 2, entityID: 10, expected: customerr.ReviewNotVerified},
+		{name: "other place", required: true, reviewType: models.ReviewTypePlace, authorID: 1, entityID: 11, expected: customerr.ReviewNotVerified},
+		{name: "route completed", required: true, reviewType: models.ReviewTypeRoute, authorID: 1, entityID: 20},
+		{name: "route not completed", required: true, reviewType: models.ReviewTypeRoute, authorID: 1, entityID: 10, expected: customerr.ReviewNotVerified},
+	}
+
+	for _, test := range tests {
+		err := r.checkVerified(context.TODO(), test.required, test.reviewType, test.authorID, test.entityID)
+		if !errors.Is(err, test.expected) {
+			t.Errorf("%v: expected %v, got %v", test.name, test.expected, err)
+		}
+	}
+}
@@ -2,21 +2,31 @@ package service
 
 import (
 	"context"
+	"errors"
+	"github.com/spf13/viper"
 	"mth/internal/models"
 	"mth/internal/repository"
+	"mth/pkg/config"
+	"mth/pkg/customerr"
 	"mth/pkg/log"
+	"mth/pkg/pagination"
 )
 
 type routeService struct {
 	routeRepo repository.Route
 	placeRepo repository.Place
+	tagRepo   repository.Tag
+	localizer localizer
 	logger    *log.Logs
 }
 
-func InitRouteService(routeRepo repository.Route, placeRepo repository.Place, logger *log.Logs) Route {
+func InitRouteService(routeRepo repository.Route, placeRepo repository.Place, tagRepo repository.Tag,
+	translationRepo repository.Translation, logger *log.Logs) Route {
 	return routeService{
 		routeRepo: routeRepo,
 		placeRepo: placeRepo,
+		tagRepo:   tagRepo,
+		localizer: localizer{translationRepo: translationRepo},
 		logger:    logger,
 	}
 }
@@ -43,6 +53,8 @@ func (r routeService) GetByID(ctx context.Context, routeID int) (models.Route, e
 	route.RouteBase = routeRaw.RouteBase
 	route.ID = routeRaw.ID
 	route.Tags = routeRaw.Tags
+	route.Rating = routeRaw.Rating
+	route.Popularity = routeRaw.Popularity
 
 	for _, placeIDWithPosition := range routeRaw.PlaceIDsWithPosition {
 		place, err := r.placeRepo.GetByID(ctx, placeIDWithPosition.PlaceID)
@@ -59,29 +71,50 @@ func (r routeService) GetByID(ctx context.Context, routeID int) (models.Route, e
 		route.Places = append(route.Places, placeWithPosition)
 	}
 
+	if err = r.localizer.routes(ctx, []*models.Route{&route}); err != nil {
+		r.logger.Error(err.Error())
+		return models.Route{}, err
+	}
+
 	return route, nil
 }
 
-func (r routeService) GetAll(ctx context.Context, page int) ([]models.Route, error) {
-	routesRaw, err := r.routeRepo.GetAll(ctx, page)
+func (r routeService) GetAll(ctx context.Context, filters models.RouteFilters) (models.Page[models.Route], error) {
+	filters.PageSize = pagination.PageSize(filters.PageSize, viper.GetInt(config.PlacesOnPage), viper.GetInt(config.MaxPageSize))
+
+	tagGroups, err := resolveTagGroups(ctx, r.tagRepo, models.TagFilter{
+		TagIDs:             filters.TagIDs,
+		Names:              filters.Tags,
+		IncludeDescendants: filters.IncludeDescendants,
+	})
 	if err != nil {
 		r.logger.Error(err.Error())
-		return []models.Route{}, err
+		return models.Page[models.Route]{}, err
 	}
 
-	routes := make([]models.Route, 0, len(routesRaw))
-	for _, routeRaw := range routesRaw {
+	routesRaw, err := r.routeRepo.GetAll(ctx, filters, tagGroups)
+	if err != nil {
+		if !errors.Is(err, customerr.InvalidCursor) {
+			r.logger.Error(err.Error())
+		}
+		return models.Page[models.Route]{}, err
+	}
+
+	routes := make([]models.Route, 0, len(routesRaw.Items))
+	for _, routeRaw := range routesRaw.Items {
 		var route models.Route
 
 		route.RouteBase = routeRaw.RouteBase
 		route.ID = routeRaw.ID
 		route.Tags = routeRaw.Tags
+		route.Rating = routeRaw.Rating
+		route.Popularity = routeRaw.Popularity
 
 		for _, placeIDWithPosition := range routeRaw.PlaceIDsWithPosition {
 			place, err := r.placeRepo.GetByID(ctx, placeIDWithPosition.PlaceID)
 			if err != nil {
 				r.logger.Error(err.Error())
-				return []models.Route{}, err
+				return models.Page[models.Route]{}, err
 			}
 
 			placeWithPosition := models.PlaceWithPosition{
@@ -95,5 +128,20 @@ func (r routeService) GetAll(ctx context.Context, page int) ([]models.Route, err
 		routes = append(routes, route)
 	}
 
-	return routes, nil
+	pointers := make([]*models.Route, 0, len(routes))
+	for i := range routes {
+		pointers = append(pointers, &routes[i])
+	}
+
+	if err = r.localizer.routes(ctx, pointers); err != nil {
+		r.logger.Error(err.Error())
+		return models.Page[models.Route]{}, err
+	}
+
+	return models.Page[models.Route]{
+		Items:      routes,
+		NextCursor: routesRaw.NextCursor,
+		HasMore:    routesRaw.HasMore,
+		Total:      routesRaw.Total,
+	}, nil
 }
@@ -2,48 +2,101 @@ package service
 
 import (
 	"context"
+	"io"
 	"mth/internal/models"
 	"mth/internal/models/swagger"
+	"mth/pkg/diary"
+	"mth/pkg/geojson"
 	"time"
 )
 
 type Tag interface {
 	Create(ctx context.Context, tag models.TagCreate) (int, error)
-	GetAll(ctx context.Context) ([]models.Tag, error)
+	GetAll(ctx context.Context) ([]models.TagDetails, error)
+	SetHierarchy(ctx context.Context, hierarchy models.TagHierarchy) error
+	AddSynonym(ctx context.Context, synonym models.TagSynonym) error
+	DeleteSynonym(ctx context.Context, name string) error
+	CreateCategory(ctx context.Context, category models.TagCategoryCreate) (int, error)
+	GetCategories(ctx context.Context) ([]models.TagCategory, error)
+	DeleteCategory(ctx context.Context, categoryID int) error
+	Rename(ctx context.Context, rename models.TagRename) error
+	Delete(ctx context.Context, tagID int, cascade bool) (models.TagDeleteResult, error)
+	Merge(ctx context.Context, request models.TagMergeRequest) (models.TagMergeResult, error)
 }
 
 type Review interface {
 	CreateOnRoute(ctx context.Context, routeReview models.RouteReviewCreate) (int, error)
 	CreateOnPlace(ctx context.Context, placeReview models.PlaceReviewCreate) (int, error)
 	GetByAuthor(ctx context.Context, authorID int) ([]models.PlaceReview, []models.RouteReview, error)
-	GetByRoute(ctx context.Context, routeID int) ([]models.RouteReview, error)
-	GetByPlace(ctx context.Context, placeID int) ([]models.PlaceReview, error)
+	GetByRoute(ctx context.Context, request models.ReviewListRequest) (models.Page[models.RouteReview], error)
+	GetByPlace(ctx context.Context, request models.ReviewListRequest) (models.Page[models.PlaceReview], error)
 	UpdateOnPlace(ctx context.Context, reviewUpd models.ReviewUpdate) error
 	UpdateOnRoute(ctx context.Context, reviewUpd models.ReviewUpdate) error
+	Delete(ctx context.Context, reviewDelete models.ReviewDelete) error
+	Flag(ctx context.Context, flag models.ReviewFlagCreate) (models.ReviewFlagResult, error)
+	Moderate(ctx context.Context, moderation models.ReviewModeration) error
+	GetModerationQueue(ctx context.Context, request models.ReviewQueueRequest) (models.Page[models.ReviewQueueItem], error)
+	Vote(ctx context.Context, vote models.ReviewVote) (models.ReviewVotes, error)
+	DeleteVote(ctx context.Context, vote models.ReviewVoteDelete) (models.ReviewVotes, error)
+	SetManager(ctx context.Context, manager models.EntityManagerUpdate) error
+	DeleteManager(ctx context.Context, manager models.EntityManagerDelete) error
+	GetManagers(ctx context.Context, entityType string, entityID int) ([]models.EntityManager, error)
+	UpsertReply(ctx context.Context, reply models.ReviewReplyUpsert) (models.ReviewReply, error)
+	DeleteReply(ctx context.Context, key models.ReviewReplyKey) error
+	GetReplyHistory(ctx context.Context, reviewType string, reviewID int) ([]models.ReviewReplyRevision, error)
 }
 
 type Place interface {
 	Create(ctx context.Context, placeCreate models.PlaceCreate) (int, error)
-	GetAllWithFilter(ctx context.Context, filters swagger.Filters) ([]models.Place, error)
+	GetAllWithFilter(ctx context.Context, filters swagger.Filters) (models.Page[models.Place], error)
 	GetByID(ctx context.Context, placeID int) (models.Place, error)
+	Update(ctx context.Context, placeUpd models.PlaceUpdate) error
+	Delete(ctx context.Context, placeID int) error
+	SetArchived(ctx context.Context, placeID int, archived bool) error
+	GetGeoJSON(ctx context.Context, filters swagger.Filters) (geojson.FeatureCollection, error)
+	GetTrending(ctx context.Context, cityID int, districtID int, limit int) ([]models.Place, error)
 }
 
 type District interface {
-	GetByID(ctx context.Context, cityID int) ([]models.District, error)
+	GetByCityID(ctx context.Context, cityID int) ([]models.District, error)
+	Create(ctx context.Context, district models.DistrictBase) (int, error)
+	GetByID(ctx context.Context, districtID int) (models.District, error)
+	Update(ctx context.Context, district models.DistrictUpdate) error
+	Delete(ctx context.Context, districtID int) error
+	GetPolygons(ctx context.Context, cityID int) (geojson.FeatureCollection, error)
+	Reconcile(ctx context.Context, cityID int, apply bool) (models.DistrictReconciliation, error)
+}
+
+type City interface {
+	Create(ctx context.Context, city models.CityBase) (int, error)
+	GetAll(ctx context.Context) ([]models.City, error)
+	GetByID(ctx context.Context, cityID int) (models.City, error)
+	Update(ctx context.Context, city models.City) error
+	Delete(ctx context.Context, cityID int) error
 }
 
 type Route interface {
 	Create(ctx context.Context, route models.RouteCreate) (int, error)
 	GetByID(ctx context.Context, routeID int) (models.Route, error)
-	GetAll(ctx context.Context, page int) ([]models.Route, error)
+	GetAll(ctx context.Context, filters models.RouteFilters) (models.Page[models.Route], error)
+	GetGeoJSON(ctx context.Context, routeID int) (geojson.FeatureCollection, error)
 }
 
 type Note interface {
 	Create(ctx context.Context, noteCreate models.NoteCreate) (int, error)
-	GetByIDs(ctx context.Context, userID int, placeID int) (models.Note, error)
-	GetByID(ctx context.Context, noteID int) (models.Note, error)
-	GetByUser(ctx context.Context, userID int) ([]models.Note, error)
+	GetByIDs(ctx context.Context, viewerID int, userID int, placeID int) (models.Note, error)
+	GetByID(ctx context.Context, viewerID int, noteID int) (models.Note, error)
+	GetByUser(ctx context.Context, viewerID int, userID int) ([]models.Note, error)
 	Update(ctx context.Context, noteUpd models.NoteCreate) error
+	CreateForTarget(ctx context.Context, noteCreate models.NoteTargetCreate) (int, error)
+	GetByTarget(ctx context.Context, notesByTarget models.NotesByTarget) ([]models.Note, error)
+	UpdateByID(ctx context.Context, noteUpd models.NoteUpdate) error
+	Delete(ctx context.Context, noteDelete models.NoteDelete) error
+	GetFeed(ctx context.Context, feed models.NotesFeed) (models.Page[models.Note], error)
+	CreateShareToken(ctx context.Context, share models.NoteShare) (string, error)
+	GetShareTokens(ctx context.Context, request models.NoteSharesRequest) ([]models.NoteShareToken, error)
+	RevokeShareToken(ctx context.Context, revoke models.NoteShareRevoke) error
+	GetByShareToken(ctx context.Context, token string) (models.Note, error)
 }
 
 type Companions interface {
@@ -51,12 +104,21 @@ type Companions interface {
 	CreateRouteCompanions(ctx context.Context, companion models.CompanionsRouteCreate) error
 	// GetByUser сначала places, затем routes
 	GetByUser(ctx context.Context, userID int) ([]models.CompanionsPlace, []models.CompanionsRoute, error)
-	GetCompanionsPlace(ctx context.Context, filters models.CompanionsFilters) ([]models.CompanionsPlace, error)
-	GetCompanionsRoute(ctx context.Context, filters models.CompanionsFilters) ([]models.CompanionsRoute, error)
+	GetCompanionsPlace(ctx context.Context, filters models.CompanionsFilters) (models.Page[models.CompanionsPlace], error)
+	GetCompanionsRoute(ctx context.Context, filters models.CompanionsFilters) (models.Page[models.CompanionsRoute], error)
 	DeleteCompanionsPlace(ctx context.Context, id int) error
 	DeleteCompanionsRoute(ctx context.Context, id int) error
 }
 
+type CompanionRequest interface {
+	Create(ctx context.Context, request models.CompanionRequestCreate) (int, error)
+	Accept(ctx context.Context, action models.CompanionRequestAction) error
+	Decline(ctx context.Context, action models.CompanionRequestAction) error
+	Cancel(ctx context.Context, action models.CompanionRequestAction) error
+	GetIncoming(ctx context.Context, request models.CompanionRequestsRequest) (models.Page[models.CompanionRequest], error)
+	GetOutgoing(ctx context.Context, request models.CompanionRequestsRequest) (models.Page[models.CompanionRequest], error)
+}
+
 type Favourite interface {
 	LikePlace(ctx context.Context, like models.Like) error
 	LikeRoute(ctx context.Context, like models.Like) error
@@ -93,3 +155,68 @@ type Trip interface {
 	DeleteRoute(ctx context.Context, tripID, routeID int) error
 	DeletePlace(ctx context.Context, tripID, placeID int) error
 }
+
+type Diary interface {
+	Export(ctx context.Context, request models.TripDiaryRequest) (diary.File, error)
+}
+
+type Media interface {
+	Upload(ctx context.Context, ownerID int, file io.Reader) (models.Media, error)
+	GetByID(ctx context.Context, mediaID int) (models.Media, error)
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	Attach(ctx context.Context, attachment models.MediaAttachment) error
+	Detach(ctx context.Context, attachment models.MediaAttachment) error
+	ChangePosition(ctx context.Context, attachment models.MediaAttachment) error
+	GetByEntity(ctx context.Context, entityType string, entityID int) ([]models.AttachedMedia, error)
+	CollectOrphans(ctx context.Context) (int, error)
+	StartOrphanCollector(ctx context.Context)
+}
+
+type Import interface {
+	// ImportPlaces format одно из models.ImportFormatCSV, models.ImportFormatGeoJSON
+	ImportPlaces(ctx context.Context, format string, file io.Reader, dryRun bool) (models.ImportReport, error)
+}
+
+type Variety interface {
+	Create(ctx context.Context, variety models.VarietyBase) (int, error)
+	GetAll(ctx context.Context) ([]models.Variety, error)
+	GetByName(ctx context.Context, name string) (models.Variety, error)
+	Update(ctx context.Context, variety models.Variety) error
+	Delete(ctx context.Context, varietyID int) error
+}
+
+type Popularity interface {
+	Recompute(ctx context.Context) error
+	StartScoring(ctx context.Context)
+}
+
+type PlaceDuplicate interface {
+	Detect(ctx context.Context) (int, error)
+	StartDetection(ctx context.Context)
+	GetPending(ctx context.Context, page models.PageRequest) (models.Page[models.PlaceDuplicate], error)
+	Dismiss(ctx context.Context, duplicateID int) error
+	Merge(ctx context.Context, request models.PlaceMergeRequest) (models.PlaceMerge, error)
+	GetMerges(ctx context.Context, survivorID int) ([]models.PlaceMerge, error)
+}
+
+type Stats interface {
+	GetDistrict(ctx context.Context, request models.StatsRequest) (models.AreaStats, error)
+	GetCity(ctx context.Context, request models.StatsRequest) (models.CityStats, error)
+}
+
+type Translation interface {
+	Upsert(ctx context.Context, translation models.TranslationUpsert) error
+	Delete(ctx context.Context, key models.TranslationKey) error
+	GetByEntity(ctx context.Context, entityType string, entityID int) ([]models.Translation, error)
+	GetMissing(ctx context.Context, request models.MissingTranslationsRequest) (models.Page[models.MissingTranslation], error)
+}
+
+type Notification interface {
+	GetByUser(ctx context.Context, request models.NotificationsRequest) (models.Page[models.Notification], error)
+	MarkRead(ctx context.Context, read models.NotificationsRead) (int, error)
+}
+
+type ContentReport interface {
+	GetOpen(ctx context.Context, request models.ContentReportsRequest) (models.Page[models.ContentReport], error)
+	Resolve(ctx context.Context, resolve models.ContentReportResolve) error
+}
@@ -0,0 +1,195 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/spf13/viper"
+	"mth/internal/models"
+	"mth/internal/repository"
+	"mth/pkg/config"
+	"mth/pkg/customerr"
+	"mth/pkg/log"
+	"time"
+)
+
+type statsService struct {
+	statsRepo    repository.Stats
+	cityRepo     repository.City
+	districtRepo repository.District
+	logger       *log.Logs
+}
+
+func InitStatsService(statsRepo repository.Stats, cityRepo repository.City, districtRepo repository.District,
+	logger *log.Logs) Stats {
+	return statsService{
+		statsRepo:    statsRepo,
+		cityRepo:     cityRepo,
+		districtRepo: districtRepo,
+		logger:       logger,
+	}
+}
+
+const (
+	statsDateLayout    = "2006-01-02"
+	statsDefaultWindow = 30
+)
+
+// truncateTime начало интервала как у date_trunc в postgres, неделя начинается с понедельника
+func truncateTime(t time.Time, granularity string) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+
+	switch granularity {
+	case models.GranularityWeek:
+		offset := (int(day.Weekday()) + 6) % 7
+		return day.AddDate(0, 0, -offset)
+	case models.GranularityMonth:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return day
+	}
+}
+
+func nextBucket(t time.Time, granularity string) time.Time {
+	switch granularity {
+	case models.GranularityWeek:
+		return t.AddDate(0, 0, 7)
+	case models.GranularityMonth:
+		return t.AddDate(0, 1, 0)
+	default:
+		return t.AddDate(0, 0, 1)
+	}
+}
+
+// parseStatsWindow по умолчанию последние 30 дней до now включительно с разбивкой по дням.
+// Число интервалов ограничено STATS_MAX_BUCKETS
+func parseStatsWindow(request models.StatsRequest, now time.Time) (models.StatsWindow, error) {
+	window := models.StatsWindow{Granularity: request.Granularity}
+	if window.Granularity == "" {
+		window.Granularity = models.GranularityDay
+	}
+	if window.Granularity != models.GranularityDay && window.Granularity != models.GranularityWeek &&
+		window.Granularity != models.GranularityMonth {
+		return models.StatsWindow{}, fmt.Errorf("%w: unknown granularity %q", customerr.BadInput, request.Granularity)
+	}
+
+	to := truncateTime(now, models.GranularityDay)
+	if request.To != "" {
+		parsed, err := time.Parse(statsDateLayout, request.To)
+		if err != nil {
+			return models.StatsWindow{}, fmt.Errorf("%w: bad date %q", customerr.BadInput, request.To)
+		}
+		to = parsed
+	}
+
+	from := to.AddDate(0, 0, -statsDefaultWindow+1)
+	if request.From != "" {
+		parsed, err := time.Parse(statsDateLayout, request.From)
+		if err != nil {
+			return models.StatsWindow{}, fmt.Errorf("%w: bad date %q", customerr.BadInput, request.From)
+		}
+		from = parsed
+	}
+
+	if from.After(to) {
+		return models.StatsWindow{}, fmt.Errorf("%w: from is after to", customerr.BadInput)
+	}
+
+	window.From = from
+	window.To = to.AddDate(0, 0, 1)
+
+	var buckets int
+	for start := truncateTime(window.From, window.Granularity); start.Before(window.To); start = nextBucket(start, window.Granularity) {
+		buckets++
+		if buckets > viper.GetInt(config.StatsMaxBuckets) {
+			return models.StatsWindow{}, fmt.Errorf("%w: too many %v intervals, max %v", customerr.BadInput,
+				window.Granularity, viper.GetInt(config.StatsMaxBuckets))
+		}
+	}
+
+	return window, nil
+}
+
+// fillTimeline дополняет нулями интервалы без отметок, чтобы на графике не было дыр
+func fillTimeline(timeline []models.StatsBucket, window models.StatsWindow) []models.StatsBucket {
+	known := make(map[time.Time]models.StatsBucket, len(timeline))
+	for _, bucket := range timeline {
+		known[bucket.Start] = bucket
+	}
+
+	filled := []models.StatsBucket{}
+	for start := truncateTime(window.From, window.Granularity); start.Before(window.To); start = nextBucket(start, window.Granularity) {
+		bucket, ok := known[start]
+		if !ok {
+			bucket = models.StatsBucket{Start: start}
+		}
+
+		filled = append(filled, bucket)
+	}
+
+	return filled
+}
+
+func (s statsService) area(ctx context.Context, cityID, districtID int, name string, window models.StatsWindow) (models.AreaStats, error) {
+	stats, err := s.statsRepo.GetArea(ctx, cityID, districtID, window)
+	if err != nil {
+		s.logger.Error(err.Error())
+		return models.AreaStats{}, err
+	}
+
+	stats.Name = name
+	stats.Timeline = fillTimeline(stats.Timeline, window)
+
+	return stats, nil
+}
+
+func (s statsService) GetDistrict(ctx context.Context, request models.StatsRequest) (models.AreaStats, error) {
+	window, err := parseStatsWindow(request, time.Now().UTC())
+	if err != nil {
+		return models.AreaStats{}, err
+	}
+
+	district, err := s.districtRepo.GetByID(ctx, request.ID)
+	if err != nil {
+		s.logger.Error(err.Error())
+		return models.AreaStats{}, err
+	}
+
+	return s.area(ctx, district.CityID, district.ID, district.Name, window)
+}
+
+// GetCity статистика по городу целиком и по каждому его району
+func (s statsService) GetCity(ctx context.Context, request models.StatsRequest) (models.CityStats, error) {
+	window, err := parseStatsWindow(request, time.Now().UTC())
+	if err != nil {
+		return models.CityStats{}, err
+	}
+
+	city, err := s.cityRepo.GetByID(ctx, request.ID)
+	if err != nil {
+		s.logger.Error(err.Error())
+		return models.CityStats{}, err
+	}
+
+	cityStats, err := s.area(ctx, city.ID, 0, city.Name, window)
+	if err != nil {
+		return models.CityStats{}, err
+	}
+
+	districts, err := s.districtRepo.GetByCityID(ctx, city.ID)
+	if err != nil {
+		s.logger.Error(err.Error())
+		return models.CityStats{}, err
+	}
+
+	result := models.CityStats{AreaStats: cityStats, Districts: make([]models.AreaStats, 0, len(districts))}
+	for _, district := range districts {
+		districtStats, err := s.area(ctx, city.ID, district.ID, district.Name, window)
+		if err != nil {
+			return models.CityStats{}, err
+		}
+
+		result.Districts = append(result.Districts, districtStats)
+	}
+
+	return result, nil
+}
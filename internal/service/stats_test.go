@@ -0,0 +1,69 @@
+package service
+
+import (
+	"errors"
+	"github.com/spf13/viper"
+	"mth/internal/models"
+	"mth/pkg/config"
+	"mth/pkg/customerr"
+	"testing"
+	"time"
+)
+
+func TestParseStatsWindow(t *testing.T) {
+	maxBuckets := viper.Get(config.StatsMaxBuckets)
+	defer viper.Set(config.StatsMaxBuckets, maxBuckets)
+	viper.Set(config.StatsMaxBuckets, 400)
+	now := time.Date(2024, 4, 10, 15, 30, 0, 0, time.UTC)
+
+	window, err := parseStatsWindow(models.StatsRequest{}, now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if window.Granularity != models.GranularityDay || !window.From.Equal(time.Date(2024, 3, 12, 0, 0, 0, 0, time.UTC)) ||
+		!window.To.Equal(time.Date(2024, 4, 11, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected default window %+v", window)
+	}
+
+	bad := []models.StatsRequest{
+		{Granularity: "year"},
+		{From: "10.04.2024"},
+		{From: "2024-04-10", To: "2024-04-01"},
+		{From: "2020-01-01", To: "2024-01-01", Granularity: models.GranularityDay},
+	}
+	for _, request := range bad {
+		if _, err = parseStatsWindow(request, now); !errors.Is(err, customerr.BadInput) {
+			t.Errorf("%+v: expected bad input, got %v", request, err)
+		}
+	}
+}
+
+func TestFillTimeline(t *testing.T) {
+	window := models.StatsWindow{
+		From:        time.Date(2024, 4, 3, 0, 0, 0, 0, time.UTC), // среда
+		To:          time.Date(2024, 4, 20, 0, 0, 0, 0, time.UTC),
+		Granularity: models.GranularityWeek,
+	}
+	known := []models.StatsBucket{{Start: time.Date(2024, 4, 8, 0, 0, 0, 0, time.UTC), CheckIns: 5, Visitors: 3}}
+
+	timeline := fillTimeline(known, window)
+
+	if len(timeline) != 3 {
+		t.Fatalf("expected 3 weeks, got %+v", timeline)
+	}
+	if !timeline[0].Start.Equal(time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected week to start on monday, got %v", timeline[0].Start)
+	}
+	if timeline[1].CheckIns != 5 || timeline[0].CheckIns != 0 || timeline[2].CheckIns != 0 {
+		t.Errorf("unexpected timeline %+v", timeline)
+	}
+
+	months := fillTimeline(nil, models.StatsWindow{
+		From:        time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC),
+		To:          time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC),
+		Granularity: models.GranularityMonth,
+	})
+	if len(months) != 3 || months[1].Start.Month() != time.February {
+		t.Errorf("unexpected months %+v", months)
+	}
+}
@@ -2,39 +2,195 @@ package service
 
 import (
 	"context"
+	"fmt"
 	"mth/internal/models"
 	"mth/internal/repository"
+	"mth/pkg/customerr"
 	"mth/pkg/log"
+	"strings"
 )
 
 type tagService struct {
-	tagRepo repository.Tag
-	logger  *log.Logs
+	tagRepo   repository.Tag
+	localizer localizer
+	logger    *log.Logs
 }
 
-func InitTagService(tagRepo repository.Tag, logger *log.Logs) Tag {
+func InitTagService(tagRepo repository.Tag, translationRepo repository.Translation, logger *log.Logs) Tag {
 	return tagService{
-		tagRepo: tagRepo,
-		logger:  logger,
+		tagRepo:   tagRepo,
+		localizer: localizer{translationRepo: translationRepo},
+		logger:    logger,
 	}
 }
 
+// resolveTagGroups nil, если фильтр по тегам не задан, иначе группа id на каждый запрошенный тег
+func resolveTagGroups(ctx context.Context, tagRepo repository.Tag, filter models.TagFilter) ([][]int, error) {
+	if len(filter.TagIDs) == 0 && len(filter.Names) == 0 {
+		return nil, nil
+	}
+
+	return tagRepo.ResolveFilter(ctx, filter)
+}
+
+// tagExpectedErrors ошибки валидации и отсутствия тега ожидаемы и не логируются
+var tagExpectedErrors = []error{
+	customerr.BadInput, customerr.TagNotFound, customerr.TagNameTaken, customerr.TagCycle,
+	customerr.TagCategoryNotFound, customerr.TagInUse,
+}
+
+func (t tagService) logUnexpected(err error) {
+	logUnexpected(t.logger, err, tagExpectedErrors)
+}
+
+func validateTagName(name string) error {
+	if strings.TrimSpace(name) == "" {
+		return fmt.Errorf("%w: tag name is empty", customerr.BadInput)
+	}
+
+	return nil
+}
+
 func (t tagService) Create(ctx context.Context, tag models.TagCreate) (int, error) {
+	if err := validateTagName(tag.Name); err != nil {
+		return 0, err
+	}
+
 	id, err := t.tagRepo.Create(ctx, tag)
 	if err != nil {
-		t.logger.Error(err.Error())
+		t.logUnexpected(err)
 		return 0, err
 	}
 
 	return id, nil
 }
 
-func (t tagService) GetAll(ctx context.Context) ([]models.Tag, error) {
+func (t tagService) GetAll(ctx context.Context) ([]models.TagDetails, error) {
 	tags, err := t.tagRepo.GetAll(ctx)
 	if err != nil {
 		t.logger.Error(err.Error())
 		return tags, err
 	}
 
+	entities := make([]localizedEntity, 0, len(tags))
+	for i := range tags {
+		entities = append(entities, localizedEntity{id: tags[i].ID, name: &tags[i].Name})
+	}
+
+	if err = t.localizer.localize(ctx, models.TranslationEntityTag, entities); err != nil {
+		t.logger.Error(err.Error())
+		return []models.TagDetails{}, err
+	}
+
 	return tags, nil
 }
+
+func (t tagService) SetHierarchy(ctx context.Context, hierarchy models.TagHierarchy) error {
+	if hierarchy.ID == hierarchy.ParentID {
+		return customerr.TagCycle
+	}
+
+	err := t.tagRepo.SetHierarchy(ctx, hierarchy)
+	if err != nil {
+		t.logUnexpected(err)
+		return err
+	}
+
+	return nil
+}
+
+func (t tagService) AddSynonym(ctx context.Context, synonym models.TagSynonym) error {
+	if err := validateTagName(synonym.Name); err != nil {
+		return err
+	}
+
+	err := t.tagRepo.AddSynonym(ctx, synonym)
+	if err != nil {
+		t.logUnexpected(err)
+		return err
+	}
+
+	return nil
+}
+
+func (t tagService) DeleteSynonym(ctx context.Context, name string) error {
+	err := t.tagRepo.DeleteSynonym(ctx, name)
+	if err != nil {
+		t.logUnexpected(err)
+		return err
+	}
+
+	return nil
+}
+
+func (t tagService) CreateCategory(ctx context.Context, category models.TagCategoryCreate) (int, error) {
+	if strings.TrimSpace(category.Name) == "" {
+		return 0, fmt.Errorf("%w: category name is empty", customerr.BadInput)
+	}
+
+	id, err := t.tagRepo.CreateCategory(ctx, category)
+	if err != nil {
+		t.logger.Error(err.Error())
+		return 0, err
+	}
+
+	return id, nil
+}
+
+func (t tagService) GetCategories(ctx context.Context) ([]models.TagCategory, error) {
+	categories, err := t.tagRepo.GetCategories(ctx)
+	if err != nil {
+		t.logger.Error(err.Error())
+		return categories, err
+	}
+
+	return categories, nil
+}
+
+func (t tagService) DeleteCategory(ctx context.Context, categoryID int) error {
+	err := t.tagRepo.DeleteCategory(ctx, categoryID)
+	if err != nil {
+		t.logUnexpected(err)
+		return err
+	}
+
+	return nil
+}
+
+func (t tagService) Rename(ctx context.Context, rename models.TagRename) error {
+	if err := validateTagName(rename.Name); err != nil {
+		return err
+	}
+
+	err := t.tagRepo.Rename(ctx, rename)
+	if err != nil {
+		t.logUnexpected(err)
+		return err
+	}
+
+	return nil
+}
+
+func (t tagService) Delete(ctx context.Context, tagID int, cascade bool) (models.TagDeleteResult, error) {
+	result, err := t.tagRepo.Delete(ctx, tagID, cascade)
+	if err != nil {
+		t.logUnexpected(err)
+		return models.TagDeleteResult{}, err
+	}
+
+	return result, nil
+}
+
+func (t tagService) Merge(ctx context.Context, request models.TagMergeRequest) (models.TagMergeResult, error) {
+	if request.SourceID == request.TargetID {
+		return models.TagMergeResult{}, fmt.Errorf("%w: tag can not be merged into itself", customerr.BadInput)
+	}
+
+	result, err := t.tagRepo.Merge(ctx, request)
+	if err != nil {
+		t.logUnexpected(err)
+		return models.TagMergeResult{}, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,281 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/spf13/viper"
+	"mth/internal/models"
+	"mth/internal/repository"
+	"mth/pkg/config"
+	"mth/pkg/customerr"
+	"mth/pkg/locale"
+	"mth/pkg/log"
+	"mth/pkg/pagination"
+	"strings"
+)
+
+type translationService struct {
+	translationRepo repository.Translation
+	logger          *log.Logs
+}
+
+func InitTranslationService(translationRepo repository.Translation, logger *log.Logs) Translation {
+	return translationService{
+		translationRepo: translationRepo,
+		logger:          logger,
+	}
+}
+
+// pickTranslations значение каждого поля на первом языке цепочки, для которого есть перевод: id -> поле -> значение
+func pickTranslations(translations []models.Translation, chain []string) map[int]map[string]string {
+	rank := make(map[string]int, len(chain))
+	for i, lang := range chain {
+		if _, ok := rank[lang]; !ok {
+			rank[lang] = i
+		}
+	}
+
+	values := make(map[int]map[string]string)
+	ranks := make(map[int]map[string]int)
+	for _, translation := range translations {
+		langRank, ok := rank[translation.Lang]
+		if !ok {
+			continue
+		}
+
+		if values[translation.EntityID] == nil {
+			values[translation.EntityID] = make(map[string]string)
+			ranks[translation.EntityID] = make(map[string]int)
+		}
+
+		if current, ok := ranks[translation.EntityID][translation.Field]; ok && current <= langRank {
+			continue
+		}
+
+		values[translation.EntityID][translation.Field] = translation.Value
+		ranks[translation.EntityID][translation.Field] = langRank
+	}
+
+	return values
+}
+
+// localizedEntity поля сущности ответа, которые заменяются переводом, description может быть nil
+type localizedEntity struct {
+	id          int
+	name        *string
+	description *string
+}
+
+// localizer подставляет переводы по цепочке языков из контекста запроса, исходные значения остаются,
+// если перевода нет ни на одном языке цепочки
+type localizer struct {
+	translationRepo repository.Translation
+}
+
+func (l localizer) localize(ctx context.Context, entityType string, entities []localizedEntity) error {
+	if len(entities) == 0 {
+		return nil
+	}
+
+	ids := make([]int, 0, len(entities))
+	for _, entity := range entities {
+		ids = append(ids, entity.id)
+	}
+
+	chain := locale.Chain(locale.FromContext(ctx), locale.Normalize(viper.GetString(config.DefaultLanguage)))
+
+	translations, err := l.translationRepo.GetForEntities(ctx, entityType, ids, chain)
+	if err != nil {
+		return err
+	}
+
+	values := pickTranslations(translations, chain)
+	for _, entity := range entities {
+		if name, ok := values[entity.id][models.TranslationFieldName]; ok {
+			*entity.name = name
+		}
+		if description, ok := values[entity.id][models.TranslationFieldDescription]; ok && entity.description != nil {
+			*entity.description = description
+		}
+	}
+
+	return nil
+}
+
+func tagEntities(tags []models.Tag) []localizedEntity {
+	entities := make([]localizedEntity, 0, len(tags))
+	for i := range tags {
+		entities = append(entities, localizedEntity{id: tags[i].ID, name: &tags[i].Name})
+	}
+
+	return entities
+}
+
+// places переводит названия и описания мест вместе с их тегами
+func (l localizer) places(ctx context.Context, places []*models.Place) error {
+	var placeEntities, tagEntitiesAll []localizedEntity
+	for _, place := range places {
+		placeEntities = append(placeEntities, localizedEntity{id: place.ID, name: &place.Name, description: &place.Description})
+		tagEntitiesAll = append(tagEntitiesAll, tagEntities(place.Tags)...)
+	}
+
+	if err := l.localize(ctx, models.TranslationEntityPlace, placeEntities); err != nil {
+		return err
+	}
+
+	return l.localize(ctx, models.TranslationEntityTag, tagEntitiesAll)
+}
+
+// routes переводит маршруты, их теги и остановки
+func (l localizer) routes(ctx context.Context, routes []*models.Route) error {
+	var routeEntities, tagEntitiesAll []localizedEntity
+	var places []*models.Place
+	for _, route := range routes {
+		routeEntities = append(routeEntities, localizedEntity{id: route.ID, name: &route.Name, description: &route.Description})
+		tagEntitiesAll = append(tagEntitiesAll, tagEntities(route.Tags)...)
+
+		for i := range route.Places {
+			places = append(places, &route.Places[i].Place)
+		}
+	}
+
+	if err := l.localize(ctx, models.TranslationEntityRoute, routeEntities); err != nil {
+		return err
+	}
+	if err := l.localize(ctx, models.TranslationEntityTag, tagEntitiesAll); err != nil {
+		return err
+	}
+
+	return l.places(ctx, places)
+}
+
+// routesRaw переводит названия маршрутов без остановок вместе с их тегами
+func (l localizer) routesRaw(ctx context.Context, routes []*models.RouteRaw) error {
+	var routeEntities, tagEntitiesAll []localizedEntity
+	for _, route := range routes {
+		routeEntities = append(routeEntities, localizedEntity{id: route.ID, name: &route.Name})
+		tagEntitiesAll = append(tagEntitiesAll, tagEntities(route.Tags)...)
+	}
+
+	if err := l.localize(ctx, models.TranslationEntityRoute, routeEntities); err != nil {
+		return err
+	}
+
+	return l.localize(ctx, models.TranslationEntityTag, tagEntitiesAll)
+}
+
+// names переводит названия мест и маршрутов, на которые ссылаются попутчики, запросы и дневник: тип -> сущности
+func (l localizer) names(ctx context.Context, entities map[string][]localizedEntity) error {
+	for _, entityType := range []string{models.TranslationEntityPlace, models.TranslationEntityRoute} {
+		if err := l.localize(ctx, entityType, entities[entityType]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func validateTranslationKey(key models.TranslationKey) (models.TranslationKey, error) {
+	fields, ok := models.TranslatableFields[key.EntityType]
+	if !ok {
+		return key, fmt.Errorf("%w: unknown entity type %v", customerr.BadInput, key.EntityType)
+	}
+
+	var fieldOk bool
+	for _, field := range fields {
+		if field == key.Field {
+			fieldOk = true
+		}
+	}
+	if !fieldOk {
+		return key, fmt.Errorf("%w: field %v can not be translated for %v", customerr.BadInput, key.Field, key.EntityType)
+	}
+
+	lang := locale.Normalize(key.Lang)
+	if lang == "" {
+		return key, fmt.Errorf("%w: invalid language %v", customerr.BadInput, key.Lang)
+	}
+	key.Lang = lang
+
+	return key, nil
+}
+
+// translationExpectedErrors ошибки валидации и отсутствия перевода ожидаемы и не логируются
+var translationExpectedErrors = []error{
+	customerr.BadInput, customerr.TranslationNotFound, customerr.TranslationEntityNotFound,
+	customerr.InvalidCursor,
+}
+
+func (t translationService) logUnexpected(err error) {
+	logUnexpected(t.logger, err, translationExpectedErrors)
+}
+
+func (t translationService) Upsert(ctx context.Context, translation models.TranslationUpsert) error {
+	key, err := validateTranslationKey(translation.TranslationKey)
+	if err != nil {
+		return err
+	}
+	translation.TranslationKey = key
+
+	translation.Value = strings.TrimSpace(translation.Value)
+	if translation.Value == "" {
+		return fmt.Errorf("%w: translation is empty", customerr.BadInput)
+	}
+
+	if err = t.translationRepo.Upsert(ctx, translation); err != nil {
+		t.logUnexpected(err)
+		return err
+	}
+
+	return nil
+}
+
+func (t translationService) Delete(ctx context.Context, key models.TranslationKey) error {
+	key, err := validateTranslationKey(key)
+	if err != nil {
+		return err
+	}
+
+	if err = t.translationRepo.Delete(ctx, key); err != nil {
+		t.logUnexpected(err)
+		return err
+	}
+
+	return nil
+}
+
+func (t translationService) GetByEntity(ctx context.Context, entityType string, entityID int) ([]models.Translation, error) {
+	if _, ok := models.TranslatableFields[entityType]; !ok {
+		return []models.Translation{}, fmt.Errorf("%w: unknown entity type %v", customerr.BadInput, entityType)
+	}
+
+	translations, err := t.translationRepo.GetByEntity(ctx, entityType, entityID)
+	if err != nil {
+		t.logger.Error(err.Error())
+		return []models.Translation{}, err
+	}
+
+	return translations, nil
+}
+
+func (t translationService) GetMissing(ctx context.Context, request models.MissingTranslationsRequest) (models.Page[models.MissingTranslation], error) {
+	fields, ok := models.TranslatableFields[request.EntityType]
+	if !ok {
+		return models.Page[models.MissingTranslation]{}, fmt.Errorf("%w: unknown entity type %v", customerr.BadInput, request.EntityType)
+	}
+
+	request.Lang = locale.Normalize(request.Lang)
+	if request.Lang == "" {
+		return models.Page[models.MissingTranslation]{}, fmt.Errorf("%w: invalid language", customerr.BadInput)
+	}
+
+	request.PageSize = pagination.PageSize(request.PageSize, viper.GetInt(config.PlacesOnPage), viper.GetInt(config.MaxPageSize))
+
+	missing, err := t.translationRepo.GetMissing(ctx, request, fields)
+	if err != nil {
+		t.logUnexpected(err)
+		return models.Page[models.MissingTranslation]{}, err
+	}
+
+	return missing, nil
+}
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"mth/internal/models"
+	"mth/internal/repository"
+	"mth/pkg/config"
+	"mth/pkg/locale"
+	"testing"
+)
+
+func translation(entityID int, lang, field, value string) models.Translation {
+	return models.Translation{TranslationUpsert: models.TranslationUpsert{
+		TranslationKey: models.TranslationKey{EntityType: models.TranslationEntityPlace, EntityID: entityID, Lang: lang, Field: field},
+		Value:          value,
+	}}
+}
+
+func TestPickTranslations(t *testing.T) {
+	translations := []models.Translation{
+		translation(1, "ru", models.TranslationFieldName, "Эрмитаж"),
+		translation(1, "en", models.TranslationFieldName, "Hermitage"),
+		translation(1, "en-gb", models.TranslationFieldName, "The Hermitage"),
+		translation(1, "ru", models.TranslationFieldDescription, "Музей"),
+		translation(2, "de", models.TranslationFieldName, "Kathedrale"),
+	}
+
+	values := pickTranslations(translations, []string{"en-us", "en", "ru"})
+
+	if got := values[1][models.TranslationFieldName]; got != "Hermitage" {
+		t.Errorf("expected first language of the chain, got %q", got)
+	}
+	if got := values[1][models.TranslationFieldDescription]; got != "Музей" {
+		t.Errorf("expected fallback to default language, got %q", got)
+	}
+	if _, ok := values[2]; ok {
+		t.Errorf("language outside of the chain must be ignored, got %v", values[2])
+	}
+}
+
+func TestValidateTranslationKey(t *testing.T) {
+	key, err := validateTranslationKey(models.TranslationKey{EntityType: "place", EntityID: 1, Lang: "EN_us", Field: "description"})
+	if err != nil || key.Lang != "en-us" {
+		t.Errorf("expected normalized language, got %+v, %v", key, err)
+	}
+
+	if _, err = validateTranslationKey(models.TranslationKey{EntityType: "tag", EntityID: 1, Lang: "en", Field: "description"}); err == nil {
+		t.Error("tag description is not translatable")
+	}
+	if _, err = validateTranslationKey(models.TranslationKey{EntityType: "review", EntityID: 1, Lang: "en", Field: "name"}); err == nil {
+		t.Error("unknown entity type must be rejected")
+	}
+}
+
+// fakeTranslationRepo переводы в памяти, отдаёт только запрошенный тип сущности
+type fakeTranslationRepo struct {
+	repository.Translation
+	translations []models.Translation
+}
+
+func (f fakeTranslationRepo) GetForEntities(_ context.Context, entityType string, _ []int, _ []string) ([]models.Translation, error) {
+	var translations []models.Translation
+	for _, translation := range f.translations {
+		if translation.EntityType == entityType {
+			translations = append(translations, translation)
+		}
+	}
+
+	return translations, nil
+}
+
+func TestLocalizerNames(t *testing.T) {
+	config.InitConfig()
+
+	routeName := translation(1, "en", models.TranslationFieldName, "Museum walk")
+	routeName.EntityType = models.TranslationEntityRoute
+	l := localizer{translationRepo: fakeTranslationRepo{translations: []models.Translation{
+		translation(1, "en", models.TranslationFieldName, "Hermitage"),
+		routeName,
+	}}}
+
+	place, samePlace, route := "Эрмитаж", "Эрмитаж", "Прогулка по музеям"
+	ctx := locale.WithLanguages(context.Background(), []string{"en"})
+	err := l.names(ctx, map[string][]localizedEntity{
+		models.TranslationEntityPlace: {{id: 1, name: &place}, {id: 1, name: &samePlace}},
+		models.TranslationEntityRoute: {{id: 1, name: &route}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if place != "Hermitage" || samePlace != "Hermitage" || route != "Museum walk" {
+		t.Errorf("every entity must be translated by its own type, got %q, %q, %q", place, samePlace, route)
+	}
+}
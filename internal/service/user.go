@@ -9,6 +9,7 @@ import (
 	"mth/internal/models"
 	"mth/internal/repository"
 	"mth/pkg/config"
+	"mth/pkg/contentfilter"
 	"mth/pkg/log"
 	"strconv"
 	"strings"
@@ -22,12 +23,16 @@ type userService struct {
 	placeRepo     repository.Place
 	tripRepo      repository.Trip
 	reviewRepo    repository.Review
+	varietyRepo   repository.Variety
+	reportRepo    repository.ContentReport
+	filter        *contentfilter.Filter
 	logger        *log.Logs
 	hashes        []string
 }
 
 func InitUserService(userRepo repository.User, logger *log.Logs, favouriteRepo repository.Favourite,
-	routeRepo repository.Route, placeRepo repository.Place, tripRepo repository.Trip, reviewRepo repository.Review) User {
+	routeRepo repository.Route, placeRepo repository.Place, tripRepo repository.Trip, reviewRepo repository.Review,
+	varietyRepo repository.Variety, reportRepo repository.ContentReport) User {
 	return &userService{
 		userRepo:      userRepo,
 		favouriteRepo: favouriteRepo,
@@ -35,6 +40,9 @@ func InitUserService(userRepo repository.User, logger *log.Logs, favouriteRepo r
 		placeRepo:     placeRepo,
 		tripRepo:      tripRepo,
 		reviewRepo:    reviewRepo,
+		varietyRepo:   varietyRepo,
+		reportRepo:    reportRepo,
+		filter:        sharedContentFilter(logger),
 		logger:        logger,
 		hashes:        make([]string, 1),
 	}
@@ -201,16 +209,11 @@ func containsWithPosition(places []models.PlaceIDWithPosition, placeID int) (boo
 	return false, 0
 }
 
-var badPlaceVarieties = [7]string{"Редкое событие", "Площади", "Архитектура", "Памятники", "Набережные", "Улицы", "Природа"}
-
-func isNonCheckinable(variety interface{}) bool {
-	for _, badPlaceVariety := range badPlaceVarieties {
-		if variety == badPlaceVariety {
-			return true
-		}
-	}
-
-	return false
+// isNonCheckinable флаг checkinable проставляется в getPlacesWithPosition из таблицы varieties,
+// вид места, которого нет в таблице, считается checkinable
+func isNonCheckinable(place map[string]interface{}) bool {
+	checkinable, ok := place["checkinable"].(bool)
+	return ok && !checkinable
 }
 
 // iterDownFromPosition flag нужен для того чтобы понимать встретили ли мы конец или
@@ -221,7 +224,7 @@ func iterDownFromPosition(places []map[string]interface{}, position int) []int {
 	for position > 0 {
 		for _, place := range places {
 			if place["position"] == position {
-				if isNonCheckinable(place["variety"]) {
+				if isNonCheckinable(place) {
 					nonCheckinablePlaceIDs = append(nonCheckinablePlaceIDs, place["id"].(int))
 					break
 				} else {
@@ -235,20 +238,49 @@ func iterDownFromPosition(places []map[string]interface{}, position int) []int {
 	return nil
 }
 
+// placesWithPosition места маршрута с позициями и флагом checkinable их вида, если вид есть в checkinable
+func placesWithPosition(places []models.Place, rawPlaces []models.PlaceIDWithPosition,
+	checkinable map[string]bool) []map[string]interface{} {
+	var positioned []map[string]interface{}
+	for i, rawPlace := range rawPlaces {
+		place := places[i]
+		placeWithPosition := map[string]interface{}{
+			"variety": place.Variety, "position": rawPlace.Position, "id": place.ID,
+		}
+		if varietyCheckinable, ok := checkinable[place.Variety]; ok {
+			placeWithPosition["checkinable"] = varietyCheckinable
+		}
+
+		positioned = append(positioned, placeWithPosition)
+	}
+
+	return positioned
+}
+
+// getPlacesWithPosition виды мест загружаются одним запросом и только встречающиеся в маршруте
 func (u *userService) getPlacesWithPosition(ctx context.Context, rawPlaces []models.PlaceIDWithPosition) ([]map[string]interface{}, error) {
-	var placesWithPosition []map[string]interface{}
+	places := make([]models.Place, 0, len(rawPlaces))
+	var varieties []string
+	seen := make(map[string]bool)
 	for _, rawPlace := range rawPlaces {
 		place, err := u.placeRepo.GetByID(ctx, rawPlace.PlaceID)
 		if err != nil {
 			return []map[string]interface{}{}, err
 		}
 
-		placesWithPosition = append(placesWithPosition, map[string]interface{}{
-			"variety": place.Variety, "position": rawPlace.Position, "id": place.ID,
-		})
+		places = append(places, place)
+		if !seen[place.Variety] {
+			seen[place.Variety] = true
+			varieties = append(varieties, place.Variety)
+		}
 	}
 
-	return placesWithPosition, nil
+	checkinable, err := u.varietyRepo.GetCheckinable(ctx, varieties)
+	if err != nil {
+		return []map[string]interface{}{}, err
+	}
+
+	return placesWithPosition(places, rawPlaces, checkinable), nil
 }
 
 func (u *userService) CheckIn(ctx context.Context, cipher string, userID int) (string, error) {
@@ -380,6 +412,7 @@ func (u *userService) timeBetween(timeStart, timeEnd, entityTime time.Time) bool
 	return entityTime.After(timeStart) && entityTime.Before(timeEnd)
 }
 
+// GetProperties собственный профиль пользователя целиком, вместе с контактами
 func (u *userService) GetProperties(ctx context.Context, userID int) (string, time.Time, interface{}, error) {
 	login, properties, err := u.userRepo.GetProperties(ctx, userID)
 	if err != nil {
@@ -404,13 +437,38 @@ func (u *userService) GetProperties(ctx context.Context, userID int) (string, ti
 	return login, currentStartTripDate, properties, nil
 }
 
+// UpdateProperties свойства профиля видны попутчикам, поэтому проходят фильтр содержимого
 func (u *userService) UpdateProperties(ctx context.Context, userID int, properties interface{}) error {
-	err := u.userRepo.UpdateProperties(ctx, userID, properties)
+	subject, err := contentSubject(ctx, u.reportRepo, userID, fmt.Sprintf("user:%v", userID))
 	if err != nil {
 		u.logger.Error(err.Error())
 		return err
 	}
 
+	properties, verdict, err := u.filter.Properties(subject, properties)
+	if err != nil {
+		return err
+	}
+
+	err = u.userRepo.UpdateProperties(ctx, userID, properties)
+	if err != nil {
+		u.logger.Error(err.Error())
+		return err
+	}
+
+	// профиль уже сохранён, поэтому ошибка жалобы только логируется
+	if verdict.Moderate {
+		err = u.reportRepo.Create(ctx, models.ContentReport{
+			EntityType: models.ContentReportUser,
+			EntityID:   userID,
+			UserID:     userID,
+			Rules:      verdict.Rules,
+		})
+		if err != nil {
+			u.logger.Error(fmt.Sprintf("unable to report user %v, rules %v: %v", userID, verdict.Rules, err))
+		}
+	}
+
 	return nil
 }
 
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"github.com/jmoiron/sqlx"
+	"mth/internal/models"
 	"mth/internal/repository"
 	"mth/pkg/config"
 	"testing"
@@ -193,3 +194,47 @@ func TestUserService_CheckINLogs(t *testing.T) {
 	//
 	//_ = userService
 }
+
+func TestPlacesWithPosition(t *testing.T) {
+	places := []models.Place{
+		{ID: 10, PlaceBase: models.PlaceBase{Variety: "park"}},
+		{ID: 11, PlaceBase: models.PlaceBase{Variety: "cafe"}},
+		{ID: 12, PlaceBase: models.PlaceBase{Variety: "unknown"}},
+	}
+	rawPlaces := []models.PlaceIDWithPosition{{PlaceID: 10, Position: 1}, {PlaceID: 11, Position: 2}, {PlaceID: 12, Position: 3}}
+
+	positioned := placesWithPosition(places, rawPlaces, map[string]bool{"park": false, "cafe": true})
+	if len(positioned) != 3 || positioned[1]["id"] != 11 || positioned[1]["position"] != 2 {
+		t.Fatalf("unexpected places %v", positioned)
+	}
+
+	if !isNonCheckinable(positioned[0]) || isNonCheckinable(positioned[1]) {
+		t.Errorf("checkinable flag must come from varieties, got %v", positioned)
+	}
+
+	if _, ok := positioned[2]["checkinable"]; ok || isNonCheckinable(positioned[2]) {
+		t.Errorf("variety missing from varieties must be checkinable, got %v", positioned[2])
+	}
+}
+
+func TestIterDownFromPosition(t *testing.T) {
+	places := []map[string]interface{}{
+		{"id": 1, "position": 1, "checkinable": true},
+		{"id": 2, "position": 2, "checkinable": false},
+		{"id": 3, "position": 3, "checkinable": false},
+		{"id": 4, "position": 4},
+	}
+
+	if ids := iterDownFromPosition(places, 4); fmt.Sprint(ids) != "[3 2]" {
+		t.Errorf("expected non-checkinable places 3 and 2 before checkinable 1, got %v", ids)
+	}
+
+	if ids := iterDownFromPosition(places, 2); len(ids) != 0 {
+		t.Errorf("checkinable place right before position must stop the walk, got %v", ids)
+	}
+
+	// до начала маршрута не встретилось ни одного checkinable места
+	if ids := iterDownFromPosition(places[1:], 4); len(ids) != 0 {
+		t.Errorf("walk reaching route start returns nothing, got %v", ids)
+	}
+}
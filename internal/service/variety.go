@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+	"mth/internal/models"
+	"mth/internal/repository"
+	"mth/pkg/customerr"
+	"mth/pkg/log"
+	"strings"
+)
+
+type varietyService struct {
+	varietyRepo repository.Variety
+	logger      *log.Logs
+}
+
+func InitVarietyService(varietyRepo repository.Variety, logger *log.Logs) Variety {
+	return varietyService{
+		varietyRepo: varietyRepo,
+		logger:      logger,
+	}
+}
+
+func validateVariety(variety models.VarietyBase) error {
+	if strings.TrimSpace(variety.Name) == "" || variety.GeofenceRadius < 0 {
+		return customerr.BadInput
+	}
+
+	return nil
+}
+
+// normalizeCriteria критерии оценки без регистра, пустых и повторов, в исходном порядке
+func normalizeCriteria(criteria []string) []string {
+	result := []string{}
+	seen := make(map[string]bool)
+	for _, criterion := range criteria {
+		criterion = strings.ToLower(strings.TrimSpace(criterion))
+		if criterion == "" || seen[criterion] {
+			continue
+		}
+
+		seen[criterion] = true
+		result = append(result, criterion)
+	}
+
+	return result
+}
+
+func (v varietyService) Create(ctx context.Context, variety models.VarietyBase) (int, error) {
+	if err := validateVariety(variety); err != nil {
+		return 0, err
+	}
+	variety.ReviewCriteria = normalizeCriteria(variety.ReviewCriteria)
+
+	id, err := v.varietyRepo.Create(ctx, variety)
+	if err != nil {
+		v.logger.Error(err.Error())
+		return 0, err
+	}
+
+	return id, nil
+}
+
+func (v varietyService) GetAll(ctx context.Context) ([]models.Variety, error) {
+	varieties, err := v.varietyRepo.GetAll(ctx)
+	if err != nil {
+		v.logger.Error(err.Error())
+		return []models.Variety{}, err
+	}
+
+	return varieties, nil
+}
+
+func (v varietyService) GetByName(ctx context.Context, name string) (models.Variety, error) {
+	variety, err := v.varietyRepo.GetByName(ctx, name)
+	if err != nil {
+		v.logger.Error(err.Error())
+		return models.Variety{}, err
+	}
+
+	return variety, nil
+}
+
+func (v varietyService) Update(ctx context.Context, variety models.Variety) error {
+	if err := validateVariety(variety.VarietyBase); err != nil {
+		return err
+	}
+	variety.ReviewCriteria = normalizeCriteria(variety.ReviewCriteria)
+
+	err := v.varietyRepo.Update(ctx, variety)
+	if err != nil {
+		v.logger.Error(err.Error())
+		return err
+	}
+
+	return nil
+}
+
+func (v varietyService) Delete(ctx context.Context, varietyID int) error {
+	err := v.varietyRepo.Delete(ctx, varietyID)
+	if err != nil {
+		v.logger.Error(err.Error())
+		return err
+	}
+
+	return nil
+}
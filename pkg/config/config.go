@@ -19,7 +19,63 @@ const (
 	JaegerPort       = "JAEGER_PORT"
 	PlacesOnPage     = "PLACES_ON_PAGE"
 	CompanionsOnPage = "COMPANIONS_ON_PAGE"
-	CipherKey        = "CIPHER_KEY"
+	NotesOnPage      = "NOTES_ON_PAGE"
+
+	NotificationsOnPage = "NOTIFICATIONS_ON_PAGE"
+
+	CompanionContactKeys = "COMPANION_CONTACT_KEYS"
+	MaxPageSize          = "MAX_PAGE_SIZE"
+	CipherKey            = "CIPHER_KEY"
+
+	MediaRoot          = "MEDIA_ROOT"
+	MediaBaseURL       = "MEDIA_BASE_URL"
+	MediaMaxSize       = "MEDIA_MAX_SIZE"
+	MediaMaxPixels     = "MEDIA_MAX_PIXELS"
+	MediaThumbnailSize = "MEDIA_THUMBNAIL_SIZE"
+	MediaOrphanTTL     = "MEDIA_ORPHAN_TTL"
+	MediaGCInterval    = "MEDIA_GC_INTERVAL"
+
+	ImportMaxSize = "IMPORT_MAX_SIZE"
+
+	DiaryEmbedMaxSize = "DIARY_EMBED_MAX_SIZE"
+
+	GeoJSONMaxFeatures = "GEOJSON_MAX_FEATURES"
+
+	PopularityCheckInWeight       = "POPULARITY_CHECKIN_WEIGHT"
+	PopularityFavouriteWeight     = "POPULARITY_FAVOURITE_WEIGHT"
+	PopularityReviewWeight        = "POPULARITY_REVIEW_WEIGHT"
+	PopularityRouteStartWeight    = "POPULARITY_ROUTE_START_WEIGHT"
+	PopularityRouteCompleteWeight = "POPULARITY_ROUTE_COMPLETE_WEIGHT"
+	PopularityHalfLife            = "POPULARITY_HALF_LIFE"
+	PopularityInterval            = "POPULARITY_INTERVAL"
+	TrendingLimit                 = "TRENDING_LIMIT"
+
+	PlaceDuplicatesInterval           = "PLACE_DUPLICATES_INTERVAL"
+	PlaceDuplicatesSimilarity         = "PLACE_DUPLICATES_SIMILARITY"
+	PlaceDuplicatesNameOnlySimilarity = "PLACE_DUPLICATES_NAME_ONLY_SIMILARITY"
+	PlaceDuplicatesRadius             = "PLACE_DUPLICATES_RADIUS"
+
+	StatsMaxBuckets = "STATS_MAX_BUCKETS"
+
+	DefaultLanguage = "DEFAULT_LANGUAGE"
+
+	ReviewPremoderation = "REVIEW_PREMODERATION"
+	ReviewFlagsToHide   = "REVIEW_FLAGS_TO_HIDE"
+	ReviewModerators    = "REVIEW_MODERATORS"
+
+	ReviewRouteRequireVerified = "REVIEW_ROUTE_REQUIRE_VERIFIED"
+	ReviewsOnPage              = "REVIEWS_ON_PAGE"
+	ReviewNewAccountDays       = "REVIEW_NEW_ACCOUNT_DAYS"
+	ReviewMarkMin              = "REVIEW_MARK_MIN"
+	ReviewMarkMax              = "REVIEW_MARK_MAX"
+	ReviewMarkStep             = "REVIEW_MARK_STEP"
+	ReviewRouteCriteria        = "REVIEW_ROUTE_CRITERIA"
+
+	ContentFilterProfanity    = "CONTENT_FILTER_PROFANITY"
+	ContentFilterSpam         = "CONTENT_FILTER_SPAM"
+	ContentFilterRepeat       = "CONTENT_FILTER_REPEAT"
+	ContentFilterWordlist     = "CONTENT_FILTER_WORDLIST"
+	ContentFilterRepeatWindow = "CONTENT_FILTER_REPEAT_WINDOW"
 )
 
 func InitConfig() {
@@ -33,6 +89,56 @@ func InitConfig() {
 
 	viper.AutomaticEnv()
 
+	viper.SetDefault(MaxPageSize, 100)
+
+	viper.SetDefault(MediaRoot, "media")
+	viper.SetDefault(MediaBaseURL, "/media/file")
+	viper.SetDefault(MediaMaxSize, 10<<20)
+	viper.SetDefault(MediaMaxPixels, 40_000_000)
+	viper.SetDefault(MediaThumbnailSize, 320)
+	viper.SetDefault(MediaOrphanTTL, 24*60)
+	viper.SetDefault(MediaGCInterval, 60)
+	viper.SetDefault(ImportMaxSize, 20<<20)
+	viper.SetDefault(DiaryEmbedMaxSize, 30<<20)
+	viper.SetDefault(GeoJSONMaxFeatures, 5000)
+
+	viper.SetDefault(PopularityCheckInWeight, 3.0)
+	viper.SetDefault(PopularityFavouriteWeight, 2.0)
+	viper.SetDefault(PopularityReviewWeight, 4.0)
+	viper.SetDefault(PopularityRouteStartWeight, 2.0)
+	viper.SetDefault(PopularityRouteCompleteWeight, 5.0)
+	viper.SetDefault(PopularityHalfLife, 7*24)
+	viper.SetDefault(PopularityInterval, 30)
+	viper.SetDefault(TrendingLimit, 10)
+
+	viper.SetDefault(PlaceDuplicatesInterval, 6*60)
+	viper.SetDefault(PlaceDuplicatesSimilarity, 0.4)
+	viper.SetDefault(PlaceDuplicatesNameOnlySimilarity, 0.8)
+	viper.SetDefault(PlaceDuplicatesRadius, 200)
+
+	viper.SetDefault(StatsMaxBuckets, 400)
+
+	viper.SetDefault(DefaultLanguage, "ru")
+
+	viper.SetDefault(ReviewPremoderation, false)
+	viper.SetDefault(ReviewFlagsToHide, 3)
+	viper.SetDefault(ReviewRouteRequireVerified, false)
+	viper.SetDefault(ReviewsOnPage, 20)
+	viper.SetDefault(ReviewNewAccountDays, 7)
+	viper.SetDefault(ReviewMarkMin, 1.0)
+	viper.SetDefault(ReviewMarkMax, 5.0)
+	viper.SetDefault(ReviewMarkStep, 0.5)
+
+	viper.SetDefault(ContentFilterProfanity, "mask")
+	viper.SetDefault(ContentFilterSpam, "moderate")
+	viper.SetDefault(ContentFilterRepeat, "moderate")
+	viper.SetDefault(ContentFilterRepeatWindow, 24*60)
+
+	viper.SetDefault(NotesOnPage, 20)
+	viper.SetDefault(NotificationsOnPage, 20)
+
+	viper.SetDefault(CompanionContactKeys, "phone,email,telegram,whatsapp,vk")
+
 	err := viper.ReadInConfig()
 
 	if err != nil {
@@ -0,0 +1,196 @@
+package contentfilter
+
+import (
+	"fmt"
+	"mth/pkg/customerr"
+	"unicode/utf8"
+)
+
+// Action что делать с текстом, в котором сработало правило
+type Action string
+
+const (
+	ActionOff      Action = "off"
+	ActionReject   Action = "reject"
+	ActionMask     Action = "mask"
+	ActionModerate Action = "moderate"
+)
+
+// ParseAction пустая строка выключает правило
+func ParseAction(raw string) (Action, error) {
+	switch action := Action(raw); action {
+	case "", ActionOff:
+		return ActionOff, nil
+	case ActionReject, ActionMask, ActionModerate:
+		return action, nil
+	default:
+		return ActionOff, fmt.Errorf("unknown content filter action %q", raw)
+	}
+}
+
+// Match найденный фрагмент, границы в байтах
+type Match struct {
+	Start int
+	End   int
+}
+
+// Subject чей текст и к чему он относится, Target различает сущности одного автора.
+// Recent - сохранённые тексты автора под другими сущностями, с ними сравнивается правило повторов
+type Subject struct {
+	AuthorID int
+	Target   string
+	Recent   []string
+}
+
+// Rule правило фильтра, возвращает фрагменты текста, на которых оно сработало
+type Rule interface {
+	Name() string
+	Find(subject Subject, text string) []Match
+}
+
+// Verdict Moderate - текст принят, но должен пройти модерацию, Rules - сработавшие правила
+type Verdict struct {
+	Moderate bool
+	Rules    []string
+}
+
+func (v *Verdict) add(rule string) {
+	for _, name := range v.Rules {
+		if name == rule {
+			return
+		}
+	}
+
+	v.Rules = append(v.Rules, rule)
+}
+
+type configuredRule struct {
+	rule   Rule
+	action Action
+}
+
+// Filter цепочка правил, применяется по порядку добавления
+type Filter struct {
+	rules []configuredRule
+}
+
+func New() *Filter {
+	return &Filter{}
+}
+
+// Add выключенное правило не добавляется
+func (f *Filter) Add(rule Rule, action Action) *Filter {
+	if action != ActionOff {
+		f.rules = append(f.rules, configuredRule{rule: rule, action: action})
+	}
+
+	return f
+}
+
+// Text проверяет одну строку. Правило с действием reject возвращает customerr.ContentRejected,
+// mask заменяет найденное звёздочками, moderate только отмечается в вердикте
+func (f *Filter) Text(subject Subject, text string) (string, Verdict, error) {
+	var verdict Verdict
+	text, err := f.text(subject, text, &verdict)
+
+	return text, verdict, err
+}
+
+func (f *Filter) text(subject Subject, text string, verdict *Verdict) (string, error) {
+	for _, configured := range f.rules {
+		matches := configured.rule.Find(subject, text)
+		if len(matches) == 0 {
+			continue
+		}
+
+		verdict.add(configured.rule.Name())
+
+		switch configured.action {
+		case ActionReject:
+			return "", fmt.Errorf("%w: %v", customerr.ContentRejected, configured.rule.Name())
+		case ActionMask:
+			text = mask(text, matches)
+		case ActionModerate:
+			verdict.Moderate = true
+		}
+	}
+
+	return text, nil
+}
+
+// Properties проверяет все строки произвольного JSON, ключи объектов не меняются
+func (f *Filter) Properties(subject Subject, properties interface{}) (interface{}, Verdict, error) {
+	var verdict Verdict
+	properties, err := f.walk(subject, properties, &verdict)
+
+	return properties, verdict, err
+}
+
+func (f *Filter) walk(subject Subject, value interface{}, verdict *Verdict) (interface{}, error) {
+	switch typed := value.(type) {
+	case string:
+		return f.text(subject, typed, verdict)
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(typed))
+		for key, item := range typed {
+			filtered, err := f.walk(subject, item, verdict)
+			if err != nil {
+				return nil, err
+			}
+			result[key] = filtered
+		}
+		return result, nil
+	case []interface{}:
+		result := make([]interface{}, len(typed))
+		for i, item := range typed {
+			filtered, err := f.walk(subject, item, verdict)
+			if err != nil {
+				return nil, err
+			}
+			result[i] = filtered
+		}
+		return result, nil
+	default:
+		return value, nil
+	}
+}
+
+// Strings все строки произвольного JSON
+func Strings(properties interface{}) []string {
+	var result []string
+	switch typed := properties.(type) {
+	case string:
+		result = append(result, typed)
+	case map[string]interface{}:
+		for _, item := range typed {
+			result = append(result, Strings(item)...)
+		}
+	case []interface{}:
+		for _, item := range typed {
+			result = append(result, Strings(item)...)
+		}
+	}
+
+	return result
+}
+
+// mask заменяет каждую букву найденных фрагментов на звёздочку, пробелы остаются
+func mask(text string, matches []Match) string {
+	masked := make([]bool, len(text))
+	for _, match := range matches {
+		for i := max(match.Start, 0); i < match.End && i < len(text); i++ {
+			masked[i] = true
+		}
+	}
+
+	result := make([]rune, 0, utf8.RuneCountInString(text))
+	for i, r := range text {
+		if masked[i] && r != ' ' {
+			result = append(result, '*')
+		} else {
+			result = append(result, r)
+		}
+	}
+
+	return string(result)
+}
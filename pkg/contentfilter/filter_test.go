@@ -0,0 +1,128 @@
+package contentfilter
+
+import (
+	"errors"
+	"fmt"
+	"mth/pkg/customerr"
+	"sort"
+	"testing"
+)
+
+func TestProfanity(t *testing.T) {
+	profanity := NewProfanity(DefaultWordlist)
+
+	dirty := []string{"ну пиздец", "Fucking place", "какая сука", "заёбанный официант", "полный xуй", "bullshit"}
+	for _, text := range dirty {
+		if len(profanity.Find(Subject{}, text)) == 0 {
+			t.Errorf("%q: profanity not found", text)
+		}
+	}
+
+	clean := []string{"Себастьян хлебал суп", "сукно и бляха", "Scunthorpe is nice", "застрахуйте багаж заранее",
+		"сел на толстый сук", "bastard sword в музее"}
+	for _, text := range clean {
+		if matches := profanity.Find(Subject{}, text); len(matches) != 0 {
+			t.Errorf("%q: unexpected matches %v", text, matches)
+		}
+	}
+}
+
+func TestSpam(t *testing.T) {
+	spam := NewSpam()
+
+	dirty := []string{"пишите в t.me/cafe_promo", "https://example.com/x", "звоните +7 (999) 123-45-67",
+		"8 999 123 45 67", "скидки на сайте кафе.рф/promo", "www.promo", "меню на cafe.ru/menu"}
+	for _, text := range dirty {
+		if len(spam.Find(Subject{}, text)) == 0 {
+			t.Errorf("%q: spam not found", text)
+		}
+	}
+
+	clean := []string{"были 2024-04-06 12:00", "оценка 4.5 из 5", "билет стоил 1500 рублей", "Mr.Smith",
+		"работаю на ASP.NET", "кафе.рф", "пишу на Node.io"}
+	for _, text := range clean {
+		if matches := spam.Find(Subject{}, text); len(matches) != 0 {
+			t.Errorf("%q: unexpected matches %v", text, matches)
+		}
+	}
+}
+
+func TestRepeat(t *testing.T) {
+	repeat := NewRepeat()
+
+	text := "Лучшее место в городе, всем советую!"
+	if len(repeat.Find(Subject{AuthorID: 1, Target: "place:1"}, text)) != 0 {
+		t.Error("text without previous texts must not be a repeat")
+	}
+
+	recent := []string{"Лучшее место в городе всем советую", "Отлично!"}
+	if len(repeat.Find(Subject{AuthorID: 1, Target: "place:2", Recent: recent}, text)) == 0 {
+		t.Error("previous text of the author must be a repeat")
+	}
+	if len(repeat.Find(Subject{AuthorID: 1, Target: "place:2", Recent: recent}, "отлично!")) != 0 {
+		t.Error("short texts must not be a repeat")
+	}
+
+	if len(repeat.Find(Subject{}, "ура!!!!!!!!!!!!")) == 0 {
+		t.Error("repeated characters must be a flood")
+	}
+	if len(repeat.Find(Subject{}, "купи купи купи купи купи")) == 0 {
+		t.Error("repeated words must be a flood")
+	}
+}
+
+func TestStrings(t *testing.T) {
+	properties := map[string]interface{}{"comment": "хорошо", "tags": []interface{}{"кофе", 5.0}, "rating": 4.0}
+
+	texts := Strings(properties)
+	sort.Strings(texts)
+	if fmt.Sprint(texts) != fmt.Sprint([]string{"кофе", "хорошо"}) {
+		t.Errorf("unexpected strings %v", texts)
+	}
+}
+
+func TestFilterActions(t *testing.T) {
+	filter := New().
+		Add(NewProfanity(DefaultWordlist), ActionMask).
+		Add(NewSpam(), ActionModerate).
+		Add(NewRepeat(), ActionOff)
+
+	text, verdict, err := filter.Text(Subject{}, "ну пиздец, а не кафе")
+	if err != nil || text != "ну ******, а не кафе" || verdict.Moderate {
+		t.Errorf("mask: got %q, %+v, %v", text, verdict, err)
+	}
+
+	properties := map[string]interface{}{
+		"comment": "звоните +7 999 123-45-67",
+		"tags":    []interface{}{"fucking", 5.0},
+	}
+	filtered, verdict, err := filter.Properties(Subject{}, properties)
+	if err != nil || !verdict.Moderate || len(verdict.Rules) != 2 {
+		t.Fatalf("properties: got %+v, %v", verdict, err)
+	}
+	tags := filtered.(map[string]interface{})["tags"].([]interface{})
+	if tags[0] != "*******" || tags[1] != 5.0 {
+		t.Errorf("unexpected tags %v", tags)
+	}
+	if properties["tags"].([]interface{})[0] != "fucking" {
+		t.Error("source properties must not be changed")
+	}
+
+	rejecting := New().Add(NewSpam(), ActionReject)
+	if _, _, err = rejecting.Properties(Subject{}, properties); !errors.Is(err, customerr.ContentRejected) {
+		t.Errorf("expected ContentRejected, got %v", err)
+	}
+}
+
+func TestParseAction(t *testing.T) {
+	for raw, expected := range map[string]Action{"": ActionOff, "off": ActionOff, "mask": ActionMask,
+		"reject": ActionReject, "moderate": ActionModerate} {
+		if action, err := ParseAction(raw); err != nil || action != expected {
+			t.Errorf("%q: got %v, %v", raw, action, err)
+		}
+	}
+
+	if _, err := ParseAction("drop"); err == nil {
+		t.Error("unknown action must fail")
+	}
+}
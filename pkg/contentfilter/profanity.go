@@ -0,0 +1,145 @@
+package contentfilter
+
+import (
+	"strings"
+	"unicode"
+)
+
+// DefaultWordlist корни русской и английской брани. Слово со звёздочкой в конце - префикс,
+// со звёздочками с обеих сторон - подстрока, без звёздочек - слово целиком. Корни, совпадающие с обычными словами
+// ("сук" - ветка, "bastard sword"), в список не входят
+var DefaultWordlist = []string{
+	"хуй*", "хуе*", "хуя*", "хуи*", "нахуй*", "похуй*", "охуе*", "охуи*",
+	"*пизд*",
+	"еб*", "заеб*", "наеб*", "уеб*", "выеб*", "доеб*", "поеб*", "отьеб*", "сьеб*", "обьеб*", "разьеб*", "подьеб*", "вьеб*",
+	"бля", "бляд*", "блят*",
+	"мудак*", "мудил*", "пидор*", "пидар*", "пидр*", "залуп*", "гандон*", "гондон*", "шлюх*",
+	"сука", "сучка", "сучара",
+	"*fuck*", "*shit*", "cunt*", "bitch*", "asshole*", "dickhead*", "whore*", "slut*",
+}
+
+// latinLookalikes латинские буквы, которыми подменяют кириллицу
+var latinLookalikes = map[rune]rune{
+	'a': 'а', 'b': 'в', 'c': 'с', 'e': 'е', 'h': 'н', 'k': 'к', 'm': 'м',
+	'o': 'о', 'p': 'р', 't': 'т', 'x': 'х', 'y': 'у',
+}
+
+type wordPattern struct {
+	word   string
+	prefix bool
+	infix  bool
+}
+
+// Profanity ищет бранные слова с учётом ё/ъ и латинских букв внутри кириллических слов
+type Profanity struct {
+	patterns []wordPattern
+}
+
+func NewProfanity(words []string) *Profanity {
+	profanity := &Profanity{}
+	for _, raw := range words {
+		raw = strings.ToLower(strings.TrimSpace(raw))
+
+		pattern := wordPattern{}
+		if strings.HasPrefix(raw, "*") && strings.HasSuffix(raw, "*") && len(raw) > 1 {
+			pattern.infix = true
+		} else if strings.HasSuffix(raw, "*") {
+			pattern.prefix = true
+		}
+
+		pattern.word = normalizeWord([]rune(strings.Trim(raw, "*")))
+		if pattern.word != "" {
+			profanity.patterns = append(profanity.patterns, pattern)
+		}
+	}
+
+	return profanity
+}
+
+func (p *Profanity) Name() string {
+	return "profanity"
+}
+
+func (p *Profanity) Find(_ Subject, text string) []Match {
+	var matches []Match
+	for _, token := range tokenize(text) {
+		word := normalizeWord([]rune(strings.ToLower(text[token.Start:token.End])))
+		if p.matches(word) {
+			matches = append(matches, token)
+		}
+	}
+
+	return matches
+}
+
+func (p *Profanity) matches(word string) bool {
+	for _, pattern := range p.patterns {
+		switch {
+		case pattern.infix:
+			if strings.Contains(word, pattern.word) {
+				return true
+			}
+		case pattern.prefix:
+			if strings.HasPrefix(word, pattern.word) {
+				return true
+			}
+		default:
+			if word == pattern.word {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// normalizeWord ё → е, ъ → ь, латинские двойники → кириллица, если в слове есть кириллица
+func normalizeWord(word []rune) string {
+	cyrillic := false
+	for _, r := range word {
+		if unicode.Is(unicode.Cyrillic, r) {
+			cyrillic = true
+			break
+		}
+	}
+
+	for i, r := range word {
+		switch {
+		case r == 'ё':
+			word[i] = 'е'
+		case r == 'ъ':
+			word[i] = 'ь'
+		case cyrillic:
+			if lookalike, ok := latinLookalikes[r]; ok {
+				word[i] = lookalike
+			}
+		}
+	}
+
+	return string(word)
+}
+
+// tokenize слова - непрерывные последовательности букв и цифр
+func tokenize(text string) []Match {
+	var tokens []Match
+	start := -1
+	for i, r := range text {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+
+		if start >= 0 {
+			tokens = append(tokens, Match{Start: start, End: i})
+			start = -1
+		}
+	}
+
+	if start >= 0 {
+		tokens = append(tokens, Match{Start: start, End: len(text)})
+	}
+
+	return tokens
+}
@@ -0,0 +1,100 @@
+package contentfilter
+
+import (
+	"strings"
+	"unicode"
+)
+
+const (
+	// floodRunes столько одинаковых символов подряд считается флудом
+	floodRunes = 10
+	// floodWords столько одинаковых слов подряд считается флудом
+	floodWords = 5
+	// repeatMinLength более короткие тексты ("Отлично!") могут совпадать у честных авторов
+	repeatMinLength = 20
+)
+
+// Repeat ищет флуд внутри текста и текст, который автор уже оставил под другими сущностями.
+// Прежние тексты автора передаются в Subject.Recent, сам фильтр ничего не запоминает
+type Repeat struct{}
+
+func NewRepeat() *Repeat {
+	return &Repeat{}
+}
+
+func (r *Repeat) Name() string {
+	return "repeat"
+}
+
+func (r *Repeat) Find(subject Subject, text string) []Match {
+	matches := flood(text)
+
+	if duplicate(subject.Recent, text) {
+		matches = append(matches, Match{Start: 0, End: len(text)})
+	}
+
+	return matches
+}
+
+// duplicate совпадает ли текст с одним из прежних без учёта регистра и знаков
+func duplicate(recent []string, text string) bool {
+	current := fingerprint(text)
+	if len([]rune(current)) < repeatMinLength {
+		return false
+	}
+
+	for _, previous := range recent {
+		if current == fingerprint(previous) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// fingerprint текст без регистра, знаков и лишних пробелов
+func fingerprint(text string) string {
+	var words []string
+	for _, token := range tokenize(text) {
+		words = append(words, strings.ToLower(text[token.Start:token.End]))
+	}
+
+	return strings.Join(words, " ")
+}
+
+// flood одинаковые символы подряд, кроме пробелов, и одинаковые слова подряд
+func flood(text string) []Match {
+	var matches []Match
+
+	runStart, runLength := 0, 0
+	var previous rune
+	for i, r := range text {
+		if r == previous && !unicode.IsSpace(r) {
+			runLength++
+		} else {
+			if runLength >= floodRunes {
+				matches = append(matches, Match{Start: runStart, End: i})
+			}
+			runStart, runLength, previous = i, 1, r
+		}
+	}
+	if runLength >= floodRunes {
+		matches = append(matches, Match{Start: runStart, End: len(text)})
+	}
+
+	tokens := tokenize(text)
+	for start := 0; start < len(tokens); {
+		word := strings.ToLower(text[tokens[start].Start:tokens[start].End])
+		end := start + 1
+		for end < len(tokens) && strings.ToLower(text[tokens[end].Start:tokens[end].End]) == word {
+			end++
+		}
+
+		if end-start >= floodWords {
+			matches = append(matches, Match{Start: tokens[start].Start, End: tokens[end-1].End})
+		}
+		start = end
+	}
+
+	return matches
+}
@@ -0,0 +1,64 @@
+package contentfilter
+
+import (
+	"regexp"
+	"unicode"
+)
+
+var (
+	// linkPattern ссылка без схемы и www. должна содержать путь, иначе "ASP.NET" и "Node.io" считались бы ссылками
+	linkPattern = regexp.MustCompile(`(?i)(?:https?://|www\.)\S+` +
+		`|\b[a-z0-9][a-z0-9-]*(?:\.[a-z0-9-]+)*\.(?:ru|com|net|org|info|biz|io|me|su|xyz|online|site|shop|club|pro|link)/\S*` +
+		`|[\p{L}\d-]+\.рф/\S*`)
+	phonePattern = regexp.MustCompile(`\+?\d[\d\s\-()]{8,}\d`)
+)
+
+// Spam ищет ссылки и номера телефонов
+type Spam struct{}
+
+func NewSpam() *Spam {
+	return &Spam{}
+}
+
+func (s *Spam) Name() string {
+	return "spam"
+}
+
+func (s *Spam) Find(_ Subject, text string) []Match {
+	var matches []Match
+	for _, loc := range linkPattern.FindAllStringIndex(text, -1) {
+		matches = append(matches, Match{Start: loc[0], End: loc[1]})
+	}
+
+	for _, loc := range phonePattern.FindAllStringIndex(text, -1) {
+		if isPhone(text[loc[0]:loc[1]]) {
+			matches = append(matches, Match{Start: loc[0], End: loc[1]})
+		}
+	}
+
+	return matches
+}
+
+// isPhone отсекает даты и прочие длинные числа: номер начинается с +, либо это 11 цифр с 7 или 8,
+// либо 10 цифр мобильного без кода страны
+func isPhone(candidate string) bool {
+	var digits []rune
+	for _, r := range candidate {
+		if unicode.IsDigit(r) {
+			digits = append(digits, r)
+		}
+	}
+
+	switch {
+	case len(digits) < 10 || len(digits) > 15:
+		return false
+	case candidate[0] == '+':
+		return true
+	case len(digits) == 11:
+		return digits[0] == '7' || digits[0] == '8'
+	case len(digits) == 10:
+		return digits[0] == '9'
+	default:
+		return false
+	}
+}
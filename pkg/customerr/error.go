@@ -10,4 +10,62 @@ func (e Error) Error() string {
 const (
 	UserNotOwner = Error("user is not owner of the place")
 	BadInput     = Error("bad input")
+
+	PlaceHasHistory   = Error("place has user history (reviews, notes, check-ins, favourites, trips or companions), archive it instead")
+	DistrictNotInCity = Error("district does not belong to the given city")
+
+	MediaTooLarge        = Error("media file is too large")
+	MediaUnsupportedType = Error("media file type is not supported")
+	UserNotEntityOwner   = Error("user is not owner of the entity")
+
+	ImportTooLarge = Error("import file is too large")
+	ImportBadFile  = Error("import file can not be parsed")
+
+	UnknownVariety = Error("unknown place variety")
+	VarietyInUse   = Error("variety is used by places")
+
+	InvalidCursor = Error("invalid pagination cursor")
+
+	PlaceNotFound  = Error("place not found")
+	MergeSamePlace = Error("place can not be merged into itself")
+
+	CityNotFound     = Error("city not found")
+	CityInUse        = Error("city has districts, places or routes")
+	DistrictNotFound = Error("district not found")
+	DistrictInUse    = Error("district has places")
+
+	TagNotFound         = Error("tag not found")
+	TagNameTaken        = Error("tag name is already used by a tag or synonym")
+	TagCycle            = Error("tag can not be a descendant of itself")
+	TagCategoryNotFound = Error("tag category not found")
+	TagInUse            = Error("tag is used by places, routes or child tags")
+
+	TranslationNotFound       = Error("translation not found")
+	TranslationEntityNotFound = Error("translated entity not found")
+
+	ReviewNotFound       = Error("review not found")
+	ReviewAlreadyFlagged = Error("review is already flagged by the user")
+	NotModerator         = Error("user is not a review moderator")
+	ReviewNotVerified    = Error("review requires a check-in at the place or a completed route")
+	ReviewVoteNotFound   = Error("user has not voted for the review")
+	ReviewReplyNotFound  = Error("review has no reply")
+
+	NotEntityManager      = Error("user is not an owner or editor of the place or route")
+	ManagedEntityNotFound = Error("place or route not found")
+	EntityManagerNotFound = Error("user is not linked to the place or route")
+
+	NoteNotFound           = Error("note not found")
+	NoteTargetNotFound     = Error("place, route or trip of the note not found")
+	NoteNotShareable       = Error("only link or public notes can be shared")
+	NoteShareTokenNotFound = Error("note share link not found or revoked")
+
+	TripNotFound = Error("trip not found")
+
+	CompanionListingNotFound = Error("companion listing not found")
+	CompanionRequestNotFound = Error("companion request not found")
+	CompanionRequestExists   = Error("user already has an active request to the listing")
+	CompanionRequestStatus   = Error("companion request status does not allow the action")
+
+	ContentRejected       = Error("text is rejected by the content filter")
+	ContentReportNotFound = Error("content report not found")
 )
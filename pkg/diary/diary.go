@@ -0,0 +1,137 @@
+package diary
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	KindPlace = "place"
+	KindRoute = "route"
+)
+
+const (
+	FormatMarkdown = "md"
+	FormatHTML     = "html"
+	FormatEPUB     = "epub"
+)
+
+// Photo Name - имя файла внутри EPUB, URL используется в Markdown, Data - для встраивания в HTML и EPUB
+type Photo struct {
+	Name        string
+	ContentType string
+	URL         string
+	Data        []byte
+}
+
+type Note struct {
+	Text      string
+	CreatedAt time.Time
+	Photos    []Photo
+}
+
+type Review struct {
+	Mark   float32
+	Text   string
+	Photos []Photo
+}
+
+// Stop место или маршрут дня, CheckedInAt - отметка автора в месте во время поездки
+type Stop struct {
+	Kind        string
+	Name        string
+	CheckedInAt *time.Time
+	Notes       []Note
+	Review      *Review
+}
+
+type Day struct {
+	Number int
+	Notes  []Note
+	Stops  []Stop
+}
+
+// Diary GeneratedAt попадает в метаданные EPUB, Notes - заметки ко всей поездке
+type Diary struct {
+	ID          string
+	Title       string
+	DateStart   time.Time
+	DateEnd     time.Time
+	GeneratedAt time.Time
+	Notes       []Note
+	Days        []Day
+}
+
+// File готовый к отдаче файл дневника
+type File struct {
+	Name        string
+	ContentType string
+	Data        []byte
+}
+
+// Render собирает дневник в одном из форматов FormatMarkdown, FormatHTML, FormatEPUB
+func Render(d Diary, format string) (File, error) {
+	switch format {
+	case FormatMarkdown:
+		return File{Name: d.fileName("md"), ContentType: "text/markdown; charset=utf-8", Data: Markdown(d)}, nil
+	case FormatHTML:
+		data, err := HTML(d)
+		if err != nil {
+			return File{}, err
+		}
+
+		return File{Name: d.fileName("html"), ContentType: "text/html; charset=utf-8", Data: data}, nil
+	case FormatEPUB:
+		data, err := EPUB(d)
+		if err != nil {
+			return File{}, err
+		}
+
+		return File{Name: d.fileName("epub"), ContentType: "application/epub+zip", Data: data}, nil
+	default:
+		return File{}, fmt.Errorf("unknown diary format %q", format)
+	}
+}
+
+func (d Diary) fileName(extension string) string {
+	return fmt.Sprintf("diary-%v.%v", d.ID, extension)
+}
+
+// Period даты поездки, пустые даты не выводятся
+func (d Diary) Period() string {
+	if d.DateStart.IsZero() {
+		return ""
+	}
+	if d.DateEnd.IsZero() || d.DateEnd.Equal(d.DateStart) {
+		return d.DateStart.Format(dateLayout)
+	}
+
+	return d.DateStart.Format(dateLayout) + " — " + d.DateEnd.Format(dateLayout)
+}
+
+const (
+	dateLayout     = "02.01.2006"
+	dateTimeLayout = "02.01.2006 15:04"
+)
+
+func dayTitle(number int) string {
+	return fmt.Sprintf("День %d", number)
+}
+
+func stopKind(kind string) string {
+	if kind == KindRoute {
+		return "Маршрут"
+	}
+
+	return "Место"
+}
+
+func formatMark(mark float32) string {
+	return strings.TrimSuffix(strings.TrimRight(fmt.Sprintf("%.1f", mark), "0"), ".")
+}
+
+func dataURI(photo Photo) string {
+	return "data:" + photo.ContentType + ";base64," + base64.StdEncoding.EncodeToString(photo.Data)
+}
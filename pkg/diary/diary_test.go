@@ -0,0 +1,167 @@
+package diary
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testDiary() Diary {
+	checkIn := time.Date(2024, 5, 2, 10, 30, 0, 0, time.UTC)
+	photo := Photo{Name: "photo-7.jpg", ContentType: "image/jpeg", URL: "/media/file/2024/05/a.jpg", Data: []byte{0xff, 0xd8}}
+
+	return Diary{
+		ID:          "trip-1",
+		Title:       "Казань <летом>",
+		DateStart:   time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC),
+		DateEnd:     time.Date(2024, 5, 3, 0, 0, 0, 0, time.UTC),
+		GeneratedAt: time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC),
+		Notes:       []Note{{Text: "Едем!", CreatedAt: time.Date(2024, 4, 30, 20, 0, 0, 0, time.UTC)}},
+		Days: []Day{{
+			Number: 1,
+			Stops: []Stop{
+				{
+					Kind:        KindPlace,
+					Name:        "Кремль",
+					CheckedInAt: &checkIn,
+					Notes:       []Note{{Text: "Очередь *огромная*", CreatedAt: checkIn, Photos: []Photo{photo}}},
+					Review:      &Review{Mark: 4.5, Text: "<b>Красиво</b>"},
+				},
+				{Kind: KindRoute, Name: "Набережная"},
+			},
+		}},
+	}
+}
+
+func TestMarkdown(t *testing.T) {
+	md := string(Markdown(testDiary()))
+
+	for _, want := range []string{
+		"# Казань &lt;летом&gt;\n",
+		"_01.05.2024 — 03.05.2024_",
+		"## День 1",
+		"### Место: Кремль",
+		"Отметка: 02.05.2024 10:30",
+		`> Очередь \*огромная\*`,
+		"![photo-7.jpg](/media/file/2024/05/a.jpg)",
+		"**Отзыв: 4.5**",
+		"### Маршрут: Набережная",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("markdown has no %q:\n%v", want, md)
+		}
+	}
+}
+
+func TestHTML(t *testing.T) {
+	page, err := HTML(testDiary())
+	if err != nil {
+		t.Fatal(err)
+	}
+	html := string(page)
+
+	if strings.Contains(html, "<b>Красиво</b>") || !strings.Contains(html, "&lt;b&gt;Красиво&lt;/b&gt;") {
+		t.Errorf("review text is not escaped:\n%v", html)
+	}
+	if !strings.Contains(html, `src="data:image/jpeg;base64,/9g="`) {
+		t.Errorf("photo is not embedded:\n%v", html)
+	}
+	if strings.Contains(html, "/media/file/") {
+		t.Errorf("embedded photo still links to storage:\n%v", html)
+	}
+}
+
+func TestEPUB(t *testing.T) {
+	book, err := EPUB(testDiary())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := zip.NewReader(bytes.NewReader(book), int64(len(book)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if first := r.File[0]; first.Name != "mimetype" || first.Method != zip.Store {
+		t.Fatalf("mimetype must be the first stored entry, got %v (method %v)", first.Name, first.Method)
+	}
+
+	files := make(map[string]string)
+	for _, file := range r.File {
+		rc, err := file.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[file.Name] = string(data)
+	}
+
+	for _, name := range []string{"META-INF/container.xml", "OEBPS/content.opf", "OEBPS/nav.xhtml",
+		"OEBPS/cover.xhtml", "OEBPS/day-1.xhtml", "OEBPS/images/photo-7.jpg"} {
+		if _, ok := files[name]; !ok {
+			t.Errorf("epub has no %v", name)
+		}
+	}
+
+	opf := files["OEBPS/content.opf"]
+	for _, want := range []string{`<dc:identifier id="book-id">trip-1</dc:identifier>`, "2024-05-10T12:00:00Z",
+		`href="images/photo-7.jpg" media-type="image/jpeg"`, `<itemref idref="day-1"/>`} {
+		if !strings.Contains(opf, want) {
+			t.Errorf("content.opf has no %q:\n%v", want, opf)
+		}
+	}
+
+	if day := files["OEBPS/day-1.xhtml"]; !strings.HasPrefix(day, `<?xml version="1.0"`) ||
+		!strings.Contains(day, `<img src="images/photo-7.jpg" alt=""/>`) {
+		t.Errorf("unexpected chapter:\n%v", day)
+	}
+}
+
+func TestEPUBLinksPhotosWithoutData(t *testing.T) {
+	d := testDiary()
+	d.Days[0].Stops[0].Review.Photos = []Photo{{Name: "photo-8.jpg", ContentType: "image/jpeg", URL: "https://cdn.example/b.jpg"}}
+
+	book, err := EPUB(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := zip.NewReader(bytes.NewReader(book), int64(len(book)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, file := range r.File {
+		switch file.Name {
+		case "OEBPS/images/photo-8.jpg":
+			t.Error("photo without data must not be packed")
+		case "OEBPS/day-1.xhtml":
+			rc, err := file.Open()
+			if err != nil {
+				t.Fatal(err)
+			}
+			data, err := io.ReadAll(rc)
+			rc.Close()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !strings.Contains(string(data), `<a href="https://cdn.example/b.jpg">`) {
+				t.Errorf("photo without data must be linked:\n%s", data)
+			}
+		}
+	}
+}
+
+func TestRenderUnknownFormat(t *testing.T) {
+	if _, err := Render(testDiary(), "pdf"); err == nil {
+		t.Error("expected error for unknown format")
+	}
+}
@@ -0,0 +1,217 @@
+package diary
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"html/template"
+	"io"
+)
+
+// xmlHeader дописывается вне html/template, который экранирует "<?"
+const xmlHeader = `<?xml version="1.0" encoding="UTF-8"?>
+`
+
+const epubContainer = `<?xml version="1.0" encoding="UTF-8"?>
+<container version="1.0" xmlns="urn:oasis:names:tc:opendocument:xmlns:container">
+<rootfiles>
+<rootfile full-path="OEBPS/content.opf" media-type="application/oebps-package+xml"/>
+</rootfiles>
+</container>
+`
+
+const epubPackage = `<package xmlns="http://www.idpf.org/2007/opf" version="3.0" unique-identifier="book-id" xml:lang="ru">
+<metadata xmlns:dc="http://purl.org/dc/elements/1.1/">
+<dc:identifier id="book-id">{{.ID}}</dc:identifier>
+<dc:title>{{.Title}}</dc:title>
+<dc:language>ru</dc:language>
+<meta property="dcterms:modified">{{.Modified}}</meta>
+</metadata>
+<manifest>
+<item id="nav" href="nav.xhtml" media-type="application/xhtml+xml" properties="nav"/>
+<item id="style" href="style.css" media-type="text/css"/>
+{{range .Chapters}}<item id="{{.ID}}" href="{{.File}}" media-type="application/xhtml+xml"/>
+{{end}}{{range .Photos}}<item id="{{.ID}}" href="images/{{.Name}}" media-type="{{.ContentType}}"/>
+{{end}}</manifest>
+<spine>
+{{range .Chapters}}<itemref idref="{{.ID}}"/>
+{{end}}</spine>
+</package>
+`
+
+const epubNav = `<html xmlns="http://www.w3.org/1999/xhtml" xmlns:epub="http://www.idpf.org/2007/ops" lang="ru" xml:lang="ru">
+<head><title>{{.Title}}</title></head>
+<body>
+<nav epub:type="toc"><ol>
+{{range .Chapters}}<li><a href="{{.File}}">{{.Title}}</a></li>
+{{end}}</ol></nav>
+</body>
+</html>
+`
+
+// epubChapter первая глава - обложка с заметками ко всей поездке, дальше по главе на день
+const epubChapter = `<html xmlns="http://www.w3.org/1999/xhtml" lang="ru" xml:lang="ru">
+<head><title>{{.Title}}</title><link rel="stylesheet" type="text/css" href="style.css"/></head>
+<body>
+{{with .Cover}}<h1>{{.Title}}</h1>
+{{with .Period}}<p class="time">{{.}}</p>{{end}}
+{{template "notes" .Notes}}{{end}}{{with .Day}}{{template "day" .}}{{end}}
+</body>
+</html>
+`
+
+type epubChapterData struct {
+	ID    string
+	File  string
+	Title string
+	Cover *Diary
+	Day   *Day
+}
+
+type epubPhoto struct {
+	ID string
+	Photo
+}
+
+// EPUB книга EPUB 3, фотографии лежат внутри архива, фото без содержимого остаются ссылками
+func EPUB(d Diary) ([]byte, error) {
+	chapterTmpl, err := newTemplate("chapter", epubChapter, func(photo Photo) template.URL {
+		if len(photo.Data) == 0 {
+			return ""
+		}
+
+		return template.URL("images/" + photo.Name)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	packageTmpl, err := template.New("package").Parse(epubPackage)
+	if err != nil {
+		return nil, err
+	}
+
+	navTmpl, err := template.New("nav").Parse(epubNav)
+	if err != nil {
+		return nil, err
+	}
+
+	chapters := []epubChapterData{{ID: "cover", File: "cover.xhtml", Title: d.Title, Cover: &d}}
+	for i := range d.Days {
+		chapters = append(chapters, epubChapterData{
+			ID:    fmt.Sprintf("day-%d", i+1),
+			File:  fmt.Sprintf("day-%d.xhtml", i+1),
+			Title: dayTitle(d.Days[i].Number),
+			Day:   &d.Days[i],
+		})
+	}
+
+	var b bytes.Buffer
+	w := zip.NewWriter(&b)
+
+	// mimetype должен идти первым и без сжатия
+	mimetype, err := w.CreateHeader(&zip.FileHeader{Name: "mimetype", Method: zip.Store})
+	if err != nil {
+		return nil, err
+	}
+	if _, err = io.WriteString(mimetype, "application/epub+zip"); err != nil {
+		return nil, err
+	}
+
+	if err = writeZipFile(w, "META-INF/container.xml", []byte(epubContainer)); err != nil {
+		return nil, err
+	}
+	if err = writeZipFile(w, "OEBPS/style.css", []byte(diaryStyle)); err != nil {
+		return nil, err
+	}
+
+	for _, chapter := range chapters {
+		page := bytes.NewBufferString(xmlHeader)
+		if err = chapterTmpl.Execute(page, chapter); err != nil {
+			return nil, err
+		}
+		if err = writeZipFile(w, "OEBPS/"+chapter.File, page.Bytes()); err != nil {
+			return nil, err
+		}
+	}
+
+	photos := epubPhotos(d)
+	for _, photo := range photos {
+		if err = writeZipFile(w, "OEBPS/images/"+photo.Name, photo.Data); err != nil {
+			return nil, err
+		}
+	}
+
+	nav := bytes.NewBufferString(xmlHeader)
+	if err = navTmpl.Execute(nav, map[string]interface{}{"Title": d.Title, "Chapters": chapters}); err != nil {
+		return nil, err
+	}
+	if err = writeZipFile(w, "OEBPS/nav.xhtml", nav.Bytes()); err != nil {
+		return nil, err
+	}
+
+	opf := bytes.NewBufferString(xmlHeader)
+	err = packageTmpl.Execute(opf, map[string]interface{}{
+		"ID":       d.ID,
+		"Title":    d.Title,
+		"Modified": d.GeneratedAt.UTC().Format("2006-01-02T15:04:05Z"),
+		"Chapters": chapters,
+		"Photos":   photos,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err = writeZipFile(w, "OEBPS/content.opf", opf.Bytes()); err != nil {
+		return nil, err
+	}
+
+	if err = w.Close(); err != nil {
+		return nil, err
+	}
+
+	return b.Bytes(), nil
+}
+
+func writeZipFile(w *zip.Writer, name string, data []byte) error {
+	file, err := w.Create(name)
+	if err != nil {
+		return err
+	}
+
+	_, err = file.Write(data)
+	return err
+}
+
+// epubPhotos все встраиваемые фотографии дневника без повторов по имени
+func epubPhotos(d Diary) []epubPhoto {
+	seen := make(map[string]bool)
+	photos := []epubPhoto{}
+
+	add := func(list []Photo) {
+		for _, photo := range list {
+			if len(photo.Data) == 0 || seen[photo.Name] {
+				continue
+			}
+			seen[photo.Name] = true
+			photos = append(photos, epubPhoto{ID: fmt.Sprintf("photo-%d", len(photos)+1), Photo: photo})
+		}
+	}
+	addNotes := func(notes []Note) {
+		for _, note := range notes {
+			add(note.Photos)
+		}
+	}
+
+	addNotes(d.Notes)
+	for _, day := range d.Days {
+		addNotes(day.Notes)
+		for _, stop := range day.Stops {
+			addNotes(stop.Notes)
+			if stop.Review != nil {
+				add(stop.Review.Photos)
+			}
+		}
+	}
+
+	return photos
+}
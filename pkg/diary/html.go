@@ -0,0 +1,84 @@
+package diary
+
+import (
+	"bytes"
+	"html/template"
+)
+
+// bodyTemplates общая разметка дня для HTML и глав EPUB, источник фотографии задаёт функция photoSrc.
+// Фото без источника выводится ссылкой на хранилище
+const bodyTemplates = `
+{{define "notes"}}{{range .}}<div class="note">
+<p class="time">{{.CreatedAt.Format "02.01.2006 15:04"}}</p>
+{{if .Text}}<p class="text">{{.Text}}</p>{{end}}
+{{template "photos" .Photos}}</div>
+{{end}}{{end}}
+{{define "photos"}}{{range .}}{{with photoSrc .}}<img src="{{.}}" alt=""/>
+{{else}}{{with .URL}}<p><a href="{{.}}">Фото</a></p>
+{{end}}{{end}}{{end}}{{end}}
+{{define "day"}}<h2>{{dayTitle .Number}}</h2>
+{{template "notes" .Notes}}{{range .Stops}}<section class="stop">
+<h3>{{stopKind .Kind}}: {{.Name}}</h3>
+{{with .CheckedInAt}}<p class="time">Отметка: {{.Format "02.01.2006 15:04"}}</p>{{end}}
+{{template "notes" .Notes}}{{with .Review}}<div class="review">
+<p class="mark">Отзыв: {{formatMark .Mark}}</p>
+{{if .Text}}<p class="text">{{.Text}}</p>{{end}}
+{{template "photos" .Photos}}</div>
+{{end}}</section>
+{{end}}{{end}}`
+
+const diaryStyle = `body{font-family:Georgia,serif;max-width:42em;margin:0 auto;padding:1em;line-height:1.5}
+.time{color:#777;font-size:.9em;margin:.2em 0}.text{white-space:pre-wrap}
+.note,.review{margin:.8em 0;padding-left:.8em;border-left:3px solid #ddd}.mark{font-weight:bold}
+img{max-width:100%;height:auto;display:block;margin:.5em 0}`
+
+const htmlTemplate = `<!DOCTYPE html>
+<html lang="ru">
+<head>
+<meta charset="utf-8"/>
+<title>{{.Title}}</title>
+<style>{{style}}</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+{{with .Period}}<p class="time">{{.}}</p>{{end}}
+{{template "notes" .Notes}}{{range .Days}}{{template "day" .}}{{end}}
+</body>
+</html>
+`
+
+func newTemplate(name, text string, photoSrc func(Photo) template.URL) (*template.Template, error) {
+	tmpl, err := template.New(name).Funcs(template.FuncMap{
+		"photoSrc":   photoSrc,
+		"dayTitle":   dayTitle,
+		"stopKind":   stopKind,
+		"formatMark": formatMark,
+		"style":      func() template.CSS { return template.CSS(diaryStyle) },
+	}).Parse(bodyTemplates)
+	if err != nil {
+		return nil, err
+	}
+
+	return tmpl.Parse(text)
+}
+
+// HTML одна страница, фотографии встроены data URI, внешние ссылки остаются только у фото без содержимого
+func HTML(d Diary) ([]byte, error) {
+	tmpl, err := newTemplate("html", htmlTemplate, func(photo Photo) template.URL {
+		if len(photo.Data) > 0 {
+			return template.URL(dataURI(photo))
+		}
+
+		return template.URL(photo.URL)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var b bytes.Buffer
+	if err = tmpl.Execute(&b, d); err != nil {
+		return nil, err
+	}
+
+	return b.Bytes(), nil
+}
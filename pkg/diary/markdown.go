@@ -0,0 +1,75 @@
+package diary
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// markdownEscaper экранирует символы, которые в тексте пользователя превратились бы в разметку
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "`", "\\`", "*", `\*`, "_", `\_`, "[", `\[`, "]", `\]`, "#", `\#`, "<", "&lt;", ">", "&gt;",
+)
+
+// Markdown фотографии подключаются ссылками, поэтому файл не самодостаточен
+func Markdown(d Diary) []byte {
+	var b bytes.Buffer
+
+	fmt.Fprintf(&b, "# %v\n\n", markdownEscaper.Replace(d.Title))
+	if period := d.Period(); period != "" {
+		fmt.Fprintf(&b, "_%v_\n\n", period)
+	}
+	writeMarkdownNotes(&b, d.Notes)
+
+	for _, day := range d.Days {
+		fmt.Fprintf(&b, "## %v\n\n", dayTitle(day.Number))
+		writeMarkdownNotes(&b, day.Notes)
+
+		for _, stop := range day.Stops {
+			fmt.Fprintf(&b, "### %v: %v\n\n", stopKind(stop.Kind), markdownEscaper.Replace(stop.Name))
+			if stop.CheckedInAt != nil {
+				fmt.Fprintf(&b, "Отметка: %v\n\n", stop.CheckedInAt.Format(dateTimeLayout))
+			}
+			writeMarkdownNotes(&b, stop.Notes)
+
+			if stop.Review != nil {
+				fmt.Fprintf(&b, "**Отзыв: %v**\n\n", formatMark(stop.Review.Mark))
+				writeMarkdownText(&b, stop.Review.Text)
+				writeMarkdownPhotos(&b, stop.Review.Photos)
+			}
+		}
+	}
+
+	return b.Bytes()
+}
+
+func writeMarkdownNotes(b *bytes.Buffer, notes []Note) {
+	for _, note := range notes {
+		fmt.Fprintf(b, "_%v_\n\n", note.CreatedAt.Format(dateTimeLayout))
+		writeMarkdownText(b, note.Text)
+		writeMarkdownPhotos(b, note.Photos)
+	}
+}
+
+// writeMarkdownText текст выводится цитатой, каждая строка отдельно
+func writeMarkdownText(b *bytes.Buffer, text string) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return
+	}
+
+	for _, line := range strings.Split(text, "\n") {
+		fmt.Fprintf(b, "> %v\n", markdownEscaper.Replace(strings.TrimRight(line, "\r")))
+	}
+	b.WriteString("\n")
+}
+
+func writeMarkdownPhotos(b *bytes.Buffer, photos []Photo) {
+	for _, photo := range photos {
+		if photo.URL == "" {
+			continue
+		}
+
+		fmt.Fprintf(b, "![%v](%v)\n\n", photo.Name, photo.URL)
+	}
+}
@@ -0,0 +1,18 @@
+package geo
+
+import (
+	"math"
+	"testing"
+)
+
+func TestDistance(t *testing.T) {
+	if d := Distance(55.75, 37.62, 55.75, 37.62); d != 0 {
+		t.Errorf("expected zero distance, got %v", d)
+	}
+
+	// Москва - Санкт-Петербург около 634 км
+	d := Distance(55.7558, 37.6173, 59.9343, 30.3351)
+	if math.Abs(d-634000) > 5000 {
+		t.Errorf("unexpected distance %v", d)
+	}
+}
@@ -0,0 +1,83 @@
+package geo
+
+// Ring замкнутый контур, точки в порядке долгота, широта, первая совпадает с последней
+type Ring [][2]float64
+
+// Polygon первый контур внешний, остальные - дыры
+type Polygon []Ring
+
+type MultiPolygon []Polygon
+
+// Valid контур не короче четырёх точек, замкнут и лежит в допустимом диапазоне координат
+func (r Ring) Valid() bool {
+	if len(r) < 4 || r[0] != r[len(r)-1] {
+		return false
+	}
+
+	for _, point := range r {
+		if point[0] < -180 || point[0] > 180 || point[1] < -90 || point[1] > 90 {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (m MultiPolygon) Valid() bool {
+	if len(m) == 0 {
+		return false
+	}
+
+	for _, polygon := range m {
+		if len(polygon) == 0 {
+			return false
+		}
+		for _, ring := range polygon {
+			if !ring.Valid() {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// contains трассировка луча по правилу чётности, точки на границе могут попасть в любую сторону
+func (r Ring) contains(lon, lat float64) bool {
+	inside := false
+
+	for i, j := 0, len(r)-1; i < len(r); j, i = i, i+1 {
+		xi, yi := r[i][0], r[i][1]
+		xj, yj := r[j][0], r[j][1]
+
+		if (yi > lat) != (yj > lat) && lon < (xj-xi)*(lat-yi)/(yj-yi)+xi {
+			inside = !inside
+		}
+	}
+
+	return inside
+}
+
+func (p Polygon) Contains(lon, lat float64) bool {
+	if len(p) == 0 || !p[0].contains(lon, lat) {
+		return false
+	}
+
+	for _, hole := range p[1:] {
+		if hole.contains(lon, lat) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (m MultiPolygon) Contains(lon, lat float64) bool {
+	for _, polygon := range m {
+		if polygon.Contains(lon, lat) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,45 @@
+package geo
+
+import "testing"
+
+func TestMultiPolygonContains(t *testing.T) {
+	square := Polygon{
+		Ring{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}},
+		Ring{{4, 4}, {6, 4}, {6, 6}, {4, 6}, {4, 4}},
+	}
+	island := Polygon{Ring{{20, 20}, {22, 20}, {21, 22}, {20, 20}}}
+	shape := MultiPolygon{square, island}
+
+	if !shape.Valid() {
+		t.Fatal("expected valid shape")
+	}
+
+	cases := []struct {
+		lon, lat float64
+		inside   bool
+	}{
+		{1, 1, true},
+		{5, 5, false},
+		{11, 5, false},
+		{21, 20.5, true},
+		{-1, -1, false},
+	}
+
+	for _, c := range cases {
+		if got := shape.Contains(c.lon, c.lat); got != c.inside {
+			t.Errorf("point (%v, %v): expected %v, got %v", c.lon, c.lat, c.inside, got)
+		}
+	}
+}
+
+func TestRingValid(t *testing.T) {
+	if (Ring{{0, 0}, {1, 0}, {1, 1}}).Valid() {
+		t.Error("expected short ring to be invalid")
+	}
+	if (Ring{{0, 0}, {1, 0}, {1, 1}, {0, 1}}).Valid() {
+		t.Error("expected open ring to be invalid")
+	}
+	if (Ring{{0, 0}, {200, 0}, {1, 1}, {0, 0}}).Valid() {
+		t.Error("expected out of range ring to be invalid")
+	}
+}
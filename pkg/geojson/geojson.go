@@ -0,0 +1,121 @@
+package geojson
+
+import (
+	"encoding/json"
+	"fmt"
+	"mth/pkg/geo"
+)
+
+const (
+	TypeFeatureCollection = "FeatureCollection"
+	TypeFeature           = "Feature"
+	TypePoint             = "Point"
+	TypeLineString        = "LineString"
+	TypePolygon           = "Polygon"
+	TypeMultiPolygon      = "MultiPolygon"
+)
+
+// Geometry координаты хранятся сырыми, разбираются методами под конкретный тип
+type Geometry struct {
+	Type        string          `json:"type"`
+	Coordinates json.RawMessage `json:"coordinates" swaggertype:"object"`
+}
+
+type Feature struct {
+	Type       string                 `json:"type"`
+	ID         interface{}            `json:"id,omitempty"`
+	Geometry   *Geometry              `json:"geometry"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+// FeatureCollection NextCursor и Truncated - сторонние члены объекта, заполняются, если выдача обрезана по лимиту
+type FeatureCollection struct {
+	Type       string    `json:"type"`
+	Features   []Feature `json:"features"`
+	NextCursor string    `json:"next_cursor,omitempty"`
+	Truncated  bool      `json:"truncated,omitempty"`
+}
+
+func NewFeatureCollection(features []Feature) FeatureCollection {
+	if features == nil {
+		features = []Feature{}
+	}
+
+	return FeatureCollection{
+		Type:     TypeFeatureCollection,
+		Features: features,
+	}
+}
+
+func NewFeature(id interface{}, geometry *Geometry, properties map[string]interface{}) Feature {
+	return Feature{
+		Type:       TypeFeature,
+		ID:         id,
+		Geometry:   geometry,
+		Properties: properties,
+	}
+}
+
+func newGeometry(geometryType string, coordinates interface{}) *Geometry {
+	raw, err := json.Marshal(coordinates)
+	if err != nil {
+		// массивы float64 маршалятся всегда
+		panic(err.Error())
+	}
+
+	return &Geometry{
+		Type:        geometryType,
+		Coordinates: raw,
+	}
+}
+
+// NewPoint порядок координат как в спецификации: долгота, широта
+func NewPoint(lon, lat float64) *Geometry {
+	return newGeometry(TypePoint, [2]float64{lon, lat})
+}
+
+// NewLineString каждая точка в порядке долгота, широта
+func NewLineString(points [][2]float64) *Geometry {
+	if points == nil {
+		points = [][2]float64{}
+	}
+
+	return newGeometry(TypeLineString, points)
+}
+
+// Point возвращает долготу и широту точки
+func (g Geometry) Point() (float64, float64, error) {
+	if g.Type != TypePoint {
+		return 0, 0, fmt.Errorf("expected geometry %s, got %s", TypePoint, g.Type)
+	}
+
+	var coordinates []float64
+	if err := json.Unmarshal(g.Coordinates, &coordinates); err != nil {
+		return 0, 0, err
+	}
+	if len(coordinates) < 2 {
+		return 0, 0, fmt.Errorf("point must have at least 2 coordinates, got %d", len(coordinates))
+	}
+
+	return coordinates[0], coordinates[1], nil
+}
+
+// MultiPolygon разбирает Polygon или MultiPolygon, Polygon возвращается как MultiPolygon из одного элемента
+func (g Geometry) MultiPolygon() (geo.MultiPolygon, error) {
+	switch g.Type {
+	case TypePolygon:
+		var polygon geo.Polygon
+		if err := json.Unmarshal(g.Coordinates, &polygon); err != nil {
+			return nil, err
+		}
+		return geo.MultiPolygon{polygon}, nil
+	case TypeMultiPolygon:
+		var multiPolygon geo.MultiPolygon
+		if err := json.Unmarshal(g.Coordinates, &multiPolygon); err != nil {
+			return nil, err
+		}
+		return multiPolygon, nil
+	default:
+		return nil, fmt.Errorf("expected geometry %s or %s, got %s", TypePolygon, TypeMultiPolygon, g.Type)
+	}
+}
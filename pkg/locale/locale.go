@@ -0,0 +1,112 @@
+package locale
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type contextKey struct{}
+
+var tagPattern = regexp.MustCompile(`^[a-z]{2,3}(-[a-z0-9]{2,8})*$`)
+
+// Normalize язык в нижнем регистре с дефисами, пустая строка для некорректного тега
+func Normalize(lang string) string {
+	lang = strings.ToLower(strings.ReplaceAll(strings.TrimSpace(lang), "_", "-"))
+	if !tagPattern.MatchString(lang) {
+		return ""
+	}
+
+	return lang
+}
+
+// appendWithBase добавляет язык и следом его базовый язык (en-us, затем en) без повторов
+func appendWithBase(langs []string, lang string) []string {
+	for _, candidate := range []string{lang, strings.SplitN(lang, "-", 2)[0]} {
+		found := false
+		for _, existing := range langs {
+			if existing == candidate {
+				found = true
+				break
+			}
+		}
+		if !found {
+			langs = append(langs, candidate)
+		}
+	}
+
+	return langs
+}
+
+// Parse языки по убыванию предпочтения: явный lang первым, затем Accept-Language по весу q.
+// Языки с q=0, "*" и некорректные теги пропускаются
+func Parse(lang, acceptLanguage string) []string {
+	var langs []string
+	if normalized := Normalize(lang); normalized != "" {
+		langs = appendWithBase(langs, normalized)
+	}
+
+	type weighted struct {
+		lang string
+		q    float64
+	}
+
+	var accepted []weighted
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		fields := strings.Split(part, ";")
+
+		q := 1.0
+		for _, param := range fields[1:] {
+			name, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+			if !ok || name != "q" {
+				continue
+			}
+			if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+				q = parsed
+			}
+		}
+
+		normalized := Normalize(fields[0])
+		if normalized == "" || q <= 0 {
+			continue
+		}
+
+		accepted = append(accepted, weighted{lang: normalized, q: q})
+	}
+
+	sort.SliceStable(accepted, func(i, j int) bool {
+		return accepted[i].q > accepted[j].q
+	})
+
+	for _, item := range accepted {
+		langs = appendWithBase(langs, item.lang)
+	}
+
+	return langs
+}
+
+// Chain цепочка поиска перевода: предпочтения клиента, затем язык по умолчанию, на котором заведён каталог.
+// Языки после языка по умолчанию не нужны, он есть всегда
+func Chain(preferred []string, defaultLang string) []string {
+	chain := make([]string, 0, len(preferred)+1)
+	for _, lang := range preferred {
+		if lang == defaultLang {
+			break
+		}
+		chain = append(chain, lang)
+	}
+
+	return append(chain, defaultLang)
+}
+
+func WithLanguages(ctx context.Context, langs []string) context.Context {
+	return context.WithValue(ctx, contextKey{}, langs)
+}
+
+// FromContext языки, выбранные middleware, nil если запрос без предпочтений
+func FromContext(ctx context.Context) []string {
+	langs, _ := ctx.Value(contextKey{}).([]string)
+	return langs
+}
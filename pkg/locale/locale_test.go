@@ -0,0 +1,49 @@
+package locale
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	cases := []struct {
+		name           string
+		lang           string
+		acceptLanguage string
+		expected       []string
+	}{
+		{"empty", "", "", nil},
+		{"weights", "", "ru;q=0.5, en-US, de;q=0.8", []string{"en-us", "en", "de", "ru"}},
+		{"explicit lang first", "de", "en-US,en;q=0.9", []string{"de", "en-us", "en"}},
+		{"skips wildcard and zero weight", "", "*, fr;q=0, en_GB", []string{"en-gb", "en"}},
+		{"bad tags", "not a lang", "12, en", []string{"en"}},
+	}
+
+	for _, tc := range cases {
+		if got := Parse(tc.lang, tc.acceptLanguage); !reflect.DeepEqual(got, tc.expected) {
+			t.Errorf("%s: expected %v, got %v", tc.name, tc.expected, got)
+		}
+	}
+}
+
+func TestChain(t *testing.T) {
+	if got := Chain(nil, "ru"); !reflect.DeepEqual(got, []string{"ru"}) {
+		t.Errorf("no preferences must fall back to default, got %v", got)
+	}
+
+	if got := Chain([]string{"en-us", "en", "ru", "de"}, "ru"); !reflect.DeepEqual(got, []string{"en-us", "en", "ru"}) {
+		t.Errorf("chain must stop at default language, got %v", got)
+	}
+}
+
+func TestContext(t *testing.T) {
+	if langs := FromContext(context.Background()); langs != nil {
+		t.Errorf("expected nil without middleware, got %v", langs)
+	}
+
+	ctx := WithLanguages(context.Background(), []string{"en"})
+	if langs := FromContext(ctx); !reflect.DeepEqual(langs, []string{"en"}) {
+		t.Errorf("unexpected languages %v", langs)
+	}
+}
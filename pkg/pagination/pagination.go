@@ -0,0 +1,52 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"mth/pkg/customerr"
+)
+
+// EncodeCursor упаковывает ключи последней записи страницы в непрозрачный для клиента токен
+func EncodeCursor(keys interface{}) (string, error) {
+	raw, err := json.Marshal(keys)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+// DecodeCursor пустой токен означает первую страницу и оставляет keys без изменений
+func DecodeCursor(token string, keys interface{}) error {
+	if token == "" {
+		return nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return fmt.Errorf("%w: %v", customerr.InvalidCursor, err)
+	}
+
+	if err = json.Unmarshal(raw, keys); err != nil {
+		return fmt.Errorf("%w: %v", customerr.InvalidCursor, err)
+	}
+
+	return nil
+}
+
+// PageSize размер страницы, выбранный клиентом, ограниченный сверху maxSize, без него defaultSize
+func PageSize(requested, defaultSize, maxSize int) int {
+	size := requested
+	if size <= 0 {
+		size = defaultSize
+	}
+	if maxSize > 0 && size > maxSize {
+		size = maxSize
+	}
+	if size <= 0 {
+		size = 1
+	}
+
+	return size
+}
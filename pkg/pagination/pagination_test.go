@@ -0,0 +1,55 @@
+package pagination
+
+import (
+	"errors"
+	"mth/pkg/customerr"
+	"testing"
+)
+
+type testKeys struct {
+	ID      int     `json:"id"`
+	Average float32 `json:"a"`
+}
+
+func TestCursorRoundTrip(t *testing.T) {
+	token, err := EncodeCursor(testKeys{ID: 42, Average: 4.3333335})
+	if err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+
+	var keys testKeys
+	if err = DecodeCursor(token, &keys); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if keys.ID != 42 || keys.Average != 4.3333335 {
+		t.Errorf("unexpected keys %+v", keys)
+	}
+}
+
+func TestDecodeCursor(t *testing.T) {
+	keys := testKeys{ID: 7}
+	if err := DecodeCursor("", &keys); err != nil || keys.ID != 7 {
+		t.Errorf("empty token must keep keys, got %+v, %v", keys, err)
+	}
+
+	if err := DecodeCursor("not a cursor!", &keys); !errors.Is(err, customerr.InvalidCursor) {
+		t.Errorf("expected InvalidCursor, got %v", err)
+	}
+}
+
+func TestPageSize(t *testing.T) {
+	cases := []struct {
+		requested, defaultSize, maxSize, expected int
+	}{
+		{0, 20, 100, 20},
+		{50, 20, 100, 50},
+		{500, 20, 100, 100},
+		{-1, 0, 100, 1},
+	}
+
+	for _, c := range cases {
+		if got := PageSize(c.requested, c.defaultSize, c.maxSize); got != c.expected {
+			t.Errorf("PageSize(%d, %d, %d) = %d, expected %d", c.requested, c.defaultSize, c.maxSize, got, c.expected)
+		}
+	}
+}
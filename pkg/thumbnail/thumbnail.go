@@ -0,0 +1,57 @@
+package thumbnail
+
+import (
+	"image"
+	"image/color"
+)
+
+// Fit уменьшает изображение так, чтобы большая сторона была не больше maxSide, сохраняя пропорции.
+// Каждый пиксель результата - среднее по покрываемой им области исходника, изображения меньше maxSide не увеличиваются
+func Fit(src image.Image, maxSide int) image.Image {
+	bounds := src.Bounds()
+	srcWidth, srcHeight := bounds.Dx(), bounds.Dy()
+
+	if maxSide <= 0 || srcWidth == 0 || srcHeight == 0 || (srcWidth <= maxSide && srcHeight <= maxSide) {
+		return src
+	}
+
+	dstWidth, dstHeight := maxSide, maxSide
+	if srcWidth > srcHeight {
+		dstHeight = max(1, srcHeight*maxSide/srcWidth)
+	} else {
+		dstWidth = max(1, srcWidth*maxSide/srcHeight)
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, dstWidth, dstHeight))
+
+	for y := 0; y < dstHeight; y++ {
+		srcY0 := bounds.Min.Y + y*srcHeight/dstHeight
+		srcY1 := max(srcY0+1, bounds.Min.Y+(y+1)*srcHeight/dstHeight)
+
+		for x := 0; x < dstWidth; x++ {
+			srcX0 := bounds.Min.X + x*srcWidth/dstWidth
+			srcX1 := max(srcX0+1, bounds.Min.X+(x+1)*srcWidth/dstWidth)
+
+			var r, g, b, a, count uint64
+			for sy := srcY0; sy < srcY1; sy++ {
+				for sx := srcX0; sx < srcX1; sx++ {
+					pr, pg, pb, pa := src.At(sx, sy).RGBA()
+					r += uint64(pr)
+					g += uint64(pg)
+					b += uint64(pb)
+					a += uint64(pa)
+					count++
+				}
+			}
+
+			dst.SetRGBA64(x, y, color.RGBA64{
+				R: uint16(r / count),
+				G: uint16(g / count),
+				B: uint16(b / count),
+				A: uint16(a / count),
+			})
+		}
+	}
+
+	return dst
+}
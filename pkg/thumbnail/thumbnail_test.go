@@ -0,0 +1,31 @@
+package thumbnail
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestFit(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 400, 200))
+	for y := 0; y < 200; y++ {
+		for x := 0; x < 400; x++ {
+			src.Set(x, y, color.RGBA{R: 255, A: 255})
+		}
+	}
+
+	thumb := Fit(src, 100)
+	if thumb.Bounds().Dx() != 100 || thumb.Bounds().Dy() != 50 {
+		t.Fatalf("wanted 100x50, got %vx%v", thumb.Bounds().Dx(), thumb.Bounds().Dy())
+	}
+
+	r, g, b, a := thumb.At(10, 10).RGBA()
+	if r>>8 != 255 || g != 0 || b != 0 || a>>8 != 255 {
+		t.Fatalf("wanted pure red pixel, got %v %v %v %v", r, g, b, a)
+	}
+
+	small := Fit(src, 1000)
+	if small != image.Image(src) {
+		t.Fatalf("image smaller than maxSide should be returned as is")
+	}
+}
@@ -0,0 +1,26 @@
+package wilson
+
+import "math"
+
+// Z95 квантиль нормального распределения для доверительного интервала 95%
+const Z95 = 1.96
+
+// LowerBound нижняя граница доверительного интервала Вильсона для доли положительных голосов.
+// Без голосов возвращает 0, поэтому отзыв с 1 из 1 ниже отзыва с 90 из 100
+func LowerBound(positive, total int, z float64) float64 {
+	if total <= 0 {
+		return 0
+	}
+	if positive < 0 {
+		positive = 0
+	}
+	if positive > total {
+		positive = total
+	}
+
+	n := float64(total)
+	p := float64(positive) / n
+	z2 := z * z
+
+	return (p + z2/(2*n) - z*math.Sqrt((p*(1-p)+z2/(4*n))/n)) / (1 + z2/n)
+}
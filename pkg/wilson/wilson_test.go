@@ -0,0 +1,31 @@
+package wilson
+
+import (
+	"math"
+	"testing"
+)
+
+func TestLowerBound(t *testing.T) {
+	if score := LowerBound(0, 0, Z95); score != 0 {
+		t.Errorf("expected zero without votes, got %v", score)
+	}
+
+	one := LowerBound(1, 1, Z95)
+	many := LowerBound(90, 100, Z95)
+	if one >= many {
+		t.Errorf("1 of 1 (%v) must be lower than 90 of 100 (%v)", one, many)
+	}
+
+	if score := LowerBound(0, 10, Z95); score != 0 {
+		t.Errorf("expected zero without positive votes, got %v", score)
+	}
+
+	// 8 из 10 при z = 1.96 около 0.49
+	if score := LowerBound(8, 10, Z95); math.Abs(score-0.4902) > 0.001 {
+		t.Errorf("unexpected score %v", score)
+	}
+
+	if score := LowerBound(100, 100, Z95); score <= 0.95 || score >= 1 {
+		t.Errorf("unexpected score %v", score)
+	}
+}